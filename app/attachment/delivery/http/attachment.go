@@ -0,0 +1,163 @@
+package http
+
+import (
+	"errors"
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/infrastructure/middlewares"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AttachmentHandler represent the http handler for attachment
+type AttachmentHandler struct {
+	AUseCase model.AttachmentUsecase
+}
+
+func NewAttachmentHandler(e *echo.Echo, us model.AttachmentUsecase) {
+	handler := &AttachmentHandler{
+		AUseCase: us,
+	}
+
+	attachments := e.Group("/api/v1/notes/:id/attachments")
+	_ = middlewares.AttachJwtToGroup(attachments)
+	attachments.GET("", handler.FetchAttachments)
+	attachments.POST("", handler.UploadAttachment)
+	attachments.GET("/:attachment_id", handler.DownloadAttachment)
+	attachments.DELETE("/:attachment_id", handler.DeleteAttachment)
+}
+
+func (a *AttachmentHandler) FetchAttachments(c echo.Context) error {
+	noteID, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	attachments, err := a.AUseCase.Fetch(ctx, noteID, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", attachments))
+}
+
+// UploadAttachment expects a multipart form with the content in the `file` field,
+// its size is bounded by the request body limit
+func (a *AttachmentHandler) UploadAttachment(c echo.Context) error {
+	noteID, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, errors.New("multipart field file is required")))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	defer file.Close()
+
+	ctx := c.Request().Context()
+
+	attachment, err := a.AUseCase.Upload(ctx, noteID, middlewares.GetUserID(c), &model.Upload{
+		Name:    fileHeader.Filename,
+		Size:    fileHeader.Size,
+		Content: file,
+	})
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("attachment uploaded", attachment))
+}
+
+// DownloadAttachment serves the content with Range support, `?thumbnail=1` serves the thumbnail of an image
+func (a *AttachmentHandler) DownloadAttachment(c echo.Context) error {
+	noteID, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	id, err := middlewares.ParamID(c, "attachment_id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	attachment, err := a.AUseCase.Get(ctx, id, noteID, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	thumbnail := c.QueryParam("thumbnail") == "1"
+
+	content, err := a.AUseCase.Open(ctx, attachment, thumbnail)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	defer content.Close()
+
+	contentType := attachment.ContentType
+	if thumbnail {
+		contentType = thumbnailContentType(attachment)
+	}
+
+	// only images are shown inline, anything else could be active content on our origin
+	disposition := "attachment"
+	if strings.HasPrefix(contentType, "image/") {
+		disposition = "inline"
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, contentType)
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{
+		"filename": attachment.Name,
+	}))
+	header.Set("X-Content-Type-Options", "nosniff")
+
+	modTime, _ := time.Parse("2006-01-02 15:04:05", attachment.CreatedAt)
+	http.ServeContent(c.Response(), c.Request(), attachment.Name, modTime, content)
+
+	return nil
+}
+
+func thumbnailContentType(attachment *model.Attachment) string {
+	if attachment.ContentType == "image/jpeg" {
+		return "image/jpeg"
+	}
+
+	return "image/png"
+}
+
+func (a *AttachmentHandler) DeleteAttachment(c echo.Context) error {
+	noteID, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	id, err := middlewares.ParamID(c, "attachment_id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	err = a.AUseCase.Delete(ctx, id, noteID, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
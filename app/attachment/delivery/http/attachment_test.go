@@ -0,0 +1,199 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	attachmentHttp "librenote/app/attachment/delivery/http"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var BaseURLV1 = "/api/v1"
+
+func buildEchoAuthorizedRequest(t *testing.T, method, path, token string, payload io.Reader) (
+	echo.Context, *httptest.ResponseRecorder) {
+	var req *http.Request
+
+	var err error
+
+	if payload != nil {
+		req, err = http.NewRequest(method, path, payload)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	} else {
+		req, err = http.NewRequest(method, path, nil)
+	}
+
+	assert.NoError(t, err)
+
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+	res := httptest.NewRecorder()
+	e := echo.New()
+	ctx := e.NewContext(req, res)
+
+	return ctx, res
+}
+
+// nolint:unparam
+func getToken(userID int32) string {
+	jwtCfg := config.Get().Jwt
+	claims := &middlewares.JwtCustomClaims{
+		UserID: userID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(jwtCfg.ExpireTime).Unix(),
+		},
+	}
+	unsignedToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token, _ := unsignedToken.SignedString([]byte(jwtCfg.SecretKey))
+
+	return token
+}
+
+func attachJWTMiddleware(hfc echo.HandlerFunc) echo.HandlerFunc {
+	mhfc := middleware.JWTWithConfig(
+		middleware.JWTConfig{
+			Claims:     &middlewares.JwtCustomClaims{},
+			SigningKey: []byte(config.Get().Jwt.SecretKey),
+		})(hfc)
+
+	return mhfc
+}
+
+type readSeekCloser struct {
+	*strings.Reader
+}
+
+func (readSeekCloser) Close() error {
+	return nil
+}
+
+func TestUploadAttachment(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id/attachments"
+
+	mockUsecase := new(mocks.AttachmentUsecase)
+	mockUsecase.On("Upload", mock.Anything, int32(1), int32(1), mock.MatchedBy(func(u *model.Upload) bool {
+		content, _ := io.ReadAll(u.Content)
+		return u.Name == "hello.txt" && u.Size == 11 && string(content) == "hello world"
+	})).Return(&model.Attachment{ID: 4, NoteID: 1, UserID: 1, Name: "hello.txt", Size: 11}, nil).Once()
+
+	handler := attachmentHttp.AttachmentHandler{
+		AUseCase: mockUsecase,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var body bytes.Buffer
+
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", "hello.txt")
+		assert.NoError(t, err)
+		_, _ = part.Write([]byte("hello world"))
+		assert.NoError(t, writer.Close())
+
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), &body)
+		ctx.Request().Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+		handle := attachJWTMiddleware(handler.UploadAttachment)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		var r response.Response
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+		assert.True(t, r.Success)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("missing-file", func(t *testing.T) {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader("{}"))
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+		handle := attachJWTMiddleware(handler.UploadAttachment)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id/attachments/:attachment_id"
+	attachment := &model.Attachment{
+		ID: 4, NoteID: 1, UserID: 1, Name: "hello.txt", ContentType: "text/plain; charset=utf-8", Size: 11,
+		CreatedAt: "2022-01-31 09:00:00",
+	}
+
+	mockUsecase := new(mocks.AttachmentUsecase)
+	mockUsecase.On("Get", mock.Anything, int32(4), int32(1), int32(1)).Return(attachment, nil)
+	mockUsecase.On("Open", mock.Anything, attachment, false).
+		Return(readSeekCloser{strings.NewReader("hello world")}, nil)
+
+	handler := attachmentHttp.AttachmentHandler{
+		AUseCase: mockUsecase,
+	}
+
+	t.Run("full", func(t *testing.T) {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+		ctx.SetParamNames("id", "attachment_id")
+		ctx.SetParamValues("1", "4")
+		handle := attachJWTMiddleware(handler.DownloadAttachment)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "hello world", res.Body.String())
+		assert.Equal(t, "attachment; filename=hello.txt", res.Header().Get(echo.HeaderContentDisposition))
+		assert.Equal(t, "text/plain; charset=utf-8", res.Header().Get(echo.HeaderContentType))
+	})
+
+	t.Run("range", func(t *testing.T) {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+		ctx.Request().Header.Set("Range", "bytes=6-")
+		ctx.SetParamNames("id", "attachment_id")
+		ctx.SetParamValues("1", "4")
+		handle := attachJWTMiddleware(handler.DownloadAttachment)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusPartialContent, res.Code)
+		assert.Equal(t, "world", res.Body.String())
+		assert.Equal(t, "bytes 6-10/11", res.Header().Get("Content-Range"))
+	})
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id/attachments/:attachment_id"
+
+	mockUsecase := new(mocks.AttachmentUsecase)
+	mockUsecase.On("Delete", mock.Anything, int32(4), int32(1), int32(1)).Return(nil).Once()
+	mockUsecase.On("Delete", mock.Anything, int32(5), int32(1), int32(1)).Return(response.ErrNotFound).Once()
+
+	handler := attachmentHttp.AttachmentHandler{
+		AUseCase: mockUsecase,
+	}
+
+	for id, code := range map[string]int{"4": http.StatusNoContent, "5": http.StatusNotFound} {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.DELETE, endPoint, getToken(1), nil)
+		ctx.SetParamNames("id", "attachment_id")
+		ctx.SetParamValues("1", id)
+		handle := attachJWTMiddleware(handler.DeleteAttachment)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, code, res.Code)
+	}
+
+	mockUsecase.AssertExpectations(t)
+}
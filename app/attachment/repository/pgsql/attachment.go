@@ -0,0 +1,156 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"librenote/app/model"
+)
+
+type attachmentRepository struct {
+	db *sql.DB
+}
+
+func NewPgsqlAttachmentRepository(db *sql.DB) model.AttachmentRepository {
+	return &attachmentRepository{
+		db: db,
+	}
+}
+
+const createAttachment = `INSERT INTO attachments (
+  note_id, user_id, name, content_type, size, blob_key, thumbnail_key, thumbnail_size, created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING id
+`
+
+// lockUser makes the uploads of a user wait for each other, the sum of their sizes is then up to date
+const lockUser = `SELECT id FROM users WHERE id = $1 FOR UPDATE`
+
+func (r *attachmentRepository) CreateAttachment(ctx context.Context, attachment *model.Attachment,
+	quota int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	if quota > 0 {
+		if _, err = tx.ExecContext(ctx, lockUser, attachment.UserID); err != nil {
+			return err
+		}
+
+		var used int64
+		if err = tx.QueryRowContext(ctx, usedStorage, attachment.UserID).Scan(&used); err != nil {
+			return err
+		}
+
+		if used+attachment.Size+attachment.ThumbnailSize > quota {
+			return model.ErrQuotaExceeded
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, createAttachment,
+		attachment.NoteID,
+		attachment.UserID,
+		attachment.Name,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.BlobKey,
+		attachment.ThumbnailKey,
+		attachment.ThumbnailSize,
+		attachment.CreatedAt,
+	).Scan(&attachment.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const attachmentColumns = `id, note_id, user_id, name, content_type, size, blob_key, thumbnail_key, thumbnail_size, created_at::text`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAttachment(row scanner) (model.Attachment, error) {
+	var i model.Attachment
+
+	err := row.Scan(
+		&i.ID,
+		&i.NoteID,
+		&i.UserID,
+		&i.Name,
+		&i.ContentType,
+		&i.Size,
+		&i.BlobKey,
+		&i.ThumbnailKey,
+		&i.ThumbnailSize,
+		&i.CreatedAt,
+	)
+	i.HasThumbnail = i.ThumbnailKey != ""
+
+	return i, err
+}
+
+const getAttachment = `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = $1 AND note_id = $2 LIMIT 1`
+
+func (r *attachmentRepository) GetAttachment(ctx context.Context, id, noteID int32) (model.Attachment, error) {
+	return scanAttachment(r.db.QueryRowContext(ctx, getAttachment, id, noteID))
+}
+
+const fetchAttachments = `SELECT ` + attachmentColumns + ` FROM attachments WHERE note_id = $1 ORDER BY id`
+
+func (r *attachmentRepository) FetchAttachments(ctx context.Context, noteID int32) ([]model.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, fetchAttachments, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	attachments := make([]model.Attachment, 0)
+
+	for rows.Next() {
+		i, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, i)
+	}
+
+	return attachments, rows.Err()
+}
+
+const deleteAttachment = `DELETE FROM attachments WHERE id = $1`
+
+func (r *attachmentRepository) DeleteAttachment(ctx context.Context, id int32) error {
+	res, err := r.db.ExecContext(ctx, deleteAttachment, id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// usedStorage counts the thumbnails too
+const usedStorage = `SELECT COALESCE(SUM(size + thumbnail_size), 0) FROM attachments WHERE user_id = $1`
+
+func (r *attachmentRepository) UsedStorage(ctx context.Context, userID int32) (int64, error) {
+	var used int64
+
+	err := r.db.QueryRowContext(ctx, usedStorage, userID).Scan(&used)
+
+	return used, err
+}
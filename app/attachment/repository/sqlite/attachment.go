@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"librenote/app/model"
+)
+
+type attachmentRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteAttachmentRepository(db *sql.DB) model.AttachmentRepository {
+	return &attachmentRepository{
+		db: db,
+	}
+}
+
+// createAttachment only inserts while the attachments of the user fit in the quota, a single statement is atomic
+// as sqlite has a single writer
+const createAttachment = `INSERT INTO attachments (
+  note_id, user_id, name, content_type, size, blob_key, thumbnail_key, thumbnail_size, created_at
+) SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?
+WHERE ? = 0 OR (SELECT COALESCE(SUM(size + thumbnail_size), 0) FROM attachments WHERE user_id = ?) + ? <= ?
+`
+
+func (r *attachmentRepository) CreateAttachment(ctx context.Context, attachment *model.Attachment,
+	quota int64) error {
+	res, err := r.db.ExecContext(ctx, createAttachment,
+		attachment.NoteID,
+		attachment.UserID,
+		attachment.Name,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.BlobKey,
+		attachment.ThumbnailKey,
+		attachment.ThumbnailSize,
+		attachment.CreatedAt,
+		quota,
+		attachment.UserID,
+		attachment.Size+attachment.ThumbnailSize,
+		quota,
+	)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return model.ErrQuotaExceeded
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	attachment.ID = int32(id)
+
+	return nil
+}
+
+const attachmentColumns = `id, note_id, user_id, name, content_type, size, blob_key, thumbnail_key, thumbnail_size, created_at`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAttachment(row scanner) (model.Attachment, error) {
+	var i model.Attachment
+
+	err := row.Scan(
+		&i.ID,
+		&i.NoteID,
+		&i.UserID,
+		&i.Name,
+		&i.ContentType,
+		&i.Size,
+		&i.BlobKey,
+		&i.ThumbnailKey,
+		&i.ThumbnailSize,
+		&i.CreatedAt,
+	)
+	i.HasThumbnail = i.ThumbnailKey != ""
+
+	return i, err
+}
+
+const getAttachment = `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = ? AND note_id = ? LIMIT 1`
+
+func (r *attachmentRepository) GetAttachment(ctx context.Context, id, noteID int32) (model.Attachment, error) {
+	return scanAttachment(r.db.QueryRowContext(ctx, getAttachment, id, noteID))
+}
+
+const fetchAttachments = `SELECT ` + attachmentColumns + ` FROM attachments WHERE note_id = ? ORDER BY id`
+
+func (r *attachmentRepository) FetchAttachments(ctx context.Context, noteID int32) ([]model.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, fetchAttachments, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	attachments := make([]model.Attachment, 0)
+
+	for rows.Next() {
+		i, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, i)
+	}
+
+	return attachments, rows.Err()
+}
+
+const deleteAttachment = `DELETE FROM attachments WHERE id = ?`
+
+func (r *attachmentRepository) DeleteAttachment(ctx context.Context, id int32) error {
+	res, err := r.db.ExecContext(ctx, deleteAttachment, id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// usedStorage counts the thumbnails too
+const usedStorage = `SELECT COALESCE(SUM(size + thumbnail_size), 0) FROM attachments WHERE user_id = ?`
+
+func (r *attachmentRepository) UsedStorage(ctx context.Context, userID int32) (int64, error) {
+	var used int64
+
+	err := r.db.QueryRowContext(ctx, usedStorage, userID).Scan(&used)
+
+	return used, err
+}
@@ -0,0 +1,104 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	attachmentRepo "librenote/app/attachment/repository/sqlite"
+	"librenote/app/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAttachment(t *testing.T) {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	a := &model.Attachment{
+		NoteID: 3, UserID: 1, Name: "cat.png", ContentType: "image/png", Size: 1024,
+		BlobKey: "attachments/1/abc", ThumbnailKey: "attachments/1/abc.thumb",
+		ThumbnailSize: 300, CreatedAt: nowTime,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	quota := int64(1 << 20)
+
+	t.Run("fits", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO attachments (.+) SELECT (.+) WHERE").
+			WithArgs(a.NoteID, a.UserID, a.Name, a.ContentType, a.Size, a.BlobKey, a.ThumbnailKey, a.ThumbnailSize,
+				a.CreatedAt, quota, a.UserID, int64(1324), quota).
+			WillReturnResult(sqlmock.NewResult(4, 1))
+
+		ar := attachmentRepo.NewSqliteAttachmentRepository(db)
+		assert.NoError(t, ar.CreateAttachment(context.TODO(), a, quota))
+		assert.Equal(t, int32(4), a.ID)
+	})
+
+	t.Run("quota-exceeded", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO attachments").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ar := attachmentRepo.NewSqliteAttachmentRepository(db)
+		assert.ErrorIs(t, ar.CreateAttachment(context.TODO(), a, quota), model.ErrQuotaExceeded)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFetchAttachments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	rows := sqlmock.NewRows([]string{"id", "note_id", "user_id", "name", "content_type", "size", "blob_key",
+		"thumbnail_key", "thumbnail_size", "created_at"}).
+		AddRow(4, 3, 1, "cat.png", "image/png", 1024, "attachments/1/abc", "attachments/1/abc.thumb", 300,
+			nowTime).
+		AddRow(5, 3, 1, "notes.txt", "text/plain", 12, "attachments/1/def", "", 0, nowTime)
+
+	mock.ExpectQuery("SELECT (.+) FROM attachments WHERE").WithArgs(int32(3)).WillReturnRows(rows)
+
+	ar := attachmentRepo.NewSqliteAttachmentRepository(db)
+	attachments, err := ar.FetchAttachments(context.TODO(), 3)
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 2)
+	assert.True(t, attachments[0].HasThumbnail)
+	assert.False(t, attachments[1].HasThumbnail)
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM attachments").WithArgs(int32(4)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM attachments").WithArgs(int32(9)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ar := attachmentRepo.NewSqliteAttachmentRepository(db)
+	assert.NoError(t, ar.DeleteAttachment(context.TODO(), 4))
+	assert.ErrorIs(t, ar.DeleteAttachment(context.TODO(), 9), sql.ErrNoRows)
+}
+
+func TestUsedStorage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT COALESCE").WithArgs(int32(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"used"}).AddRow(2048))
+
+	ar := attachmentRepo.NewSqliteAttachmentRepository(db)
+	used, err := ar.UsedStorage(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2048), used)
+}
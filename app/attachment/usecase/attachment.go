@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/logger"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+var errQuotaExceeded = response.WrapError(model.ErrQuotaExceeded, http.StatusRequestEntityTooLarge)
+
+type attachmentUsecase struct {
+	repo           model.AttachmentRepository
+	noteRepo       model.NoteRepository
+	blobs          model.BlobStore
+	contextTimeout time.Duration
+	cfg            config.StorageConfig
+}
+
+func NewAttachmentUsecase(repo model.AttachmentRepository, noteRepo model.NoteRepository, blobs model.BlobStore,
+	timeout time.Duration, cfg config.StorageConfig) model.AttachmentUsecase {
+	return &attachmentUsecase{
+		repo:           repo,
+		noteRepo:       noteRepo,
+		blobs:          blobs,
+		contextTimeout: timeout,
+		cfg:            cfg,
+	}
+}
+
+// Upload stores the content and a thumbnail of images, the content type is sniffed instead of trusting the client
+func (u *attachmentUsecase) Upload(c context.Context, noteID, userID int32, upload *model.Upload) (
+	*model.Attachment, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err := u.checkNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+
+	// refused early to spare the upload, the quota is enforced by the insert as parallel uploads pass this check
+	if u.cfg.UserQuotaBytes > 0 {
+		used, err := u.repo.UsedStorage(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		if used+upload.Size > u.cfg.UserQuotaBytes {
+			return nil, errQuotaExceeded
+		}
+	}
+
+	contentType, err := sniff(upload.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newKey(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &model.Attachment{
+		NoteID:      noteID,
+		UserID:      userID,
+		Name:        filepath.Base(upload.Name),
+		ContentType: contentType,
+		Size:        upload.Size,
+		BlobKey:     key,
+		CreatedAt:   time.Now().UTC().Format(timeLayout),
+	}
+
+	if err = u.blobs.Put(ctx, m.BlobKey, upload.Content, m.Size, m.ContentType); err != nil {
+		return nil, err
+	}
+
+	u.putThumbnail(ctx, m, upload.Content)
+
+	if err = u.repo.CreateAttachment(ctx, m, u.cfg.UserQuotaBytes); err != nil {
+		u.deleteBlobs(ctx, m)
+
+		if errors.Is(err, model.ErrQuotaExceeded) {
+			return nil, errQuotaExceeded
+		}
+
+		return nil, err
+	}
+
+	m.HasThumbnail = m.ThumbnailKey != ""
+
+	return m, nil
+}
+
+func sniff(content io.ReadSeeker) (string, error) {
+	head := make([]byte, 512)
+
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	if _, err = content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return http.DetectContentType(head[:n]), nil
+}
+
+// newKey returns a random blob key, the user id prefix keeps a user's blobs together
+func newKey(userID int32) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("attachments/%d/%s", userID, hex.EncodeToString(b)), nil
+}
+
+// putThumbnail is best effort, an image which can't be decoded is still a valid attachment
+func (u *attachmentUsecase) putThumbnail(ctx context.Context, m *model.Attachment, content io.ReadSeeker) {
+	switch m.ContentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return
+	}
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+
+	data, contentType, err := thumbnail(content, u.cfg.ThumbnailSize)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("no thumbnail for %s: %s", m.BlobKey, err)
+		return
+	}
+
+	key := m.BlobKey + ".thumb"
+	if err = u.blobs.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		logger.FromContext(ctx).Errorf("failed to store thumbnail %s: %s", key, err)
+		return
+	}
+
+	m.ThumbnailKey = key
+	m.ThumbnailSize = int64(len(data))
+}
+
+func (u *attachmentUsecase) Fetch(c context.Context, noteID, userID int32) ([]model.Attachment, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err := u.checkNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FetchAttachments(ctx, noteID)
+}
+
+func (u *attachmentUsecase) Get(c context.Context, id, noteID, userID int32) (*model.Attachment, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err := u.checkNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+
+	attachment, err := u.repo.GetAttachment(ctx, id, noteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, response.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// Open isn't bound to the usecase timeout, the content is read while it is streamed to the client
+func (u *attachmentUsecase) Open(c context.Context, attachment *model.Attachment, thumbnail bool) (
+	io.ReadSeekCloser, error) {
+	key, size := attachment.BlobKey, attachment.Size
+
+	if thumbnail {
+		if attachment.ThumbnailKey == "" {
+			return nil, response.ErrNotFound
+		}
+
+		key, size = attachment.ThumbnailKey, attachment.ThumbnailSize
+	}
+
+	content, err := u.blobs.Open(c, key, size)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, response.ErrNotFound
+	}
+
+	return content, err
+}
+
+func (u *attachmentUsecase) Delete(c context.Context, id, noteID, userID int32) error {
+	attachment, err := u.Get(c, id, noteID, userID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err = u.repo.DeleteAttachment(ctx, attachment.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return response.ErrNotFound
+		}
+
+		return err
+	}
+
+	u.deleteBlobs(ctx, attachment)
+
+	return nil
+}
+
+// deleteBlobs only logs failures, an orphaned blob costs space but breaks nothing
+func (u *attachmentUsecase) deleteBlobs(ctx context.Context, attachment *model.Attachment) {
+	for _, key := range []string{attachment.BlobKey, attachment.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+
+		if err := u.blobs.Delete(ctx, key); err != nil {
+			logger.FromContext(ctx).Errorf("failed to delete blob %s: %s", key, err)
+		}
+	}
+}
+
+// Publish removes the attachments of deleted notes
+func (u *attachmentUsecase) Publish(c context.Context, _ int32, event string, data interface{}) {
+	if event != model.EventNoteDeleted {
+		return
+	}
+
+	note, ok := data.(map[string]int32)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	attachments, err := u.repo.FetchAttachments(ctx, note["id"])
+	if err != nil {
+		logger.FromContext(ctx).Errorf("failed to fetch attachments of note %d: %s", note["id"], err)
+		return
+	}
+
+	for i := range attachments {
+		if err = u.repo.DeleteAttachment(ctx, attachments[i].ID); err != nil {
+			logger.FromContext(ctx).Errorf("failed to delete attachment %d: %s", attachments[i].ID, err)
+			continue
+		}
+
+		u.deleteBlobs(ctx, &attachments[i])
+	}
+}
+
+func (u *attachmentUsecase) checkNote(ctx context.Context, noteID, userID int32) error {
+	if _, err := u.noteRepo.GetNote(ctx, noteID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return response.ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"librenote/app/attachment/usecase"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/storage"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func pngImage(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, x%h, color.RGBA{R: 255, A: 255})
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+func storageConfig() config.StorageConfig {
+	return config.StorageConfig{UserQuotaBytes: 1 << 20, ThumbnailSize: 64}
+}
+
+func TestUpload(t *testing.T) {
+	t.Run("image", func(t *testing.T) {
+		mockRepo := new(mocks.AttachmentRepository)
+		mockNoteRepo := new(mocks.NoteRepository)
+		blobs := storage.NewLocalStore(t.TempDir())
+		content := pngImage(t, 400, 200)
+
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(model.Note{ID: 1, UserID: 1}, nil)
+		mockRepo.On("UsedStorage", mock.Anything, int32(1)).Return(int64(1000), nil)
+		mockRepo.On("CreateAttachment", mock.Anything, mock.AnythingOfType("*model.Attachment"), int64(1<<20)).
+			Return(nil).Once()
+
+		u := usecase.NewAttachmentUsecase(mockRepo, mockNoteRepo, blobs, time.Second*2, storageConfig())
+		a, err := u.Upload(context.TODO(), 1, 1, &model.Upload{
+			Name: "../cat.png", Size: int64(len(content)), Content: bytes.NewReader(content),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "cat.png", a.Name)
+		assert.Equal(t, "image/png", a.ContentType)
+		assert.True(t, a.HasThumbnail)
+
+		r, err := u.Open(context.TODO(), a, true)
+		assert.NoError(t, err)
+		defer r.Close()
+
+		thumb, err := png.DecodeConfig(r)
+		assert.NoError(t, err)
+		assert.Equal(t, 64, thumb.Width)
+		assert.Equal(t, 32, thumb.Height)
+
+		r, err = u.Open(context.TODO(), a, false)
+		assert.NoError(t, err)
+		defer r.Close()
+
+		stored, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, content, stored)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		mockRepo := new(mocks.AttachmentRepository)
+		mockNoteRepo := new(mocks.NoteRepository)
+		content := []byte("hello world")
+
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(model.Note{ID: 1, UserID: 1}, nil)
+		mockRepo.On("UsedStorage", mock.Anything, int32(1)).Return(int64(0), nil)
+		mockRepo.On("CreateAttachment", mock.Anything, mock.AnythingOfType("*model.Attachment"), int64(1<<20)).
+			Return(nil).Once()
+
+		u := usecase.NewAttachmentUsecase(mockRepo, mockNoteRepo, storage.NewLocalStore(t.TempDir()), time.Second*2,
+			storageConfig())
+		a, err := u.Upload(context.TODO(), 1, 1, &model.Upload{
+			Name: "hello.txt", Size: int64(len(content)), Content: bytes.NewReader(content),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "text/plain; charset=utf-8", a.ContentType)
+		assert.False(t, a.HasThumbnail)
+
+		_, err = u.Open(context.TODO(), a, true)
+		assert.ErrorIs(t, err, response.ErrNotFound)
+	})
+
+	t.Run("quota-exceeded", func(t *testing.T) {
+		mockRepo := new(mocks.AttachmentRepository)
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockBlobs := new(mocks.BlobStore)
+
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(model.Note{ID: 1, UserID: 1}, nil)
+		mockRepo.On("UsedStorage", mock.Anything, int32(1)).Return(int64(1<<20-10), nil)
+
+		u := usecase.NewAttachmentUsecase(mockRepo, mockNoteRepo, mockBlobs, time.Second*2, storageConfig())
+		_, err := u.Upload(context.TODO(), 1, 1, &model.Upload{
+			Name: "big.bin", Size: 11, Content: bytes.NewReader(make([]byte, 11)),
+		})
+		assert.Error(t, err)
+
+		code, _ := response.RespondError(err)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+		mockBlobs.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("quota-exceeded-by-parallel-upload", func(t *testing.T) {
+		mockRepo := new(mocks.AttachmentRepository)
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockBlobs := new(mocks.BlobStore)
+
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(model.Note{ID: 1, UserID: 1}, nil)
+		mockRepo.On("UsedStorage", mock.Anything, int32(1)).Return(int64(0), nil)
+		mockRepo.On("CreateAttachment", mock.Anything, mock.AnythingOfType("*model.Attachment"), int64(1<<20)).
+			Return(model.ErrQuotaExceeded).Once()
+		mockBlobs.On("Put", mock.Anything, mock.Anything, mock.Anything, int64(11), mock.Anything).Return(nil).Once()
+		mockBlobs.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+
+		u := usecase.NewAttachmentUsecase(mockRepo, mockNoteRepo, mockBlobs, time.Second*2, storageConfig())
+		_, err := u.Upload(context.TODO(), 1, 1, &model.Upload{
+			Name: "big.bin", Size: 11, Content: bytes.NewReader(make([]byte, 11)),
+		})
+
+		code, _ := response.RespondError(err)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+		// the stored content is deleted
+		mockBlobs.AssertExpectations(t)
+	})
+
+	t.Run("not-owner", func(t *testing.T) {
+		mockRepo := new(mocks.AttachmentRepository)
+		mockNoteRepo := new(mocks.NoteRepository)
+
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(2)).Return(model.Note{}, sql.ErrNoRows)
+
+		u := usecase.NewAttachmentUsecase(mockRepo, mockNoteRepo, new(mocks.BlobStore), time.Second*2, storageConfig())
+		_, err := u.Upload(context.TODO(), 1, 2, &model.Upload{Name: "a", Size: 1, Content: bytes.NewReader([]byte("a"))})
+		assert.ErrorIs(t, err, response.ErrNotFound)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockRepo := new(mocks.AttachmentRepository)
+	mockNoteRepo := new(mocks.NoteRepository)
+	mockBlobs := new(mocks.BlobStore)
+	a := model.Attachment{ID: 4, NoteID: 1, UserID: 1, BlobKey: "attachments/1/abc", ThumbnailKey: ""}
+
+	mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(model.Note{ID: 1, UserID: 1}, nil)
+	mockRepo.On("GetAttachment", mock.Anything, int32(4), int32(1)).Return(a, nil)
+	mockRepo.On("DeleteAttachment", mock.Anything, int32(4)).Return(nil).Once()
+	mockBlobs.On("Delete", mock.Anything, "attachments/1/abc").Return(nil).Once()
+
+	u := usecase.NewAttachmentUsecase(mockRepo, mockNoteRepo, mockBlobs, time.Second*2, storageConfig())
+	assert.NoError(t, u.Delete(context.TODO(), 4, 1, 1))
+	mockRepo.AssertExpectations(t)
+	mockBlobs.AssertExpectations(t)
+}
+
+func TestPublishNoteDeleted(t *testing.T) {
+	mockRepo := new(mocks.AttachmentRepository)
+	mockBlobs := new(mocks.BlobStore)
+	attachments := []model.Attachment{
+		{ID: 4, NoteID: 1, BlobKey: "attachments/1/abc", ThumbnailKey: "attachments/1/abc.thumb"},
+	}
+
+	mockRepo.On("FetchAttachments", mock.Anything, int32(1)).Return(attachments, nil).Once()
+	mockRepo.On("DeleteAttachment", mock.Anything, int32(4)).Return(nil).Once()
+	mockBlobs.On("Delete", mock.Anything, "attachments/1/abc").Return(nil).Once()
+	mockBlobs.On("Delete", mock.Anything, "attachments/1/abc.thumb").Return(nil).Once()
+
+	u := usecase.NewAttachmentUsecase(mockRepo, new(mocks.NoteRepository), mockBlobs, time.Second*2, storageConfig())
+	u.Publish(context.TODO(), 1, model.EventNoteUpdated, map[string]int32{"id": 1})
+	u.Publish(context.TODO(), 1, model.EventNoteDeleted, map[string]int32{"id": 1})
+
+	mockRepo.AssertExpectations(t)
+	mockBlobs.AssertExpectations(t)
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif" // register the gif decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// maxPixels refuses to decode images which would take too much memory
+const maxPixels = 50_000_000
+
+// thumbnail scales an image down to fit a size x size square, jpeg sources stay jpeg, others become png
+func thumbnail(r io.ReadSeeker, size int) ([]byte, string, error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, "", errors.New("image is too large for a thumbnail")
+	}
+
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return nil, "", err
+	}
+
+	src, format, err := image.Decode(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dst := scale(src, size)
+
+	var buf bytes.Buffer
+
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80})
+		return buf.Bytes(), "image/jpeg", err
+	}
+
+	err = png.Encode(&buf, dst)
+
+	return buf.Bytes(), "image/png", err
+}
+
+// scale shrinks the image keeping its aspect ratio, every destination pixel is the average of its source box
+func scale(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	if w <= size && h <= size {
+		return src
+	}
+
+	dw, dh := size, h*size/w
+	if h > w {
+		dw, dh = w*size/h, size
+	}
+
+	if dw < 1 {
+		dw = 1
+	}
+
+	if dh < 1 {
+		dh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		y0, y1 := b.Min.Y+y*h/dh, b.Min.Y+(y+1)*h/dh
+
+		for x := 0; x < dw; x++ {
+			x0, x1 := b.Min.X+x*w/dw, b.Min.X+(x+1)*w/dw
+
+			var r, g, bl, a, n uint64
+
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
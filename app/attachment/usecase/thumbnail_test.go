@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThumbnail(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 100; x++ {
+			img.Set(x, y, color.RGBA{B: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, img, nil))
+
+	data, contentType, err := thumbnail(bytes.NewReader(buf.Bytes()), 30)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/jpeg", contentType)
+
+	thumb, err := jpeg.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, thumb.Bounds().Dx())
+	assert.Equal(t, 30, thumb.Bounds().Dy())
+
+	_, _, b, _ := thumb.At(5, 15).RGBA()
+	assert.InDelta(t, 200, b>>8, 10)
+
+	_, _, err = thumbnail(bytes.NewReader([]byte("not an image")), 30)
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/app/validation"
+	"librenote/infrastructure/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AuditHandler represent the http handler for the audit log
+type AuditHandler struct {
+	AUseCase model.AuditUsecase
+}
+
+func NewAuditHandler(e *echo.Echo, us model.AuditUsecase) {
+	handler := &AuditHandler{
+		AUseCase: us,
+	}
+
+	me := e.Group("/api/v1/me/audit")
+	_ = middlewares.AttachJwtToGroup(me)
+	me.GET("", handler.FetchEvents)
+
+	admin := e.Group("/api/v1/admin/audit")
+	_ = middlewares.AttachJwtToGroup(admin)
+	admin.GET("", handler.FetchAllEvents)
+}
+
+// FetchEvents returns the events of the user's own account
+func (a *AuditHandler) FetchEvents(c echo.Context) error {
+	var fReq fetchEventsReq
+
+	err := c.Bind(&fReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&fReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	filter, err := fReq.filter()
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	events, count, err := a.AUseCase.Fetch(ctx, middlewares.GetUserID(c), filter, fReq.Page, fReq.PageSize)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondPage("request success", events, count, fReq.Page, fReq.PageSize))
+}
+
+// FetchAllEvents returns the events of all the accounts, filtered by user_id and actor_id as well, to the admins
+func (a *AuditHandler) FetchAllEvents(c echo.Context) error {
+	var fReq fetchEventsReq
+
+	err := c.Bind(&fReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&fReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	filter, err := fReq.filter()
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	if fReq.UserID > 0 {
+		filter.UserID = &fReq.UserID
+	}
+
+	if fReq.ActorID > 0 {
+		filter.ActorID = &fReq.ActorID
+	}
+
+	ctx := c.Request().Context()
+
+	events, count, err := a.AUseCase.FetchAll(ctx, middlewares.GetUserID(c), filter, fReq.Page, fReq.PageSize)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondPage("request success", events, count, fReq.Page, fReq.PageSize))
+}
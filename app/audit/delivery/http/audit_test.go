@@ -0,0 +1,135 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	auditHttp "librenote/app/audit/delivery/http"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var BaseURLV1 = "/api/v1"
+
+func buildEchoAuthorizedRequest(t *testing.T, path, token string) (echo.Context, *httptest.ResponseRecorder) {
+	req, err := http.NewRequest(echo.GET, path, nil)
+	assert.NoError(t, err)
+
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+	res := httptest.NewRecorder()
+	e := echo.New()
+	ctx := e.NewContext(req, res)
+
+	return ctx, res
+}
+
+func getToken(userID int32) string {
+	jwtCfg := config.Get().Jwt
+	claims := &middlewares.JwtCustomClaims{
+		UserID: userID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(jwtCfg.ExpireTime).Unix(),
+		},
+	}
+	unsignedToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token, _ := unsignedToken.SignedString([]byte(jwtCfg.SecretKey))
+
+	return token
+}
+
+func attachJWTMiddleware(hfc echo.HandlerFunc) echo.HandlerFunc {
+	mhfc := middleware.JWTWithConfig(
+		middleware.JWTConfig{
+			Claims:     &middlewares.JwtCustomClaims{},
+			SigningKey: []byte(config.Get().Jwt.SecretKey),
+		})(hfc)
+
+	return mhfc
+}
+
+func TestFetchEvents(t *testing.T) {
+	mockUsecase := new(mocks.AuditUsecase)
+	mockUsecase.On("Fetch", mock.Anything, int32(1), model.AuditFilter{
+		Action: model.AuditLoginFailed, Since: "2022-01-31 03:00:00",
+	}, 2, 1).Return([]model.AuditEvent{{ID: 5, Action: model.AuditLoginFailed}}, 3, nil).Once()
+
+	handler := auditHttp.AuditHandler{
+		AUseCase: mockUsecase,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		endPoint := BaseURLV1 + "/me/audit?action=login.failed&since=2022-01-31T09:00:00%2B06:00&page=2&page_size=1"
+		ctx, res := buildEchoAuthorizedRequest(t, endPoint, getToken(1))
+
+		assert.NoError(t, attachJWTMiddleware(handler.FetchEvents)(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		var r response.Response
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+		assert.Equal(t, 3, *r.Count)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("every-action", func(t *testing.T) {
+		mockUsecase.On("Fetch", mock.Anything, int32(1), model.AuditFilter{Action: model.AuditAdminGranted}, 1, 1).
+			Return([]model.AuditEvent{}, 0, nil).Once()
+
+		ctx, res := buildEchoAuthorizedRequest(t, BaseURLV1+"/me/audit?action=admin.granted&page_size=1", getToken(1))
+
+		assert.NoError(t, attachJWTMiddleware(handler.FetchEvents)(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	for name, query := range map[string]string{
+		"invalid-action": "?action=note.created&page_size=1",
+		"invalid-since":  "?since=yesterday&page_size=1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, res := buildEchoAuthorizedRequest(t, BaseURLV1+"/me/audit"+query, getToken(1))
+
+			assert.NoError(t, attachJWTMiddleware(handler.FetchEvents)(ctx))
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+		})
+	}
+}
+
+func TestFetchAllEvents(t *testing.T) {
+	userID, actorID := int32(2), int32(3)
+
+	mockUsecase := new(mocks.AuditUsecase)
+	mockUsecase.On("FetchAll", mock.Anything, int32(1), model.AuditFilter{
+		UserID: &userID, ActorID: &actorID, Until: "2022-02-01 00:00:00",
+	}, 1, 10).Return([]model.AuditEvent{}, 0, nil).Once()
+	mockUsecase.On("FetchAll", mock.Anything, int32(4), model.AuditFilter{}, 1, 10).
+		Return(nil, 0, response.WrapError(errors.New("forbidden"), http.StatusForbidden)).Once()
+
+	handler := auditHttp.AuditHandler{
+		AUseCase: mockUsecase,
+	}
+
+	endPoint := BaseURLV1 + "/admin/audit?user_id=2&actor_id=3&until=2022-02-01T00:00:00Z&page_size=10"
+	ctx, res := buildEchoAuthorizedRequest(t, endPoint, getToken(1))
+
+	assert.NoError(t, attachJWTMiddleware(handler.FetchAllEvents)(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	ctx, res = buildEchoAuthorizedRequest(t, BaseURLV1+"/admin/audit?page_size=10", getToken(4))
+
+	assert.NoError(t, attachJWTMiddleware(handler.FetchAllEvents)(ctx))
+	assert.Equal(t, http.StatusForbidden, res.Code)
+	mockUsecase.AssertExpectations(t)
+}
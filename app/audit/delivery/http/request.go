@@ -0,0 +1,58 @@
+package http
+
+import (
+	"errors"
+	"librenote/app/model"
+	"librenote/infrastructure/config"
+	"time"
+)
+
+type fetchEventsReq struct {
+	Action string `query:"action" validate:"omitempty,auditaction"`
+	// RFC 3339 times
+	Since    string `query:"since"`
+	Until    string `query:"until"`
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	PageSize int    `query:"page_size" validate:"omitempty,min=1"`
+	// only read by the admins endpoint
+	UserID  int32 `query:"user_id" validate:"omitempty,min=1"`
+	ActorID int32 `query:"actor_id" validate:"omitempty,min=1"`
+}
+
+// filter converts the times to UTC and sets the default page
+func (r *fetchEventsReq) filter() (model.AuditFilter, error) {
+	filter := model.AuditFilter{Action: r.Action}
+
+	for _, t := range []struct {
+		name  string
+		value string
+		field *string
+	}{{"since", r.Since, &filter.Since}, {"until", r.Until, &filter.Until}} {
+		if t.value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, t.value)
+		if err != nil {
+			return filter, errors.New(t.name + " must be a RFC 3339 time like 2022-01-31T09:00:00+06:00")
+		}
+
+		*t.field = parsed.UTC().Format("2006-01-02 15:04:05")
+	}
+
+	cfg := config.Get().App
+
+	if r.Page == 0 {
+		r.Page = 1
+	}
+
+	if r.PageSize == 0 {
+		r.PageSize = cfg.DefaultPageSize
+	}
+
+	if cfg.MaxPageSize > 0 && r.PageSize > cfg.MaxPageSize {
+		r.PageSize = cfg.MaxPageSize
+	}
+
+	return filter, nil
+}
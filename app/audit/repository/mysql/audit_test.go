@@ -0,0 +1,79 @@
+package mysql_test
+
+import (
+	"context"
+	auditRepo "librenote/app/audit/repository/mysql"
+	"librenote/app/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateEvent(t *testing.T) {
+	userID := int32(1)
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	e := &model.AuditEvent{
+		UserID: &userID, ActorID: &userID, Action: model.AuditLogin, IP: "10.0.0.1", UserAgent: "curl/7.81.0",
+		Details: map[string]interface{}{"email": "mrtest@example.com"}, CreatedAt: nowTime,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO audit_events").WithArgs(e.UserID, e.ActorID, e.Action, e.IP, e.UserAgent,
+		`{"email":"mrtest@example.com"}`, e.CreatedAt).WillReturnResult(sqlmock.NewResult(7, 1))
+
+	ar := auditRepo.NewMysqlAuditRepository(db)
+	assert.NoError(t, ar.CreateEvent(context.TODO(), e))
+	assert.Equal(t, int32(7), e.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	columns := []string{"id", "user_id", "actor_id", "action", "ip", "user_agent", "details", "created_at"}
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM audit_events WHERE user_id = \\? AND action = \\? AND "+
+		"created_at >= \\?").WithArgs(int32(1), model.AuditLoginFailed, "2022-01-31 03:00:00").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(6))
+	mock.ExpectQuery("SELECT (.+) FROM audit_events WHERE user_id = \\? AND action = \\? AND created_at >= \\? "+
+		"ORDER BY created_at DESC, id DESC LIMIT \\? OFFSET \\?").
+		WithArgs(int32(1), model.AuditLoginFailed, "2022-01-31 03:00:00", 5, 5).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(5, 1, nil, model.AuditLoginFailed, "10.0.0.1", "curl/7.81.0", `{"reason":"wrong password"}`, nowTime))
+
+	// no filter
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM audit_events$").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("SELECT (.+) FROM audit_events ORDER BY").WithArgs(5, 0).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	userID := int32(1)
+	ar := auditRepo.NewMysqlAuditRepository(db)
+
+	events, count, err := ar.FetchEvents(context.TODO(), model.AuditFilter{
+		UserID: &userID, Action: model.AuditLoginFailed, Since: "2022-01-31 03:00:00",
+	}, 5, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, count)
+	assert.Len(t, events, 1)
+	assert.Nil(t, events[0].ActorID)
+	assert.Equal(t, "wrong password", events[0].Details["reason"])
+
+	events, count, err = ar.FetchEvents(context.TODO(), model.AuditFilter{}, 5, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Empty(t, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,140 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"librenote/app/model"
+	"strings"
+)
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewPgsqlAuditRepository(db *sql.DB) model.AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+const createEvent = `INSERT INTO audit_events (
+  user_id, actor_id, action, ip, user_agent, details, created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING id
+`
+
+func (r *auditRepository) CreateEvent(ctx context.Context, event *model.AuditEvent) error {
+	details, err := json.Marshal(event.Details)
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRowContext(ctx, createEvent,
+		event.UserID,
+		event.ActorID,
+		event.Action,
+		event.IP,
+		event.UserAgent,
+		string(details),
+		event.CreatedAt,
+	).Scan(&event.ID)
+}
+
+const countEvents = `SELECT COUNT(*) FROM audit_events`
+
+const fetchEvents = `SELECT id, user_id, actor_id, action, ip, user_agent, details, created_at::text
+FROM audit_events`
+
+const eventsOrder = ` ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`
+
+// eventsFilter returns the where clause of the filter and its arguments
+func eventsFilter(filter model.AuditFilter) (string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+
+	if filter.ActorID != nil {
+		args = append(args, *filter.ActorID)
+		conditions = append(conditions, fmt.Sprintf("actor_id = $%d", len(args)))
+	}
+
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+	}
+
+	if filter.Since != "" {
+		args = append(args, filter.Since)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	if filter.Until != "" {
+		args = append(args, filter.Until)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *auditRepository) FetchEvents(ctx context.Context, filter model.AuditFilter, limit, offset int) (
+	[]model.AuditEvent, int, error) {
+	var count int
+
+	where, args := eventsFilter(filter)
+
+	if err := r.db.QueryRowContext(ctx, countEvents+where, args...).Scan(&count); err != nil {
+		return nil, 0, err
+	}
+
+	order := fmt.Sprintf(eventsOrder, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, fetchEvents+where+order, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	events := make([]model.AuditEvent, 0)
+
+	for rows.Next() {
+		var (
+			i       model.AuditEvent
+			details string
+		)
+
+		if err = rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ActorID,
+			&i.Action,
+			&i.IP,
+			&i.UserAgent,
+			&details,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+
+		if err = json.Unmarshal([]byte(details), &i.Details); err != nil {
+			return nil, 0, err
+		}
+
+		events = append(events, i)
+	}
+
+	return events, count, rows.Err()
+}
@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"librenote/app/model"
+	"strings"
+)
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteAuditRepository(db *sql.DB) model.AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+const createEvent = `INSERT INTO audit_events (
+  user_id, actor_id, action, ip, user_agent, details, created_at
+) VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+func (r *auditRepository) CreateEvent(ctx context.Context, event *model.AuditEvent) error {
+	details, err := json.Marshal(event.Details)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db.ExecContext(ctx, createEvent,
+		event.UserID,
+		event.ActorID,
+		event.Action,
+		event.IP,
+		event.UserAgent,
+		string(details),
+		event.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	event.ID = int32(id)
+
+	return nil
+}
+
+const countEvents = `SELECT COUNT(*) FROM audit_events`
+
+const fetchEvents = `SELECT id, user_id, actor_id, action, ip, user_agent, details, created_at FROM audit_events`
+
+const eventsOrder = ` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+
+// eventsFilter returns the where clause of the filter and its arguments
+func eventsFilter(filter model.AuditFilter) (string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	if filter.UserID != nil {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, *filter.UserID)
+	}
+
+	if filter.ActorID != nil {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, *filter.ActorID)
+	}
+
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+
+	if filter.Since != "" {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since)
+	}
+
+	if filter.Until != "" {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.Until)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *auditRepository) FetchEvents(ctx context.Context, filter model.AuditFilter, limit, offset int) (
+	[]model.AuditEvent, int, error) {
+	var count int
+
+	where, args := eventsFilter(filter)
+
+	if err := r.db.QueryRowContext(ctx, countEvents+where, args...).Scan(&count); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, fetchEvents+where+eventsOrder, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	events := make([]model.AuditEvent, 0)
+
+	for rows.Next() {
+		var (
+			i       model.AuditEvent
+			details string
+		)
+
+		if err = rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ActorID,
+			&i.Action,
+			&i.IP,
+			&i.UserAgent,
+			&details,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+
+		if err = json.Unmarshal([]byte(details), &i.Details); err != nil {
+			return nil, 0, err
+		}
+
+		events = append(events, i)
+	}
+
+	return events, count, rows.Err()
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/infrastructure/logger"
+	"net/http"
+	"time"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// maxUserAgent is the length of the user agents kept, the clients send what they like
+const maxUserAgent = 500
+
+type auditUsecase struct {
+	repo           model.AuditRepository
+	userRepo       model.UserRepository
+	contextTimeout time.Duration
+}
+
+// NewAuditUsecase lets the admins read the events of all the accounts, the admin command grants it
+func NewAuditUsecase(repo model.AuditRepository, userRepo model.UserRepository,
+	timeout time.Duration) model.AuditUsecase {
+	return &auditUsecase{
+		repo:           repo,
+		userRepo:       userRepo,
+		contextTimeout: timeout,
+	}
+}
+
+// Record stores the event with the client of the request, the failures are only logged
+func (u *auditUsecase) Record(c context.Context, event *model.AuditEvent) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	client := model.ClientFrom(ctx)
+	if event.IP == "" {
+		event.IP = client.IP
+	}
+
+	if event.UserAgent == "" {
+		event.UserAgent = client.UserAgent
+	}
+
+	if len(event.UserAgent) > maxUserAgent {
+		event.UserAgent = event.UserAgent[:maxUserAgent]
+	}
+
+	if event.Details == nil {
+		event.Details = map[string]interface{}{}
+	}
+
+	event.CreatedAt = time.Now().UTC().Format(timeLayout)
+
+	if err := u.repo.CreateEvent(ctx, event); err != nil {
+		logger.FromContext(ctx).Errorf("failed to record the audit event %s: %s", event.Action, err)
+	}
+}
+
+func (u *auditUsecase) Fetch(c context.Context, userID int32, filter model.AuditFilter, page, pageSize int) (
+	[]model.AuditEvent, int, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if page < 1 || pageSize < 1 {
+		return nil, 0, response.ErrInvalidPage
+	}
+
+	filter.UserID = &userID
+
+	return u.repo.FetchEvents(ctx, filter, pageSize, (page-1)*pageSize)
+}
+
+// FetchAll is recorded as well, reading the events of the other accounts is an admin action
+func (u *auditUsecase) FetchAll(c context.Context, adminID int32, filter model.AuditFilter, page, pageSize int) (
+	[]model.AuditEvent, int, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if page < 1 || pageSize < 1 {
+		return nil, 0, response.ErrInvalidPage
+	}
+
+	admin, err := u.userRepo.GetUser(ctx, adminID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	isAdmin, err := u.userRepo.IsAdmin(ctx, adminID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if admin.IsActive == 0 || admin.IsTrashed == 1 || !isAdmin {
+		return nil, 0, response.WrapError(errors.New("only the admins can read the audit log"), http.StatusForbidden)
+	}
+
+	events, count, err := u.repo.FetchEvents(ctx, filter, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	details := map[string]interface{}{"page": page}
+	if filter.UserID != nil {
+		details["user_id"] = *filter.UserID
+	}
+
+	if filter.ActorID != nil {
+		details["actor_id"] = *filter.ActorID
+	}
+
+	if filter.Action != "" {
+		details["action"] = filter.Action
+	}
+
+	u.Record(ctx, &model.AuditEvent{ActorID: &adminID, Action: model.AuditAdminAuditViewed, Details: details})
+
+	return events, count, nil
+}
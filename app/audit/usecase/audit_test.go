@@ -0,0 +1,117 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"librenote/app/audit/usecase"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecord(t *testing.T) {
+	userID := int32(1)
+
+	t.Run("from-request", func(t *testing.T) {
+		mockRepo := new(mocks.AuditRepository)
+		mockRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(e *model.AuditEvent) bool {
+			_, err := time.Parse("2006-01-02 15:04:05", e.CreatedAt)
+
+			return e.IP == "10.0.0.1" && len(e.UserAgent) == 500 && e.Details != nil && err == nil
+		})).Return(nil).Once()
+
+		u := usecase.NewAuditUsecase(mockRepo, new(mocks.UserRepository), time.Second*2)
+		ctx := model.WithClient(context.TODO(), model.Client{IP: "10.0.0.1", UserAgent: strings.Repeat("a", 600)})
+		u.Record(ctx, &model.AuditEvent{
+			UserID: &userID, ActorID: &userID, Action: model.AuditLogin,
+		})
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("from-command", func(t *testing.T) {
+		mockRepo := new(mocks.AuditRepository)
+		mockRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(e *model.AuditEvent) bool {
+			return e.IP == "" && e.UserAgent == "librenote export" && e.ActorID == nil
+		})).Return(errors.New("database is locked")).Once()
+
+		// the failure is only logged
+		u := usecase.NewAuditUsecase(mockRepo, new(mocks.UserRepository), time.Second*2)
+		u.Record(context.TODO(), &model.AuditEvent{
+			UserID: &userID, Action: model.AuditAdminExport, UserAgent: "librenote export",
+		})
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestFetch(t *testing.T) {
+	mockRepo := new(mocks.AuditRepository)
+	mockRepo.On("FetchEvents", mock.Anything, mock.MatchedBy(func(f model.AuditFilter) bool {
+		return f.UserID != nil && *f.UserID == 1 && f.Action == model.AuditLogin
+	}), 10, 10).Return([]model.AuditEvent{{ID: 3}}, 11, nil).Once()
+
+	u := usecase.NewAuditUsecase(mockRepo, new(mocks.UserRepository), time.Second*2)
+
+	// the user can't read the events of another account
+	other := int32(2)
+	events, count, err := u.Fetch(context.TODO(), 1, model.AuditFilter{UserID: &other, Action: model.AuditLogin}, 2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 11, count)
+	assert.Len(t, events, 1)
+
+	_, _, err = u.Fetch(context.TODO(), 1, model.AuditFilter{}, 0, 10)
+	assert.ErrorIs(t, err, response.ErrInvalidPage)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFetchAll(t *testing.T) {
+	filter := model.AuditFilter{Action: model.AuditLoginFailed}
+
+	t.Run("admin", func(t *testing.T) {
+		mockRepo := new(mocks.AuditRepository)
+		mockRepo.On("FetchEvents", mock.Anything, filter, 10, 0).Return([]model.AuditEvent{{ID: 3}}, 1, nil).Once()
+		mockRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(e *model.AuditEvent) bool {
+			return e.Action == model.AuditAdminAuditViewed && *e.ActorID == 1 && e.UserID == nil &&
+				e.Details["action"] == model.AuditLoginFailed
+		})).Return(nil).Once()
+
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetUser", mock.Anything, int32(1)).
+			Return(model.User{ID: 1, Email: "admin@example.com", IsActive: 1}, nil).Once()
+		mockUserRepo.On("IsAdmin", mock.Anything, int32(1)).Return(true, nil).Once()
+
+		u := usecase.NewAuditUsecase(mockRepo, mockUserRepo, time.Second*2)
+		events, count, err := u.FetchAll(context.TODO(), 1, filter, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Len(t, events, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("not-admin", func(t *testing.T) {
+		mockRepo := new(mocks.AuditRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetUser", mock.Anything, int32(2)).
+			Return(model.User{ID: 2, Email: "admin@example.com", IsActive: 1}, nil).Once()
+		mockUserRepo.On("IsAdmin", mock.Anything, int32(2)).Return(false, nil).Once()
+		mockUserRepo.On("GetUser", mock.Anything, int32(1)).
+			Return(model.User{ID: 1, Email: "admin@example.com", IsActive: 1, IsTrashed: 1}, nil).Once()
+		mockUserRepo.On("IsAdmin", mock.Anything, int32(1)).Return(true, nil).Once()
+
+		u := usecase.NewAuditUsecase(mockRepo, mockUserRepo, time.Second*2)
+
+		for _, id := range []int32{2, 1} {
+			_, _, err := u.FetchAll(context.TODO(), id, filter, 1, 10)
+			code, _ := response.RespondError(err)
+			assert.Equal(t, http.StatusForbidden, code)
+		}
+
+		mockRepo.AssertNotCalled(t, "FetchEvents", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
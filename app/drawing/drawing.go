@@ -0,0 +1,303 @@
+package drawing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"librenote/app/model"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	// MaxCanvasSize bounds both sides of the canvas and of a rendered image
+	MaxCanvasSize = 4096
+	// MaxStrokeWidth is in canvas units
+	MaxStrokeWidth = 100
+	// MaxStrokes bounds the strokes of a drawing
+	MaxStrokes = 1000
+	// MaxPoints bounds the points of all the strokes of a drawing
+	MaxPoints = 10000
+	// MaxInk bounds the area swept by all the segments, counted without their overlaps, as the rendering time
+	// follows it
+	MaxInk = 16 * MaxCanvasSize * MaxCanvasSize
+	// renderBudget bounds the time spent rendering a drawing, whatever the deadline of the caller
+	renderBudget = 5 * time.Second
+)
+
+// ErrTooComplex is returned when a drawing can't be rendered within the time budget
+var ErrTooComplex = errors.New("drawing is too complex to render")
+
+// ParseColor accepts #rrggbb and #rrggbbaa
+func ParseColor(s string) (color.NRGBA, error) {
+	if (len(s) != 7 && len(s) != 9) || s[0] != '#' {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q, expected #rrggbb or #rrggbbaa", s)
+	}
+
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q, expected #rrggbb or #rrggbbaa", s)
+	}
+
+	if len(s) == 7 {
+		v = v<<8 | 0xff
+	}
+
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// Validate checks the canvas, the colors, that every point lies on the canvas, and bounds the strokes, their points
+// and the area they cover
+func Validate(d *model.Drawing) error {
+	if d.Width < 1 || d.Height < 1 || d.Width > MaxCanvasSize || d.Height > MaxCanvasSize {
+		return fmt.Errorf("canvas must be between 1x1 and %dx%d", MaxCanvasSize, MaxCanvasSize)
+	}
+
+	if d.Background != "" {
+		if _, err := ParseColor(d.Background); err != nil {
+			return err
+		}
+	}
+
+	if len(d.Strokes) > MaxStrokes {
+		return fmt.Errorf("drawing can't have more than %d strokes", MaxStrokes)
+	}
+
+	points, ink := 0, 0.0
+
+	for i, stroke := range d.Strokes {
+		if _, err := ParseColor(stroke.Color); err != nil {
+			return fmt.Errorf("stroke %d: %w", i, err)
+		}
+
+		if stroke.Width <= 0 || stroke.Width > MaxStrokeWidth {
+			return fmt.Errorf("stroke %d: width must be greater than 0 and at most %d", i, MaxStrokeWidth)
+		}
+
+		if len(stroke.Points) < 2 || len(stroke.Points)%2 != 0 {
+			return fmt.Errorf("stroke %d: points must be x, y pairs", i)
+		}
+
+		if points += len(stroke.Points) / 2; points > MaxPoints {
+			return fmt.Errorf("drawing can't have more than %d points", MaxPoints)
+		}
+
+		for j := 0; j < len(stroke.Points); j += 2 {
+			x, y := stroke.Points[j], stroke.Points[j+1]
+			if x < 0 || y < 0 || x > float64(d.Width) || y > float64(d.Height) {
+				return fmt.Errorf("stroke %d: point (%g, %g) is outside of the canvas", i, x, y)
+			}
+
+			if j > 0 {
+				ink += (math.Hypot(x-stroke.Points[j-2], y-stroke.Points[j-1]) + stroke.Width) * stroke.Width
+			}
+		}
+
+		if ink > MaxInk {
+			return fmt.Errorf("drawing covers too much, its strokes can't sum up to more than %d canvases",
+				MaxInk/(MaxCanvasSize*MaxCanvasSize))
+		}
+	}
+
+	return nil
+}
+
+// Render rasterizes the drawing with anti-aliased round strokes, scaled so that its longest side is `size`
+// pixels, a size of 0 keeps the canvas size. It stops with the context, or with ErrTooComplex once past the time budget
+func Render(ctx context.Context, d *model.Drawing, size int) (*image.RGBA, error) {
+	if err := Validate(d); err != nil {
+		return nil, err
+	}
+
+	if size < 0 || size > MaxCanvasSize {
+		return nil, errors.New("invalid image size")
+	}
+
+	scale := 1.0
+	if size > 0 {
+		scale = float64(size) / math.Max(float64(d.Width), float64(d.Height))
+	}
+
+	w := int(math.Max(1, math.Round(float64(d.Width)*scale)))
+	h := int(math.Max(1, math.Round(float64(d.Height)*scale)))
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	background := color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	if d.Background != "" {
+		background, _ = ParseColor(d.Background)
+	}
+
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	deadline := time.Now().Add(renderBudget)
+	canceled := func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return ErrTooComplex
+		}
+
+		return nil
+	}
+
+	for i := range d.Strokes {
+		if err := renderStroke(dst, &d.Strokes[i], scale, canceled); err != nil {
+			return nil, err
+		}
+	}
+
+	return dst, nil
+}
+
+// renderStroke builds the coverage mask of the whole stroke first, so overlapping segments of a
+// translucent stroke don't darken each other. canceled is checked before every segment
+func renderStroke(dst *image.RGBA, stroke *model.Stroke, scale float64, canceled func() error) error {
+	c, _ := ParseColor(stroke.Color)
+	radius := math.Max(stroke.Width*scale/2, 0.5)
+
+	points := make([]float64, len(stroke.Points))
+	for i, p := range stroke.Points {
+		points[i] = p * scale
+	}
+
+	minX, minY, maxX, maxY := points[0], points[1], points[0], points[1]
+	for i := 2; i < len(points); i += 2 {
+		minX, maxX = math.Min(minX, points[i]), math.Max(maxX, points[i])
+		minY, maxY = math.Min(minY, points[i+1]), math.Max(maxY, points[i+1])
+	}
+
+	bounds := image.Rect(
+		int(math.Floor(minX-radius-1)), int(math.Floor(minY-radius-1)),
+		int(math.Ceil(maxX+radius+1)), int(math.Ceil(maxY+radius+1)),
+	).Intersect(dst.Bounds())
+	if bounds.Empty() {
+		return nil
+	}
+
+	mask := image.NewAlpha(bounds)
+
+	// a single point is drawn as a dot
+	if len(points) == 2 {
+		points = append(points, points[0], points[1])
+	}
+
+	for i := 2; i < len(points); i += 2 {
+		if err := canceled(); err != nil {
+			return err
+		}
+
+		coverSegment(mask, points[i-2], points[i-1], points[i], points[i+1], radius)
+	}
+
+	draw.DrawMask(dst, bounds, image.NewUniform(c), image.Point{}, mask, bounds.Min, draw.Over)
+
+	return nil
+}
+
+// coverSegment marks the pixels within radius of the segment, the edge is smoothed over one pixel. Only the span of
+// every row crossing the segment is visited, not its whole bounding box
+func coverSegment(mask *image.Alpha, x0, y0, x1, y1, radius float64) {
+	// pixels further than reach get no coverage, the ones closer than inner are fully covered
+	reach := radius + 0.5
+	inner := math.Max(0, radius-0.5) * math.Max(0, radius-0.5)
+
+	rows := image.Rect(
+		mask.Rect.Min.X, int(math.Floor(math.Min(y0, y1)-reach)),
+		mask.Rect.Max.X, int(math.Ceil(math.Max(y0, y1)+reach)),
+	).Intersect(mask.Rect)
+
+	dx, dy := x1-x0, y1-y0
+	length := dx*dx + dy*dy
+
+	for y := rows.Min.Y; y < rows.Max.Y; y++ {
+		py := float64(y) + 0.5
+
+		lo, hi, ok := rowSpan(py, x0, y0, x1, y1, reach)
+		if !ok {
+			continue
+		}
+
+		minX := int(math.Max(float64(rows.Min.X), math.Ceil(lo-0.5)))
+		maxX := int(math.Min(float64(rows.Max.X-1), math.Floor(hi-0.5)))
+
+		offset := mask.PixOffset(minX, y)
+
+		for x := minX; x <= maxX; x, offset = x+1, offset+1 {
+			// distance from the pixel center to the closest point of the segment
+			px := float64(x) + 0.5
+
+			t := 0.0
+			if length > 0 {
+				t = math.Max(0, math.Min(1, ((px-x0)*dx+(py-y0)*dy)/length))
+			}
+
+			ex, ey := px-(x0+t*dx), py-(y0+t*dy)
+
+			a := uint8(0xff)
+			if squared := ex*ex + ey*ey; squared > inner {
+				a = uint8(math.Max(0, math.Min(1, reach-math.Sqrt(squared))) * 0xff)
+			}
+
+			if a > mask.Pix[offset] {
+				mask.Pix[offset] = a
+			}
+		}
+	}
+}
+
+// rowSpan returns the x interval of the row py within reach of the segment. The capsule around the segment is the
+// union of the discs at both ends and of the band along it, all convex, so their spans on the row make one interval
+func rowSpan(py, x0, y0, x1, y1, reach float64) (lo, hi float64, ok bool) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+
+	include := func(a, b float64) {
+		if a <= b {
+			lo, hi = math.Min(lo, a), math.Max(hi, b)
+		}
+	}
+
+	for _, end := range [][2]float64{{x0, y0}, {x1, y1}} {
+		if d := py - end[1]; math.Abs(d) <= reach {
+			half := math.Sqrt(reach*reach - d*d)
+			include(end[0]-half, end[0]+half)
+		}
+	}
+
+	dx, dy := x1-x0, y1-y0
+	if length := math.Hypot(dx, dy); length > 0 {
+		// the band is 0 <= projection <= length² and |cross| <= reach * length, both linear in x on the row
+		a, b, ok := solveBetween(dx, (py-y0)*dy-x0*dx, 0, length*length)
+		if ok {
+			c, d, ok := solveBetween(dy, -x0*dy-(py-y0)*dx, -reach*length, reach*length)
+			if ok {
+				include(math.Max(a, c), math.Min(b, d))
+			}
+		}
+	}
+
+	return lo, hi, lo <= hi
+}
+
+// solveBetween returns the x interval where min <= k*x + m <= max
+func solveBetween(k, m, min, max float64) (lo, hi float64, ok bool) {
+	if k == 0 {
+		if m < min || m > max {
+			return 0, 0, false
+		}
+
+		return math.Inf(-1), math.Inf(1), true
+	}
+
+	lo, hi = (min-m)/k, (max-m)/k
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+
+	return lo, hi, true
+}
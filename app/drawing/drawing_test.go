@@ -0,0 +1,96 @@
+package drawing_test
+
+import (
+	"context"
+	"image/color"
+	"librenote/app/drawing"
+	"librenote/app/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColor(t *testing.T) {
+	c, err := drawing.ParseColor("#ff8000")
+	assert.NoError(t, err)
+	assert.Equal(t, color.NRGBA{R: 0xff, G: 0x80, B: 0x00, A: 0xff}, c)
+
+	c, err = drawing.ParseColor("#00000080")
+	assert.NoError(t, err)
+	assert.Equal(t, color.NRGBA{A: 0x80}, c)
+
+	for _, s := range []string{"", "red", "#fff", "#gggggg", "ff8000"} {
+		_, err = drawing.ParseColor(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *model.Drawing {
+		return &model.Drawing{Width: 100, Height: 50, Strokes: []model.Stroke{
+			{Color: "#000000", Width: 2, Points: []float64{0, 0, 100, 50}},
+		}}
+	}
+
+	assert.NoError(t, drawing.Validate(valid()))
+
+	for name, change := range map[string]func(d *model.Drawing){
+		"empty-canvas":   func(d *model.Drawing) { d.Width = 0 },
+		"huge-canvas":    func(d *model.Drawing) { d.Height = drawing.MaxCanvasSize + 1 },
+		"bad-background": func(d *model.Drawing) { d.Background = "white" },
+		"bad-color":      func(d *model.Drawing) { d.Strokes[0].Color = "black" },
+		"zero-width":     func(d *model.Drawing) { d.Strokes[0].Width = 0 },
+		"odd-points":     func(d *model.Drawing) { d.Strokes[0].Points = []float64{1, 2, 3} },
+		"outside":        func(d *model.Drawing) { d.Strokes[0].Points = []float64{101, 2} },
+		"many-strokes": func(d *model.Drawing) {
+			d.Strokes = make([]model.Stroke, drawing.MaxStrokes+1)
+			for i := range d.Strokes {
+				d.Strokes[i] = model.Stroke{Color: "#000000", Width: 1, Points: []float64{1, 1}}
+			}
+		},
+		"many-points": func(d *model.Drawing) {
+			d.Strokes[0].Points = make([]float64, 2*drawing.MaxPoints+2)
+		},
+		"too-much-ink": func(d *model.Drawing) {
+			d.Width, d.Height = drawing.MaxCanvasSize, drawing.MaxCanvasSize
+			d.Strokes[0].Width = drawing.MaxStrokeWidth
+			for i := 0; i < 400; i++ {
+				d.Strokes[0].Points = append(d.Strokes[0].Points, drawing.MaxCanvasSize, drawing.MaxCanvasSize, 0, 0)
+			}
+		},
+	} {
+		d := valid()
+		change(d)
+		assert.Error(t, drawing.Validate(d), name)
+	}
+}
+
+func TestRender(t *testing.T) {
+	d := &model.Drawing{Width: 200, Height: 100, Background: "#ffffff", Strokes: []model.Stroke{
+		{Color: "#ff0000", Width: 10, Points: []float64{20, 50, 180, 50}},
+		{Color: "#0000ff", Width: 4, Points: []float64{100, 10}},
+	}}
+
+	img, err := drawing.Render(context.Background(), d, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, img.Bounds().Dx())
+	assert.Equal(t, 100, img.Bounds().Dy())
+	assert.Equal(t, color.RGBA{R: 0xff, A: 0xff}, img.RGBAAt(100, 50))
+	assert.Equal(t, color.RGBA{B: 0xff, A: 0xff}, img.RGBAAt(100, 10))
+	assert.Equal(t, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, img.RGBAAt(100, 80))
+
+	img, err = drawing.Render(context.Background(), d, 50)
+	assert.NoError(t, err)
+	assert.Equal(t, 50, img.Bounds().Dx())
+	assert.Equal(t, 25, img.Bounds().Dy())
+	assert.Equal(t, color.RGBA{R: 0xff, A: 0xff}, img.RGBAAt(25, 12))
+
+	_, err = drawing.Render(context.Background(), d, drawing.MaxCanvasSize+1)
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = drawing.Render(ctx, d, 0)
+	assert.ErrorIs(t, err, context.Canceled)
+}
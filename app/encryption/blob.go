@@ -0,0 +1,275 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"librenote/app/model"
+	"os"
+)
+
+// blobMagic starts the encrypted blobs, the blobs without it are stored in clear
+var blobMagic = []byte("\x00lnenc1\x00") // nolint:gochecknoglobals
+
+// the content is encrypted in chunks, so it can be read from any offset
+const chunkSize = 64 * 1024
+
+// the tag of the chunks
+const tagSize = 16
+
+// BlobStore encrypts the blobs of another store. A blob is the magic, the header holding its wrapped data key and
+// the encrypted chunks of its content
+type BlobStore struct {
+	store   model.BlobStore
+	keyring *Keyring
+}
+
+func NewBlobStore(store model.BlobStore, keyring *Keyring) *BlobStore {
+	return &BlobStore{
+		store:   store,
+		keyring: keyring,
+	}
+}
+
+func chunks(size int64) int64 {
+	if size == 0 {
+		return 1
+	}
+
+	return (size + chunkSize - 1) / chunkSize
+}
+
+// encryptedSize is the size of the stored blob of a content of the given size
+func encryptedSize(size int64) int64 {
+	return int64(len(blobMagic)+headerSize) + size + chunks(size)*tagSize
+}
+
+// chunkNonce is the index of the chunk, its last byte flags the last chunk so a truncated blob doesn't decrypt.
+// Every blob has its own data key, so the nonces never repeat
+func chunkNonce(index int64, last bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[3:11], uint64(index))
+
+	if last {
+		nonce[11] = 1
+	}
+
+	return nonce
+}
+
+func (s *BlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	aead, header, err := s.keyring.newDataKey()
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(encryptChunks(pw, aead, header, r, size))
+	}()
+
+	err = s.store.Put(ctx, key, pr, encryptedSize(size), contentType)
+	_ = pr.CloseWithError(errors.New("blob upload ended"))
+
+	return err
+}
+
+func encryptChunks(w io.Writer, aead cipher.AEAD, header []byte, r io.Reader, size int64) error {
+	if _, err := w.Write(append(append([]byte{}, blobMagic...), header...)); err != nil {
+		return err
+	}
+
+	n := chunks(size)
+	buf := make([]byte, chunkSize)
+
+	for i := int64(0); i < n; i++ {
+		plain := buf[:min64(chunkSize, size-i*chunkSize)]
+		if _, err := io.ReadFull(r, plain); err != nil {
+			return fmt.Errorf("blob size mismatch, expected %d bytes: %w", size, err)
+		}
+
+		if _, err := w.Write(aead.Seal(nil, chunkNonce(i, i == n-1), plain, nil)); err != nil {
+			return err
+		}
+	}
+
+	// the content must not be longer than its size either
+	if m, _ := r.Read(buf[:1]); m != 0 {
+		return fmt.Errorf("blob size mismatch, expected %d bytes", size)
+	}
+
+	return nil
+}
+
+// Open reads the blobs stored in clear as they are, like the ones stored before the encryption was enabled
+func (s *BlobStore) Open(ctx context.Context, key string, size int64) (io.ReadSeekCloser, error) {
+	rc, header, err := s.openHeader(ctx, key, size)
+	if err != nil || header == nil {
+		return rc, err
+	}
+
+	aead, err := s.keyring.openDataKey(header)
+	if err != nil {
+		_ = rc.Close()
+
+		return nil, err
+	}
+
+	return &blobReader{rc: rc, aead: aead, size: size, chunks: chunks(size), chunk: -1}, nil
+}
+
+// openHeader returns the blob and its header, the header is nil when the blob is stored in clear
+func (s *BlobStore) openHeader(ctx context.Context, key string, size int64) (io.ReadSeekCloser, []byte, error) {
+	rc, err := s.store.Open(ctx, key, encryptedSize(size))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	head := make([]byte, len(blobMagic)+headerSize)
+
+	_, err = io.ReadFull(rc, head)
+	if err == nil && bytes.Equal(head[:len(blobMagic)], blobMagic) {
+		return rc, head[len(blobMagic):], nil
+	}
+
+	_ = rc.Close()
+
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, nil, err
+	}
+
+	rc, err = s.store.Open(ctx, key, size)
+
+	return rc, nil, err
+}
+
+func (s *BlobStore) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, key)
+}
+
+// Reencrypt encrypts a blob by the primary key, unless it already is. The content is decrypted to a temporary file
+// first, as the blob is replaced while writing it
+func (s *BlobStore) Reencrypt(ctx context.Context, key string, size int64) (bool, error) {
+	rc, header, err := s.openHeader(ctx, key, size)
+	if err != nil {
+		return false, err
+	}
+
+	_ = rc.Close()
+
+	if header != nil && s.keyring.isPrimary(header) {
+		return false, nil
+	}
+
+	content, err := s.Open(ctx, key, size)
+	if err != nil {
+		return false, err
+	}
+
+	defer content.Close()
+
+	tmp, err := os.CreateTemp("", "librenote-blob-*")
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = io.Copy(tmp, content); err != nil {
+		return false, err
+	}
+
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	return true, s.Put(ctx, key, tmp, size, "application/octet-stream")
+}
+
+// blobReader decrypts the chunk holding the offset on reading
+type blobReader struct {
+	rc     io.ReadSeekCloser
+	aead   cipher.AEAD
+	size   int64
+	chunks int64
+	offset int64
+	// index of the decrypted chunk of plain
+	chunk int64
+	plain []byte
+}
+
+func (r *blobReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	index := r.offset / chunkSize
+	if index != r.chunk {
+		if err := r.readChunk(index); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.plain[r.offset-index*chunkSize:])
+	r.offset += int64(n)
+
+	return n, nil
+}
+
+func (r *blobReader) readChunk(index int64) error {
+	start := int64(len(blobMagic)+headerSize) + index*(chunkSize+tagSize)
+	if _, err := r.rc.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+
+	sealed := make([]byte, min64(chunkSize, r.size-index*chunkSize)+tagSize)
+	if _, err := io.ReadFull(r.rc, sealed); err != nil {
+		return err
+	}
+
+	plain, err := r.aead.Open(sealed[:0], chunkNonce(index, index == r.chunks-1), sealed, nil)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt the blob: %w", err)
+	}
+
+	r.chunk, r.plain = index, plain
+
+	return nil
+}
+
+func (r *blobReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	}
+
+	if offset < 0 {
+		return 0, errors.New("negative offset")
+	}
+
+	r.offset = offset
+
+	return offset, nil
+}
+
+func (r *blobReader) Close() error {
+	return r.rc.Close()
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,133 @@
+package encryption_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"librenote/app/encryption"
+	"librenote/infrastructure/storage"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobStore(t *testing.T) {
+	root := t.TempDir()
+	oldKey, newKey := newKey(t), newKey(t)
+
+	oldKeyring, err := encryption.NewKeyring(oldKey)
+	assert.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(oldKey, newKey)
+	assert.NoError(t, err)
+
+	local := storage.NewLocalStore(root)
+	oldBlobs := encryption.NewBlobStore(local, oldKeyring)
+	blobs := encryption.NewBlobStore(local, keyring)
+
+	// larger than a chunk
+	content := bytes.Repeat([]byte("0123456789abcdef"), 10000)
+	size := int64(len(content))
+
+	t.Run("round-trip", func(t *testing.T) {
+		assert.NoError(t, blobs.Put(context.TODO(), "a/1", bytes.NewReader(content), size, "text/plain"))
+
+		stored, err := os.ReadFile(filepath.Join(root, "a", "1"))
+		assert.NoError(t, err)
+		assert.NotContains(t, string(stored), "0123456789abcdef")
+
+		r, err := blobs.Open(context.TODO(), "a/1", size)
+		assert.NoError(t, err)
+
+		defer r.Close()
+
+		read, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, content, read)
+
+		// reading from an offset of the second chunk, as the range requests do
+		end, err := r.Seek(0, io.SeekEnd)
+		assert.NoError(t, err)
+		assert.Equal(t, size, end)
+
+		_, err = r.Seek(70000, io.SeekStart)
+		assert.NoError(t, err)
+
+		part := make([]byte, 16)
+		_, err = io.ReadFull(r, part)
+		assert.NoError(t, err)
+		assert.Equal(t, content[70000:70016], part)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.NoError(t, blobs.Put(context.TODO(), "a/empty", strings.NewReader(""), 0, "text/plain"))
+
+		r, err := blobs.Open(context.TODO(), "a/empty", 0)
+		assert.NoError(t, err)
+
+		read, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Empty(t, read)
+		assert.NoError(t, r.Close())
+	})
+
+	t.Run("size-mismatch", func(t *testing.T) {
+		err := blobs.Put(context.TODO(), "a/2", bytes.NewReader(content), size+1, "text/plain")
+		assert.Error(t, err)
+
+		err = blobs.Put(context.TODO(), "a/2", bytes.NewReader(content), size-1, "text/plain")
+		assert.Error(t, err)
+
+		_, err = os.Stat(filepath.Join(root, "a", "2"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("in-clear", func(t *testing.T) {
+		assert.NoError(t, local.Put(context.TODO(), "a/3", strings.NewReader("hello"), 5, "text/plain"))
+
+		r, err := blobs.Open(context.TODO(), "a/3", 5)
+		assert.NoError(t, err)
+
+		read, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(read))
+		assert.NoError(t, r.Close())
+	})
+
+	t.Run("reencrypt", func(t *testing.T) {
+		assert.NoError(t, oldBlobs.Put(context.TODO(), "a/4", bytes.NewReader(content), size, "text/plain"))
+
+		rotated, err := blobs.Reencrypt(context.TODO(), "a/4", size)
+		assert.NoError(t, err)
+		assert.True(t, rotated)
+
+		rotated, err = blobs.Reencrypt(context.TODO(), "a/4", size)
+		assert.NoError(t, err)
+		assert.False(t, rotated)
+
+		// the previous keyring can't read it anymore
+		_, err = oldBlobs.Open(context.TODO(), "a/4", size)
+		assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+
+		r, err := blobs.Open(context.TODO(), "a/4", size)
+		assert.NoError(t, err)
+
+		read, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, content, read)
+		assert.NoError(t, r.Close())
+
+		// the blobs stored in clear are encrypted as well
+		rotated, err = blobs.Reencrypt(context.TODO(), "a/3", 5)
+		assert.NoError(t, err)
+		assert.True(t, rotated)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := blobs.Open(context.TODO(), "a/none", 5)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
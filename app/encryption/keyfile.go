@@ -0,0 +1,106 @@
+package encryption
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"librenote/infrastructure/config"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Load returns the keyring of the config, nil when the encryption at rest is disabled
+func Load(cfg config.EncryptionConfig) (*Keyring, error) {
+	if cfg.Key != "" {
+		key, err := ParseKey(cfg.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewKeyring(key)
+	}
+
+	if cfg.KeyFile == "" {
+		return nil, nil // nolint:nilnil
+	}
+
+	keys, err := ReadKeyFile(cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyring(keys...)
+}
+
+// ReadKeyFile reads the base64 encoded master keys of a key file, one per line. The empty lines and the ones
+// starting with # are skipped
+func ReadKeyFile(path string) ([][]byte, error) {
+	f, err := os.Open(path) // nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var keys [][]byte
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, err := ParseKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", path, n, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no master key in %s", path)
+	}
+
+	return keys, nil
+}
+
+// AppendKeyFile adds a key to a key file as its primary key, the file is created when missing. The file is replaced
+// at once, so a failure never leaves it truncated
+func AppendKeyFile(path string, key []byte) error {
+	content, err := os.ReadFile(path) // nolint:gosec
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		content = append(content, '\n')
+	}
+
+	content = append(content, base64.StdEncoding.EncodeToString(key)+"\n"...)
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".keys-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,280 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KeySize of the master keys, AES-256
+const KeySize = 32
+
+// the length of the key ids, the first bytes of the sha256 of the key
+const keyIDSize = 4
+
+// valuePrefix marks the encrypted values of the database, the values without it are stored in clear
+const valuePrefix = "$lnenc1$"
+
+// hashPrefix marks the keyed hashes of the database
+const hashPrefix = "$lnmac1$"
+
+// hashKeyInfo derives the hash key of a master key, the master key itself only wraps the data keys
+const hashKeyInfo = "librenote hash key"
+
+// a wrapped data key is its nonce, the data key encrypted by the master key and the tag
+const wrappedKeySize = 12 + KeySize + 16
+
+// header of the encrypted values, the id of the master key and the wrapped data key
+const headerSize = keyIDSize + wrappedKeySize
+
+// ErrUnknownKey is returned on decrypting data encrypted by a master key missing from the keyring
+var ErrUnknownKey = errors.New("the data is encrypted by an unknown key")
+
+// Keyring holds the master keys, the primary one encrypts and all of them decrypt. The data is encrypted by a
+// random data key, which is stored along with the data wrapped by the master key. This is envelope encryption
+type Keyring struct {
+	keys     map[string]cipher.AEAD
+	hashKeys map[string][]byte
+	primary  string
+}
+
+// NewKeyring the last key is the primary one
+func NewKeyring(keys ...[]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no master key")
+	}
+
+	k := &Keyring{
+		keys:     make(map[string]cipher.AEAD, len(keys)),
+		hashKeys: make(map[string][]byte, len(keys)),
+	}
+
+	for _, key := range keys {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("a master key must be %d bytes long, not %d", KeySize, len(key))
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+
+		k.primary = keyID(key)
+		k.keys[k.primary] = aead
+		k.hashKeys[k.primary] = mac([]byte(hashKeyInfo), key)
+	}
+
+	return k, nil
+}
+
+// GenerateKey returns a new random master key
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// ParseKey decodes a base64 encoded master key
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("a master key must be %d bytes long, not %d", KeySize, len(key))
+	}
+
+	return key, nil
+}
+
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+
+	return hex.EncodeToString(sum[:keyIDSize])
+}
+
+func mac(data, key []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+
+	return h.Sum(nil)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// newDataKey returns a random data key and the header holding it wrapped by the primary key
+func (k *Keyring) newDataKey() (cipher.AEAD, []byte, error) {
+	dataKey, err := GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, 12)
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, err
+	}
+
+	id, _ := hex.DecodeString(k.primary)
+	header := make([]byte, 0, headerSize)
+	header = append(header, id...)
+	header = append(header, nonce...)
+	header = k.keys[k.primary].Seal(header, nonce, dataKey, id)
+
+	aead, err := newAEAD(dataKey)
+
+	return aead, header, err
+}
+
+// openDataKey unwraps the data key of a header
+func (k *Keyring) openDataKey(header []byte) (cipher.AEAD, error) {
+	if len(header) < headerSize {
+		return nil, errors.New("the encrypted data is truncated")
+	}
+
+	id := header[:keyIDSize]
+
+	master, ok := k.keys[hex.EncodeToString(id)]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	nonce := header[keyIDSize : keyIDSize+12]
+
+	dataKey, err := master.Open(nil, nonce, header[keyIDSize+12:headerSize], id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap the data key: %w", err)
+	}
+
+	return newAEAD(dataKey)
+}
+
+// isPrimary tells whether a header wraps its data key by the primary key
+func (k *Keyring) isPrimary(header []byte) bool {
+	return len(header) >= keyIDSize && hex.EncodeToString(header[:keyIDSize]) == k.primary
+}
+
+// Encrypt returns the header followed by the nonce and the ciphertext
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	aead, header, err := k.newDataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := append(header, nonce...)
+
+	return aead.Seal(out, nonce, plaintext, nil), nil
+}
+
+func (k *Keyring) Decrypt(data []byte) ([]byte, error) {
+	aead, err := k.openDataKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[headerSize:]
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("the encrypted data is truncated")
+	}
+
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+}
+
+// EncryptString encrypts a value of the database, the result is base64 encoded
+func (k *Keyring) EncryptString(s string) (string, error) {
+	data, err := k.Encrypt([]byte(s))
+	if err != nil {
+		return "", err
+	}
+
+	return valuePrefix + base64.RawStdEncoding.EncodeToString(data), nil
+}
+
+// DecryptString returns the values stored in clear as they are, like the ones stored before the encryption was
+// enabled
+func (k *Keyring) DecryptString(s string) (string, error) {
+	if !strings.HasPrefix(s, valuePrefix) {
+		return s, nil
+	}
+
+	data, err := base64.RawStdEncoding.DecodeString(s[len(valuePrefix):])
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+
+	plaintext, err := k.Decrypt(data)
+
+	return string(plaintext), err
+}
+
+// IsCurrent tells whether a value of the database is encrypted by the primary key, so rotating the keys can skip it
+func (k *Keyring) IsCurrent(s string) bool {
+	// 8 base64 characters hold the 4 bytes of the key id
+	if !strings.HasPrefix(s, valuePrefix) || len(s) < len(valuePrefix)+8 {
+		return false
+	}
+
+	header, err := base64.RawStdEncoding.DecodeString(s[len(valuePrefix) : len(valuePrefix)+8])
+
+	return err == nil && k.isPrimary(header)
+}
+
+// Hash returns the keyed hash of a value by the primary key, equal values have equal hashes so the encrypted values
+// can be looked up by them
+func (k *Keyring) Hash(s string) string {
+	return k.hash(k.primary, s)
+}
+
+// Hashes returns the keyed hashes of a value by all the keys, the primary one first. The values hashed by other keys
+// are found by them until the rotation hashes them again
+func (k *Keyring) Hashes(s string) []string {
+	hashes := []string{k.Hash(s)}
+
+	for id := range k.hashKeys {
+		if id != k.primary {
+			hashes = append(hashes, k.hash(id, s))
+		}
+	}
+
+	return hashes
+}
+
+func (k *Keyring) hash(id, s string) string {
+	b, _ := hex.DecodeString(id)
+
+	return hashPrefix + base64.RawStdEncoding.EncodeToString(append(b, mac([]byte(s), k.hashKeys[id])...))
+}
+
+// IsCurrentHash tells whether a hash of the database is made by the primary key
+func (k *Keyring) IsCurrentHash(s string) bool {
+	if !strings.HasPrefix(s, hashPrefix) || len(s) < len(hashPrefix)+8 {
+		return false
+	}
+
+	id, err := base64.RawStdEncoding.DecodeString(s[len(hashPrefix) : len(hashPrefix)+8])
+
+	return err == nil && hex.EncodeToString(id[:keyIDSize]) == k.primary
+}
@@ -0,0 +1,131 @@
+package encryption_test
+
+import (
+	"librenote/app/encryption"
+	"librenote/infrastructure/config"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newKey(t *testing.T) []byte {
+	key, err := encryption.GenerateKey()
+	assert.NoError(t, err)
+
+	return key
+}
+
+func TestEncryptString(t *testing.T) {
+	oldKey, newKey := newKey(t), newKey(t)
+
+	oldKeyring, err := encryption.NewKeyring(oldKey)
+	assert.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(oldKey, newKey)
+	assert.NoError(t, err)
+
+	value, err := keyring.EncryptString("Groceries")
+	assert.NoError(t, err)
+	assert.NotContains(t, value, "Groceries")
+	assert.True(t, keyring.IsCurrent(value))
+
+	other, err := keyring.EncryptString("Groceries")
+	assert.NoError(t, err)
+	assert.NotEqual(t, value, other)
+
+	plaintext, err := keyring.DecryptString(value)
+	assert.NoError(t, err)
+	assert.Equal(t, "Groceries", plaintext)
+
+	// the values encrypted by a previous key stay readable
+	old, err := oldKeyring.EncryptString("milk")
+	assert.NoError(t, err)
+	assert.False(t, keyring.IsCurrent(old))
+
+	plaintext, err = keyring.DecryptString(old)
+	assert.NoError(t, err)
+	assert.Equal(t, "milk", plaintext)
+
+	_, err = oldKeyring.DecryptString(value)
+	assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+
+	// the values stored in clear are kept as they are
+	plaintext, err = keyring.DecryptString("eggs")
+	assert.NoError(t, err)
+	assert.Equal(t, "eggs", plaintext)
+	assert.False(t, keyring.IsCurrent("eggs"))
+
+	tampered := []byte(value)
+	if tampered[len(tampered)-5] == 'A' {
+		tampered[len(tampered)-5] = 'B'
+	} else {
+		tampered[len(tampered)-5] = 'A'
+	}
+
+	_, err = keyring.DecryptString(string(tampered))
+	assert.Error(t, err)
+}
+
+func TestHash(t *testing.T) {
+	oldKey, newKey := newKey(t), newKey(t)
+
+	oldKeyring, err := encryption.NewKeyring(oldKey)
+	assert.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(oldKey, newKey)
+	assert.NoError(t, err)
+
+	hash := keyring.Hash("groceries")
+	assert.NotContains(t, hash, "groceries")
+	assert.Equal(t, hash, keyring.Hash("groceries"))
+	assert.NotEqual(t, hash, keyring.Hash("recipes"))
+	assert.LessOrEqual(t, len(hash), 64)
+	assert.True(t, keyring.IsCurrentHash(hash))
+
+	// the hashes by a previous key are still looked up, until the rotation hashes the values again
+	old := oldKeyring.Hash("groceries")
+	assert.NotEqual(t, hash, old)
+	assert.False(t, keyring.IsCurrentHash(old))
+	assert.Equal(t, []string{hash, old}, keyring.Hashes("groceries"))
+	assert.Equal(t, []string{old}, oldKeyring.Hashes("groceries"))
+
+	assert.False(t, keyring.IsCurrentHash("groceries"))
+}
+
+func TestNewKeyring(t *testing.T) {
+	_, err := encryption.NewKeyring()
+	assert.Error(t, err)
+
+	_, err = encryption.NewKeyring([]byte("short"))
+	assert.Error(t, err)
+
+	_, err = encryption.ParseKey("bm90IDMyIGJ5dGVz")
+	assert.Error(t, err)
+}
+
+func TestKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys")
+	first, second := newKey(t), newKey(t)
+
+	_, err := encryption.ReadKeyFile(path)
+	assert.Error(t, err)
+
+	assert.NoError(t, encryption.AppendKeyFile(path, first))
+	assert.NoError(t, encryption.AppendKeyFile(path, second))
+
+	keys, err := encryption.ReadKeyFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{first, second}, keys)
+
+	keyring, err := encryption.NewKeyring(second)
+	assert.NoError(t, err)
+
+	value, err := keyring.EncryptString("Groceries")
+	assert.NoError(t, err)
+
+	// the last key of the file is the primary one
+	keyring, err = encryption.Load(config.EncryptionConfig{KeyFile: path})
+	assert.NoError(t, err)
+	assert.True(t, keyring.IsCurrent(value))
+}
@@ -0,0 +1,172 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"librenote/app/model"
+	"strings"
+)
+
+type keyRotationRepository struct {
+	db *sql.DB
+}
+
+func NewPgsqlKeyRotationRepository(db *sql.DB) model.KeyRotationRepository {
+	return &keyRotationRepository{
+		db: db,
+	}
+}
+
+// splitColumn accepts the columns of model.EncryptedColumns and model.EncryptedItemColumns only, as their names are
+// part of the queries
+func splitColumn(column string) (string, string, error) {
+	for _, columns := range [][]string{model.EncryptedColumns, model.EncryptedItemColumns} {
+		for _, c := range columns {
+			if c == column {
+				parts := strings.SplitN(column, ".", 2)
+
+				return parts[0], parts[1], nil
+			}
+		}
+	}
+
+	return "", "", fmt.Errorf("%s isn't an encrypted column", column)
+}
+
+const fetchValues = `SELECT id, %s FROM %s WHERE id > $1 ORDER BY id LIMIT $2`
+
+func (r *keyRotationRepository) FetchValues(ctx context.Context, column string, afterID int32, limit int) (
+	[]model.EncryptedValue, error) {
+	table, name, err := splitColumn(column)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(fetchValues, name, table), afterID, limit) // nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	values := make([]model.EncryptedValue, 0)
+
+	for rows.Next() {
+		var v model.EncryptedValue
+		if err = rows.Scan(&v.ID, &v.Value); err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values, rows.Err()
+}
+
+const updateValue = `UPDATE %s SET %s = $1 WHERE id = $2 AND %s = $3`
+
+func (r *keyRotationRepository) UpdateValue(ctx context.Context, column string, id int32, old, value string) (
+	bool, error) {
+	table, name, err := splitColumn(column)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := r.db.ExecContext(ctx, fmt.Sprintf(updateValue, table, name, name), value, id, old) // nolint:gosec
+	if err != nil {
+		return false, err
+	}
+
+	affect, err := res.RowsAffected()
+
+	return affect == 1, err
+}
+
+// splitHashedColumn accepts the columns of model.HashedColumns only
+func splitHashedColumn(column string) (string, string, error) {
+	for _, c := range model.HashedColumns {
+		if c == column {
+			return splitColumn(column)
+		}
+	}
+
+	return "", "", fmt.Errorf("%s isn't a hashed column", column)
+}
+
+const fetchHashes = `SELECT id, %s, %s_hash FROM %s WHERE id > $1 ORDER BY id LIMIT $2`
+
+func (r *keyRotationRepository) FetchHashes(ctx context.Context, column string, afterID int32, limit int) (
+	[]model.EncryptedValue, error) {
+	table, name, err := splitHashedColumn(column)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(fetchHashes, name, name, table), afterID, limit) // nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	values := make([]model.EncryptedValue, 0)
+
+	for rows.Next() {
+		var v model.EncryptedValue
+		if err = rows.Scan(&v.ID, &v.Value, &v.Hash); err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values, rows.Err()
+}
+
+const updateHash = `UPDATE %s SET %s_hash = $1 WHERE id = $2 AND %s = $3`
+
+func (r *keyRotationRepository) UpdateHash(ctx context.Context, column string, id int32, value, hash string) (
+	bool, error) {
+	table, name, err := splitHashedColumn(column)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := r.db.ExecContext(ctx, fmt.Sprintf(updateHash, table, name, name), hash, id, value) // nolint:gosec
+	if err != nil {
+		return false, err
+	}
+
+	affect, err := res.RowsAffected()
+
+	return affect == 1, err
+}
+
+const fetchAttachments = `SELECT id, note_id, user_id, blob_key, size, thumbnail_key, thumbnail_size
+FROM attachments WHERE id > $1 ORDER BY id LIMIT $2`
+
+func (r *keyRotationRepository) FetchAttachments(ctx context.Context, afterID int32, limit int) (
+	[]model.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, fetchAttachments, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	attachments := make([]model.Attachment, 0)
+
+	for rows.Next() {
+		var i model.Attachment
+		if err = rows.Scan(&i.ID, &i.NoteID, &i.UserID, &i.BlobKey, &i.Size, &i.ThumbnailKey,
+			&i.ThumbnailSize); err != nil {
+			return nil, err
+		}
+
+		i.HasThumbnail = i.ThumbnailKey != ""
+		attachments = append(attachments, i)
+	}
+
+	return attachments, rows.Err()
+}
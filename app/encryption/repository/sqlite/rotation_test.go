@@ -0,0 +1,132 @@
+package sqlite_test
+
+import (
+	"context"
+	rotationRepo "librenote/app/encryption/repository/sqlite"
+	"librenote/app/model"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchValues(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, title FROM notes WHERE id > \\? ORDER BY id LIMIT \\?").WithArgs(int32(4), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(5, "Groceries").AddRow(6, nil))
+
+	r := rotationRepo.NewSqliteKeyRotationRepository(db)
+
+	values, err := r.FetchValues(context.TODO(), "notes.title", 4, 2)
+	assert.NoError(t, err)
+	assert.Len(t, values, 2)
+	assert.Equal(t, "Groceries", *values[0].Value)
+	assert.Nil(t, values[1].Value)
+
+	_, err = r.FetchValues(context.TODO(), "users.hash", 0, 2)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateValue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE notes_items SET text = \\? WHERE id = \\? AND text = \\?").WithArgs("new", int32(5), "old").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE notes_items").WithArgs("new", int32(6), "old").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	r := rotationRepo.NewSqliteKeyRotationRepository(db)
+
+	updated, err := r.UpdateValue(context.TODO(), "notes_items.text", 5, "old", "new")
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	updated, err = r.UpdateValue(context.TODO(), "notes_items.text", 6, "old", "new")
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchHashes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, title, title_hash FROM notes_references WHERE id > \\? ORDER BY id LIMIT \\?").
+		WithArgs(int32(4), 2).WillReturnRows(sqlmock.NewRows([]string{"id", "title", "title_hash"}).
+		AddRow(5, "groceries", nil).AddRow(6, "$lnenc1$abc", "$lnmac1$def"))
+
+	r := rotationRepo.NewSqliteKeyRotationRepository(db)
+
+	values, err := r.FetchHashes(context.TODO(), "notes_references.title", 4, 2)
+	assert.NoError(t, err)
+	assert.Len(t, values, 2)
+	assert.Equal(t, "groceries", *values[0].Value)
+	assert.Nil(t, values[0].Hash)
+	assert.Equal(t, "$lnmac1$def", *values[1].Hash)
+
+	_, err = r.FetchHashes(context.TODO(), "notes.title", 0, 2)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE notes_references SET title_hash = \\? WHERE id = \\? AND title = \\?").
+		WithArgs("hash", int32(5), "title").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE notes_references").WithArgs("hash", int32(6), "title").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	r := rotationRepo.NewSqliteKeyRotationRepository(db)
+
+	updated, err := r.UpdateHash(context.TODO(), "notes_references.title", 5, "title", "hash")
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	updated, err = r.UpdateHash(context.TODO(), "notes_references.title", 6, "title", "hash")
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchAttachments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM attachments WHERE id >").WithArgs(int32(0), 10).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "note_id", "user_id", "blob_key", "size", "thumbnail_key", "thumbnail_size"}).
+			AddRow(1, 3, 1, "attachments/1/abc", 11, "", 0).
+			AddRow(2, 3, 1, "attachments/1/def", 2048, "attachments/1/def-thumb", 512))
+
+	r := rotationRepo.NewSqliteKeyRotationRepository(db)
+
+	attachments, err := r.FetchAttachments(context.TODO(), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Attachment{
+		{ID: 1, NoteID: 3, UserID: 1, BlobKey: "attachments/1/abc", Size: 11},
+		{ID: 2, NoteID: 3, UserID: 1, BlobKey: "attachments/1/def", Size: 2048, ThumbnailKey: "attachments/1/def-thumb",
+			ThumbnailSize: 512, HasThumbnail: true},
+	}, attachments)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"librenote/app/encryption"
+	"librenote/app/model"
+	"time"
+)
+
+type keyRotationUsecase struct {
+	repo           model.KeyRotationRepository
+	keyring        *encryption.Keyring
+	blobs          *encryption.BlobStore
+	contextTimeout time.Duration
+}
+
+// NewKeyRotationUsecase the keyring holds the new primary key along with the keys the data is encrypted by
+func NewKeyRotationUsecase(repo model.KeyRotationRepository, keyring *encryption.Keyring,
+	blobs *encryption.BlobStore, timeout time.Duration) model.KeyRotationUsecase {
+	return &keyRotationUsecase{
+		repo:           repo,
+		keyring:        keyring,
+		blobs:          blobs,
+		contextTimeout: timeout,
+	}
+}
+
+func (u *keyRotationUsecase) Rotate(c context.Context, batchSize int) (*model.KeyRotationReport, error) {
+	report := &model.KeyRotationReport{
+		Values: make(map[string]int, len(model.EncryptedColumns)+len(model.EncryptedItemColumns)),
+		Hashes: make(map[string]int, len(model.HashedColumns)),
+	}
+
+	for _, column := range model.EncryptedColumns {
+		n, err := u.rotateColumn(c, column, batchSize, u.reencrypt)
+		report.Values[column] = n
+
+		if err != nil {
+			return report, err
+		}
+	}
+
+	for _, column := range model.EncryptedItemColumns {
+		n, err := u.rotateColumn(c, column, batchSize, u.reencryptItems)
+		report.Values[column] = n
+
+		if err != nil {
+			return report, err
+		}
+	}
+
+	// the values are encrypted by the primary key already, the ones stored in clear included
+	for _, column := range model.HashedColumns {
+		n, err := u.rotateHashes(c, column, batchSize)
+		report.Hashes[column] = n
+
+		if err != nil {
+			return report, err
+		}
+	}
+
+	var err error
+	report.Blobs, err = u.rotateBlobs(c, batchSize)
+
+	return report, err
+}
+
+// reencryptFunc re-encrypts a value by the primary key, it tells whether the value needed it
+type reencryptFunc func(value string) (string, bool, error)
+
+func (u *keyRotationUsecase) rotateColumn(c context.Context, column string, batchSize int,
+	reencrypt reencryptFunc) (int, error) {
+	var afterID int32
+
+	count := 0
+
+	for {
+		n, lastID, err := u.rotateValues(c, column, afterID, batchSize, reencrypt)
+		count += n
+
+		if err != nil || lastID == afterID {
+			return count, err
+		}
+
+		afterID = lastID
+	}
+}
+
+// rotateValues re-encrypts a batch of values, it returns the id of the last row of the batch
+func (u *keyRotationUsecase) rotateValues(c context.Context, column string, afterID int32, batchSize int,
+	reencrypt reencryptFunc) (int, int32, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	values, err := u.repo.FetchValues(ctx, column, afterID, batchSize)
+	if err != nil {
+		return 0, afterID, err
+	}
+
+	count := 0
+
+	for _, v := range values {
+		afterID = v.ID
+
+		if v.Value == nil {
+			continue
+		}
+
+		value, ok, err := reencrypt(*v.Value)
+		if err != nil {
+			return count, afterID, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		// a value changed meanwhile is encrypted by the server already
+		updated, err := u.repo.UpdateValue(ctx, column, v.ID, *v.Value, value)
+		if err != nil {
+			return count, afterID, err
+		}
+
+		if updated {
+			count++
+		}
+	}
+
+	return count, afterID, nil
+}
+
+func (u *keyRotationUsecase) rotateHashes(c context.Context, column string, batchSize int) (int, error) {
+	var afterID int32
+
+	count := 0
+
+	for {
+		n, lastID, err := u.rehashValues(c, column, afterID, batchSize)
+		count += n
+
+		if err != nil || lastID == afterID {
+			return count, err
+		}
+
+		afterID = lastID
+	}
+}
+
+// rehashValues hashes a batch of values again by the primary key, it returns the id of the last row of the batch
+func (u *keyRotationUsecase) rehashValues(c context.Context, column string, afterID int32, batchSize int) (int,
+	int32, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	values, err := u.repo.FetchHashes(ctx, column, afterID, batchSize)
+	if err != nil {
+		return 0, afterID, err
+	}
+
+	count := 0
+
+	for _, v := range values {
+		afterID = v.ID
+
+		if v.Value == nil || (v.Hash != nil && u.keyring.IsCurrentHash(*v.Hash)) {
+			continue
+		}
+
+		plaintext, err := u.keyring.DecryptString(*v.Value)
+		if err != nil {
+			return count, afterID, err
+		}
+
+		// a value changed meanwhile is hashed by the server already
+		updated, err := u.repo.UpdateHash(ctx, column, v.ID, *v.Value, u.keyring.Hash(plaintext))
+		if err != nil {
+			return count, afterID, err
+		}
+
+		if updated {
+			count++
+		}
+	}
+
+	return count, afterID, nil
+}
+
+func (u *keyRotationUsecase) reencrypt(value string) (string, bool, error) {
+	if u.keyring.IsCurrent(value) {
+		return value, false, nil
+	}
+
+	plaintext, err := u.keyring.DecryptString(value)
+	if err != nil {
+		return "", false, err
+	}
+
+	value, err = u.keyring.EncryptString(plaintext)
+
+	return value, err == nil, err
+}
+
+// reencryptItems re-encrypts the texts of json encoded items, the value needs it when any of the texts does
+func (u *keyRotationUsecase) reencryptItems(value string) (string, bool, error) {
+	var items []model.SnapshotItem
+	if err := json.Unmarshal([]byte(value), &items); err != nil {
+		return "", false, err
+	}
+
+	rotated := false
+
+	for i := range items {
+		text, ok, err := u.reencrypt(items[i].Text)
+		if err != nil {
+			return "", false, err
+		}
+
+		items[i].Text = text
+		rotated = rotated || ok
+	}
+
+	if !rotated {
+		return value, false, nil
+	}
+
+	b, err := json.Marshal(items)
+
+	return string(b), err == nil, err
+}
+
+// rotateBlobs isn't bound to the usecase timeout, the blobs may be large
+func (u *keyRotationUsecase) rotateBlobs(c context.Context, batchSize int) (int, error) {
+	var afterID int32
+
+	count := 0
+
+	for {
+		ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+		attachments, err := u.repo.FetchAttachments(ctx, afterID, batchSize)
+
+		cancel()
+
+		if err != nil || len(attachments) == 0 {
+			return count, err
+		}
+
+		for i := range attachments {
+			afterID = attachments[i].ID
+
+			n, err := u.rotateAttachment(c, &attachments[i])
+			count += n
+
+			if err != nil {
+				return count, err
+			}
+		}
+	}
+}
+
+func (u *keyRotationUsecase) rotateAttachment(c context.Context, attachment *model.Attachment) (int, error) {
+	count := 0
+
+	rotated, err := u.blobs.Reencrypt(c, attachment.BlobKey, attachment.Size)
+	if err != nil {
+		return count, err
+	}
+
+	if rotated {
+		count++
+	}
+
+	if attachment.ThumbnailKey == "" {
+		return count, nil
+	}
+
+	if rotated, err = u.blobs.Reencrypt(c, attachment.ThumbnailKey, attachment.ThumbnailSize); rotated {
+		count++
+	}
+
+	return count, err
+}
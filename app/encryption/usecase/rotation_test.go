@@ -0,0 +1,139 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"librenote/app/encryption"
+	"librenote/app/encryption/usecase"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/infrastructure/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestRotate(t *testing.T) {
+	oldKey, err := encryption.GenerateKey()
+	assert.NoError(t, err)
+
+	newKey, err := encryption.GenerateKey()
+	assert.NoError(t, err)
+
+	oldKeyring, _ := encryption.NewKeyring(oldKey)
+	keyring, _ := encryption.NewKeyring(oldKey, newKey)
+
+	oldTitle, _ := oldKeyring.EncryptString("Groceries")
+	newTitle, _ := keyring.EncryptString("Books")
+
+	local := storage.NewLocalStore(t.TempDir())
+	assert.NoError(t, local.Put(context.TODO(), "attachments/1/abc", strings.NewReader("hello"), 5, "text/plain"))
+	assert.NoError(t, encryption.NewBlobStore(local, keyring).Put(context.TODO(), "attachments/1/def",
+		strings.NewReader("world"), 5, "text/plain"))
+
+	mockRepo := new(mocks.KeyRotationRepository)
+	mockRepo.On("FetchValues", mock.Anything, "notes.title", int32(0), 2).Return([]model.EncryptedValue{
+		{ID: 1, Value: &oldTitle}, {ID: 2, Value: nil},
+	}, nil).Once()
+	mockRepo.On("FetchValues", mock.Anything, "notes.title", int32(2), 2).Return([]model.EncryptedValue{
+		{ID: 3, Value: &newTitle},
+	}, nil).Once()
+	mockRepo.On("FetchValues", mock.Anything, "notes.title", int32(3), 2).Return([]model.EncryptedValue{}, nil).Once()
+	mockRepo.On("FetchValues", mock.Anything, "notes.body", int32(0), 2).Return([]model.EncryptedValue{
+		{ID: 1, Value: strPtr("stored in clear")}, {ID: 2, Value: strPtr("changed meanwhile")},
+	}, nil).Once()
+	mockRepo.On("FetchValues", mock.Anything, "notes.body", int32(2), 2).Return([]model.EncryptedValue{}, nil).Once()
+	oldItems := `[{"text":"` + oldTitle + `","is_checked":0},{"text":"` + newTitle + `","is_checked":1}]`
+	newItems := `[{"text":"` + newTitle + `","is_checked":0}]`
+	mockRepo.On("FetchValues", mock.Anything, "templates.items", int32(0), 2).Return([]model.EncryptedValue{
+		{ID: 1, Value: &oldItems}, {ID: 2, Value: &newItems},
+	}, nil).Once()
+	mockRepo.On("FetchValues", mock.Anything, "templates.items", int32(2), 2).Return([]model.EncryptedValue{}, nil).
+		Once()
+	mockRepo.On("FetchValues", mock.Anything, mock.Anything, int32(0), 2).Return([]model.EncryptedValue{}, nil)
+
+	mockRepo.On("UpdateValue", mock.Anything, "notes.title", int32(1), oldTitle, mock.MatchedBy(func(v string) bool {
+		plaintext, err := keyring.DecryptString(v)
+
+		return err == nil && plaintext == "Groceries" && keyring.IsCurrent(v)
+	})).Return(true, nil).Once()
+	mockRepo.On("UpdateValue", mock.Anything, "notes.body", int32(1), "stored in clear", mock.Anything).
+		Return(true, nil).Once()
+	mockRepo.On("UpdateValue", mock.Anything, "notes.body", int32(2), "changed meanwhile", mock.Anything).
+		Return(false, nil).Once()
+	mockRepo.On("UpdateValue", mock.Anything, "templates.items", int32(1), oldItems,
+		mock.MatchedBy(func(v string) bool {
+			var items []model.SnapshotItem
+			if json.Unmarshal([]byte(v), &items) != nil || len(items) != 2 {
+				return false
+			}
+
+			plaintext, err := keyring.DecryptString(items[0].Text)
+
+			return err == nil && plaintext == "Groceries" && keyring.IsCurrent(items[0].Text) &&
+				items[1].Text == newTitle && items[1].IsChecked == 1
+		})).Return(true, nil).Once()
+
+	// the hashes by the previous key or missing are made again
+	oldRef, _ := oldKeyring.EncryptString("groceries")
+	newRef, _ := keyring.EncryptString("books")
+	mockRepo.On("FetchHashes", mock.Anything, "notes_references.title", int32(0), 2).Return([]model.EncryptedValue{
+		{ID: 1, Value: &oldRef, Hash: strPtr(oldKeyring.Hash("groceries"))},
+		{ID: 2, Value: &newRef, Hash: strPtr(keyring.Hash("books"))},
+	}, nil).Once()
+	mockRepo.On("FetchHashes", mock.Anything, "notes_references.title", int32(2), 2).Return([]model.EncryptedValue{
+		{ID: 3, Value: &newRef}, {ID: 4, Value: nil},
+	}, nil).Once()
+	mockRepo.On("FetchHashes", mock.Anything, "notes_references.title", int32(4), 2).
+		Return([]model.EncryptedValue{}, nil).Once()
+	mockRepo.On("UpdateHash", mock.Anything, "notes_references.title", int32(1), oldRef, keyring.Hash("groceries")).
+		Return(true, nil).Once()
+	mockRepo.On("UpdateHash", mock.Anything, "notes_references.title", int32(3), newRef, keyring.Hash("books")).
+		Return(true, nil).Once()
+
+	mockRepo.On("FetchAttachments", mock.Anything, int32(0), 2).Return([]model.Attachment{
+		{ID: 1, BlobKey: "attachments/1/abc", Size: 5}, {ID: 2, BlobKey: "attachments/1/def", Size: 5},
+	}, nil).Once()
+	mockRepo.On("FetchAttachments", mock.Anything, int32(2), 2).Return([]model.Attachment{}, nil).Once()
+
+	u := usecase.NewKeyRotationUsecase(mockRepo, keyring, encryption.NewBlobStore(local, keyring), time.Second*2)
+
+	report, err := u.Rotate(context.TODO(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"notes.title": 1, "notes.body": 1, "notes_items.text": 0, "notes_revisions.snapshot": 0,
+		"notes_references.title": 0, "templates.title": 0, "templates.body": 0, "templates.items": 1,
+		"webhooks_deliveries.payload": 0, "reminders_events.message": 0,
+	}, report.Values)
+	assert.Equal(t, map[string]int{"notes_references.title": 2}, report.Hashes)
+	assert.Equal(t, 1, report.Blobs)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRotateFailure(t *testing.T) {
+	key, _ := encryption.GenerateKey()
+	keyring, _ := encryption.NewKeyring(key)
+
+	mockRepo := new(mocks.KeyRotationRepository)
+	mockRepo.On("FetchValues", mock.Anything, "notes.title", int32(0), 10).Return([]model.EncryptedValue{
+		{ID: 1, Value: strPtr("Groceries")},
+	}, nil).Once()
+	mockRepo.On("UpdateValue", mock.Anything, "notes.title", int32(1), "Groceries", mock.Anything).
+		Return(false, errors.New("database is locked")).Once()
+
+	u := usecase.NewKeyRotationUsecase(mockRepo, keyring, encryption.NewBlobStore(storage.NewLocalStore(t.TempDir()),
+		keyring), time.Second*2)
+
+	report, err := u.Rotate(context.TODO(), 10)
+	assert.Error(t, err)
+	assert.Equal(t, 0, report.Values["notes.title"])
+	mockRepo.AssertNotCalled(t, "FetchAttachments", mock.Anything, mock.Anything, mock.Anything)
+}
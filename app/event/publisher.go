@@ -0,0 +1,23 @@
+package event
+
+import (
+	"context"
+	"librenote/app/model"
+)
+
+type publisher struct {
+	subscribers []model.EventPublisher
+}
+
+// NewPublisher passes every event on to all the subscribers, in order
+func NewPublisher(subscribers ...model.EventPublisher) model.EventPublisher {
+	return &publisher{
+		subscribers: subscribers,
+	}
+}
+
+func (p *publisher) Publish(c context.Context, userID int32, event string, data interface{}) {
+	for _, subscriber := range p.subscribers {
+		subscriber.Publish(c, userID, event, data)
+	}
+}
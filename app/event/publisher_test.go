@@ -0,0 +1,24 @@
+package event_test
+
+import (
+	"context"
+	"librenote/app/event"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPublish(t *testing.T) {
+	first, second := new(mocks.EventPublisher), new(mocks.EventPublisher)
+	data := map[string]int32{"id": 1}
+
+	first.On("Publish", mock.Anything, int32(1), model.EventNoteDeleted, data).Return().Once()
+	second.On("Publish", mock.Anything, int32(1), model.EventNoteDeleted, data).Return().Once()
+
+	event.NewPublisher(first, second).Publish(context.TODO(), 1, model.EventNoteDeleted, data)
+
+	first.AssertExpectations(t)
+	second.AssertExpectations(t)
+}
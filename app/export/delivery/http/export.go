@@ -0,0 +1,49 @@
+package http
+
+import (
+	"fmt"
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/infrastructure/logger"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ExportHandler represent the http handler for export
+type ExportHandler struct {
+	EUseCase model.ExportUsecase
+}
+
+func NewExportHandler(e *echo.Echo, us model.ExportUsecase) {
+	handler := &ExportHandler{
+		EUseCase: us,
+	}
+
+	export := e.Group("/api/v1/me/export")
+	_ = middlewares.AttachJwtToGroup(export)
+	export.GET("", handler.Export)
+}
+
+// Export streams a zip of all the data of the user, an error after the first byte can only abort the download
+func (h *ExportHandler) Export(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	export, err := h.EUseCase.Export(ctx, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "application/zip")
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="librenote-export-%s.zip"`, time.Now().UTC().Format("20060102")))
+	c.Response().WriteHeader(http.StatusOK)
+
+	if err = h.EUseCase.WriteZip(ctx, export, c.Response()); err != nil {
+		logger.FromContext(ctx).Errorf("export of user %d aborted: %s", middlewares.GetUserID(c), err)
+	}
+
+	return nil
+}
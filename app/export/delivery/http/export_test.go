@@ -0,0 +1,114 @@
+package http_test
+
+import (
+	"errors"
+	"io"
+	exportHttp "librenote/app/export/delivery/http"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var BaseURLV1 = "/api/v1"
+
+func buildEchoAuthorizedRequest(t *testing.T, method, path, token string, payload io.Reader) (
+	echo.Context, *httptest.ResponseRecorder) {
+	var req *http.Request
+
+	var err error
+
+	if payload != nil {
+		req, err = http.NewRequest(method, path, payload)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	} else {
+		req, err = http.NewRequest(method, path, nil)
+	}
+
+	assert.NoError(t, err)
+
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+	res := httptest.NewRecorder()
+	e := echo.New()
+	ctx := e.NewContext(req, res)
+
+	return ctx, res
+}
+
+// nolint:unparam
+func getToken(userID int32) string {
+	jwtCfg := config.Get().Jwt
+	claims := &middlewares.JwtCustomClaims{
+		UserID: userID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(jwtCfg.ExpireTime).Unix(),
+		},
+	}
+	unsignedToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token, _ := unsignedToken.SignedString([]byte(jwtCfg.SecretKey))
+
+	return token
+}
+
+func attachJWTMiddleware(hfc echo.HandlerFunc) echo.HandlerFunc {
+	mhfc := middleware.JWTWithConfig(
+		middleware.JWTConfig{
+			Claims:     &middlewares.JwtCustomClaims{},
+			SigningKey: []byte(config.Get().Jwt.SecretKey),
+		})(hfc)
+
+	return mhfc
+}
+
+func TestExport(t *testing.T) {
+	endPoint := BaseURLV1 + "/me/export"
+	export := &model.AccountExport{User: model.UserDetails{Email: "mrtest@example.com"}}
+
+	mockUsecase := new(mocks.ExportUsecase)
+	mockUsecase.On("Export", mock.Anything, int32(1)).Return(export, nil).Once()
+	mockUsecase.On("WriteZip", mock.Anything, export, mock.Anything).Run(func(args mock.Arguments) {
+		_, _ = args.Get(2).(io.Writer).Write([]byte("PK"))
+	}).Return(nil).Once()
+	mockUsecase.On("Export", mock.Anything, int32(2)).Return(nil, response.ErrNotFound).Once()
+	mockUsecase.On("Export", mock.Anything, int32(3)).Return(nil, errors.New("db down")).Once()
+
+	handler := exportHttp.ExportHandler{
+		EUseCase: mockUsecase,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+		handle := attachJWTMiddleware(handler.Export)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "application/zip", res.Header().Get(echo.HeaderContentType))
+		assert.True(t, strings.HasPrefix(res.Header().Get(echo.HeaderContentDisposition), "attachment;"))
+		assert.Equal(t, "PK", res.Body.String())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		for userID, code := range map[int32]int{2: http.StatusNotFound, 3: http.StatusInternalServerError} {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(userID), nil)
+			handle := attachJWTMiddleware(handler.Export)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, code, res.Code)
+		}
+	})
+
+	mockUsecase.AssertExpectations(t)
+}
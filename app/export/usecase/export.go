@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"librenote/app/model"
+	"librenote/app/response"
+	"time"
+)
+
+const (
+	timeLayout = "2006-01-02 15:04:05"
+	// notes are fetched by pages of this size
+	notesPageSize = 100
+)
+
+type exportUsecase struct {
+	userRepo       model.UserRepository
+	noteRepo       model.NoteRepository
+	labelRepo      model.LabelRepository
+	attachmentRepo model.AttachmentRepository
+	reminderRepo   model.ReminderRepository
+	webhookRepo    model.WebhookRepository
+	blobs          model.BlobStore
+	contextTimeout time.Duration
+}
+
+func NewExportUsecase(userRepo model.UserRepository, noteRepo model.NoteRepository, labelRepo model.LabelRepository,
+	attachmentRepo model.AttachmentRepository, reminderRepo model.ReminderRepository,
+	webhookRepo model.WebhookRepository, blobs model.BlobStore, timeout time.Duration) model.ExportUsecase {
+	return &exportUsecase{
+		userRepo:       userRepo,
+		noteRepo:       noteRepo,
+		labelRepo:      labelRepo,
+		attachmentRepo: attachmentRepo,
+		reminderRepo:   reminderRepo,
+		webhookRepo:    webhookRepo,
+		blobs:          blobs,
+		contextTimeout: timeout,
+	}
+}
+
+// Export the timeout applies to each step, as an account can have any number of notes
+func (u *exportUsecase) Export(c context.Context, userID int32) (*model.AccountExport, error) {
+	export, err := u.exportAccount(c, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	labelNames := make(map[int32]string, len(export.Labels))
+	for _, label := range export.Labels {
+		labelNames[label.ID] = label.Name
+	}
+
+	noteLabels, err := u.noteLabels(c, userID, labelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := u.fetchNotes(c, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, note := range notes {
+		exported, err := u.exportNote(c, note)
+		if err != nil {
+			return nil, err
+		}
+
+		exported.Labels = append(exported.Labels, noteLabels[note.ID]...)
+		export.Notes = append(export.Notes, *exported)
+	}
+
+	return export, nil
+}
+
+func (u *exportUsecase) exportAccount(c context.Context, userID int32) (*model.AccountExport, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	user, err := u.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, response.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	labels, err := u.labelRepo.FetchLabels(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := u.webhookRepo.FetchWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyEnvelope *model.KeyEnvelope
+
+	envelope, err := u.userRepo.GetKeyEnvelope(ctx, userID)
+	if err == nil {
+		keyEnvelope = &envelope
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	return &model.AccountExport{
+		ExportedAt: time.Now().UTC().Format(timeLayout),
+		User: model.UserDetails{
+			FullName:        user.FullName,
+			Email:           user.Email,
+			ListViewEnabled: user.ListViewEnabled,
+			DarkModeEnabled: user.DarkModeEnabled,
+		},
+		Labels:      labels,
+		Notes:       make([]model.ExportedNote, 0),
+		Webhooks:    webhooks,
+		KeyEnvelope: keyEnvelope,
+	}, nil
+}
+
+// noteLabels maps the notes to the names of their labels
+func (u *exportUsecase) noteLabels(c context.Context, userID int32, labelNames map[int32]string) (
+	map[int32][]string, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	notesLabels, err := u.labelRepo.FetchNotesLabels(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	noteLabels := make(map[int32][]string)
+	for _, nl := range notesLabels {
+		noteLabels[nl.NoteID] = append(noteLabels[nl.NoteID], labelNames[nl.LabelID])
+	}
+
+	return noteLabels, nil
+}
+
+// fetchNotes fetches the notes of every archived and trashed state
+func (u *exportUsecase) fetchNotes(c context.Context, userID int32) ([]model.Note, error) {
+	notes := make([]model.Note, 0)
+
+	for _, filter := range []model.NoteFilter{
+		{UserID: userID},
+		{UserID: userID, IsArchived: 1},
+		{UserID: userID, IsTrashed: 1},
+		{UserID: userID, IsArchived: 1, IsTrashed: 1},
+	} {
+		for offset := 0; ; offset += notesPageSize {
+			page, count, err := u.fetchNotesPage(c, filter, offset)
+			if err != nil {
+				return nil, err
+			}
+
+			notes = append(notes, page...)
+
+			if len(page) == 0 || offset+len(page) >= count {
+				break
+			}
+		}
+	}
+
+	return notes, nil
+}
+
+func (u *exportUsecase) fetchNotesPage(c context.Context, filter model.NoteFilter, offset int) (
+	[]model.Note, int, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.noteRepo.FetchNotes(ctx, filter, notesPageSize, offset)
+}
+
+func (u *exportUsecase) exportNote(c context.Context, note model.Note) (*model.ExportedNote, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	attachments, err := u.attachmentRepo.FetchAttachments(ctx, note.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminders, err := u.reminderRepo.FetchReminders(ctx, note.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := u.noteRepo.FetchRevisions(ctx, note.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range revisions {
+		var content model.NoteSnapshot
+		if err = json.Unmarshal([]byte(revisions[i].Snapshot), &content); err != nil {
+			return nil, err
+		}
+
+		revisions[i].Content = &content
+	}
+
+	return &model.ExportedNote{
+		Note:        note,
+		Labels:      make([]string, 0),
+		Attachments: attachments,
+		Reminders:   reminders,
+		Revisions:   revisions,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"librenote/app/export/usecase"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/infrastructure/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExport(t *testing.T) {
+	nowTime := "2022-01-31 09:00:00"
+	title, milk, eggs, text := "Groceries: this week", "milk", "eggs", "line one\nline two"
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockNoteRepo := new(mocks.NoteRepository)
+	mockLabelRepo := new(mocks.LabelRepository)
+	mockAttachmentRepo := new(mocks.AttachmentRepository)
+	mockReminderRepo := new(mocks.ReminderRepository)
+	mockWebhookRepo := new(mocks.WebhookRepository)
+	blobs := storage.NewLocalStore(t.TempDir())
+
+	assert.NoError(t, blobs.Put(context.TODO(), "attachments/1/abc", strings.NewReader("hello world"), 11,
+		"text/plain"))
+
+	mockUserRepo.On("GetUser", mock.Anything, int32(1)).
+		Return(model.User{ID: 1, FullName: "Mr. Test", Email: "mrtest@example.com", Hash: "secret"}, nil).Once()
+	mockUserRepo.On("GetKeyEnvelope", mock.Anything, int32(1)).
+		Return(model.KeyEnvelope{UserID: 1, WrappedKey: "d3JhcHBlZA==", KDF: "argon2id", KeyVersion: 1}, nil).Once()
+	mockLabelRepo.On("FetchLabels", mock.Anything, int32(1)).
+		Return([]model.Label{{ID: 2, Name: "Home", UserID: 1}}, nil).Once()
+	mockLabelRepo.On("FetchNotesLabels", mock.Anything, int32(1)).
+		Return([]model.NotesLabel{{NoteID: 3, LabelID: 2}}, nil).Once()
+	mockWebhookRepo.On("FetchWebhooks", mock.Anything, int32(1)).
+		Return([]model.Webhook{{ID: 1, UserID: 1, URL: "http://example.com", Secret: "s3cr3t"}}, nil).Once()
+
+	mockNoteRepo.On("FetchNotes", mock.Anything, model.NoteFilter{UserID: 1}, 100, 0).Return([]model.Note{
+		{
+			ID: 3, UserID: 1, Title: &title, Color: "red", Type: "list", IsPinned: 1, CreatedAt: nowTime,
+			UpdatedAt: nowTime,
+			Items:     []model.NotesItem{{Text: &milk, IsChecked: 1}, {Text: &eggs}},
+		},
+	}, 1, nil).Once()
+	mockNoteRepo.On("FetchNotes", mock.Anything, model.NoteFilter{UserID: 1, IsArchived: 1}, 100, 0).
+		Return([]model.Note{
+			{ID: 4, UserID: 1, Type: "note", IsArchived: 1, Items: []model.NotesItem{{Text: &text}}},
+		}, 1, nil).Once()
+	mockNoteRepo.On("FetchNotes", mock.Anything, mock.Anything, 100, 0).Return([]model.Note{}, 0, nil).Twice()
+
+	mockAttachmentRepo.On("FetchAttachments", mock.Anything, int32(3)).Return([]model.Attachment{
+		{ID: 5, NoteID: 3, UserID: 1, Name: "hello.txt", ContentType: "text/plain", Size: 11,
+			BlobKey: "attachments/1/abc"},
+	}, nil).Once()
+	mockAttachmentRepo.On("FetchAttachments", mock.Anything, int32(4)).Return([]model.Attachment{}, nil).Once()
+	mockReminderRepo.On("FetchReminders", mock.Anything, mock.Anything).Return([]model.Reminder{}, nil).Twice()
+	mockNoteRepo.On("FetchRevisions", mock.Anything, int32(3)).
+		Return([]model.NoteRevision{{ID: 1, NoteID: 3, Snapshot: `{"title": "Groceries"}`}}, nil).Once()
+	mockNoteRepo.On("FetchRevisions", mock.Anything, int32(4)).Return([]model.NoteRevision{}, nil).Once()
+
+	u := usecase.NewExportUsecase(mockUserRepo, mockNoteRepo, mockLabelRepo, mockAttachmentRepo, mockReminderRepo,
+		mockWebhookRepo, blobs, time.Second*2)
+
+	export, err := u.Export(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, export.Notes, 2)
+	assert.Equal(t, []string{"Home"}, export.Notes[0].Labels)
+	assert.Equal(t, "Groceries", *export.Notes[0].Revisions[0].Content.Title)
+
+	var buf bytes.Buffer
+	assert.NoError(t, u.WriteZip(context.TODO(), export, &buf))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	files := make(map[string]string)
+
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+
+		content, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	assert.Equal(t, "hello world", files["attachments/3/5-hello.txt"])
+	assert.Equal(t, `---
+title: "Groceries: this week"
+type: list
+color: "red"
+labels: ["Home"]
+pinned: true
+archived: false
+trashed: false
+created_at: "2022-01-31 09:00:00"
+updated_at: "2022-01-31 09:00:00"
+---
+
+- [x] milk
+- [ ] eggs
+
+## Attachments
+
+- [hello.txt](<../attachments/3/5-hello.txt>)
+`, files["notes/3-groceries-this-week.md"])
+	assert.Contains(t, files["notes/4-note.md"], "archived: true\n")
+	assert.Contains(t, files["notes/4-note.md"], "\nline one\nline two\n")
+
+	var account map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(files["librenote.json"]), &account))
+	assert.Equal(t, "mrtest@example.com", account["user"].(map[string]interface{})["email"])
+	assert.NotContains(t, files["librenote.json"], "secret")
+	assert.NotContains(t, files["librenote.json"], "s3cr3t")
+	assert.Equal(t, "d3JhcHBlZA==", account["key_envelope"].(map[string]interface{})["wrapped_key"])
+
+	mockNoteRepo.AssertExpectations(t)
+	mockAttachmentRepo.AssertExpectations(t)
+}
+
+func TestExportDrawing(t *testing.T) {
+	note := model.ExportedNote{Note: model.Note{
+		ID: 7, Type: "drawing", Drawing: &model.Drawing{Width: 20, Height: 10, Strokes: []model.Stroke{
+			{Color: "#000000", Width: 2, Points: []float64{1, 1, 19, 9}},
+		}},
+	}, Labels: []string{}}
+
+	u := usecase.NewExportUsecase(nil, nil, nil, nil, nil, nil, nil, time.Second)
+
+	var buf bytes.Buffer
+	assert.NoError(t, u.WriteZip(context.TODO(), &model.AccountExport{Notes: []model.ExportedNote{note}}, &buf))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+
+	assert.Equal(t, []string{"notes/7-note.md", "notes/7-note.png", "librenote.json"}, names)
+}
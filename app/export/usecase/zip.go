@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"io"
+	"librenote/app/drawing"
+	"librenote/app/model"
+	"librenote/infrastructure/logger"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxSlug limits the part of the note file names taken from the title
+const maxSlug = 50
+
+// noteFiles are the paths in the zip of a note and of its files
+type noteFiles struct {
+	markdown string
+	// empty unless the note is a drawing
+	drawing     string
+	attachments []string
+}
+
+// WriteZip lays out the export as
+//
+//	notes/<id>-<title>.md, with a <id>-<title>.png next to it for the drawings
+//	attachments/<note id>/<attachment id>-<name>
+//	librenote.json
+func (u *exportUsecase) WriteZip(c context.Context, export *model.AccountExport, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	modified := time.Now().UTC()
+
+	create := func(name string) (io.Writer, error) {
+		return zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	}
+
+	for i := range export.Notes {
+		note := &export.Notes[i]
+		files := filesOf(note)
+
+		f, err := create(files.markdown)
+		if err != nil {
+			return err
+		}
+
+		if _, err = io.WriteString(f, markdown(note, files)); err != nil {
+			return err
+		}
+
+		if files.drawing != "" {
+			if err = writeDrawing(c, create, files.drawing, note.Drawing); err != nil {
+				return err
+			}
+		}
+
+		for idx := range note.Attachments {
+			if err = u.writeAttachment(c, create, files.attachments[idx], &note.Attachments[idx]); err != nil {
+				return err
+			}
+		}
+	}
+
+	f, err := create("librenote.json")
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+
+	if err = encoder.Encode(export); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// writeDrawing skips the png of a drawing which can't be rendered, its strokes are still in librenote.json
+func writeDrawing(c context.Context, create func(string) (io.Writer, error), name string, d *model.Drawing) error {
+	img, err := drawing.Render(c, d, 0)
+	if err != nil {
+		if c.Err() != nil {
+			return err
+		}
+
+		logger.FromContext(c).Warnf("no png for %s: %s", name, err)
+
+		return nil
+	}
+
+	f, err := create(name)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(f, img)
+}
+
+func (u *exportUsecase) writeAttachment(c context.Context, create func(string) (io.Writer, error), name string,
+	attachment *model.Attachment) error {
+	blob, err := u.blobs.Open(c, attachment.BlobKey, attachment.Size)
+	if err != nil {
+		return err
+	}
+
+	defer blob.Close()
+
+	f, err := create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, blob)
+
+	return err
+}
+
+func filesOf(note *model.ExportedNote) noteFiles {
+	var title string
+	if note.Title != nil {
+		title = *note.Title
+	}
+
+	base := fmt.Sprintf("%d-%s", note.ID, slug(title))
+	files := noteFiles{markdown: "notes/" + base + ".md"}
+
+	if note.Type == "drawing" && note.Drawing != nil {
+		files.drawing = "notes/" + base + ".png"
+	}
+
+	for _, attachment := range note.Attachments {
+		files.attachments = append(files.attachments,
+			fmt.Sprintf("attachments/%d/%d-%s", note.ID, attachment.ID, path.Base(attachment.Name)))
+	}
+
+	return files
+}
+
+// slug keeps the letters and digits of the title, anything else becomes a dash
+func slug(title string) string {
+	var sb strings.Builder
+
+	dash := false
+
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+
+			dash = true
+		}
+	}
+
+	s := []rune(strings.TrimSuffix(sb.String(), "-"))
+	if len(s) > maxSlug {
+		s = []rune(strings.TrimSuffix(string(s[:maxSlug]), "-"))
+	}
+
+	if len(s) == 0 {
+		return "note"
+	}
+
+	return string(s)
+}
+
+// markdown renders the note with a front matter, checklists are task lists
+func markdown(note *model.ExportedNote, files noteFiles) string {
+	var sb strings.Builder
+
+	sb.WriteString("---\n")
+
+	if note.Title != nil {
+		fmt.Fprintf(&sb, "title: %s\n", quote(*note.Title))
+	}
+
+	fmt.Fprintf(&sb, "type: %s\n", note.Type)
+
+	if note.Color != "" {
+		fmt.Fprintf(&sb, "color: %s\n", quote(note.Color))
+	}
+
+	// the ciphertext is in librenote.json, along with the key envelope
+	if note.Encrypted != nil {
+		sb.WriteString("encrypted: true\n")
+	}
+
+	fmt.Fprintf(&sb, "labels: %s\n", quote(note.Labels))
+	fmt.Fprintf(&sb, "pinned: %t\n", note.IsPinned == 1)
+	fmt.Fprintf(&sb, "archived: %t\n", note.IsArchived == 1)
+	fmt.Fprintf(&sb, "trashed: %t\n", note.IsTrashed == 1)
+	fmt.Fprintf(&sb, "created_at: %s\n", quote(note.CreatedAt))
+	fmt.Fprintf(&sb, "updated_at: %s\n", quote(note.UpdatedAt))
+	sb.WriteString("---\n")
+
+	var paragraphs []string
+
+	if note.Body != "" {
+		paragraphs = append(paragraphs, strings.TrimSpace(note.Body))
+	}
+
+	switch {
+	case files.drawing != "":
+		paragraphs = append(paragraphs, fmt.Sprintf("![drawing](<%s>)", path.Base(files.drawing)))
+	case note.Type == "list":
+		var tasks []string
+
+		for _, item := range note.Items {
+			mark := " "
+			if item.IsChecked == 1 {
+				mark = "x"
+			}
+
+			tasks = append(tasks, fmt.Sprintf("- [%s] %s", mark, strings.ReplaceAll(text(item), "\n", "\n  ")))
+		}
+
+		if len(tasks) > 0 {
+			paragraphs = append(paragraphs, strings.Join(tasks, "\n"))
+		}
+	default:
+		for _, item := range note.Items {
+			paragraphs = append(paragraphs, text(item))
+		}
+	}
+
+	if len(note.Attachments) > 0 {
+		links := []string{"## Attachments\n"}
+
+		for idx, attachment := range note.Attachments {
+			link := fmt.Sprintf("[%s](<../%s>)", attachment.Name, files.attachments[idx])
+			if strings.HasPrefix(attachment.ContentType, "image/") {
+				link = "!" + link
+			}
+
+			links = append(links, "- "+link)
+		}
+
+		paragraphs = append(paragraphs, strings.Join(links, "\n"))
+	}
+
+	for _, paragraph := range paragraphs {
+		sb.WriteString("\n" + paragraph + "\n")
+	}
+
+	return sb.String()
+}
+
+func text(item model.NotesItem) string {
+	if item.Text == nil {
+		return ""
+	}
+
+	return *item.Text
+}
+
+// quote writes strings and lists as JSON, which YAML front matter readers accept
+func quote(v interface{}) string {
+	b, _ := json.Marshal(v)
+
+	return string(b)
+}
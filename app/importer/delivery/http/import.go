@@ -0,0 +1,79 @@
+package http
+
+import (
+	"errors"
+	"librenote/app/importer"
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/app/validation"
+	"librenote/infrastructure/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ImportHandler represent the http handler for import
+type ImportHandler struct {
+	IUseCase model.ImportUsecase
+}
+
+func NewImportHandler(e *echo.Echo, us model.ImportUsecase) {
+	handler := &ImportHandler{
+		IUseCase: us,
+	}
+
+	imports := e.Group("/api/v1/import")
+	_ = middlewares.AttachJwtToGroup(imports)
+	imports.POST("/:source", handler.Import)
+}
+
+// Import expects the export in the `file` field of a multipart form, either a zip or a single file
+// like an ENEX notebook, `?dry_run=1` only reports what would be created
+func (i *ImportHandler) Import(c echo.Context) error {
+	var iReq importReq
+
+	// the default binder doesn't bind the query of POST requests
+	err := (&echo.DefaultBinder{}).BindQueryParams(c, &iReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&iReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, errors.New("multipart field file is required")))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	defer file.Close()
+
+	fsys, err := importer.NewFS(file, fileHeader.Size, fileHeader.Filename)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	report, err := i.IUseCase.Import(ctx, c.Param("source"), middlewares.GetUserID(c), fsys, iReq.DryRun == 1)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	message := "notes imported"
+	if report.DryRun {
+		message = "dry run, nothing imported"
+	}
+
+	return c.JSON(response.RespondSuccess(message, report))
+}
@@ -0,0 +1,129 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/fs"
+	importHttp "librenote/app/importer/delivery/http"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var BaseURLV1 = "/api/v1"
+
+func buildEchoAuthorizedRequest(t *testing.T, method, path, token string, payload io.Reader) (
+	echo.Context, *httptest.ResponseRecorder) {
+	var req *http.Request
+
+	var err error
+
+	if payload != nil {
+		req, err = http.NewRequest(method, path, payload)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	} else {
+		req, err = http.NewRequest(method, path, nil)
+	}
+
+	assert.NoError(t, err)
+
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+	res := httptest.NewRecorder()
+	e := echo.New()
+	ctx := e.NewContext(req, res)
+
+	return ctx, res
+}
+
+// nolint:unparam
+func getToken(userID int32) string {
+	jwtCfg := config.Get().Jwt
+	claims := &middlewares.JwtCustomClaims{
+		UserID: userID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(jwtCfg.ExpireTime).Unix(),
+		},
+	}
+	unsignedToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token, _ := unsignedToken.SignedString([]byte(jwtCfg.SecretKey))
+
+	return token
+}
+
+func attachJWTMiddleware(hfc echo.HandlerFunc) echo.HandlerFunc {
+	mhfc := middleware.JWTWithConfig(
+		middleware.JWTConfig{
+			Claims:     &middlewares.JwtCustomClaims{},
+			SigningKey: []byte(config.Get().Jwt.SecretKey),
+		})(hfc)
+
+	return mhfc
+}
+
+func TestImport(t *testing.T) {
+	mockUsecase := new(mocks.ImportUsecase)
+	mockUsecase.On("Import", mock.Anything, "keep", int32(1), mock.MatchedBy(func(fsys fs.FS) bool {
+		b, err := fs.ReadFile(fsys, "notes.json")
+		return err == nil && string(b) == "json bytes"
+	}), true).Return(&model.ImportReport{Source: "keep", DryRun: true, Notes: 2, Labels: []string{"Home"}}, nil).Once()
+
+	handler := importHttp.ImportHandler{
+		IUseCase: mockUsecase,
+	}
+
+	t.Run("dry-run", func(t *testing.T) {
+		var body bytes.Buffer
+
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", "notes.json")
+		assert.NoError(t, err)
+		_, _ = part.Write([]byte("json bytes"))
+		assert.NoError(t, writer.Close())
+
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, BaseURLV1+"/import/keep?dry_run=1", getToken(1), &body)
+		ctx.Request().Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		ctx.SetParamNames("source")
+		ctx.SetParamValues("keep")
+		handle := attachJWTMiddleware(handler.Import)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		var r response.Response
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+		assert.True(t, r.Success)
+		assert.Equal(t, float64(2), r.Results.(map[string]interface{})["notes"])
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, path := range map[string]string{
+			"missing-file": BaseURLV1 + "/import/keep",
+			"bad-dry-run":  BaseURLV1 + "/import/keep?dry_run=2",
+		} {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.POST, path, getToken(1), strings.NewReader("{}"))
+			ctx.SetParamNames("source")
+			ctx.SetParamValues("keep")
+			handle := attachJWTMiddleware(handler.Import)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, http.StatusBadRequest, res.Code, name)
+		}
+	})
+}
@@ -0,0 +1,5 @@
+package http
+
+type importReq struct {
+	DryRun int8 `query:"dry_run" validate:"min=0,max=1"`
+}
@@ -0,0 +1,214 @@
+// Package enex reads the ENEX notebook exports of Evernote
+package enex
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"librenote/app/importer"
+	"librenote/app/model"
+	"mime"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const timeLayout = "20060102T150405Z"
+
+type enexNote struct {
+	Title     string         `xml:"title"`
+	Content   string         `xml:"content"`
+	Created   string         `xml:"created"`
+	Updated   string         `xml:"updated"`
+	Tags      []string       `xml:"tag"`
+	Resources []enexResource `xml:"resource"`
+}
+
+type enexResource struct {
+	Data struct {
+		Encoding string `xml:"encoding,attr"`
+		Value    string `xml:",chardata"`
+	} `xml:"data"`
+	Mime     string `xml:"mime"`
+	FileName string `xml:"resource-attributes>file-name"`
+}
+
+type enexImporter struct{}
+
+func NewImporter() model.Importer {
+	return enexImporter{}
+}
+
+func (enexImporter) Name() string {
+	return "enex"
+}
+
+// Parse reads every .enex file, the notes of a file are reported as <file>#<position>
+func (enexImporter) Parse(fsys fs.FS) (*model.ImportBatch, error) {
+	batch := &model.ImportBatch{}
+	labels := make(map[string]bool)
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(path.Ext(name), ".enex") {
+			return err
+		}
+
+		f, err := fsys.Open(name)
+		if err != nil {
+			batch.Skipped = append(batch.Skipped, model.ImportSkip{Source: name, Reason: err.Error()})
+			return nil
+		}
+
+		defer f.Close()
+
+		readNotes(batch, name, f)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, note := range batch.Notes {
+		for _, label := range note.Labels {
+			labels[label] = true
+		}
+	}
+
+	for label := range labels {
+		batch.Labels = append(batch.Labels, label)
+	}
+
+	sort.Strings(batch.Labels)
+
+	return batch, nil
+}
+
+// readNotes decodes the notes one by one, as a notebook with its resources can be large
+func readNotes(batch *model.ImportBatch, name string, r io.Reader) {
+	decoder := xml.NewDecoder(r)
+	// the ENML content is in CDATA, but the export declares its DTD
+	decoder.Strict = false
+	position := 0
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+
+		if err != nil {
+			batch.Skipped = append(batch.Skipped, model.ImportSkip{Source: name, Reason: err.Error()})
+			return
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "note" {
+			continue
+		}
+
+		position++
+		source := fmt.Sprintf("%s#%d", name, position)
+
+		var note enexNote
+		if err = decoder.DecodeElement(&note, &start); err != nil {
+			batch.Skipped = append(batch.Skipped, model.ImportSkip{Source: source, Reason: err.Error()})
+			return
+		}
+
+		imported, err := note.toImported(source)
+		if err != nil {
+			batch.Skipped = append(batch.Skipped, model.ImportSkip{Source: source, Reason: err.Error()})
+			continue
+		}
+
+		batch.Notes = append(batch.Notes, imported)
+	}
+}
+
+func (n *enexNote) toImported(source string) (model.ImportedNote, error) {
+	createdAt := parseTime(n.Created)
+	updatedAt := parseTime(n.Updated)
+
+	if createdAt == "" {
+		createdAt = updatedAt
+	}
+
+	if updatedAt == "" {
+		updatedAt = createdAt
+	}
+
+	text, tasks, err := enml(n.Content)
+	if err != nil {
+		return model.ImportedNote{}, err
+	}
+
+	note := model.Note{
+		Title:     importer.Title(n.Title),
+		Type:      "note",
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}
+
+	if items, ok := importer.TaskItems(tasks, createdAt); ok {
+		note.Type = "list"
+		note.Items = items
+	} else {
+		note.Items = importer.TextItems(text, createdAt)
+	}
+
+	imported := model.ImportedNote{Source: source, Note: note, Labels: importer.LabelNames(n.Tags)}
+
+	for i, resource := range n.Resources {
+		attachment, err := resource.toImported(i + 1)
+		if err != nil {
+			return model.ImportedNote{}, err
+		}
+
+		imported.Attachments = append(imported.Attachments, attachment)
+	}
+
+	return imported, nil
+}
+
+func (r *enexResource) toImported(position int) (model.ImportedAttachment, error) {
+	if r.Data.Encoding != "" && r.Data.Encoding != "base64" {
+		return model.ImportedAttachment{}, fmt.Errorf("unsupported resource encoding %q", r.Data.Encoding)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(r.Data.Value), ""))
+	if err != nil {
+		return model.ImportedAttachment{}, err
+	}
+
+	name := path.Base(strings.ReplaceAll(r.FileName, "\\", "/"))
+	if name == "" || name == "." || name == "/" {
+		name = fmt.Sprintf("attachment-%d", position)
+
+		if exts, _ := mime.ExtensionsByType(r.Mime); len(exts) > 0 {
+			name += exts[0]
+		}
+	}
+
+	return model.ImportedAttachment{
+		Name: name,
+		Size: int64(len(data)),
+		Open: func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		},
+	}, nil
+}
+
+func parseTime(s string) string {
+	t, err := time.Parse(timeLayout, strings.TrimSpace(s))
+	if err != nil {
+		return ""
+	}
+
+	return t.UTC().Format(importer.TimeLayout)
+}
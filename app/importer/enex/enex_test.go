@@ -0,0 +1,90 @@
+package enex_test
+
+import (
+	"io"
+	"librenote/app/importer/enex"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const notebook = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE en-export SYSTEM "http://xml.evernote.com/pub/evernote-export3.dtd">
+<en-export export-date="20220131T090000Z" application="Evernote" version="10.0">
+  <note>
+    <title>Groceries</title>
+    <created>20220131T090000Z</created>
+    <updated>20220131T100000Z</updated>
+    <tag>Home</tag>
+    <content><![CDATA[<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<!DOCTYPE en-note SYSTEM "http://xml.evernote.com/pub/enml2.dtd">
+<en-note><div><en-todo checked="true"/>milk</div><div><en-todo checked="false"/>eggs &amp; ham</div></en-note>]]>
+    </content>
+  </note>
+  <note>
+    <title>Meeting</title>
+    <created>20220130T090000Z</created>
+    <tag>Work</tag>
+    <tag>Home</tag>
+    <content><![CDATA[<en-note><h1>Agenda</h1><p>First<br/>Second</p>
+<div><en-todo/>follow up</div></en-note>]]></content>
+    <resource>
+      <data encoding="base64">aGVsbG8g
+d29ybGQ=</data>
+      <mime>text/plain</mime>
+      <resource-attributes><file-name>notes.txt</file-name></resource-attributes>
+    </resource>
+    <resource>
+      <data encoding="base64">iVBORw==</data>
+      <mime>image/png</mime>
+    </resource>
+  </note>
+  <note>
+    <title>Broken</title>
+    <content><![CDATA[<en-note>text</en-note>]]></content>
+    <resource><data encoding="base64">not base64!</data></resource>
+  </note>
+</en-export>
+`
+
+func TestParse(t *testing.T) {
+	fsys := fstest.MapFS{
+		"My Notebook.enex": {Data: []byte(notebook)},
+		"readme.txt":       {Data: []byte("ignored")},
+	}
+
+	batch, err := enex.NewImporter().Parse(fsys)
+	assert.NoError(t, err)
+	assert.Len(t, batch.Notes, 2)
+
+	groceries := batch.Notes[0]
+	assert.Equal(t, "My Notebook.enex#1", groceries.Source)
+	assert.Equal(t, "Groceries", *groceries.Note.Title)
+	assert.Equal(t, "list", groceries.Note.Type)
+	assert.Equal(t, "2022-01-31 09:00:00", groceries.Note.CreatedAt)
+	assert.Equal(t, "2022-01-31 10:00:00", groceries.Note.UpdatedAt)
+	assert.Len(t, groceries.Note.Items, 2)
+	assert.Equal(t, int8(1), groceries.Note.Items[0].IsChecked)
+	assert.Equal(t, "eggs & ham", *groceries.Note.Items[1].Text)
+	assert.Equal(t, []string{"Home"}, groceries.Labels)
+
+	meeting := batch.Notes[1]
+	assert.Equal(t, "note", meeting.Note.Type)
+	assert.Equal(t, meeting.Note.CreatedAt, meeting.Note.UpdatedAt)
+	assert.Equal(t, "Agenda\nFirst\nSecond\nfollow up", *meeting.Note.Items[0].Text)
+	assert.Len(t, meeting.Attachments, 2)
+	assert.Equal(t, "notes.txt", meeting.Attachments[0].Name)
+	assert.Equal(t, int64(11), meeting.Attachments[0].Size)
+	assert.Equal(t, "attachment-2.png", meeting.Attachments[1].Name)
+
+	rc, err := meeting.Attachments[0].Open()
+	assert.NoError(t, err)
+
+	content, _ := io.ReadAll(rc)
+	assert.Equal(t, "hello world", string(content))
+
+	assert.Equal(t, []string{"Home", "Work"}, batch.Labels)
+	assert.Len(t, batch.Skipped, 1)
+	assert.Equal(t, "My Notebook.enex#3", batch.Skipped[0].Source)
+}
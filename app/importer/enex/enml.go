@@ -0,0 +1,84 @@
+package enex
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// blocks are the elements which start a new line
+var blocks = map[string]bool{
+	"div": true, "p": true, "li": true, "tr": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "blockquote": true, "pre": true, "hr": true, "table": true, "ul": true, "ol": true,
+}
+
+// enml converts the ENML content to text, tasks is the same text with the <en-todo> checkboxes
+// as Markdown task markers, so that checklists can be told apart
+func enml(content string) (text, tasks string, err error) {
+	doc, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		return "", "", err
+	}
+
+	var plain, marked strings.Builder
+
+	write := func(s string) {
+		plain.WriteString(s)
+		marked.WriteString(s)
+	}
+
+	newLine := func() {
+		if !lineStart(plain.String()) {
+			write("\n")
+		}
+	}
+
+	var walk func(*html.Node)
+
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode && strings.TrimSpace(n.Data) == "" && lineStart(plain.String()):
+			// indentation between blocks of pretty printed notes
+		case n.Type == html.TextNode:
+			write(n.Data)
+		case n.Type == html.ElementNode && n.Data == "br":
+			write("\n")
+		case n.Type == html.ElementNode && n.Data == "en-todo":
+			newLine()
+
+			if attr(n, "checked") == "true" {
+				marked.WriteString("- [x] ")
+			} else {
+				marked.WriteString("- [ ] ")
+			}
+		case n.Type == html.ElementNode && blocks[n.Data]:
+			newLine()
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+
+		if n.Type == html.ElementNode && blocks[n.Data] {
+			newLine()
+		}
+	}
+
+	walk(doc)
+
+	return strings.TrimSpace(plain.String()), marked.String(), nil
+}
+
+func lineStart(s string) bool {
+	return s == "" || strings.HasSuffix(s, "\n")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
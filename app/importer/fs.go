@@ -0,0 +1,120 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+var zipMagic = []byte("PK\x03\x04")
+
+// NewFS opens an uploaded export, a zip is read as the tree of its files
+// and any other file as a tree holding that file only, under its base name.
+// A zip is rejected before anything is uncompressed when its files add up to
+// more than MaxExport
+func NewFS(r io.ReaderAt, size int64, name string) (fs.FS, error) {
+	magic := make([]byte, len(zipMagic))
+	if _, err := r.ReadAt(magic, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if bytes.Equal(magic, zipMagic) {
+		return newZipFS(r, size)
+	}
+
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if !fs.ValidPath(name) || name == "." || name == "/" {
+		name = "import"
+	}
+
+	return &fileFS{info: fileInfo{name: name, size: size}, r: r}, nil
+}
+
+// newZipFS checks the uncompressed sizes of the headers, archive/zip fails the reads going past them
+func newZipFS(r io.ReaderAt, size int64) (fs.FS, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var total uint64
+
+	for _, f := range zr.File {
+		if f.UncompressedSize64 > MaxExport-total {
+			return nil, ErrExportTooLarge
+		}
+
+		total += f.UncompressedSize64
+	}
+
+	return zr, nil
+}
+
+// fileFS is a tree of one file
+type fileFS struct {
+	info fileInfo
+	r    io.ReaderAt
+}
+
+func (f *fileFS) Open(name string) (fs.File, error) {
+	switch name {
+	case ".":
+		return &dirFile{info: fileInfo{name: ".", dir: true}}, nil
+	case f.info.name:
+		return &file{info: f.info, SectionReader: io.NewSectionReader(f.r, 0, f.info.size)}, nil
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir lets fs.WalkDir list the file
+func (f *fileFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return []fs.DirEntry{fs.FileInfoToDirEntry(f.info)}, nil
+}
+
+type file struct {
+	*io.SectionReader
+	info fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *file) Close() error               { return nil }
+
+type dirFile struct {
+	info fileInfo
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+func (d *dirFile) Close() error { return nil }
+
+type fileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i fileInfo) Name() string       { return i.name }
+func (i fileInfo) Size() int64        { return i.size }
+func (i fileInfo) ModTime() time.Time { return time.Time{} }
+func (i fileInfo) IsDir() bool        { return i.dir }
+func (i fileInfo) Sys() interface{}   { return nil }
+
+func (i fileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+
+	return 0o444
+}
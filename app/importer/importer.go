@@ -0,0 +1,197 @@
+// Package importer holds what the importers of the other applications' exports share
+package importer
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"librenote/app/model"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	TimeLayout = "2006-01-02 15:04:05"
+	// limits of the notes, notes_items and labels tables
+	MaxTitle     = 255
+	MaxItemText  = 1000
+	MaxLabelName = 50
+	// limit of the note bodies, as accepted by the API
+	MaxBody = 100000
+	// notes are read in memory, anything bigger isn't read
+	MaxNoteFile = 10 << 20
+	// attachments are copied to a temporary file, anything bigger isn't imported
+	MaxAttachment = 100 << 20
+	// uncompressed size of all the files of a zip export
+	MaxExport = 2 << 30
+)
+
+var (
+	// ErrFileTooLarge is reported for the notes over MaxNoteFile and the attachments over MaxAttachment
+	ErrFileTooLarge = errors.New("file too large")
+	// ErrExportTooLarge is returned for the zip exports over MaxExport once uncompressed
+	ErrExportTooLarge = errors.New("export too large once uncompressed")
+)
+
+// ReadFile reads a file of at most MaxNoteFile bytes
+func ReadFile(fsys fs.FS, name string) ([]byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	b, err := io.ReadAll(io.LimitReader(f, MaxNoteFile+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) > MaxNoteFile {
+		return nil, ErrFileTooLarge
+	}
+
+	return b, nil
+}
+
+// Attachment imports a file of the export as attachment, the files over MaxAttachment aren't. The size is the one
+// of the zip header, reading the file fails past it
+func Attachment(fsys fs.FS, name string) (model.ImportedAttachment, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return model.ImportedAttachment{}, err
+	}
+
+	if info.IsDir() {
+		return model.ImportedAttachment{}, fs.ErrNotExist
+	}
+
+	if info.Size() > MaxAttachment {
+		return model.ImportedAttachment{}, ErrFileTooLarge
+	}
+
+	return model.ImportedAttachment{
+		Name: info.Name(),
+		Size: info.Size(),
+		Open: func() (io.ReadCloser, error) {
+			return fsys.Open(name)
+		},
+	}, nil
+}
+
+// Title trims and truncates the title, an empty title is nil
+func Title(s string) *string {
+	title := Truncate(strings.TrimSpace(s), MaxTitle)
+	if title == "" {
+		return nil
+	}
+
+	return &title
+}
+
+// Color keeps the colors of our palette only
+func Color(s string) string {
+	color := strings.ToLower(strings.TrimSpace(s))
+	if model.IsColor(color) {
+		return color
+	}
+
+	return ""
+}
+
+// LabelNames trims, truncates and deduplicates the label names
+func LabelNames(names []string) []string {
+	var labels []string
+
+	seen := make(map[string]bool)
+
+	for _, name := range names {
+		if name = Truncate(strings.TrimSpace(name), MaxLabelName); name != "" && !seen[name] {
+			seen[name] = true
+			labels = append(labels, name)
+		}
+	}
+
+	return labels
+}
+
+// TextItems splits a text in items which fit the items table, on line breaks when possible
+func TextItems(text, createdAt string) []model.NotesItem {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	items := make([]model.NotesItem, 0)
+
+	var chunk []rune
+
+	flush := func() {
+		if s := strings.TrimSuffix(string(chunk), "\n"); strings.TrimSpace(s) != "" {
+			items = append(items, model.NotesItem{Text: &s, CreatedAt: createdAt})
+		}
+
+		chunk = nil
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		runes := []rune(line)
+		if len(chunk)+len(runes) > MaxItemText {
+			flush()
+		}
+
+		for len(runes) > MaxItemText {
+			chunk = runes[:MaxItemText]
+			flush()
+			runes = runes[MaxItemText:]
+		}
+
+		chunk = append(chunk, runes...)
+	}
+
+	flush()
+
+	return items
+}
+
+// TaskItems reads a Markdown task list like "- [x] milk", ok is false when the text has other lines than tasks
+func TaskItems(text, createdAt string) (items []model.NotesItem, ok bool) {
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if len(line) < 5 || (line[0] != '-' && line[0] != '*') || line[1] != ' ' || line[2] != '[' ||
+			line[4] != ']' || (len(line) > 5 && line[5] != ' ') {
+			return nil, false
+		}
+
+		var checked int8
+
+		switch line[3] {
+		case 'x', 'X':
+			checked = 1
+		case ' ':
+		default:
+			return nil, false
+		}
+
+		task := Truncate(strings.TrimSpace(line[5:]), MaxItemText)
+		items = append(items, model.NotesItem{Text: &task, IsChecked: checked, CreatedAt: createdAt})
+	}
+
+	return items, len(items) > 0
+}
+
+func Truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) > max {
+		return string([]rune(s)[:max])
+	}
+
+	return s
+}
+
+func Flag(b bool) int8 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,131 @@
+package importer_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/fs"
+	"librenote/app/importer"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextItems(t *testing.T) {
+	long := strings.Repeat("a", 600) + "\n" + strings.Repeat("b", 600) + "\n" + strings.Repeat("c", 2100)
+
+	items := importer.TextItems(long, "2022-01-31 09:00:00")
+	assert.Len(t, items, 5)
+	assert.Equal(t, strings.Repeat("a", 600), *items[0].Text)
+	assert.Equal(t, strings.Repeat("b", 600), *items[1].Text)
+	assert.Len(t, *items[2].Text, 1000)
+	assert.Len(t, *items[4].Text, 100)
+	assert.Equal(t, "2022-01-31 09:00:00", items[0].CreatedAt)
+
+	assert.Empty(t, importer.TextItems(" \n\n ", ""))
+}
+
+func TestTaskItems(t *testing.T) {
+	items, ok := importer.TaskItems("- [x] milk\n\n* [ ] eggs\n- [X] ham", "")
+	assert.True(t, ok)
+	assert.Len(t, items, 3)
+	assert.Equal(t, int8(1), items[0].IsChecked)
+	assert.Equal(t, "eggs", *items[1].Text)
+	assert.Equal(t, int8(1), items[2].IsChecked)
+
+	for _, text := range []string{"", "- [x] milk\nsome text", "- milk", "- [?] milk", "- [x]milk"} {
+		_, ok = importer.TaskItems(text, "")
+		assert.False(t, ok, text)
+	}
+}
+
+func TestNewFS(t *testing.T) {
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("Keep/a.json")
+	_, _ = w.Write([]byte("{}"))
+	assert.NoError(t, zw.Close())
+
+	fsys, err := importer.NewFS(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "takeout.zip")
+	assert.NoError(t, err)
+
+	b, err := fs.ReadFile(fsys, "Keep/a.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(b))
+
+	r := strings.NewReader("<en-export/>")
+	fsys, err = importer.NewFS(r, r.Size(), "../notebooks/Work.enex")
+	assert.NoError(t, err)
+
+	var names []string
+
+	assert.NoError(t, fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		names = append(names, name)
+		return err
+	}))
+	assert.Equal(t, []string{".", "Work.enex"}, names)
+
+	b, err = fs.ReadFile(fsys, "Work.enex")
+	assert.NoError(t, err)
+	assert.Equal(t, "<en-export/>", string(b))
+
+	_, err = fsys.Open("other.enex")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+// rawZip stores the files with the uncompressed sizes given, whatever their content
+func rawZip(t *testing.T, files map[string]uint64) *bytes.Reader {
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for name, size := range files {
+		w, err := zw.CreateRaw(&zip.FileHeader{Name: name, Method: zip.Store, UncompressedSize64: size,
+			CompressedSize64: 4})
+		assert.NoError(t, err)
+
+		_, err = w.Write([]byte("data"))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, zw.Close())
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestNewFSTooLarge(t *testing.T) {
+	r := rawZip(t, map[string]uint64{"a.md": importer.MaxExport / 2, "b.md": importer.MaxExport/2 + 1})
+	_, err := importer.NewFS(r, r.Size(), "export.zip")
+	assert.ErrorIs(t, err, importer.ErrExportTooLarge)
+
+	r = rawZip(t, map[string]uint64{"a.md": 1<<64 - 1})
+	_, err = importer.NewFS(r, r.Size(), "export.zip")
+	assert.ErrorIs(t, err, importer.ErrExportTooLarge)
+}
+
+func TestAttachment(t *testing.T) {
+	r := rawZip(t, map[string]uint64{"big.png": importer.MaxAttachment + 1, "short.png": 2})
+	fsys, err := importer.NewFS(r, r.Size(), "export.zip")
+	assert.NoError(t, err)
+
+	_, err = importer.Attachment(fsys, "big.png")
+	assert.ErrorIs(t, err, importer.ErrFileTooLarge)
+
+	_, err = importer.Attachment(fsys, "none.png")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// the content going past the size of the header isn't read
+	attachment, err := importer.Attachment(fsys, "short.png")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), attachment.Size)
+
+	rc, err := attachment.Open()
+	assert.NoError(t, err)
+
+	defer rc.Close()
+
+	_, err = io.ReadAll(rc)
+	assert.ErrorIs(t, err, zip.ErrFormat)
+}
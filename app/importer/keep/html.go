@@ -0,0 +1,208 @@
+package keep
+
+import (
+	"bytes"
+	"io/fs"
+	"librenote/app/importer"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// layouts of the edit time in the heading of the HTML notes, which is in the time zone of the exporter
+var headingLayouts = []string{
+	"Jan 2, 2006, 3:04:05 PM",
+	"2 Jan 2006, 15:04:05",
+	"Jan 2, 2006, 15:04:05",
+}
+
+// readHTML reads a note of the older exports, like
+//
+//	<div class="note RED"><div class="heading"><div class="meta-icons">
+//	<span class="pinned"></span></div>Jan 2, 2020, 10:00:00 AM</div>
+//	<div class="title">..</div><div class="content">..<br>..</div>
+//	<div class="chips"><span class="label-name">..</span></div></div>
+//
+// checklists are <ul class="list"> with a <li class="listitem checked"> per item
+func readHTML(fsys fs.FS, name string) (*keepNote, error) {
+	b, err := importer.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	root := find(doc, "note")
+	if root == nil {
+		return nil, errNotKeepNote
+	}
+
+	note := &keepNote{}
+
+	for _, class := range classes(root) {
+		if class != "note" {
+			note.Color = class
+		}
+	}
+
+	if heading := find(root, "heading"); heading != nil {
+		note.IsPinned = find(heading, "pinned") != nil
+		note.IsArchived = find(heading, "archived") != nil
+		note.IsTrashed = find(heading, "trashed") != nil
+		note.editedAt = parseHeading(ownText(heading))
+	}
+
+	if title := find(root, "title"); title != nil {
+		note.Title = text(title)
+	}
+
+	if content := find(root, "content"); content != nil {
+		readContent(note, content)
+	}
+
+	if chips := find(root, "chips"); chips != nil {
+		walk(chips, func(n *html.Node) {
+			if hasClass(n, "label-name") {
+				note.Labels = append(note.Labels, keepLabel{Name: text(n)})
+			}
+		})
+	}
+
+	if attachments := find(root, "attachments"); attachments != nil {
+		walk(attachments, func(n *html.Node) {
+			src := attr(n, "src")
+			if n.Data == "a" {
+				src = attr(n, "href")
+			}
+
+			if (n.Data == "img" || n.Data == "a") && src != "" && !strings.Contains(src, ":") {
+				note.Attachments = append(note.Attachments, keepAttachment{FilePath: src})
+			}
+		})
+	}
+
+	return note, nil
+}
+
+func readContent(note *keepNote, content *html.Node) {
+	list := find(content, "list")
+	if list == nil {
+		note.TextContent = text(content)
+		return
+	}
+
+	walk(list, func(n *html.Node) {
+		if !hasClass(n, "listitem") {
+			return
+		}
+
+		item := n
+		if span := find(n, "text"); span != nil {
+			item = span
+		}
+
+		note.ListContent = append(note.ListContent, keepListItem{Text: text(item), IsChecked: hasClass(n, "checked")})
+	})
+}
+
+func parseHeading(s string) time.Time {
+	// recent exports put a narrow no-break space before AM/PM
+	s = strings.TrimSpace(strings.NewReplacer("\u202f", " ", "\u00a0", " ").Replace(s))
+
+	for _, layout := range headingLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func classes(n *html.Node) []string {
+	return strings.Fields(attr(n, "class"))
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range classes(n) {
+		if c == class {
+			return true
+		}
+	}
+
+	return false
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+// walk calls fn for n and every element below it, in document order
+func walk(n *html.Node, fn func(*html.Node)) {
+	if n.Type == html.ElementNode {
+		fn(n)
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
+
+// find returns the first element of the class below n, n included
+func find(n *html.Node, class string) *html.Node {
+	var found *html.Node
+
+	walk(n, func(e *html.Node) {
+		if found == nil && hasClass(e, class) {
+			found = e
+		}
+	})
+
+	return found
+}
+
+// text is the text of n with <br> as line breaks
+func text(n *html.Node) string {
+	var sb strings.Builder
+
+	var collect func(*html.Node)
+
+	collect = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			sb.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.Data == "br":
+			sb.WriteString("\n")
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+
+	collect(n)
+
+	return sb.String()
+}
+
+// ownText is the text directly in n, without the one of its elements
+func ownText(n *html.Node) string {
+	var sb strings.Builder
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,274 @@
+// Package keep reads the Google Keep export of Google Takeout
+package keep
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"librenote/app/importer"
+	"librenote/app/model"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+var errNotKeepNote = errors.New("not a Keep note")
+
+// colors maps the Keep colors to ours
+var colors = map[string]string{
+	"RED":      "red",
+	"ORANGE":   "orange",
+	"YELLOW":   "yellow",
+	"GREEN":    "green",
+	"TEAL":     "teal",
+	"BLUE":     "blue",
+	"CERULEAN": "dark blue",
+	"PURPLE":   "purple",
+	"PINK":     "pink",
+	"BROWN":    "brown",
+	"GRAY":     "gray",
+}
+
+// keepNote is a note as Keep exports it in JSON, the HTML notes are read into it too
+type keepNote struct {
+	Color                   string           `json:"color"`
+	IsTrashed               bool             `json:"isTrashed"`
+	IsPinned                bool             `json:"isPinned"`
+	IsArchived              bool             `json:"isArchived"`
+	Title                   string           `json:"title"`
+	TextContent             string           `json:"textContent"`
+	ListContent             []keepListItem   `json:"listContent"`
+	Labels                  []keepLabel      `json:"labels"`
+	Attachments             []keepAttachment `json:"attachments"`
+	CreatedTimestampUsec    int64            `json:"createdTimestampUsec"`
+	UserEditedTimestampUsec int64            `json:"userEditedTimestampUsec"`
+	// only set by the HTML notes, which lack the creation time
+	editedAt time.Time
+}
+
+type keepListItem struct {
+	Text      string `json:"text"`
+	IsChecked bool   `json:"isChecked"`
+}
+
+type keepLabel struct {
+	Name string `json:"name"`
+}
+
+type keepAttachment struct {
+	FilePath string `json:"filePath"`
+	Mimetype string `json:"mimetype"`
+}
+
+type keepImporter struct{}
+
+func NewImporter() model.Importer {
+	return keepImporter{}
+}
+
+func (keepImporter) Name() string {
+	return "keep"
+}
+
+// Parse reads a Takeout export, notes are read from their JSON file and from the HTML file
+// only when there is no JSON file, as in older exports
+func (keepImporter) Parse(fsys fs.FS) (*model.ImportBatch, error) {
+	batch := &model.ImportBatch{}
+	labels := make(map[string]bool)
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		var note *keepNote
+
+		switch ext := path.Ext(name); {
+		case d.Name() == "Labels.txt":
+			err = readLabels(fsys, name, labels)
+		case strings.EqualFold(ext, ".json"):
+			note, err = readJSON(fsys, name)
+		case strings.EqualFold(ext, ".html"):
+			if _, statErr := fs.Stat(fsys, strings.TrimSuffix(name, ext)+".json"); statErr == nil {
+				return nil
+			}
+
+			note, err = readHTML(fsys, name)
+		default:
+			// attachments are read along with their note
+			return nil
+		}
+
+		if err != nil {
+			batch.Skipped = append(batch.Skipped, model.ImportSkip{Source: name, Reason: err.Error()})
+			return nil
+		}
+
+		if note == nil {
+			return nil
+		}
+
+		imported := note.toImported(name)
+		imported.Attachments, batch.Skipped = note.attachments(fsys, name, batch.Skipped)
+
+		for _, label := range imported.Labels {
+			labels[label] = true
+		}
+
+		batch.Notes = append(batch.Notes, imported)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for label := range labels {
+		batch.Labels = append(batch.Labels, label)
+	}
+
+	sort.Strings(batch.Labels)
+
+	return batch, nil
+}
+
+func readLabels(fsys fs.FS, name string, labels map[string]bool) error {
+	b, err := importer.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		names = append(names, sc.Text())
+	}
+
+	for _, label := range importer.LabelNames(names) {
+		labels[label] = true
+	}
+
+	return sc.Err()
+}
+
+func readJSON(fsys fs.FS, name string) (*keepNote, error) {
+	b, err := importer.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var note keepNote
+	if err = json.Unmarshal(b, &note); err != nil {
+		return nil, errNotKeepNote
+	}
+
+	if note.CreatedTimestampUsec == 0 && note.UserEditedTimestampUsec == 0 {
+		return nil, errNotKeepNote
+	}
+
+	return &note, nil
+}
+
+func (n *keepNote) toImported(source string) model.ImportedNote {
+	createdAt := usecTime(n.CreatedTimestampUsec)
+	updatedAt := usecTime(n.UserEditedTimestampUsec)
+
+	if !n.editedAt.IsZero() {
+		updatedAt = n.editedAt.UTC().Format(importer.TimeLayout)
+	}
+
+	if createdAt == "" {
+		createdAt = updatedAt
+	}
+
+	if updatedAt == "" {
+		updatedAt = createdAt
+	}
+
+	note := model.Note{
+		Title:      importer.Title(n.Title),
+		Color:      colors[strings.ToUpper(n.Color)],
+		Type:       "note",
+		IsPinned:   importer.Flag(n.IsPinned),
+		IsArchived: importer.Flag(n.IsArchived),
+		IsTrashed:  importer.Flag(n.IsTrashed),
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+		Items:      importer.TextItems(n.TextContent, createdAt),
+	}
+
+	if len(n.ListContent) > 0 {
+		note.Type = "list"
+		note.Items = make([]model.NotesItem, 0, len(n.ListContent))
+
+		for _, item := range n.ListContent {
+			text := importer.Truncate(item.Text, importer.MaxItemText)
+			note.Items = append(note.Items, model.NotesItem{
+				Text:      &text,
+				IsChecked: importer.Flag(item.IsChecked),
+				CreatedAt: createdAt,
+			})
+		}
+	}
+
+	names := make([]string, 0, len(n.Labels))
+	for _, label := range n.Labels {
+		names = append(names, label.Name)
+	}
+
+	return model.ImportedNote{Source: source, Note: note, Labels: importer.LabelNames(names)}
+}
+
+// attachments finds the files of the note next to it, Keep sometimes names .jpg files .jpeg in the note
+func (n *keepNote) attachments(fsys fs.FS, source string, skipped []model.ImportSkip) (
+	[]model.ImportedAttachment, []model.ImportSkip) {
+	var attachments []model.ImportedAttachment
+
+	for _, attachment := range n.Attachments {
+		name := path.Join(path.Dir(source), attachment.FilePath)
+
+		imported, err := importer.Attachment(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			imported, err = importer.Attachment(fsys, alternateExt(name))
+		}
+
+		if errors.Is(err, importer.ErrFileTooLarge) {
+			skipped = append(skipped, model.ImportSkip{Source: name, Reason: err.Error()})
+			continue
+		}
+
+		if err != nil {
+			skipped = append(skipped, model.ImportSkip{Source: name, Reason: "attachment not found in the export"})
+			continue
+		}
+
+		attachments = append(attachments, imported)
+	}
+
+	return attachments, skipped
+}
+
+func alternateExt(name string) string {
+	ext := path.Ext(name)
+
+	switch strings.ToLower(ext) {
+	case ".jpeg":
+		return strings.TrimSuffix(name, ext) + ".jpg"
+	case ".jpg":
+		return strings.TrimSuffix(name, ext) + ".jpeg"
+	}
+
+	return name
+}
+
+func usecTime(usec int64) string {
+	if usec <= 0 {
+		return ""
+	}
+
+	return time.UnixMicro(usec).UTC().Format(importer.TimeLayout)
+}
@@ -0,0 +1,155 @@
+package keep_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/fs"
+	"librenote/app/importer"
+	"librenote/app/importer/keep"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildZip(t *testing.T, files map[string]string) fs.FS {
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, zw.Close())
+
+	fsys, err := importer.NewFS(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "takeout.zip")
+	assert.NoError(t, err)
+
+	return fsys
+}
+
+func TestParse(t *testing.T) {
+	fsys := buildZip(t, map[string]string{
+		"Takeout/Keep/Groceries.json": `{"color": "CERULEAN", "isTrashed": false, "isPinned": true,
+			"isArchived": false, "title": "Groceries", "createdTimestampUsec": 1643619600000000,
+			"userEditedTimestampUsec": 1643623200000000,
+			"listContent": [{"text": "milk", "isChecked": true}, {"text": "eggs", "isChecked": false}],
+			"labels": [{"name": "Home"}, {"name": "Home"}],
+			"attachments": [{"filePath": "cat.jpeg", "mimetype": "image/jpeg"},
+				{"filePath": "lost.png", "mimetype": "image/png"}]}`,
+		"Takeout/Keep/cat.jpg":        "jpeg bytes",
+		"Takeout/Keep/Groceries.html": `<div class="note"></div>`,
+		"Takeout/Keep/Idea.json": `{"color": "DEFAULT", "isArchived": true, "isTrashed": true,
+			"textContent": "` + strings.Repeat("a", 1500) + `", "createdTimestampUsec": 1643619600000000}`,
+		"Takeout/Keep/Old.html": `<html><body><div class="note RED"><div class="heading">` +
+			`<div class="meta-icons"><span class="archived" title="Note archived"></span></div>` +
+			"Jan 31, 2022, 9:00:00 AM</div><div class=\"title\">Old</div>" +
+			`<div class="content">first line<br>second line</div>` +
+			`<div class="chips"><span class="chip label"><span class="label-name">Work</span></span></div>` +
+			`</div></body></html>`,
+		"Takeout/Keep/Labels.txt":      "Home\nWork\nUnused\n",
+		"Takeout/Keep/broken.json":     `{"title": `,
+		"Takeout/archive_browser.html": `<html><body>Archive</body></html>`,
+	})
+
+	batch, err := keep.NewImporter().Parse(fsys)
+	assert.NoError(t, err)
+	assert.Len(t, batch.Notes, 3)
+
+	notes := make(map[string]int)
+	for i, n := range batch.Notes {
+		notes[n.Source] = i
+	}
+
+	groceries := batch.Notes[notes["Takeout/Keep/Groceries.json"]]
+	assert.Equal(t, "list", groceries.Note.Type)
+	assert.Equal(t, "dark blue", groceries.Note.Color)
+	assert.Equal(t, int8(1), groceries.Note.IsPinned)
+	assert.Equal(t, "2022-01-31 09:00:00", groceries.Note.CreatedAt)
+	assert.Equal(t, "2022-01-31 10:00:00", groceries.Note.UpdatedAt)
+	assert.Len(t, groceries.Note.Items, 2)
+	assert.Equal(t, int8(1), groceries.Note.Items[0].IsChecked)
+	assert.Equal(t, []string{"Home"}, groceries.Labels)
+	assert.Len(t, groceries.Attachments, 1)
+	assert.Equal(t, "cat.jpg", groceries.Attachments[0].Name)
+
+	rc, err := groceries.Attachments[0].Open()
+	assert.NoError(t, err)
+	content, _ := io.ReadAll(rc)
+	assert.NoError(t, rc.Close())
+	assert.Equal(t, "jpeg bytes", string(content))
+
+	idea := batch.Notes[notes["Takeout/Keep/Idea.json"]]
+	assert.Equal(t, "note", idea.Note.Type)
+	assert.Nil(t, idea.Note.Title)
+	assert.Equal(t, "", idea.Note.Color)
+	assert.Equal(t, int8(1), idea.Note.IsTrashed)
+	assert.Equal(t, idea.Note.CreatedAt, idea.Note.UpdatedAt)
+	assert.Len(t, idea.Note.Items, 2)
+	assert.Len(t, *idea.Note.Items[0].Text, 1000)
+	assert.Len(t, *idea.Note.Items[1].Text, 500)
+
+	old := batch.Notes[notes["Takeout/Keep/Old.html"]]
+	assert.Equal(t, "Old", *old.Note.Title)
+	assert.Equal(t, "red", old.Note.Color)
+	assert.Equal(t, int8(1), old.Note.IsArchived)
+	assert.Equal(t, "2022-01-31 09:00:00", old.Note.UpdatedAt)
+	assert.Equal(t, "first line\nsecond line", *old.Note.Items[0].Text)
+	assert.Equal(t, []string{"Work"}, old.Labels)
+
+	assert.Equal(t, []string{"Home", "Unused", "Work"}, batch.Labels)
+
+	skipped := make(map[string]string)
+	for _, s := range batch.Skipped {
+		skipped[s.Source] = s.Reason
+	}
+
+	assert.Len(t, skipped, 3)
+	assert.Equal(t, "not a Keep note", skipped["Takeout/Keep/broken.json"])
+	assert.Equal(t, "not a Keep note", skipped["Takeout/archive_browser.html"])
+	assert.Equal(t, "attachment not found in the export", skipped["Takeout/Keep/lost.png"])
+}
+
+func TestParseHTMLList(t *testing.T) {
+	fsys := buildZip(t, map[string]string{
+		"Keep/List.html": `<div class="note"><div class="heading">2 Jan 2020, 15:04:05</div>` +
+			`<div class="content"><ul class="list">` +
+			`<li class="listitem checked"><span class="bullet">&#9745;</span><span class="text">milk</span></li>` +
+			`<li class="listitem"><span class="bullet">&#9744;</span><span class="text">eggs &amp; ham</span></li>` +
+			`</ul></div><div class="attachments"><ul><li><img src="pic.png"/></li></ul></div></div>`,
+		"Keep/pic.png": "png bytes",
+	})
+
+	batch, err := keep.NewImporter().Parse(fsys)
+	assert.NoError(t, err)
+	assert.Len(t, batch.Notes, 1)
+
+	note := batch.Notes[0]
+	assert.Equal(t, "list", note.Note.Type)
+	assert.Equal(t, "2020-01-02 15:04:05", note.Note.CreatedAt)
+	assert.Len(t, note.Note.Items, 2)
+	assert.Equal(t, "milk", *note.Note.Items[0].Text)
+	assert.Equal(t, int8(1), note.Note.Items[0].IsChecked)
+	assert.Equal(t, "eggs & ham", *note.Note.Items[1].Text)
+	assert.Equal(t, int8(0), note.Note.Items[1].IsChecked)
+	assert.Len(t, note.Attachments, 1)
+	assert.Empty(t, batch.Skipped)
+}
+
+func TestParseSingleFile(t *testing.T) {
+	r := strings.NewReader(`{"title": "Alone", "createdTimestampUsec": 1643619600000000}`)
+
+	fsys, err := importer.NewFS(r, r.Size(), "C:\\Downloads\\Alone.json")
+	assert.NoError(t, err)
+
+	batch, err := keep.NewImporter().Parse(fsys)
+	assert.NoError(t, err)
+	assert.Len(t, batch.Notes, 1)
+	assert.Equal(t, "Alone.json", batch.Notes[0].Source)
+}
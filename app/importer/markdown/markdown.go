@@ -0,0 +1,334 @@
+// Package markdown reads folders of Markdown notes, as exported by LibreNote and most Markdown editors
+package markdown
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"librenote/app/importer"
+	"librenote/app/model"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dateLayouts of the front matter dates, which are taken as UTC without a zone
+var dateLayouts = []string{
+	importer.TimeLayout,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// links matches the inline links and images, with or without <> around the destination
+var links = regexp.MustCompile(`!?\[[^\]]*\]\((?:<([^>]+)>|([^)\s]+))`)
+
+type markdownImporter struct{}
+
+func NewImporter() model.Importer {
+	return markdownImporter{}
+}
+
+func (markdownImporter) Name() string {
+	return "markdown"
+}
+
+// Parse reads the .md files, the front matter sets the title, color, labels (or tags), flags and dates.
+// Files linked with a relative path are imported as attachments.
+func (markdownImporter) Parse(fsys fs.FS) (*model.ImportBatch, error) {
+	batch := &model.ImportBatch{}
+	labels := make(map[string]bool)
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// like .git or .obsidian
+		if d.IsDir() && name != "." && strings.HasPrefix(d.Name(), ".") {
+			return fs.SkipDir
+		}
+
+		ext := strings.ToLower(path.Ext(name))
+		if d.IsDir() || (ext != ".md" && ext != ".markdown") {
+			return nil
+		}
+
+		b, err := importer.ReadFile(fsys, name)
+		if err != nil {
+			batch.Skipped = append(batch.Skipped, model.ImportSkip{Source: name, Reason: err.Error()})
+			return nil
+		}
+
+		var modTime time.Time
+		if info, err := d.Info(); err == nil {
+			modTime = info.ModTime()
+		}
+
+		imported := parseNote(name, string(b), modTime)
+		imported.Attachments, batch.Skipped = attachments(fsys, name, string(b), batch.Skipped)
+
+		for _, label := range imported.Labels {
+			labels[label] = true
+		}
+
+		batch.Notes = append(batch.Notes, imported)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for label := range labels {
+		batch.Labels = append(batch.Labels, label)
+	}
+
+	sort.Strings(batch.Labels)
+
+	return batch, nil
+}
+
+func parseNote(name, text string, modTime time.Time) model.ImportedNote {
+	meta, body := frontMatter(strings.ReplaceAll(text, "\r\n", "\n"))
+	body = dropAttachmentsSection(body)
+
+	title := meta.get("title")
+	if title == "" {
+		title, body = heading(body)
+	}
+
+	if title == "" {
+		title = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	}
+
+	updatedAt := parseDate(meta.get("updated_at", "updated", "modified"))
+	if updatedAt == "" && !modTime.IsZero() {
+		updatedAt = modTime.UTC().Format(importer.TimeLayout)
+	}
+
+	createdAt := parseDate(meta.get("created_at", "created", "date"))
+	if createdAt == "" {
+		createdAt = updatedAt
+	}
+
+	if updatedAt == "" {
+		updatedAt = createdAt
+	}
+
+	note := model.Note{
+		Title:      importer.Title(title),
+		Color:      importer.Color(meta.get("color")),
+		Type:       "note",
+		IsPinned:   importer.Flag(meta.flag("pinned")),
+		IsArchived: importer.Flag(meta.flag("archived")),
+		IsTrashed:  importer.Flag(meta.flag("trashed")),
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+	}
+
+	if items, ok := importer.TaskItems(body, createdAt); ok {
+		note.Type = "list"
+		note.Items = items
+	} else {
+		note.Body = importer.Truncate(strings.TrimSpace(body), importer.MaxBody)
+	}
+
+	labels := meta["labels"]
+	if len(labels) == 0 {
+		labels = meta["tags"]
+	}
+
+	return model.ImportedNote{Source: name, Note: note, Labels: importer.LabelNames(labels)}
+}
+
+// heading takes the title from a leading "# title" line
+func heading(body string) (string, string) {
+	trimmed := strings.TrimLeft(body, "\n")
+	line := trimmed
+
+	if i := strings.IndexByte(trimmed, '\n'); i >= 0 {
+		line = trimmed[:i]
+	}
+
+	if !strings.HasPrefix(line, "# ") {
+		return "", body
+	}
+
+	return strings.TrimSpace(line[2:]), strings.TrimPrefix(trimmed, line)
+}
+
+// dropAttachmentsSection removes the list of attachment links which ends the notes exported by LibreNote,
+// the files are imported from the links
+func dropAttachmentsSection(body string) string {
+	i := strings.LastIndex(body, "\n## Attachments\n")
+	if i < 0 {
+		return body
+	}
+
+	for _, line := range strings.Split(body[i+len("\n## Attachments\n"):], "\n") {
+		if line = strings.TrimSpace(line); line != "" && !(strings.HasPrefix(line, "- ") && links.MatchString(line)) {
+			return body
+		}
+	}
+
+	return body[:i+1]
+}
+
+// attachments imports the files linked with a relative path
+func attachments(fsys fs.FS, source, text string, skipped []model.ImportSkip) (
+	[]model.ImportedAttachment, []model.ImportSkip) {
+	var imported []model.ImportedAttachment
+
+	seen := make(map[string]bool)
+
+	for _, match := range links.FindAllStringSubmatch(text, -1) {
+		target := match[1] + match[2]
+		if i := strings.IndexAny(target, "#?"); i >= 0 {
+			target = target[:i]
+		}
+
+		if unescaped, err := url.PathUnescape(target); err == nil {
+			target = unescaped
+		}
+
+		if target == "" || strings.Contains(target, ":") || strings.HasPrefix(target, "/") {
+			continue
+		}
+
+		name := path.Join(path.Dir(source), target)
+		if seen[name] || strings.HasPrefix(name, "../") {
+			continue
+		}
+
+		seen[name] = true
+
+		attachment, err := importer.Attachment(fsys, name)
+		if errors.Is(err, importer.ErrFileTooLarge) {
+			skipped = append(skipped, model.ImportSkip{Source: name, Reason: err.Error()})
+			continue
+		}
+
+		if err != nil {
+			skipped = append(skipped, model.ImportSkip{Source: name, Reason: "attachment not found in the export"})
+			continue
+		}
+
+		imported = append(imported, attachment)
+	}
+
+	return imported, skipped
+}
+
+func parseDate(s string) string {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC().Format(importer.TimeLayout)
+		}
+	}
+
+	return ""
+}
+
+// meta is the front matter, every value is a list, scalars hold one value
+type meta map[string][]string
+
+// get returns the first value of the first key set
+func (m meta) get(keys ...string) string {
+	for _, key := range keys {
+		if values := m[key]; len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
+
+func (m meta) flag(key string) bool {
+	switch strings.ToLower(m.get(key)) {
+	case "true", "yes", "1":
+		return true
+	}
+
+	return false
+}
+
+// frontMatter reads the YAML subset the Markdown editors write: scalars, flow lists and block lists
+func frontMatter(text string) (meta, string) {
+	m := make(meta)
+
+	if !strings.HasPrefix(text, "---\n") {
+		return m, text
+	}
+
+	lines := strings.Split(text, "\n")
+
+	var key string
+
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+
+		if line == "---" || line == "..." {
+			return m, strings.Join(lines[i+1:], "\n")
+		}
+
+		if item := strings.TrimSpace(line); strings.HasPrefix(item, "- ") && key != "" {
+			m[key] = append(m[key], unquote(strings.TrimSpace(item[2:])))
+			continue
+		}
+
+		sep := strings.Index(line, ":")
+		if sep <= 0 || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(line[:sep]))
+		m[key] = values(key, strings.TrimSpace(line[sep+1:]))
+	}
+
+	// no closing line, so it wasn't a front matter
+	return make(meta), text
+}
+
+func values(key, value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		var list []string
+		if err := json.Unmarshal([]byte(value), &list); err == nil {
+			return list
+		}
+
+		value = value[1 : len(value)-1]
+	} else if key != "labels" && key != "tags" {
+		return []string{unquote(value)}
+	}
+
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		list = append(list, unquote(strings.TrimSpace(v)))
+	}
+
+	return list
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		var v string
+		if err := json.Unmarshal([]byte(s), &v); err == nil {
+			return v
+		}
+	}
+
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+
+	return s
+}
@@ -0,0 +1,81 @@
+package markdown_test
+
+import (
+	"librenote/app/importer/markdown"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	modTime := time.Date(2022, 2, 1, 8, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"notes/3-groceries.md": {Data: []byte(`---
+title: "Groceries: this week"
+type: list
+color: "dark blue"
+labels: ["Home", "Shopping"]
+pinned: true
+archived: false
+created_at: "2022-01-31 09:00:00"
+updated_at: "2022-01-31 10:00:00"
+---
+
+- [x] milk
+- [ ] eggs
+
+## Attachments
+
+- ![cat.png](<../attachments/3/5-cat.png>)
+`)},
+		"attachments/3/5-cat.png": {Data: []byte("png bytes")},
+		"Projects/plan.md": {Data: []byte("---\ntags:\n  - work\n  - 'big plans'\ncolor: magenta\n" +
+			"date: 2022-01-30\n---\n# The plan\n\nFirst paragraph.\n\n![missing](diagram.png) [site](https://example.com)\n"),
+			ModTime: modTime},
+		"Inbox.md":               {Data: []byte("just text, no title"), ModTime: modTime},
+		".obsidian/config.md":    {Data: []byte("ignored")},
+		"attachments/readme.txt": {Data: []byte("not markdown")},
+	}
+
+	batch, err := markdown.NewImporter().Parse(fsys)
+	assert.NoError(t, err)
+	assert.Len(t, batch.Notes, 3)
+
+	notes := make(map[string]int)
+	for i, n := range batch.Notes {
+		notes[n.Source] = i
+	}
+
+	groceries := batch.Notes[notes["notes/3-groceries.md"]]
+	assert.Equal(t, "Groceries: this week", *groceries.Note.Title)
+	assert.Equal(t, "list", groceries.Note.Type)
+	assert.Equal(t, "dark blue", groceries.Note.Color)
+	assert.Equal(t, int8(1), groceries.Note.IsPinned)
+	assert.Equal(t, "2022-01-31 09:00:00", groceries.Note.CreatedAt)
+	assert.Equal(t, "2022-01-31 10:00:00", groceries.Note.UpdatedAt)
+	assert.Len(t, groceries.Note.Items, 2)
+	assert.Equal(t, int8(1), groceries.Note.Items[0].IsChecked)
+	assert.Equal(t, []string{"Home", "Shopping"}, groceries.Labels)
+	assert.Len(t, groceries.Attachments, 1)
+	assert.Equal(t, "5-cat.png", groceries.Attachments[0].Name)
+
+	plan := batch.Notes[notes["Projects/plan.md"]]
+	assert.Equal(t, "The plan", *plan.Note.Title)
+	assert.Equal(t, "note", plan.Note.Type)
+	assert.Equal(t, "", plan.Note.Color)
+	assert.Equal(t, "2022-01-30 00:00:00", plan.Note.CreatedAt)
+	assert.Equal(t, "2022-02-01 08:00:00", plan.Note.UpdatedAt)
+	assert.Equal(t, []string{"work", "big plans"}, plan.Labels)
+	assert.Empty(t, plan.Note.Items)
+	assert.Contains(t, plan.Note.Body, "First paragraph.")
+
+	inbox := batch.Notes[notes["Inbox.md"]]
+	assert.Equal(t, "Inbox", *inbox.Note.Title)
+	assert.Equal(t, "just text, no title", inbox.Note.Body)
+
+	assert.Equal(t, []string{"Home", "Shopping", "big plans", "work"}, batch.Labels)
+	assert.Len(t, batch.Skipped, 1)
+	assert.Equal(t, "Projects/diagram.png", batch.Skipped[0].Source)
+}
@@ -0,0 +1,197 @@
+// Package standardnotes reads the decrypted backups of Standard Notes
+package standardnotes
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"librenote/app/importer"
+	"librenote/app/model"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+var errEncrypted = errors.New("encrypted backups aren't supported, export a decrypted backup")
+
+type backup struct {
+	Items []item `json:"items"`
+}
+
+type item struct {
+	UUID        string          `json:"uuid"`
+	ContentType string          `json:"content_type"`
+	Content     json.RawMessage `json:"content"`
+	CreatedAt   string          `json:"created_at"`
+	UpdatedAt   string          `json:"updated_at"`
+	Deleted     bool            `json:"deleted"`
+}
+
+type content struct {
+	Title      string `json:"title"`
+	Text       string `json:"text"`
+	Trashed    bool   `json:"trashed"`
+	References []struct {
+		UUID        string `json:"uuid"`
+		ContentType string `json:"content_type"`
+	} `json:"references"`
+	AppData struct {
+		Client struct {
+			Pinned          bool   `json:"pinned"`
+			Archived        bool   `json:"archived"`
+			ClientUpdatedAt string `json:"client_updated_at"`
+		} `json:"org.standardnotes.sn"`
+	} `json:"appData"`
+}
+
+type standardNotesImporter struct{}
+
+func NewImporter() model.Importer {
+	return standardNotesImporter{}
+}
+
+func (standardNotesImporter) Name() string {
+	return "standardnotes"
+}
+
+// Parse reads the .txt and .json files holding a backup, the tags become labels
+func (standardNotesImporter) Parse(fsys fs.FS) (*model.ImportBatch, error) {
+	batch := &model.ImportBatch{}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		if ext := strings.ToLower(path.Ext(name)); ext != ".txt" && ext != ".json" {
+			return nil
+		}
+
+		b, err := importer.ReadFile(fsys, name)
+		if err == nil {
+			err = readBackup(batch, name, b)
+		}
+
+		if err != nil {
+			batch.Skipped = append(batch.Skipped, model.ImportSkip{Source: name, Reason: err.Error()})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]bool)
+
+	for _, note := range batch.Notes {
+		for _, label := range note.Labels {
+			labels[label] = true
+		}
+	}
+
+	for label := range labels {
+		batch.Labels = append(batch.Labels, label)
+	}
+
+	sort.Strings(batch.Labels)
+
+	return batch, nil
+}
+
+func readBackup(batch *model.ImportBatch, name string, b []byte) error {
+	var bk backup
+	if err := json.Unmarshal(b, &bk); err != nil || bk.Items == nil {
+		return errors.New("not a Standard Notes backup")
+	}
+
+	var notes []*item
+
+	contents := make(map[string]*content)
+	noteLabels := make(map[string][]string)
+
+	for i := range bk.Items {
+		it := &bk.Items[i]
+		if it.Deleted || (it.ContentType != "Note" && it.ContentType != "Tag") {
+			continue
+		}
+
+		var c content
+		if err := json.Unmarshal(it.Content, &c); err != nil {
+			// encrypted items hold a string like 004:...
+			var encrypted string
+			if json.Unmarshal(it.Content, &encrypted) == nil {
+				return errEncrypted
+			}
+
+			batch.Skipped = append(batch.Skipped, model.ImportSkip{Source: name + "#" + it.UUID, Reason: err.Error()})
+
+			continue
+		}
+
+		if it.ContentType == "Tag" {
+			for _, ref := range c.References {
+				noteLabels[ref.UUID] = append(noteLabels[ref.UUID], c.Title)
+			}
+
+			continue
+		}
+
+		notes = append(notes, it)
+		contents[it.UUID] = &c
+	}
+
+	// the tags may come after their notes
+	for _, it := range notes {
+		batch.Notes = append(batch.Notes, toImported(name+"#"+it.UUID, it, contents[it.UUID], noteLabels[it.UUID]))
+	}
+
+	return nil
+}
+
+func toImported(source string, it *item, c *content, labels []string) model.ImportedNote {
+	createdAt := parseTime(it.CreatedAt)
+
+	updatedAt := parseTime(c.AppData.Client.ClientUpdatedAt)
+	if updatedAt == "" {
+		updatedAt = parseTime(it.UpdatedAt)
+	}
+
+	if createdAt == "" {
+		createdAt = updatedAt
+	}
+
+	if updatedAt == "" {
+		updatedAt = createdAt
+	}
+
+	note := model.Note{
+		Title:      importer.Title(c.Title),
+		Type:       "note",
+		IsPinned:   importer.Flag(c.AppData.Client.Pinned),
+		IsArchived: importer.Flag(c.AppData.Client.Archived),
+		IsTrashed:  importer.Flag(c.Trashed),
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+	}
+
+	// the checklist editors store the tasks as a Markdown task list
+	if items, ok := importer.TaskItems(c.Text, createdAt); ok {
+		note.Type = "list"
+		note.Items = items
+	} else {
+		note.Items = importer.TextItems(c.Text, createdAt)
+	}
+
+	return model.ImportedNote{Source: source, Note: note, Labels: importer.LabelNames(labels)}
+}
+
+func parseTime(s string) string {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return ""
+	}
+
+	return t.UTC().Format(importer.TimeLayout)
+}
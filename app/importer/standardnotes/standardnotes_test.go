@@ -0,0 +1,69 @@
+package standardnotes_test
+
+import (
+	"librenote/app/importer/standardnotes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const backup = `{
+  "version": "004",
+  "items": [
+    {
+      "uuid": "n1", "content_type": "Note",
+      "created_at": "2022-01-31T09:00:00.000Z", "updated_at": "2022-01-31T11:00:00.000Z",
+      "content": {"title": "Groceries", "text": "- [x] milk\n- [ ] eggs", "references": [],
+        "appData": {"org.standardnotes.sn": {"pinned": true, "client_updated_at": "2022-01-31T10:00:00.000Z"}}}
+    },
+    {
+      "uuid": "n2", "content_type": "Note", "created_at": "2022-01-30T09:00:00.000Z",
+      "content": {"title": "Journal", "text": "Dear diary", "trashed": true,
+        "appData": {"org.standardnotes.sn": {"archived": true}}}
+    },
+    {"uuid": "n3", "content_type": "Note", "deleted": true, "content": null},
+    {"uuid": "t1", "content_type": "Tag", "content": {"title": "Home", "references": [
+      {"uuid": "n1", "content_type": "Note"}, {"uuid": "n2", "content_type": "Note"}]}},
+    {"uuid": "p1", "content_type": "SN|UserPreferences", "content": {}}
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Standard Notes Backup and Import File.txt": {Data: []byte(backup)},
+		"Items/encrypted.json": {Data: []byte(`{"items": [{"uuid": "x", "content_type": "Note",
+			"content": "004:abc:def"}]}`)},
+		"other.txt": {Data: []byte("plain text")},
+	}
+
+	batch, err := standardnotes.NewImporter().Parse(fsys)
+	assert.NoError(t, err)
+	assert.Len(t, batch.Notes, 2)
+
+	groceries := batch.Notes[0]
+	assert.Equal(t, "Standard Notes Backup and Import File.txt#n1", groceries.Source)
+	assert.Equal(t, "list", groceries.Note.Type)
+	assert.Equal(t, int8(1), groceries.Note.IsPinned)
+	assert.Equal(t, "2022-01-31 09:00:00", groceries.Note.CreatedAt)
+	assert.Equal(t, "2022-01-31 10:00:00", groceries.Note.UpdatedAt)
+	assert.Len(t, groceries.Note.Items, 2)
+	assert.Equal(t, []string{"Home"}, groceries.Labels)
+
+	journal := batch.Notes[1]
+	assert.Equal(t, "note", journal.Note.Type)
+	assert.Equal(t, int8(1), journal.Note.IsArchived)
+	assert.Equal(t, int8(1), journal.Note.IsTrashed)
+	assert.Equal(t, "Dear diary", *journal.Note.Items[0].Text)
+
+	assert.Equal(t, []string{"Home"}, batch.Labels)
+
+	skipped := make(map[string]string)
+	for _, s := range batch.Skipped {
+		skipped[s.Source] = s.Reason
+	}
+
+	assert.Len(t, skipped, 2)
+	assert.Contains(t, skipped["Items/encrypted.json"], "encrypted backups")
+	assert.Equal(t, "not a Standard Notes backup", skipped["other.txt"])
+}
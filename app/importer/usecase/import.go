@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"librenote/app/model"
+	"librenote/app/response"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+var errLargerThanDeclared = errors.New("attachment larger than declared in the export")
+
+type importUsecase struct {
+	notes          model.NoteUsecase
+	attachments    model.AttachmentUsecase
+	labelRepo      model.LabelRepository
+	contextTimeout time.Duration
+	importers      map[string]model.Importer
+}
+
+// NewImportUsecase notes and attachments are stored through their usecases, so imports are checked,
+// revisioned and published as the notes created through the API
+func NewImportUsecase(notes model.NoteUsecase, attachments model.AttachmentUsecase, labelRepo model.LabelRepository,
+	timeout time.Duration, importers ...model.Importer) model.ImportUsecase {
+	u := &importUsecase{
+		notes:          notes,
+		attachments:    attachments,
+		labelRepo:      labelRepo,
+		contextTimeout: timeout,
+		importers:      make(map[string]model.Importer, len(importers)),
+	}
+
+	for _, i := range importers {
+		u.importers[i.Name()] = i
+	}
+
+	return u
+}
+
+func (u *importUsecase) Sources() []string {
+	sources := make([]string, 0, len(u.importers))
+	for name := range u.importers {
+		sources = append(sources, name)
+	}
+
+	sort.Strings(sources)
+
+	return sources
+}
+
+// Import reports the notes and attachments which can't be stored as skipped and goes on with the next ones
+func (u *importUsecase) Import(c context.Context, source string, userID int32, fsys fs.FS, dryRun bool) (
+	*model.ImportReport, error) {
+	i, ok := u.importers[source]
+	if !ok {
+		return nil, response.WrapError(fmt.Errorf("unknown import source %q, one of %s", source,
+			strings.Join(u.Sources(), ", ")), http.StatusNotFound)
+	}
+
+	batch, err := i.Parse(fsys)
+	if err != nil {
+		return nil, response.WrapError(err, http.StatusBadRequest)
+	}
+
+	labelIDs, err := u.labelIDs(c, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.ImportReport{
+		Source:   source,
+		DryRun:   dryRun,
+		Labels:   make([]string, 0),
+		Imported: make([]model.ImportItem, 0, len(batch.Notes)),
+		Skipped:  append(make([]model.ImportSkip, 0), batch.Skipped...),
+	}
+
+	for _, name := range batch.Labels {
+		if _, ok := labelIDs[name]; ok {
+			continue
+		}
+
+		report.Labels = append(report.Labels, name)
+
+		if !dryRun {
+			if labelIDs[name], err = u.createLabel(c, userID, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Strings(report.Labels)
+
+	for idx := range batch.Notes {
+		// the client went away or the command was stopped
+		if err = c.Err(); err != nil {
+			return nil, err
+		}
+
+		imported := &batch.Notes[idx]
+
+		if !dryRun {
+			if err = u.createNote(c, userID, imported, labelIDs); err != nil {
+				report.Skipped = append(report.Skipped, model.ImportSkip{Source: imported.Source, Reason: err.Error()})
+				continue
+			}
+		}
+
+		report.Notes++
+		report.Items += len(imported.Note.Items)
+		report.Imported = append(report.Imported, model.ImportItem{
+			Source: imported.Source,
+			Title:  imported.Note.Title,
+			NoteID: imported.Note.ID,
+		})
+
+		if dryRun {
+			report.Attachments += len(imported.Attachments)
+			continue
+		}
+
+		for _, attachment := range imported.Attachments {
+			if err = u.upload(c, imported.Note.ID, userID, attachment); err != nil {
+				report.Skipped = append(report.Skipped, model.ImportSkip{Source: attachment.Name, Reason: err.Error()})
+				continue
+			}
+
+			report.Attachments++
+		}
+	}
+
+	return report, nil
+}
+
+func (u *importUsecase) labelIDs(c context.Context, userID int32) (map[string]int32, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	labels, err := u.labelRepo.FetchLabels(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// imported labels are top level labels, the names of sub labels may repeat
+	ids := make(map[string]int32, len(labels))
+	for _, label := range labels {
+		if label.ParentID == nil {
+			ids[label.Name] = label.ID
+		}
+	}
+
+	return ids, nil
+}
+
+func (u *importUsecase) createLabel(c context.Context, userID int32, name string) (int32, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	nowTime := time.Now().UTC().Format(timeLayout)
+	label := &model.Label{Name: name, UserID: userID, CreatedAt: nowTime, UpdatedAt: nowTime}
+
+	if err := u.labelRepo.CreateLabel(ctx, label); err != nil {
+		return 0, err
+	}
+
+	return label.ID, nil
+}
+
+func (u *importUsecase) createNote(c context.Context, userID int32, imported *model.ImportedNote,
+	labelIDs map[string]int32) error {
+	note := &imported.Note
+	note.UserID = userID
+
+	if note.CreatedAt == "" {
+		nowTime := time.Now().UTC().Format(timeLayout)
+		note.CreatedAt = nowTime
+		note.UpdatedAt = nowTime
+
+		for i := range note.Items {
+			note.Items[i].CreatedAt = nowTime
+		}
+	}
+
+	if err := u.notes.Create(c, note); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	for _, name := range imported.Labels {
+		if err := u.labelRepo.AddNoteLabel(ctx, note.ID, labelIDs[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// upload copies the attachment to a temporary file, as the attachments need a seekable content. The copy stops
+// past the size the importer read from the export, a file growing once uncompressed isn't stored
+func (u *importUsecase) upload(c context.Context, noteID, userID int32, attachment model.ImportedAttachment) error {
+	rc, err := attachment.Open()
+	if err != nil {
+		return err
+	}
+
+	defer rc.Close()
+
+	tmp, err := os.CreateTemp("", "librenote-import-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	n, err := io.Copy(tmp, io.LimitReader(rc, attachment.Size+1))
+	if err != nil {
+		return err
+	}
+
+	if n > attachment.Size {
+		return errLargerThanDeclared
+	}
+
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = u.attachments.Upload(c, noteID, userID, &model.Upload{
+		Name:    attachment.Name,
+		Size:    n,
+		Content: tmp,
+	})
+
+	return err
+}
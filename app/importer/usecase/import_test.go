@@ -0,0 +1,180 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"librenote/app/importer"
+	"librenote/app/importer/keep"
+	"librenote/app/importer/markdown"
+	"librenote/app/importer/usecase"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func keepExport(t *testing.T) fs.FS {
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for name, content := range map[string]string{
+		"Takeout/Keep/Groceries.json": `{"title": "Groceries", "createdTimestampUsec": 1643619600000000,
+			"listContent": [{"text": "milk", "isChecked": true}, {"text": "eggs"}],
+			"labels": [{"name": "Home"}, {"name": "Shopping"}],
+			"attachments": [{"filePath": "cat.png", "mimetype": "image/png"}]}`,
+		"Takeout/Keep/cat.png":    "png bytes",
+		"Takeout/Keep/Empty.json": `{"textContent": "hello", "createdTimestampUsec": 1643619600000000}`,
+	} {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, zw.Close())
+
+	fsys, err := importer.NewFS(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "takeout.zip")
+	assert.NoError(t, err)
+
+	return fsys
+}
+
+func newImportUsecase(notes model.NoteUsecase, attachments model.AttachmentUsecase,
+	labelRepo model.LabelRepository) model.ImportUsecase {
+	return usecase.NewImportUsecase(notes, attachments, labelRepo, time.Second*2, keep.NewImporter(),
+		markdown.NewImporter())
+}
+
+func TestImportKeep(t *testing.T) {
+	t.Run("dry-run", func(t *testing.T) {
+		mockNotes := new(mocks.NoteUsecase)
+		mockAttachments := new(mocks.AttachmentUsecase)
+		mockLabelRepo := new(mocks.LabelRepository)
+		mockLabelRepo.On("FetchLabels", mock.Anything, int32(1)).
+			Return([]model.Label{{ID: 5, Name: "Home", UserID: 1}}, nil).Once()
+
+		u := newImportUsecase(mockNotes, mockAttachments, mockLabelRepo)
+		report, err := u.Import(context.TODO(), "keep", 1, keepExport(t), true)
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 2, report.Notes)
+		assert.Equal(t, 3, report.Items)
+		assert.Equal(t, 1, report.Attachments)
+		assert.Equal(t, []string{"Shopping"}, report.Labels)
+		assert.Len(t, report.Imported, 2)
+
+		mockNotes.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mockLabelRepo.AssertExpectations(t)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		mockNotes := new(mocks.NoteUsecase)
+		mockAttachments := new(mocks.AttachmentUsecase)
+		mockLabelRepo := new(mocks.LabelRepository)
+		mockLabelRepo.On("FetchLabels", mock.Anything, int32(1)).
+			Return([]model.Label{{ID: 5, Name: "Home", UserID: 1}}, nil).Once()
+		mockLabelRepo.On("CreateLabel", mock.Anything, mock.MatchedBy(func(l *model.Label) bool {
+			return l.Name == "Shopping" && l.UserID == 1
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*model.Label).ID = 6
+		}).Return(nil).Once()
+
+		noteID := int32(10)
+		mockNotes.On("Create", mock.Anything, mock.MatchedBy(func(n *model.Note) bool {
+			return n.UserID == 1 && n.Type == "list" && n.CreatedAt == "2022-01-31 09:00:00"
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*model.Note).ID = noteID
+			noteID++
+		}).Return(nil).Once()
+		mockNotes.On("Create", mock.Anything, mock.AnythingOfType("*model.Note")).
+			Return(errors.New("database is locked")).Once()
+
+		mockLabelRepo.On("AddNoteLabel", mock.Anything, mock.Anything, int32(5)).Return(nil).Once()
+		mockLabelRepo.On("AddNoteLabel", mock.Anything, mock.Anything, int32(6)).Return(nil).Once()
+		mockAttachments.On("Upload", mock.Anything, mock.Anything, int32(1), mock.MatchedBy(func(up *model.Upload) bool {
+			content, err := io.ReadAll(up.Content)
+			return up.Name == "cat.png" && up.Size == 9 && err == nil && string(content) == "png bytes"
+		})).Return(nil, response.WrapError(errors.New("storage quota exceeded"),
+			http.StatusRequestEntityTooLarge)).Once()
+
+		u := newImportUsecase(mockNotes, mockAttachments, mockLabelRepo)
+		report, err := u.Import(context.TODO(), "keep", 1, keepExport(t), false)
+		assert.NoError(t, err)
+		assert.False(t, report.DryRun)
+		assert.Equal(t, 1, report.Notes)
+		assert.Equal(t, 0, report.Attachments)
+		assert.Equal(t, []model.ImportItem{{Source: "Takeout/Keep/Groceries.json", Title: report.Imported[0].Title,
+			NoteID: 10}}, report.Imported)
+		assert.Equal(t, []model.ImportSkip{
+			{Source: "Takeout/Keep/Empty.json", Reason: "database is locked"},
+			{Source: "cat.png", Reason: "storage quota exceeded"},
+		}, report.Skipped)
+
+		mockNotes.AssertExpectations(t)
+		mockLabelRepo.AssertExpectations(t)
+		mockAttachments.AssertExpectations(t)
+	})
+
+	t.Run("unknown-source", func(t *testing.T) {
+		mockLabelRepo := new(mocks.LabelRepository)
+
+		u := newImportUsecase(new(mocks.NoteUsecase), new(mocks.AttachmentUsecase), mockLabelRepo)
+		assert.Equal(t, []string{"keep", "markdown"}, u.Sources())
+
+		_, err := u.Import(context.TODO(), "onenote", 1, keepExport(t), true)
+		assert.EqualError(t, err, `unknown import source "onenote", one of keep, markdown`)
+		mockLabelRepo.AssertNotCalled(t, "FetchLabels", mock.Anything, mock.Anything)
+	})
+}
+
+func TestImportAttachmentSize(t *testing.T) {
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	w, err := zw.Create("Takeout/Keep/Cat.json")
+	assert.NoError(t, err)
+
+	_, err = w.Write([]byte(`{"textContent": "cat", "createdTimestampUsec": 1643619600000000,
+		"attachments": [{"filePath": "cat.png"}]}`))
+	assert.NoError(t, err)
+
+	// the header understates the size of the content
+	w, err = zw.CreateRaw(&zip.FileHeader{Name: "Takeout/Keep/cat.png", Method: zip.Store, UncompressedSize64: 2,
+		CompressedSize64: 9})
+	assert.NoError(t, err)
+
+	_, err = w.Write([]byte("png bytes"))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+
+	fsys, err := importer.NewFS(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "takeout.zip")
+	assert.NoError(t, err)
+
+	mockNotes := new(mocks.NoteUsecase)
+	mockAttachments := new(mocks.AttachmentUsecase)
+	mockLabelRepo := new(mocks.LabelRepository)
+	mockLabelRepo.On("FetchLabels", mock.Anything, int32(1)).Return([]model.Label{}, nil).Once()
+	mockNotes.On("Create", mock.Anything, mock.AnythingOfType("*model.Note")).Return(nil).Once()
+
+	u := newImportUsecase(mockNotes, mockAttachments, mockLabelRepo)
+	report, err := u.Import(context.TODO(), "keep", 1, fsys, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Notes)
+	assert.Equal(t, 0, report.Attachments)
+	assert.Len(t, report.Skipped, 1)
+	assert.Equal(t, "cat.png", report.Skipped[0].Source)
+
+	mockAttachments.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
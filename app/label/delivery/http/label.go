@@ -0,0 +1,252 @@
+package http
+
+import (
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/app/validation"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// LabelHandler represent the http handler for label
+type LabelHandler struct {
+	LUseCase model.LabelUsecase
+}
+
+func NewLabelHandler(e *echo.Echo, us model.LabelUsecase) {
+	handler := &LabelHandler{
+		LUseCase: us,
+	}
+
+	labels := e.Group("/api/v1/labels")
+	_ = middlewares.AttachJwtToGroup(labels)
+
+	labels.GET("", handler.FetchLabels)
+	labels.POST("", handler.CreateLabel)
+	labels.GET("/tree", handler.FetchTree)
+	labels.GET("/:id", handler.GetLabel)
+	labels.PUT("/:id", handler.UpdateLabel)
+	labels.DELETE("/:id", handler.DeleteLabel)
+	labels.PUT("/:id/parent", handler.MoveLabel)
+	labels.GET("/:id/notes", handler.FetchNotes)
+}
+
+func (l *LabelHandler) FetchLabels(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	labels, err := l.LUseCase.Fetch(ctx, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", labels))
+}
+
+func (l *LabelHandler) FetchTree(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tree, err := l.LUseCase.Tree(ctx, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", tree))
+}
+
+func (l *LabelHandler) CreateLabel(c echo.Context) error {
+	var lReq createLabelReq
+
+	err := c.Bind(&lReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&lReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	label := model.Label{
+		Name:      lReq.Name,
+		UserID:    middlewares.GetUserID(c),
+		ParentID:  lReq.ParentID,
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+	}
+
+	ctx := c.Request().Context()
+
+	err = l.LUseCase.Create(ctx, &label)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("label created", label))
+}
+
+func (l *LabelHandler) GetLabel(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	label, err := l.LUseCase.Get(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", label))
+}
+
+func (l *LabelHandler) UpdateLabel(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var lReq updateLabelReq
+
+	err = c.Bind(&lReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&lReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	label := model.Label{
+		ID:        id,
+		Name:      lReq.Name,
+		UserID:    middlewares.GetUserID(c),
+		UpdatedAt: time.Now().UTC().Format("2006-01-02 15:04:05"),
+	}
+
+	ctx := c.Request().Context()
+
+	err = l.LUseCase.Update(ctx, &label)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("updated successfully", label))
+}
+
+// MoveLabel moves the label with its sub labels under another label
+func (l *LabelHandler) MoveLabel(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var lReq moveLabelReq
+
+	err = c.Bind(&lReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&lReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	label := model.Label{
+		ID:        id,
+		UserID:    middlewares.GetUserID(c),
+		ParentID:  lReq.ParentID,
+		UpdatedAt: time.Now().UTC().Format("2006-01-02 15:04:05"),
+	}
+
+	ctx := c.Request().Context()
+
+	err = l.LUseCase.Move(ctx, &label)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("label moved", label))
+}
+
+func (l *LabelHandler) DeleteLabel(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	err = l.LUseCase.Delete(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// FetchNotes lists the notes with the label or any of its sub labels
+func (l *LabelHandler) FetchNotes(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var fReq fetchLabelNotesReq
+
+	err = c.Bind(&fReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&fReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	cfg := config.Get().App
+
+	if fReq.Page == 0 {
+		fReq.Page = 1
+	}
+
+	if fReq.PageSize == 0 {
+		fReq.PageSize = cfg.DefaultPageSize
+	}
+
+	if cfg.MaxPageSize > 0 && fReq.PageSize > cfg.MaxPageSize {
+		fReq.PageSize = cfg.MaxPageSize
+	}
+
+	ctx := c.Request().Context()
+
+	notes, count, err := l.LUseCase.FetchNotes(ctx, id, middlewares.GetUserID(c), fReq.Page, fReq.PageSize)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondPage("request success", notes, count, fReq.Page, fReq.PageSize))
+}
@@ -0,0 +1,195 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	labelHttp "librenote/app/label/delivery/http"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var BaseURLV1 = "/api/v1"
+
+func buildEchoAuthorizedRequest(t *testing.T, method, path, token string, payload io.Reader) (
+	echo.Context, *httptest.ResponseRecorder) {
+	var req *http.Request
+
+	var err error
+
+	if payload != nil {
+		req, err = http.NewRequest(method, path, payload)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	} else {
+		req, err = http.NewRequest(method, path, nil)
+	}
+
+	assert.NoError(t, err)
+
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+	res := httptest.NewRecorder()
+	e := echo.New()
+	ctx := e.NewContext(req, res)
+
+	return ctx, res
+}
+
+// nolint:unparam
+func getToken(userID int32) string {
+	jwtCfg := config.Get().Jwt
+	claims := &middlewares.JwtCustomClaims{
+		UserID: userID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(jwtCfg.ExpireTime).Unix(),
+		},
+	}
+	unsignedToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token, _ := unsignedToken.SignedString([]byte(jwtCfg.SecretKey))
+
+	return token
+}
+
+func attachJWTMiddleware(hfc echo.HandlerFunc) echo.HandlerFunc {
+	mhfc := middleware.JWTWithConfig(
+		middleware.JWTConfig{
+			Claims:     &middlewares.JwtCustomClaims{},
+			SigningKey: []byte(config.Get().Jwt.SecretKey),
+		})(hfc)
+
+	return mhfc
+}
+
+func TestCreateLabel(t *testing.T) {
+	endPoint := BaseURLV1 + "/labels"
+
+	mockUsecase := new(mocks.LabelUsecase)
+	mockUsecase.On("Create", mock.Anything, mock.MatchedBy(func(l *model.Label) bool {
+		return l.UserID == 1 && l.Name == "projects" && *l.ParentID == 2
+	})).Return(nil).Once()
+
+	handler := labelHttp.LabelHandler{
+		LUseCase: mockUsecase,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		body := `{"name": "projects", "parent_id": 2}`
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+		handle := attachJWTMiddleware(handler.CreateLabel)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"missing-name": `{"parent_id": 2}`,
+			"path-name":    `{"name": "work/projects"}`,
+			"zero-parent":  `{"name": "projects", "parent_id": 0}`,
+		} {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+			handle := attachJWTMiddleware(handler.CreateLabel)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, http.StatusBadRequest, res.Code, name)
+		}
+	})
+}
+
+func TestMoveLabel(t *testing.T) {
+	endPoint := BaseURLV1 + "/labels/:id/parent"
+
+	mockUsecase := new(mocks.LabelUsecase)
+	mockUsecase.On("Move", mock.Anything, mock.MatchedBy(func(l *model.Label) bool {
+		return l.ID == 2 && l.ParentID == nil
+	})).Return(nil).Once()
+	mockUsecase.On("Move", mock.Anything, mock.MatchedBy(func(l *model.Label) bool {
+		return l.ID == 2 && l.ParentID != nil && *l.ParentID == 3
+	})).Return(response.WrapError(errors.New("cycle"), http.StatusConflict)).Once()
+
+	handler := labelHttp.LabelHandler{
+		LUseCase: mockUsecase,
+	}
+
+	for body, code := range map[string]int{`{"parent_id": null}`: http.StatusOK, `{"parent_id": 3}`: http.StatusConflict} {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.PUT, endPoint, getToken(1), strings.NewReader(body))
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("2")
+		handle := attachJWTMiddleware(handler.MoveLabel)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, code, res.Code, body)
+	}
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestFetchTree(t *testing.T) {
+	endPoint := BaseURLV1 + "/labels/tree"
+	parentID := int32(1)
+	tree := []model.LabelNode{{
+		Label:    model.Label{ID: 1, Name: "work", UserID: 1},
+		Children: []model.LabelNode{{Label: model.Label{ID: 2, Name: "projects", UserID: 1, ParentID: &parentID}}},
+	}}
+
+	mockUsecase := new(mocks.LabelUsecase)
+	mockUsecase.On("Tree", mock.Anything, int32(1)).Return(tree, nil).Once()
+
+	handler := labelHttp.LabelHandler{
+		LUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+	handle := attachJWTMiddleware(handler.FetchTree)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var r struct {
+		Result []model.LabelNode `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+	assert.Equal(t, tree, r.Result)
+}
+
+func TestFetchLabelNotes(t *testing.T) {
+	endPoint := BaseURLV1 + "/labels/:id/notes?page=1&page_size=5"
+
+	mockUsecase := new(mocks.LabelUsecase)
+	mockUsecase.On("FetchNotes", mock.Anything, int32(2), int32(1), 1, 5).
+		Return([]model.Note{{ID: 4, UserID: 1, Type: "note"}}, 1, nil).Once()
+
+	handler := labelHttp.LabelHandler{
+		LUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("2")
+	handle := attachJWTMiddleware(handler.FetchNotes)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var r response.Response
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+	assert.Equal(t, 1, *r.Count)
+
+	mockUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,21 @@
+package http
+
+type createLabelReq struct {
+	// a slash separates the labels of a path like work/projects
+	Name     string `json:"name" validate:"required,max=50,excludes=/"`
+	ParentID *int32 `json:"parent_id" validate:"omitempty,min=1"`
+}
+
+type updateLabelReq struct {
+	Name string `json:"name" validate:"required,max=50,excludes=/"`
+}
+
+type moveLabelReq struct {
+	// null moves the label to the top level
+	ParentID *int32 `json:"parent_id" validate:"omitempty,min=1"`
+}
+
+type fetchLabelNotesReq struct {
+	Page     int `json:"page" query:"page" validate:"omitempty,min=1"`
+	PageSize int `json:"page_size" query:"page_size" validate:"omitempty,min=1"`
+}
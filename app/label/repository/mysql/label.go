@@ -0,0 +1,222 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"librenote/app/model"
+)
+
+type labelRepository struct {
+	db *sql.DB
+}
+
+func NewMysqlLabelRepository(db *sql.DB) model.LabelRepository {
+	return &labelRepository{
+		db: db,
+	}
+}
+
+const createLabel = `INSERT INTO labels (
+  name, user_id, parent_id, is_trashed, created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?)
+`
+
+func (r *labelRepository) CreateLabel(ctx context.Context, label *model.Label) error {
+	res, err := r.db.ExecContext(ctx, createLabel,
+		label.Name,
+		label.UserID,
+		label.ParentID,
+		label.IsTrashed,
+		label.CreatedAt,
+		label.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	label.ID = int32(id)
+
+	return nil
+}
+
+const getLabel = `SELECT id, name, user_id, parent_id, is_trashed, created_at, updated_at FROM labels
+WHERE id = ? AND user_id = ? LIMIT 1`
+
+func (r *labelRepository) GetLabel(ctx context.Context, id, userID int32) (model.Label, error) {
+	var i model.Label
+	err := r.db.QueryRowContext(ctx, getLabel, id, userID).Scan(
+		&i.ID,
+		&i.Name,
+		&i.UserID,
+		&i.ParentID,
+		&i.IsTrashed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+
+	return i, err
+}
+
+const fetchLabels = `SELECT id, name, user_id, parent_id, is_trashed, created_at, updated_at FROM labels
+WHERE user_id = ? ORDER BY name, id`
+
+func (r *labelRepository) FetchLabels(ctx context.Context, userID int32) ([]model.Label, error) {
+	rows, err := r.db.QueryContext(ctx, fetchLabels, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	labels := make([]model.Label, 0)
+
+	for rows.Next() {
+		var i model.Label
+		if err = rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.UserID,
+			&i.ParentID,
+			&i.IsTrashed,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		labels = append(labels, i)
+	}
+
+	return labels, rows.Err()
+}
+
+const updateLabel = `UPDATE labels
+SET name = ?,
+parent_id = ?,
+updated_at = ?
+WHERE id = ? AND user_id = ?
+`
+
+func (r *labelRepository) UpdateLabel(ctx context.Context, label *model.Label) error {
+	res, err := r.db.ExecContext(ctx, updateLabel,
+		label.Name,
+		label.ParentID,
+		label.UpdatedAt,
+		label.ID,
+		label.UserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return errors.New("nothing changed")
+	}
+
+	return nil
+}
+
+const getLabelParentID = `SELECT parent_id FROM labels WHERE id = ? AND user_id = ? LIMIT 1`
+
+const moveSubLabels = `UPDATE labels SET parent_id = ? WHERE parent_id = ?`
+
+const deleteLabelNotes = `DELETE FROM notes_labels WHERE label_id = ?`
+
+const deleteLabelTemplates = `DELETE FROM templates_labels WHERE label_id = ?`
+
+const deleteLabel = `DELETE FROM labels WHERE id = ?`
+
+func (r *labelRepository) DeleteLabel(ctx context.Context, id, userID int32) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	var parentID *int32
+	if err = tx.QueryRowContext(ctx, getLabelParentID, id, userID).Scan(&parentID); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, moveSubLabels, parentID, id); err != nil {
+		return err
+	}
+
+	for _, query := range []string{deleteLabelNotes, deleteLabelTemplates, deleteLabel} {
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+const fetchNoteLabelIDs = `SELECT nl.label_id FROM notes_labels nl
+JOIN labels l ON l.id = nl.label_id WHERE nl.note_id = ? AND l.user_id = ? ORDER BY l.name`
+
+func (r *labelRepository) FetchNoteLabelIDs(ctx context.Context, noteID, userID int32) ([]int32, error) {
+	rows, err := r.db.QueryContext(ctx, fetchNoteLabelIDs, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	labelIDs := make([]int32, 0)
+
+	for rows.Next() {
+		var id int32
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		labelIDs = append(labelIDs, id)
+	}
+
+	return labelIDs, rows.Err()
+}
+
+const addNoteLabel = `INSERT IGNORE INTO notes_labels (note_id, label_id) VALUES (?, ?)`
+
+func (r *labelRepository) AddNoteLabel(ctx context.Context, noteID, labelID int32) error {
+	_, err := r.db.ExecContext(ctx, addNoteLabel, noteID, labelID)
+
+	return err
+}
+
+const fetchNotesLabels = `SELECT nl.note_id, nl.label_id FROM notes_labels nl
+JOIN labels l ON l.id = nl.label_id WHERE l.user_id = ? ORDER BY nl.note_id, l.name`
+
+func (r *labelRepository) FetchNotesLabels(ctx context.Context, userID int32) ([]model.NotesLabel, error) {
+	rows, err := r.db.QueryContext(ctx, fetchNotesLabels, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	notesLabels := make([]model.NotesLabel, 0)
+
+	for rows.Next() {
+		var i model.NotesLabel
+		if err = rows.Scan(&i.NoteID, &i.LabelID); err != nil {
+			return nil, err
+		}
+
+		notesLabels = append(notesLabels, i)
+	}
+
+	return notesLabels, rows.Err()
+}
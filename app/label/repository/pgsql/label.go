@@ -0,0 +1,212 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"librenote/app/model"
+)
+
+type labelRepository struct {
+	db *sql.DB
+}
+
+func NewPgsqlLabelRepository(db *sql.DB) model.LabelRepository {
+	return &labelRepository{
+		db: db,
+	}
+}
+
+const createLabel = `INSERT INTO labels (
+  name, user_id, parent_id, is_trashed, created_at, updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id
+`
+
+func (r *labelRepository) CreateLabel(ctx context.Context, label *model.Label) error {
+	return r.db.QueryRowContext(ctx, createLabel,
+		label.Name,
+		label.UserID,
+		label.ParentID,
+		label.IsTrashed,
+		label.CreatedAt,
+		label.UpdatedAt,
+	).Scan(&label.ID)
+}
+
+const getLabel = `SELECT id, name, user_id, parent_id, is_trashed, created_at::text, updated_at::text FROM labels
+WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+func (r *labelRepository) GetLabel(ctx context.Context, id, userID int32) (model.Label, error) {
+	var i model.Label
+	err := r.db.QueryRowContext(ctx, getLabel, id, userID).Scan(
+		&i.ID,
+		&i.Name,
+		&i.UserID,
+		&i.ParentID,
+		&i.IsTrashed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+
+	return i, err
+}
+
+const fetchLabels = `SELECT id, name, user_id, parent_id, is_trashed, created_at::text, updated_at::text FROM labels
+WHERE user_id = $1 ORDER BY name, id`
+
+func (r *labelRepository) FetchLabels(ctx context.Context, userID int32) ([]model.Label, error) {
+	rows, err := r.db.QueryContext(ctx, fetchLabels, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	labels := make([]model.Label, 0)
+
+	for rows.Next() {
+		var i model.Label
+		if err = rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.UserID,
+			&i.ParentID,
+			&i.IsTrashed,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		labels = append(labels, i)
+	}
+
+	return labels, rows.Err()
+}
+
+const updateLabel = `UPDATE labels
+SET name = $1,
+parent_id = $2,
+updated_at = $3
+WHERE id = $4 AND user_id = $5
+`
+
+func (r *labelRepository) UpdateLabel(ctx context.Context, label *model.Label) error {
+	res, err := r.db.ExecContext(ctx, updateLabel,
+		label.Name,
+		label.ParentID,
+		label.UpdatedAt,
+		label.ID,
+		label.UserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return errors.New("nothing changed")
+	}
+
+	return nil
+}
+
+const getLabelParentID = `SELECT parent_id FROM labels WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+const moveSubLabels = `UPDATE labels SET parent_id = $1 WHERE parent_id = $2`
+
+const deleteLabelNotes = `DELETE FROM notes_labels WHERE label_id = $1`
+
+const deleteLabelTemplates = `DELETE FROM templates_labels WHERE label_id = $1`
+
+const deleteLabel = `DELETE FROM labels WHERE id = $1`
+
+func (r *labelRepository) DeleteLabel(ctx context.Context, id, userID int32) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	var parentID *int32
+	if err = tx.QueryRowContext(ctx, getLabelParentID, id, userID).Scan(&parentID); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, moveSubLabels, parentID, id); err != nil {
+		return err
+	}
+
+	for _, query := range []string{deleteLabelNotes, deleteLabelTemplates, deleteLabel} {
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+const fetchNoteLabelIDs = `SELECT nl.label_id FROM notes_labels nl
+JOIN labels l ON l.id = nl.label_id WHERE nl.note_id = $1 AND l.user_id = $2 ORDER BY l.name`
+
+func (r *labelRepository) FetchNoteLabelIDs(ctx context.Context, noteID, userID int32) ([]int32, error) {
+	rows, err := r.db.QueryContext(ctx, fetchNoteLabelIDs, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	labelIDs := make([]int32, 0)
+
+	for rows.Next() {
+		var id int32
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		labelIDs = append(labelIDs, id)
+	}
+
+	return labelIDs, rows.Err()
+}
+
+const addNoteLabel = `INSERT INTO notes_labels (note_id, label_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+func (r *labelRepository) AddNoteLabel(ctx context.Context, noteID, labelID int32) error {
+	_, err := r.db.ExecContext(ctx, addNoteLabel, noteID, labelID)
+
+	return err
+}
+
+const fetchNotesLabels = `SELECT nl.note_id, nl.label_id FROM notes_labels nl
+JOIN labels l ON l.id = nl.label_id WHERE l.user_id = $1 ORDER BY nl.note_id, l.name`
+
+func (r *labelRepository) FetchNotesLabels(ctx context.Context, userID int32) ([]model.NotesLabel, error) {
+	rows, err := r.db.QueryContext(ctx, fetchNotesLabels, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	notesLabels := make([]model.NotesLabel, 0)
+
+	for rows.Next() {
+		var i model.NotesLabel
+		if err = rows.Scan(&i.NoteID, &i.LabelID); err != nil {
+			return nil, err
+		}
+
+		notesLabels = append(notesLabels, i)
+	}
+
+	return notesLabels, rows.Err()
+}
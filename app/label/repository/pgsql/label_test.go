@@ -0,0 +1,186 @@
+package pgsql_test
+
+import (
+	"context"
+	"database/sql"
+	labelRepo "librenote/app/label/repository/pgsql"
+	"librenote/app/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateLabel(t *testing.T) {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	parentID := int32(1)
+	l := &model.Label{Name: "Work", UserID: 1, ParentID: &parentID, CreatedAt: nowTime, UpdatedAt: nowTime}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("INSERT INTO labels").
+		WithArgs(l.Name, l.UserID, l.ParentID, l.IsTrashed, l.CreatedAt, l.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	lr := labelRepo.NewPgsqlLabelRepository(db)
+	assert.NoError(t, lr.CreateLabel(context.TODO(), l))
+	assert.Equal(t, int32(2), l.ID)
+}
+
+func TestFetchLabels(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	rows := sqlmock.NewRows([]string{"id", "name", "user_id", "parent_id", "is_trashed", "created_at", "updated_at"}).
+		AddRow(2, "Home", 1, nil, 0, nowTime, nowTime).
+		AddRow(1, "Work", 1, 2, 0, nowTime, nowTime)
+
+	mock.ExpectQuery("SELECT (.+) FROM labels WHERE").WithArgs(int32(1)).WillReturnRows(rows)
+
+	lr := labelRepo.NewPgsqlLabelRepository(db)
+	labels, err := lr.FetchLabels(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+	assert.Equal(t, "Home", labels[0].Name)
+	assert.Nil(t, labels[0].ParentID)
+	assert.Equal(t, int32(2), *labels[1].ParentID)
+}
+
+func TestAddNoteLabel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO notes_labels (.+) ON CONFLICT DO NOTHING").WithArgs(int32(3), int32(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	lr := labelRepo.NewPgsqlLabelRepository(db)
+	assert.NoError(t, lr.AddNoteLabel(context.TODO(), 3, 2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchNotesLabels(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"note_id", "label_id"}).AddRow(3, 2).AddRow(3, 1).AddRow(4, 2)
+
+	mock.ExpectQuery("SELECT (.+) FROM notes_labels (.+) WHERE l.user_id").WithArgs(int32(1)).WillReturnRows(rows)
+
+	lr := labelRepo.NewPgsqlLabelRepository(db)
+	notesLabels, err := lr.FetchNotesLabels(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.NotesLabel{
+		{NoteID: 3, LabelID: 2}, {NoteID: 3, LabelID: 1}, {NoteID: 4, LabelID: 2},
+	}, notesLabels)
+}
+
+func TestFetchNoteLabelIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"label_id"}).AddRow(2).AddRow(1)
+
+	mock.ExpectQuery("SELECT (.+) FROM notes_labels (.+) WHERE nl.note_id").WithArgs(int32(3), int32(1)).
+		WillReturnRows(rows)
+
+	lr := labelRepo.NewPgsqlLabelRepository(db)
+	labelIDs, err := lr.FetchNoteLabelIDs(context.TODO(), 3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{2, 1}, labelIDs)
+}
+
+func TestGetLabel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	rows := sqlmock.NewRows([]string{"id", "name", "user_id", "parent_id", "is_trashed", "created_at", "updated_at"}).
+		AddRow(3, "Projects", 1, 2, 0, nowTime, nowTime)
+
+	mock.ExpectQuery("SELECT (.+) FROM labels WHERE id").WithArgs(int32(3), int32(1)).WillReturnRows(rows)
+
+	lr := labelRepo.NewPgsqlLabelRepository(db)
+	label, err := lr.GetLabel(context.TODO(), 3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Projects", label.Name)
+	assert.Equal(t, int32(2), *label.ParentID)
+}
+
+func TestUpdateLabel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	l := &model.Label{ID: 3, Name: "Projects", UserID: 1, UpdatedAt: nowTime}
+
+	mock.ExpectExec("UPDATE labels").WithArgs(l.Name, l.ParentID, l.UpdatedAt, l.ID, l.UserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE labels").WithArgs(l.Name, l.ParentID, l.UpdatedAt, l.ID, int32(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	lr := labelRepo.NewPgsqlLabelRepository(db)
+	assert.NoError(t, lr.UpdateLabel(context.TODO(), l))
+
+	l.UserID = 2
+	assert.Error(t, lr.UpdateLabel(context.TODO(), l))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteLabel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT parent_id FROM labels").WithArgs(int32(3), int32(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(2))
+		mock.ExpectExec("UPDATE labels SET parent_id = \\$1 WHERE parent_id = \\$2").WithArgs(int32(2), int32(3)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("DELETE FROM notes_labels").WithArgs(int32(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM templates_labels").WithArgs(int32(3)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM labels").WithArgs(int32(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		lr := labelRepo.NewPgsqlLabelRepository(db)
+		assert.NoError(t, lr.DeleteLabel(context.TODO(), 3, 1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not-owner", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT parent_id FROM labels").WithArgs(int32(3), int32(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"parent_id"}))
+		mock.ExpectRollback()
+
+		lr := labelRepo.NewPgsqlLabelRepository(db)
+		assert.ErrorIs(t, lr.DeleteLabel(context.TODO(), 3, 2), sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"librenote/app/model"
+	"librenote/app/response"
+	"net/http"
+	"time"
+)
+
+type labelUsecase struct {
+	repo           model.LabelRepository
+	noteRepo       model.NoteRepository
+	events         model.EventPublisher
+	contextTimeout time.Duration
+}
+
+func NewLabelUsecase(repo model.LabelRepository, noteRepo model.NoteRepository, events model.EventPublisher,
+	timeout time.Duration) model.LabelUsecase {
+	return &labelUsecase{
+		repo:           repo,
+		noteRepo:       noteRepo,
+		events:         events,
+		contextTimeout: timeout,
+	}
+}
+
+func (u *labelUsecase) Create(c context.Context, m *model.Label) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if m.ParentID != nil {
+		if _, err := u.repo.GetLabel(ctx, *m.ParentID, m.UserID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return response.WrapError(errors.New("parent label not found"), http.StatusBadRequest)
+			}
+
+			return err
+		}
+	}
+
+	if err := u.repo.CreateLabel(ctx, m); err != nil {
+		return err
+	}
+
+	u.events.Publish(ctx, m.UserID, model.EventLabelCreated, m)
+
+	return nil
+}
+
+func (u *labelUsecase) Fetch(c context.Context, userID int32) ([]model.Label, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.repo.FetchLabels(ctx, userID)
+}
+
+func (u *labelUsecase) Tree(c context.Context, userID int32) ([]model.LabelNode, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	labels, err := u.repo.FetchLabels(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := subLabels(labels)
+
+	var build func(parentID int32, visited map[int32]bool) []model.LabelNode
+	build = func(parentID int32, visited map[int32]bool) []model.LabelNode {
+		nodes := make([]model.LabelNode, 0, len(children[parentID]))
+
+		for _, label := range children[parentID] {
+			if visited[label.ID] {
+				continue
+			}
+
+			visited[label.ID] = true
+			nodes = append(nodes, model.LabelNode{Label: label, Children: build(label.ID, visited)})
+		}
+
+		return nodes
+	}
+
+	return build(0, make(map[int32]bool, len(labels))), nil
+}
+
+// subLabels groups the labels by their parent, the top level labels are under 0.
+// A label of a missing parent is a top level label
+func subLabels(labels []model.Label) map[int32][]model.Label {
+	ids := make(map[int32]bool, len(labels))
+	for _, label := range labels {
+		ids[label.ID] = true
+	}
+
+	children := make(map[int32][]model.Label)
+
+	for _, label := range labels {
+		var parentID int32
+		if label.ParentID != nil && ids[*label.ParentID] {
+			parentID = *label.ParentID
+		}
+
+		children[parentID] = append(children[parentID], label)
+	}
+
+	return children
+}
+
+func (u *labelUsecase) Get(c context.Context, id, userID int32) (*model.Label, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.getLabel(ctx, id, userID)
+}
+
+func (u *labelUsecase) getLabel(ctx context.Context, id, userID int32) (*model.Label, error) {
+	label, err := u.repo.GetLabel(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, response.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &label, nil
+}
+
+func (u *labelUsecase) Update(c context.Context, m *model.Label) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	current, err := u.getLabel(ctx, m.ID, m.UserID)
+	if err != nil {
+		return err
+	}
+
+	m.ParentID = current.ParentID
+	m.IsTrashed = current.IsTrashed
+	m.CreatedAt = current.CreatedAt
+
+	if err = u.repo.UpdateLabel(ctx, m); err != nil {
+		return err
+	}
+
+	u.events.Publish(ctx, m.UserID, model.EventLabelUpdated, m)
+
+	return nil
+}
+
+// Move refuses to move a label under itself or one of its sub labels, which would make a cycle
+func (u *labelUsecase) Move(c context.Context, m *model.Label) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	labels, err := u.repo.FetchLabels(ctx, m.UserID)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int32]model.Label, len(labels))
+	for _, label := range labels {
+		byID[label.ID] = label
+	}
+
+	current, ok := byID[m.ID]
+	if !ok {
+		return response.ErrNotFound
+	}
+
+	if m.ParentID != nil {
+		if err = checkParent(byID, m.ID, *m.ParentID); err != nil {
+			return err
+		}
+	}
+
+	m.Name = current.Name
+	m.IsTrashed = current.IsTrashed
+	m.CreatedAt = current.CreatedAt
+
+	if err = u.repo.UpdateLabel(ctx, m); err != nil {
+		return err
+	}
+
+	u.events.Publish(ctx, m.UserID, model.EventLabelUpdated, m)
+
+	return nil
+}
+
+// checkParent walks up from the new parent, reaching the label means the parent is inside its subtree
+func checkParent(labels map[int32]model.Label, id, parentID int32) error {
+	parent, ok := labels[parentID]
+	if !ok {
+		return response.WrapError(errors.New("parent label not found"), http.StatusBadRequest)
+	}
+
+	// the steps are bounded, in case the stored labels already have a cycle
+	for steps := 0; steps <= len(labels); steps++ {
+		if parent.ID == id {
+			return response.WrapError(errors.New("a label can't be moved under itself or its sub labels"),
+				http.StatusConflict)
+		}
+
+		if parent.ParentID == nil {
+			return nil
+		}
+
+		if parent, ok = labels[*parent.ParentID]; !ok {
+			return nil
+		}
+	}
+
+	return response.WrapError(errors.New("the label tree has a cycle"), http.StatusConflict)
+}
+
+// Delete moves the sub labels of the label to its parent, the event tells the new parent
+func (u *labelUsecase) Delete(c context.Context, id, userID int32) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	label, err := u.getLabel(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	err = u.repo.DeleteLabel(ctx, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return response.ErrNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	u.events.Publish(ctx, userID, model.EventLabelDeleted, map[string]*int32{"id": &id, "parent_id": label.ParentID})
+
+	return nil
+}
+
+func (u *labelUsecase) FetchNotes(c context.Context, id, userID int32, page, pageSize int) ([]model.Note, int, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if page < 1 || pageSize < 1 {
+		return nil, 0, response.ErrInvalidPage
+	}
+
+	labels, err := u.repo.FetchLabels(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := descendants(labels, id)
+	if len(ids) == 0 {
+		return nil, 0, response.ErrNotFound
+	}
+
+	filter := model.NoteFilter{UserID: userID, LabelIDs: ids}
+
+	return u.noteRepo.FetchNotes(ctx, filter, pageSize, (page-1)*pageSize)
+}
+
+// descendants returns the label and all of its sub labels, nothing when the label doesn't exist
+func descendants(labels []model.Label, id int32) []int32 {
+	children := make(map[int32][]int32)
+	found := false
+
+	for _, label := range labels {
+		if label.ID == id {
+			found = true
+		}
+
+		if label.ParentID != nil {
+			children[*label.ParentID] = append(children[*label.ParentID], label.ID)
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	ids := []int32{id}
+	visited := map[int32]bool{id: true}
+
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !visited[child] {
+				visited[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+
+	return ids
+}
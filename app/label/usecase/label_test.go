@@ -0,0 +1,162 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"librenote/app/label/usecase"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func noEvents() *mocks.EventPublisher {
+	events := new(mocks.EventPublisher)
+	events.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	return events
+}
+
+// mockLabels is the tree work > projects > x, work > y and home
+func mockLabels() []model.Label {
+	return []model.Label{
+		{ID: 1, Name: "work", UserID: 1},
+		{ID: 2, Name: "projects", UserID: 1, ParentID: int32Ptr(1)},
+		{ID: 3, Name: "x", UserID: 1, ParentID: int32Ptr(2)},
+		{ID: 4, Name: "y", UserID: 1, ParentID: int32Ptr(1)},
+		{ID: 5, Name: "home", UserID: 1},
+	}
+}
+
+func statusCode(err error) int {
+	code, _ := response.RespondError(err)
+
+	return code
+}
+
+func TestCreate(t *testing.T) {
+	mockLabelRepo := new(mocks.LabelRepository)
+	mockLabelRepo.On("GetLabel", mock.Anything, int32(9), int32(1)).Return(model.Label{}, sql.ErrNoRows).Once()
+	mockLabelRepo.On("GetLabel", mock.Anything, int32(1), int32(1)).Return(mockLabels()[0], nil).Once()
+	mockLabelRepo.On("CreateLabel", mock.Anything, mock.AnythingOfType("*model.Label")).Return(nil).Once()
+
+	events := new(mocks.EventPublisher)
+	events.On("Publish", mock.Anything, int32(1), model.EventLabelCreated, mock.Anything).Once()
+
+	u := usecase.NewLabelUsecase(mockLabelRepo, new(mocks.NoteRepository), events, time.Second*2)
+
+	err := u.Create(context.TODO(), &model.Label{Name: "projects", UserID: 1, ParentID: int32Ptr(9)})
+	assert.Equal(t, http.StatusBadRequest, statusCode(err))
+
+	assert.NoError(t, u.Create(context.TODO(), &model.Label{Name: "projects", UserID: 1, ParentID: int32Ptr(1)}))
+	mockLabelRepo.AssertExpectations(t)
+	events.AssertExpectations(t)
+}
+
+func TestTree(t *testing.T) {
+	mockLabelRepo := new(mocks.LabelRepository)
+	mockLabelRepo.On("FetchLabels", mock.Anything, int32(1)).Return(mockLabels(), nil).Once()
+
+	u := usecase.NewLabelUsecase(mockLabelRepo, new(mocks.NoteRepository), noEvents(), time.Second*2)
+
+	tree, err := u.Tree(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "work", tree[0].Name)
+	assert.Len(t, tree[0].Children, 2)
+	assert.Equal(t, "x", tree[0].Children[0].Children[0].Name)
+	assert.Empty(t, tree[0].Children[0].Children[0].Children)
+	assert.Equal(t, "home", tree[1].Name)
+}
+
+func TestMove(t *testing.T) {
+	cases := map[string]struct {
+		id       int32
+		parentID *int32
+		code     int
+	}{
+		"to-top":          {id: 2, code: http.StatusOK},
+		"to-other-branch": {id: 2, parentID: int32Ptr(5), code: http.StatusOK},
+		"under-itself":    {id: 2, parentID: int32Ptr(2), code: http.StatusConflict},
+		"under-child":     {id: 1, parentID: int32Ptr(3), code: http.StatusConflict},
+		"missing-parent":  {id: 2, parentID: int32Ptr(9), code: http.StatusBadRequest},
+		"missing-label":   {id: 9, code: http.StatusNotFound},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockLabelRepo := new(mocks.LabelRepository)
+			mockLabelRepo.On("FetchLabels", mock.Anything, int32(1)).Return(mockLabels(), nil).Once()
+
+			if c.code == http.StatusOK {
+				mockLabelRepo.On("UpdateLabel", mock.Anything, mock.MatchedBy(func(l *model.Label) bool {
+					return l.Name == "projects" && assert.ObjectsAreEqual(c.parentID, l.ParentID)
+				})).Return(nil).Once()
+			}
+
+			u := usecase.NewLabelUsecase(mockLabelRepo, new(mocks.NoteRepository), noEvents(), time.Second*2)
+
+			err := u.Move(context.TODO(), &model.Label{ID: c.id, UserID: 1, ParentID: c.parentID})
+			if c.code == http.StatusOK {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, c.code, statusCode(err))
+			}
+
+			mockLabelRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	mockLabelRepo := new(mocks.LabelRepository)
+	mockLabelRepo.On("GetLabel", mock.Anything, int32(2), int32(1)).Return(mockLabels()[1], nil).Once()
+	mockLabelRepo.On("DeleteLabel", mock.Anything, int32(2), int32(1)).Return(nil).Once()
+	mockLabelRepo.On("GetLabel", mock.Anything, int32(2), int32(2)).Return(model.Label{}, sql.ErrNoRows).Once()
+
+	events := new(mocks.EventPublisher)
+	deleted := mock.MatchedBy(func(data map[string]*int32) bool {
+		return *data["id"] == 2 && *data["parent_id"] == 1
+	})
+	events.On("Publish", mock.Anything, int32(1), model.EventLabelDeleted, deleted).Once()
+
+	u := usecase.NewLabelUsecase(mockLabelRepo, new(mocks.NoteRepository), events, time.Second*2)
+
+	assert.NoError(t, u.Delete(context.TODO(), 2, 1))
+	assert.ErrorIs(t, u.Delete(context.TODO(), 2, 2), response.ErrNotFound)
+	mockLabelRepo.AssertExpectations(t)
+	events.AssertExpectations(t)
+}
+
+func TestFetchNotes(t *testing.T) {
+	mockLabelRepo := new(mocks.LabelRepository)
+	mockLabelRepo.On("FetchLabels", mock.Anything, int32(1)).Return(mockLabels(), nil).Twice()
+
+	mockNoteRepo := new(mocks.NoteRepository)
+	mockNoteRepo.On("FetchNotes", mock.Anything, model.NoteFilter{UserID: 1, LabelIDs: []int32{1, 2, 4, 3}}, 10, 10).
+		Return([]model.Note{{ID: 7}}, 11, nil).Once()
+
+	u := usecase.NewLabelUsecase(mockLabelRepo, mockNoteRepo, noEvents(), time.Second*2)
+
+	notes, count, err := u.FetchNotes(context.TODO(), 1, 1, 2, 10)
+	assert.NoError(t, err)
+	assert.Len(t, notes, 1)
+	assert.Equal(t, 11, count)
+
+	_, _, err = u.FetchNotes(context.TODO(), 9, 1, 1, 10)
+	assert.ErrorIs(t, err, response.ErrNotFound)
+
+	_, _, err = u.FetchNotes(context.TODO(), 1, 1, 0, 10)
+	assert.ErrorIs(t, err, response.ErrInvalidPage)
+	mockLabelRepo.AssertExpectations(t)
+	mockNoteRepo.AssertExpectations(t)
+}
@@ -0,0 +1,463 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxReferenceTitle is the size of the note titles
+const maxReferenceTitle = 255
+
+var noteID = regexp.MustCompile(`^#(\d{1,10})$`)
+
+func (r *renderer) inline(s string) {
+	r.b.WriteString(r.inlineHTML(s, true))
+}
+
+// inlineHTML renders the spans of a block, links are false for the text of a link as links don't nest
+func (r *renderer) inlineHTML(s string, links bool) string {
+	p := &inlineParser{r: r, s: s, links: links}
+
+	return p.render()
+}
+
+func (p *inlineParser) render() string {
+	var b strings.Builder
+
+	r, s, links := p.r, p.s, p.links
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			b.WriteString("<br>\n")
+			i += 2
+		case c == '\\' && i+1 < len(s) && isPunct(s[i+1]):
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+		case c == '`':
+			code, n := p.codeSpan(i)
+			if n > 0 {
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+			} else {
+				n = runLength(s[i:], '`')
+				b.WriteString(s[i : i+n])
+			}
+
+			i += n
+		case c == '!' && strings.HasPrefix(s[i+1:], "["):
+			n := p.image(&b, i+1)
+			if n == 0 {
+				b.WriteString("!")
+			}
+
+			i += 1 + n
+		case c == '[' && links:
+			n := r.reference(&b, s[i:])
+			if n == 0 {
+				n = p.link(&b, i)
+			}
+
+			if n == 0 {
+				b.WriteString("[")
+				n = 1
+			}
+
+			i += n
+		case c == '<':
+			n := 0
+			if links {
+				n = r.autolink(&b, s[i:])
+			}
+
+			if n == 0 {
+				b.WriteString("&lt;")
+				n = 1
+			}
+
+			i += n
+		case c == '*' || c == '_' || c == '~':
+			n := p.emphasis(&b, i)
+			if n == 0 {
+				n = runLength(s[i:], c)
+				b.WriteString(s[i : i+n])
+			}
+
+			i += n
+		case c == ' ':
+			n := runLength(s[i:], ' ')
+
+			switch {
+			case i+n < len(s) && s[i+n] == '\n' && n >= 2:
+				b.WriteString("<br>\n")
+				n++
+			case i+n < len(s) && s[i+n] == '\n':
+				b.WriteString("\n")
+				n++
+			default:
+				b.WriteString(s[i : i+n])
+			}
+
+			i += n
+		case c == 'h' && links && !precededByWord(s, i):
+			n := r.bareURL(&b, s[i:])
+			if n == 0 {
+				b.WriteString("h")
+				n = 1
+			}
+
+			i += n
+		default:
+			_, n := utf8.DecodeRuneInString(s[i:])
+			b.WriteString(html.EscapeString(s[i : i+n]))
+			i += n
+		}
+	}
+
+	return b.String()
+}
+
+func isPunct(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
+
+func runLength(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+
+	return n
+}
+
+func precededByWord(s string, i int) bool {
+	if i == 0 {
+		return false
+	}
+
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func followedBySpace(s string, i int) bool {
+	if i >= len(s) {
+		return true
+	}
+
+	r, _ := utf8.DecodeRuneInString(s[i:])
+
+	return unicode.IsSpace(r)
+}
+
+func precededBySpace(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+
+	return unicode.IsSpace(r)
+}
+
+// codeSpan parses the code span at s[i], n is 0 when its backticks aren't closed
+func (p *inlineParser) codeSpan(i int) (code string, n int) {
+	n = p.codeSpanEnd(i)
+	if n == 0 {
+		return "", 0
+	}
+
+	open := runLength(p.s[i:], '`')
+
+	code = strings.ReplaceAll(p.s[i+open:i+n-open], "\n", " ")
+	if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+		code = code[1 : len(code)-1]
+	}
+
+	return code, n
+}
+
+// emphasis renders the *em*, **strong** or ~~strikethrough~~ span opening at s[i],
+// it returns how much of s was consumed, 0 when the delimiters don't make a span
+func (p *inlineParser) emphasis(b *strings.Builder, i int) int {
+	s := p.s
+	c := s[i]
+	run := runLength(s[i:], c)
+
+	size, tag := 1, "em"
+
+	switch {
+	case c == '~' && run == 2:
+		size, tag = 2, "del"
+	case c == '~':
+		return 0
+	case run >= 2:
+		size, tag = 2, "strong"
+	}
+
+	if followedBySpace(s, i+run) || c == '_' && precededByWord(s, i) {
+		return 0
+	}
+
+	k := p.closer(delimiter{c: c, size: size}, i+run)
+	if k < 0 {
+		return 0
+	}
+
+	end := k + runLength(s[k:], c)
+	inner := s[i+size : end-size]
+	b.WriteString("<" + tag + ">" + p.r.inlineHTML(inner, p.links) + "</" + tag + ">")
+
+	return end - i
+}
+
+func isWordByte(s string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(s[i:])
+
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// linkParts parses `[text](destination "title")` at s[i]
+func (p *inlineParser) linkParts(i int) (text, dest, title string, n int) {
+	s := p.s
+
+	end := p.closingBracket(i)
+	if end < 0 || end+1 >= len(s) || s[end+1] != '(' {
+		return "", "", "", 0
+	}
+
+	text = s[i+1 : end]
+	ends := p.linkEnds()
+	k := ends.text[end+2]
+
+	if k < len(s) && s[k] == '<' {
+		gt := ends.angle[k+1]
+		if gt >= len(s) || s[gt] != '>' {
+			return "", "", "", 0
+		}
+
+		dest = s[k+1 : gt]
+		k = gt + 1
+	} else {
+		start := k
+		k = ends.destination[k]
+		dest = s[start:k]
+	}
+
+	if j := ends.text[k]; j > k && j < len(s) && strings.IndexByte(`"'(`, s[j]) >= 0 {
+		c := s[j]
+		if c == '(' {
+			c = ')'
+		}
+
+		closing := ends.titles[c][j+1]
+		if closing >= len(s) {
+			return "", "", "", 0
+		}
+
+		title = s[j+1 : closing]
+		k = closing + 1
+	}
+
+	k = ends.text[k]
+	if k >= len(s) || s[k] != ')' {
+		return "", "", "", 0
+	}
+
+	return text, unescape(dest), unescape(title), k + 1 - i
+}
+
+// unescape drops the backslashes escaping punctuation
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isPunct(s[i+1]) {
+			i++
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// reference renders the [[title]] or [[#id]] at the start of s, titles are at most 255 characters
+func (r *renderer) reference(b *strings.Builder, s string) int {
+	if !strings.HasPrefix(s, "[[") {
+		return 0
+	}
+
+	// the title can't have brackets or line breaks, it ends on the first of them
+	end := strings.IndexAny(s[2:], "[]\n") + 2
+	if end < 2 || !strings.HasPrefix(s[end:], "]]") {
+		return 0
+	}
+
+	name := s[2:end]
+	title := strings.TrimSpace(name)
+
+	if title == "" || utf8.RuneCountInString(title) > maxReferenceTitle {
+		return 0
+	}
+
+	if m := noteID.FindStringSubmatch(title); m != nil {
+		if id, err := strconv.ParseInt(m[1], 10, 32); err == nil && id > 0 {
+			r.addReference(Reference{NoteID: int32(id)})
+			b.WriteString(`<span class="note-link" data-note-id="` + m[1] + `">` + html.EscapeString(title) + "</span>")
+
+			return end + 2
+		}
+	}
+
+	r.addReference(Reference{Title: title})
+	b.WriteString(`<span class="note-link" data-note-title="` + html.EscapeString(title) + `">` +
+		html.EscapeString(title) + "</span>")
+
+	return end + 2
+}
+
+// addReference keeps the first of the references to a same title, titles are case insensitive
+func (r *renderer) addReference(ref Reference) {
+	key := Reference{Title: strings.ToLower(ref.Title), NoteID: ref.NoteID}
+	if !r.seenRefs[key] {
+		r.seenRefs[key] = true
+		r.refs = append(r.refs, ref)
+	}
+}
+
+func (p *inlineParser) link(b *strings.Builder, i int) int {
+	r := p.r
+
+	text, dest, title, n := p.linkParts(i)
+	if n == 0 {
+		return 0
+	}
+
+	if !safeURL(dest, linkSchemes) {
+		b.WriteString(r.inlineHTML(text, false))
+
+		return n
+	}
+
+	r.anchor(b, dest, title, r.inlineHTML(text, false))
+
+	return n
+}
+
+func (r *renderer) anchor(b *strings.Builder, dest, title, content string) {
+	r.addLink(dest)
+
+	b.WriteString(`<a href="` + html.EscapeString(dest) + `"`)
+
+	if title != "" {
+		b.WriteString(` title="` + html.EscapeString(title) + `"`)
+	}
+
+	b.WriteString(` rel="` + linkRel + `">` + content + "</a>")
+}
+
+func (r *renderer) addLink(dest string) {
+	if !r.seen[dest] {
+		r.seen[dest] = true
+		r.links = append(r.links, dest)
+	}
+}
+
+func (p *inlineParser) image(b *strings.Builder, i int) int {
+	alt, dest, title, n := p.linkParts(i)
+	if n == 0 {
+		return 0
+	}
+
+	if !safeURL(dest, imageSchemes) {
+		b.WriteString(html.EscapeString(alt))
+
+		return n
+	}
+
+	b.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(alt) + `"`)
+
+	if title != "" {
+		b.WriteString(` title="` + html.EscapeString(title) + `"`)
+	}
+
+	b.WriteString(">")
+
+	return n
+}
+
+func (r *renderer) autolink(b *strings.Builder, s string) int {
+	if m := autolink.FindStringSubmatch(s); m != nil && safeURL(m[1], linkSchemes) {
+		r.anchor(b, m[1], "", html.EscapeString(m[1]))
+
+		return len(m[0])
+	}
+
+	if m := emailAutolink.FindStringSubmatch(s); m != nil {
+		r.anchor(b, "mailto:"+m[1], "", html.EscapeString(m[1]))
+
+		return len(m[0])
+	}
+
+	return 0
+}
+
+// bareURL links the http(s) URL at the start of s, without its trailing punctuation
+func (r *renderer) bareURL(b *strings.Builder, s string) int {
+	if !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") {
+		return 0
+	}
+
+	n := strings.IndexAny(s, " \n<")
+	if n < 0 {
+		n = len(s)
+	}
+
+	opening, closing := strings.Count(s[:n], "("), strings.Count(s[:n], ")")
+
+	for n > 0 {
+		if s[n-1] == ')' && opening < closing {
+			closing--
+		} else if strings.IndexByte(`.,:;!?"'*_~`, s[n-1]) < 0 {
+			break
+		}
+
+		n--
+	}
+
+	url := s[:n]
+	if i := strings.Index(url, "://"); len(url) == i+3 {
+		return 0
+	}
+
+	r.anchor(b, url, "", html.EscapeString(url))
+
+	return n
+}
+
+// safeURL tells whether dest is relative or uses one of the schemes, the URLs which could be
+// interpreted differently by browsers, like with control characters, are refused
+func safeURL(dest string, schemes map[string]bool) bool {
+	for _, c := range dest {
+		if c < ' ' || c == 0x7f {
+			return false
+		}
+	}
+
+	colon := strings.IndexByte(dest, ':')
+	if colon < 0 || strings.ContainsAny(dest[:colon], "/?#") {
+		return true
+	}
+
+	return schemes[strings.ToLower(dest[:colon])]
+}
@@ -0,0 +1,477 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// the block syntax is matched on lines whose leading tabs are already expanded
+var (
+	thematicBreak = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	setextLine    = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	fenceStart    = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`]*)$")
+	listMarker    = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])( +|$)`)
+	quoteStart    = regexp.MustCompile(`^ {0,3}>`)
+	taskMarker    = regexp.MustCompile(`^\[([ xX])\](?: +|$)`)
+	language      = regexp.MustCompile(`^[A-Za-z0-9_+#.-]{1,32}$`)
+	autolink      = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^<>\s]*)>`)
+	emailAutolink = regexp.MustCompile(`^<([^\s@<>\\]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*)>`)
+)
+
+// the schemes a link may use, relative links have none
+var (
+	linkSchemes  = map[string]bool{"http": true, "https": true, "mailto": true}
+	imageSchemes = map[string]bool{"http": true, "https": true}
+)
+
+// rel of the rendered links, notes are user content
+const linkRel = "nofollow noopener noreferrer"
+
+// maxNesting bounds the depth of the block quotes and lists, the deeper ones are rendered as paragraphs since every
+// level goes through the lines of the levels below it
+const maxNesting = 32
+
+// Render converts a CommonMark subset to HTML: headings, paragraphs, emphasis, strikethrough, code,
+// block quotes, nested and task lists, links, images and bare URLs.
+// References to other notes become `<span class="note-link">` with the title or id as data attribute.
+// Raw HTML of the source is escaped rather than passed through and only http(s), mailto and relative
+// links are kept, so the output is safe to serve as is.
+func Render(src string) string {
+	r := newRenderer()
+	r.blocks(lines(src), false)
+
+	return r.b.String()
+}
+
+// Links lists the distinct destinations of the links rendered from the source, in order of appearance.
+// Links in code and the ones dropped as unsafe are left out.
+func Links(src string) []string {
+	r := newRenderer()
+	r.blocks(lines(src), false)
+
+	return r.links
+}
+
+// Reference is a wiki style reference to another note, [[title]] or [[#id]]
+type Reference struct {
+	Title  string
+	NoteID int32
+}
+
+// References lists the distinct references to other notes, in order of appearance, leaving out the code
+func References(src string) []Reference {
+	r := newRenderer()
+	r.blocks(lines(src), false)
+
+	return r.refs
+}
+
+type renderer struct {
+	b        *strings.Builder
+	links    []string
+	seen     map[string]bool
+	refs     []Reference
+	seenRefs map[Reference]bool
+	// nesting of the blocks being rendered
+	depth int
+}
+
+func newRenderer() *renderer {
+	return &renderer{b: &strings.Builder{}, seen: map[string]bool{}, seenRefs: map[Reference]bool{}}
+}
+
+func lines(src string) []string {
+	src = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\x00", "�").Replace(src)
+
+	res := strings.Split(src, "\n")
+	for i, line := range res {
+		res[i] = expandTabs(line)
+	}
+
+	return res
+}
+
+// expandTabs replaces the tabs of the indentation by spaces, up to the next multiple of 4
+func expandTabs(line string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			b.WriteByte(' ')
+		case '\t':
+			b.WriteString(strings.Repeat(" ", 4-b.Len()%4))
+		default:
+			return b.String() + line[i:]
+		}
+	}
+
+	return b.String()
+}
+
+func indent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// trimIndent removes up to n leading spaces
+func trimIndent(line string, n int) string {
+	if i := indent(line); i < n {
+		n = i
+	}
+
+	return line[n:]
+}
+
+func (r *renderer) blocks(lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case isBlank(line):
+			i++
+		case indent(line) >= 4:
+			i = r.indentedCode(lines, i)
+		case fenceStart.MatchString(line):
+			i = r.fencedCode(lines, i)
+		case thematicBreak.MatchString(line):
+			r.b.WriteString("<hr>\n")
+			i++
+		case quoteStart.MatchString(line) && r.depth < maxNesting:
+			i = r.blockquote(lines, i)
+		case listMarker.MatchString(line) && r.depth < maxNesting:
+			i = r.list(lines, i)
+		default:
+			if level, text, ok := heading(line); ok {
+				r.heading(level, text)
+				i++
+
+				continue
+			}
+
+			i = r.paragraph(lines, i, tight)
+		}
+	}
+}
+
+// heading parses an ATX heading, `## title ##`
+func heading(line string) (int, string, bool) {
+	if indent(line) >= 4 {
+		return 0, "", false
+	}
+
+	s := strings.TrimLeft(line, " ")
+
+	level := 0
+	for level < len(s) && s[level] == '#' {
+		level++
+	}
+
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+
+	text := s[level:]
+	if text != "" && text[0] != ' ' && text[0] != '\t' {
+		return 0, "", false
+	}
+
+	text = strings.TrimSpace(text)
+	if t := strings.TrimRight(text, "#"); t == "" || strings.HasSuffix(t, " ") || strings.HasSuffix(t, "\t") {
+		text = strings.TrimSpace(t)
+	}
+
+	return level, text, true
+}
+
+func (r *renderer) heading(level int, text string) {
+	tag := "h" + strconv.Itoa(level)
+	r.b.WriteString("<" + tag + ">")
+	r.inline(text)
+	r.b.WriteString("</" + tag + ">\n")
+}
+
+// interrupts tells whether a line ends a paragraph rather than continuing it
+func interrupts(line string) bool {
+	if indent(line) >= 4 {
+		return false
+	}
+
+	if _, _, ok := heading(line); ok {
+		return true
+	}
+
+	if fenceStart.MatchString(line) || thematicBreak.MatchString(line) || quoteStart.MatchString(line) {
+		return true
+	}
+
+	// only non empty bullets and lists starting at 1 can interrupt a paragraph
+	m := listMarker.FindStringSubmatch(line)
+
+	return m != nil && !isBlank(line[len(m[0]):]) && (isBullet(m[2]) || m[2] == "1." || m[2] == "1)")
+}
+
+func (r *renderer) paragraph(lines []string, i int, tight bool) int {
+	var para []string
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) {
+			break
+		}
+
+		if len(para) > 0 {
+			if m := setextLine.FindStringSubmatch(line); m != nil {
+				level := 1
+				if m[1][0] == '-' {
+					level = 2
+				}
+
+				r.heading(level, strings.TrimSpace(strings.Join(para, "\n")))
+
+				return i + 1
+			}
+
+			if interrupts(line) {
+				break
+			}
+		}
+
+		para = append(para, strings.TrimLeft(line, " "))
+	}
+
+	text := strings.TrimRight(strings.Join(para, "\n"), " ")
+
+	if tight {
+		r.inline(text)
+		r.b.WriteString("\n")
+	} else {
+		r.b.WriteString("<p>")
+		r.inline(text)
+		r.b.WriteString("</p>\n")
+	}
+
+	return i
+}
+
+func (r *renderer) indentedCode(lines []string, i int) int {
+	var code []string
+
+	for ; i < len(lines) && (isBlank(lines[i]) || indent(lines[i]) >= 4); i++ {
+		code = append(code, trimIndent(lines[i], 4))
+	}
+
+	for len(code) > 0 && isBlank(code[len(code)-1]) {
+		code = code[:len(code)-1]
+	}
+
+	r.code("", code)
+
+	return i
+}
+
+func (r *renderer) fencedCode(lines []string, i int) int {
+	m := fenceStart.FindStringSubmatch(lines[i])
+	pad, fence := len(m[1]), m[2]
+
+	var lang string
+	if info := strings.Fields(m[3]); len(info) > 0 && language.MatchString(info[0]) {
+		lang = info[0]
+	}
+
+	var code []string
+
+	for i++; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if indent(lines[i]) < 4 && len(line) >= len(fence) && strings.Trim(line, fence[:1]) == "" {
+			i++
+
+			break
+		}
+
+		code = append(code, trimIndent(lines[i], pad))
+	}
+
+	r.code(lang, code)
+
+	return i
+}
+
+func (r *renderer) code(lang string, code []string) {
+	if lang != "" {
+		r.b.WriteString(`<pre><code class="language-` + lang + `">`)
+	} else {
+		r.b.WriteString("<pre><code>")
+	}
+
+	for _, line := range code {
+		r.b.WriteString(html.EscapeString(line) + "\n")
+	}
+
+	r.b.WriteString("</code></pre>\n")
+}
+
+func (r *renderer) blockquote(lines []string, i int) int {
+	var quote []string
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+
+		if quoteStart.MatchString(line) {
+			line = strings.TrimLeft(line, " ")[1:]
+			if strings.HasPrefix(line, " ") {
+				line = line[1:]
+			}
+
+			quote = append(quote, line)
+
+			continue
+		}
+
+		// lazy continuation of a quoted paragraph
+		if isBlank(line) || len(quote) == 0 || isBlank(quote[len(quote)-1]) || interrupts(line) {
+			break
+		}
+
+		quote = append(quote, line)
+	}
+
+	r.b.WriteString("<blockquote>\n")
+	r.depth++
+	r.blocks(quote, false)
+	r.depth--
+	r.b.WriteString("</blockquote>\n")
+
+	return i
+}
+
+func isBullet(marker string) bool {
+	return marker == "-" || marker == "*" || marker == "+"
+}
+
+func (r *renderer) list(lines []string, i int) int {
+	m := listMarker.FindStringSubmatch(lines[i])
+	delimiter := m[2][len(m[2])-1:]
+	ordered := !isBullet(m[2])
+	start, _ := strconv.Atoi(strings.TrimSuffix(m[2], delimiter))
+
+	var (
+		items [][]string
+		loose bool
+	)
+
+	for i < len(lines) {
+		m = listMarker.FindStringSubmatch(lines[i])
+		if m == nil || thematicBreak.MatchString(lines[i]) || !strings.HasSuffix(m[2], delimiter) {
+			break
+		}
+
+		if len(items) > 0 && isBlank(lines[i-1]) {
+			loose = true
+		}
+
+		var item []string
+
+		item, i = listItem(lines, i, m)
+		items = append(items, item)
+
+		for _, line := range item {
+			if isBlank(line) {
+				loose = true
+			}
+		}
+	}
+
+	switch {
+	case !ordered:
+		r.b.WriteString("<ul>\n")
+	case start != 1:
+		r.b.WriteString(`<ol start="` + strconv.Itoa(start) + `">` + "\n")
+	default:
+		r.b.WriteString("<ol>\n")
+	}
+
+	r.depth++
+
+	for _, item := range items {
+		r.listItem(item, ordered, loose)
+	}
+
+	r.depth--
+
+	if ordered {
+		r.b.WriteString("</ol>\n")
+	} else {
+		r.b.WriteString("</ul>\n")
+	}
+
+	return i
+}
+
+// listItem collects the lines of the item starting at i, without its marker and indentation
+func listItem(lines []string, i int, m []string) ([]string, int) {
+	width := len(m[0])
+	first := lines[i][width:]
+
+	// the content is indented by one space only when followed by an indented code block
+	if len(m[3]) > 4 {
+		width = len(m[1]) + len(m[2]) + 1
+		first = lines[i][width:]
+	} else if m[3] == "" {
+		width++
+	}
+
+	item := []string{first}
+
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case isBlank(line):
+			item = append(item, "")
+		case indent(line) >= width:
+			item = append(item, line[width:])
+		case !isBlank(item[len(item)-1]) && !interrupts(line) && !listMarker.MatchString(line):
+			item = append(item, line)
+		default:
+			return trimBlankLines(item), i
+		}
+	}
+
+	return trimBlankLines(item), i
+}
+
+func trimBlankLines(item []string) []string {
+	for len(item) > 1 && isBlank(item[len(item)-1]) {
+		item = item[:len(item)-1]
+	}
+
+	return item
+}
+
+func (r *renderer) listItem(item []string, ordered, loose bool) {
+	if m := taskMarker.FindStringSubmatch(item[0]); m != nil && !ordered {
+		item[0] = item[0][len(m[0]):]
+
+		if m[1] == " " {
+			r.b.WriteString(`<li class="task"><input type="checkbox" disabled> `)
+		} else {
+			r.b.WriteString(`<li class="task"><input type="checkbox" checked disabled> `)
+		}
+	} else {
+		r.b.WriteString("<li>")
+	}
+
+	b := r.b
+	r.b = &strings.Builder{}
+	r.blocks(item, !loose)
+	content := strings.TrimSuffix(r.b.String(), "\n")
+	r.b = b
+
+	r.b.WriteString(content + "</li>\n")
+}
@@ -0,0 +1,128 @@
+package markdown_test
+
+import (
+	"librenote/app/markdown"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	cases := map[string]struct {
+		src  string
+		html string
+	}{
+		"heading": {
+			"# Title #\nSub\n---", "<h1>Title</h1>\n<h2>Sub</h2>\n",
+		},
+		"paragraphs": {
+			"first line\nsecond  \nthird\n\nnext", "<p>first line\nsecond<br>\nthird</p>\n<p>next</p>\n",
+		},
+		"emphasis": {
+			"*em* **strong** ***both*** ~~del~~ snake_case_name 2 * 3",
+			"<p><em>em</em> <strong>strong</strong> <strong><em>both</em></strong> <del>del</del> " +
+				"snake_case_name 2 * 3</p>\n",
+		},
+		"code": {
+			"run `a < b` now\n\n```go\nif a < b {}\n```\n\n    indented",
+			"<p>run <code>a &lt; b</code> now</p>\n<pre><code class=\"language-go\">if a &lt; b {}\n</code></pre>\n" +
+				"<pre><code>indented\n</code></pre>\n",
+		},
+		"lists": {
+			"- [ ] milk\n- [x] eggs\n  1. nested\n\n3. three\n4. four",
+			"<ul>\n<li class=\"task\"><input type=\"checkbox\" disabled> milk</li>\n" +
+				"<li class=\"task\"><input type=\"checkbox\" checked disabled> eggs\n<ol>\n<li>nested</li>\n</ol></li>\n" +
+				"</ul>\n<ol start=\"3\">\n<li>three</li>\n<li>four</li>\n</ol>\n",
+		},
+		"loose-list": {
+			"* one\n\n* two", "<ul>\n<li><p>one</p></li>\n<li><p>two</p></li>\n</ul>\n",
+		},
+		"blockquote": {
+			"> quoted\nlazy\n\n---", "<blockquote>\n<p>quoted\nlazy</p>\n</blockquote>\n<hr>\n",
+		},
+		"links": {
+			`[site](https://example.com "Example") <https://a.b/c> <me@example.com> see https://x.org/p.`,
+			`<p><a href="https://example.com" title="Example" rel="nofollow noopener noreferrer">site</a> ` +
+				`<a href="https://a.b/c" rel="nofollow noopener noreferrer">https://a.b/c</a> ` +
+				`<a href="mailto:me@example.com" rel="nofollow noopener noreferrer">me@example.com</a> ` +
+				`see <a href="https://x.org/p" rel="nofollow noopener noreferrer">https://x.org/p</a>.</p>` + "\n",
+		},
+		"image": {
+			`![a "cat"](/cat.png)`, `<p><img src="/cat.png" alt="a &#34;cat&#34;"></p>` + "\n",
+		},
+		"raw-html": {
+			`<script>alert(1)</script> <img src=x onerror=alert(1)>`,
+			`<p>&lt;script&gt;alert(1)&lt;/script&gt; &lt;img src=x onerror=alert(1)&gt;</p>` + "\n",
+		},
+		"unsafe-links": {
+			"[click](javascript:alert(1)) [x](JavaScript:alert(1)) ![i](data:image/svg+xml,x) <vbscript:x>",
+			"<p>click x i &lt;vbscript:x&gt;</p>\n",
+		},
+		"attribute-injection": {
+			`[x](https://a.b/"onmouseover="alert(1))`,
+			`<p><a href="https://a.b/&#34;onmouseover=&#34;alert(1)" rel="nofollow noopener noreferrer">x</a></p>` +
+				"\n",
+		},
+		"references": {
+			"see [[Project <X>]] and [[#12]], not [[]] nor `[[code]]`",
+			`<p>see <span class="note-link" data-note-title="Project &lt;X&gt;">Project &lt;X&gt;</span> and ` +
+				`<span class="note-link" data-note-id="12">#12</span>, not [[]] nor <code>[[code]]</code></p>` + "\n",
+		},
+		"escapes": {
+			`\*not em\* a & b`, "<p>*not em* a &amp; b</p>\n",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.html, markdown.Render(c.src))
+		})
+	}
+}
+
+func TestLinks(t *testing.T) {
+	src := "[a](https://a.com) [again](https://a.com) [bad](javascript:x)\n\n" +
+		"`[code](https://code.com)`\n\n- see [b](/notes/2) and https://c.com"
+
+	assert.Equal(t, []string{"https://a.com", "/notes/2", "https://c.com"}, markdown.Links(src))
+	assert.Empty(t, markdown.Links("no links"))
+}
+
+func TestReferences(t *testing.T) {
+	src := "[[Groceries]] [[groceries]] [[#4]] [[ #4 ]] [[#0]]\n\n```\n[[In code]]\n```\n\n- [[Plan|x]]"
+
+	assert.Equal(t, []markdown.Reference{
+		{Title: "Groceries"}, {NoteID: 4}, {Title: "#0"}, {Title: "Plan|x"},
+	}, markdown.References(src))
+}
+
+func TestRenderNesting(t *testing.T) {
+	html := markdown.Render(strings.Repeat("> ", 40) + "deep")
+
+	assert.Equal(t, 32, strings.Count(html, "<blockquote>"))
+	assert.Contains(t, html, "<p>&gt; &gt; &gt; &gt; &gt; &gt; &gt; &gt; deep</p>")
+}
+
+// the sources would take seconds or minutes if every opening delimiter scanned the rest of the text
+func TestRenderUnclosed(t *testing.T) {
+	for name, unit := range map[string]string{
+		"list":      "- ",
+		"bracket":   "[",
+		"emphasis":  "*a ",
+		"reference": "[[a",
+		"link":      "[a](x",
+		"title":     `[a](x "`,
+		"code":      "`a ``",
+		"url":       "http://x)",
+	} {
+		src := strings.Repeat(unit, 100000/len(unit)) + "a"
+		start := time.Now()
+
+		markdown.Render(src)
+		markdown.Links(src)
+
+		assert.Less(t, time.Since(start), 2*time.Second, name)
+	}
+}
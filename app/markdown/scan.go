@@ -0,0 +1,237 @@
+package markdown
+
+import "sort"
+
+// inlineParser renders the spans of a text. Looking for the closing delimiter of every opening one by scanning the
+// rest of the text would make a text full of unclosed delimiters quadratic, so the scans are answered from tables
+// built at most once per text instead.
+//
+// A scan skips the escaped characters and the code spans, so from every position it follows a single path going
+// forward. The tables are built backwards, every position taking the answer of the next one on its path.
+type inlineParser struct {
+	r     *renderer
+	s     string
+	links bool
+
+	// starts of the backtick runs, by length
+	ticks map[int][]int
+	// first closing delimiter on the path from every position, by delimiter
+	closers map[delimiter][]int
+	// the ']' making the depth drop below the one of every position
+	drops []int
+	// ends of the link destinations and titles
+	ends *linkEnds
+}
+
+type delimiter struct {
+	c    byte
+	size int
+}
+
+// codeSpanEnd returns the length of the code span at s[i], 0 when its backticks aren't closed
+func (p *inlineParser) codeSpanEnd(i int) int {
+	s := p.s
+
+	if p.ticks == nil {
+		p.ticks = map[int][]int{}
+
+		for k := 0; k < len(s); {
+			n := runLength(s[k:], '`')
+			if n == 0 {
+				k++
+
+				continue
+			}
+
+			p.ticks[n] = append(p.ticks[n], k)
+			k += n
+		}
+	}
+
+	// the closing run is the first one of the same length after the opening one
+	open := runLength(s[i:], '`')
+	starts := p.ticks[open]
+
+	j := sort.SearchInts(starts, i+open)
+	if j == len(starts) {
+		return 0
+	}
+
+	return starts[j] + open - i
+}
+
+// next is the position following k on the path of a scan
+func (p *inlineParser) next(k int) int {
+	switch p.s[k] {
+	case '\\':
+		return k + 2
+	case '`':
+		if n := p.codeSpanEnd(k); n > 0 {
+			return k + n
+		}
+	}
+
+	return k + 1
+}
+
+// closer returns the position of the first delimiter closing an emphasis opened before from, -1 when there is none
+func (p *inlineParser) closer(d delimiter, from int) int {
+	closers, ok := p.closers[d]
+
+	if !ok {
+		s := p.s
+		closers = make([]int, len(s)+2)
+		closers[len(s)], closers[len(s)+1] = -1, -1
+
+		for k := len(s) - 1; k >= 0; k-- {
+			if s[k] != d.c {
+				closers[k] = closers[p.next(k)]
+
+				continue
+			}
+
+			closing := runLength(s[k:], d.c)
+			end := k + closing
+
+			if closing >= d.size && !precededBySpace(s, k) && !(d.c == '_' && end < len(s) && isWordByte(s, end)) &&
+				(d.c != '~' || closing == 2) {
+				closers[k] = k
+			} else {
+				closers[k] = closers[end]
+			}
+		}
+
+		if p.closers == nil {
+			p.closers = map[delimiter][]int{}
+		}
+
+		p.closers[d] = closers
+	}
+
+	return closers[from]
+}
+
+// closingBracket returns the position of the ']' closing the '[' at s[i], -1 when there is none. Brackets nest, the
+// ones escaped or in code don't count
+func (p *inlineParser) closingBracket(i int) int {
+	if p.drops == nil {
+		s := p.s
+		drops := make([]int, len(s)+2)
+		drops[len(s)], drops[len(s)+1] = -1, -1
+
+		for k := len(s) - 1; k >= 0; k-- {
+			switch s[k] {
+			case ']':
+				drops[k] = k
+			case '[':
+				// past its own ']', the depth has to drop once more
+				if closing := drops[k+1]; closing >= 0 {
+					drops[k] = drops[closing+1]
+				} else {
+					drops[k] = -1
+				}
+			default:
+				drops[k] = drops[p.next(k)]
+			}
+		}
+
+		p.drops = drops
+	}
+
+	return p.drops[i+1]
+}
+
+// linkEnds holds, for every position of a text, where a link destination or title starting there ends, len(s)
+// when it doesn't
+type linkEnds struct {
+	// the '>' or the end of line after an opening '<'
+	angle []int
+	// the space, control character or ')' without its '(' ending a destination
+	destination []int
+	// the unescaped closing quote or parenthesis of a title
+	titles map[byte][]int
+	// the first character other than a space or a line break
+	text []int
+}
+
+// linkEnds builds the ends once. A backslash escapes any character of a destination or a title, they never start
+// right after one so the escapes are the same when counted from the start of the text
+func (p *inlineParser) linkEnds() *linkEnds {
+	if p.ends != nil {
+		return p.ends
+	}
+
+	s := p.s
+	n := len(s)
+
+	escaped := make([]bool, n)
+	// depth of the parentheses before every position
+	depth := make([]int, n)
+
+	for k, d := 0, 0; k < n; k++ {
+		depth[k] = d
+
+		switch {
+		case escaped[k]:
+		case s[k] == '\\' && k+1 < n:
+			escaped[k+1] = true
+		case s[k] == '(':
+			d++
+		case s[k] == ')':
+			d--
+		}
+	}
+
+	ends := &linkEnds{
+		angle: make([]int, n+1), destination: make([]int, n+1), text: make([]int, n+1),
+		titles: map[byte][]int{'"': make([]int, n+1), '\'': make([]int, n+1), ')': make([]int, n+1)},
+	}
+
+	angle, space, text := n, n, n
+	titles := map[byte]int{'"': n, '\'': n, ')': n}
+	// the first ')' at every depth, a destination ends on the one at its starting depth
+	parens := map[int]int{}
+
+	for k := n; k >= 0; k-- {
+		destination := space
+
+		if k < n {
+			if s[k] == '>' || s[k] == '\n' {
+				angle = k
+			}
+
+			if s[k] != ' ' && s[k] != '\n' {
+				text = k
+			}
+
+			if !escaped[k] {
+				if s[k] <= ' ' {
+					space = k
+				}
+
+				if _, ok := titles[s[k]]; ok {
+					titles[s[k]] = k
+				}
+
+				if s[k] == ')' {
+					parens[depth[k]] = k
+				}
+			}
+
+			destination = space
+			if paren, ok := parens[depth[k]]; ok && paren < destination {
+				destination = paren
+			}
+		}
+
+		ends.angle[k], ends.destination[k], ends.text[k] = angle, destination, text
+
+		for c, end := range titles {
+			ends.titles[c][k] = end
+		}
+	}
+
+	p.ends = ends
+
+	return ends
+}
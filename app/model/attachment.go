@@ -0,0 +1,62 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrQuotaExceeded is returned when an attachment doesn't fit in the storage quota of its user
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+type Attachment struct {
+	ID          int32  `json:"id"`
+	NoteID      int32  `json:"note_id"`
+	UserID      int32  `json:"user_id"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	BlobKey     string `json:"-"`
+	// empty when the attachment isn't an image
+	ThumbnailKey  string `json:"-"`
+	ThumbnailSize int64  `json:"-"`
+	HasThumbnail  bool   `json:"has_thumbnail"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// Upload is an attachment sent by the client
+type Upload struct {
+	Name    string
+	Size    int64
+	Content io.ReadSeeker
+}
+
+// BlobStore stores the content of the attachments
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns a reader of the blob, size is the known size of the blob
+	Open(ctx context.Context, key string, size int64) (io.ReadSeekCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// AttachmentRepository represent the attachment's repository contract
+type AttachmentRepository interface {
+	// CreateAttachment returns ErrQuotaExceeded when the attachments of the user, thumbnails included, would take
+	// more than quota bytes along with this one. A zero quota is no limit
+	CreateAttachment(ctx context.Context, attachment *Attachment, quota int64) error
+	GetAttachment(ctx context.Context, id, noteID int32) (Attachment, error)
+	FetchAttachments(ctx context.Context, noteID int32) ([]Attachment, error)
+	DeleteAttachment(ctx context.Context, id int32) error
+	UsedStorage(ctx context.Context, userID int32) (int64, error)
+}
+
+// AttachmentUsecase represent the attachment's usecase contract
+type AttachmentUsecase interface {
+	EventPublisher
+	Upload(c context.Context, noteID, userID int32, upload *Upload) (*Attachment, error)
+	Fetch(c context.Context, noteID, userID int32) ([]Attachment, error)
+	Get(c context.Context, id, noteID, userID int32) (*Attachment, error)
+	// Open returns the content of the attachment or its thumbnail
+	Open(c context.Context, attachment *Attachment, thumbnail bool) (io.ReadSeekCloser, error)
+	Delete(c context.Context, id, noteID, userID int32) error
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"context"
+)
+
+// actions recorded by the audit log
+const (
+	AuditLogin           = "login"
+	AuditLoginFailed     = "login.failed"
+	AuditLogout          = "logout"
+	AuditTokenCreated    = "token.created"
+	AuditPasswordChanged = "password.changed"
+	AuditAccountDeleted  = "account.deleted"
+	// the admin actions, from the API or the command line
+	AuditAdminAuditViewed = "admin.audit_viewed"
+	AuditAdminExport      = "admin.export"
+	AuditAdminImport      = "admin.import"
+	AuditAdminGranted     = "admin.granted"
+	AuditAdminRevoked     = "admin.revoked"
+)
+
+// AuditActions are all the recorded actions
+var AuditActions = []string{
+	AuditLogin, AuditLoginFailed, AuditLogout, AuditTokenCreated, AuditPasswordChanged, AuditAccountDeleted,
+	AuditAdminAuditViewed, AuditAdminExport, AuditAdminImport, AuditAdminGranted, AuditAdminRevoked,
+}
+
+// IsAuditAction tells whether action is one of the recorded actions
+func IsAuditAction(action string) bool {
+	for _, auditAction := range AuditActions {
+		if auditAction == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+type clientKey struct{}
+
+// Client is who sent the request
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// WithClient keeps the client of the request in ctx, for the usecases recording it
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFrom returns the client kept by WithClient, it's empty outside of a request, like in the commands
+func ClientFrom(ctx context.Context) Client {
+	client, _ := ctx.Value(clientKey{}).(Client)
+
+	return client
+}
+
+// AuditEvent records who did a security relevant action and when, it's never changed afterwards
+type AuditEvent struct {
+	ID int32 `json:"id"`
+	// the account the action is about, nil when there is none, like a login with an unknown email
+	UserID *int32 `json:"user_id"`
+	// who did the action, nil when it's anonymous or from the command line
+	ActorID   *int32 `json:"actor_id"`
+	Action    string `json:"action"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	// stored json encoded
+	Details   map[string]interface{} `json:"details"`
+	CreatedAt string                 `json:"created_at"`
+}
+
+// AuditFilter narrows the audit events down, the empty fields match all of them
+type AuditFilter struct {
+	UserID  *int32
+	ActorID *int32
+	Action  string
+	// UTC times, since is inclusive and until exclusive
+	Since string
+	Until string
+}
+
+// AuditRepository represent the audit log's repository contract, it only appends
+type AuditRepository interface {
+	CreateEvent(ctx context.Context, event *AuditEvent) error
+	// FetchEvents returns the latest events first
+	FetchEvents(ctx context.Context, filter AuditFilter, limit, offset int) ([]AuditEvent, int, error)
+}
+
+// AuditRecorder records the audit events, the failures are only logged so the recorded action goes on
+type AuditRecorder interface {
+	Record(c context.Context, event *AuditEvent)
+}
+
+// AuditUsecase represent the audit log's usecase contract
+type AuditUsecase interface {
+	AuditRecorder
+	// Fetch returns the events of the user's own account
+	Fetch(c context.Context, userID int32, filter AuditFilter, page, pageSize int) ([]AuditEvent, int, error)
+	// FetchAll returns the events of all the accounts to the admins
+	FetchAll(c context.Context, adminID int32, filter AuditFilter, page, pageSize int) ([]AuditEvent, int, error)
+}
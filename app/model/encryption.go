@@ -0,0 +1,61 @@
+package model
+
+import "context"
+
+// EncryptedColumns are the columns encrypted at rest, as table.column. Some content of the notes stays in clear:
+//   - notes_links.url, the links are looked up by them
+//   - notes.drawing and templates.drawing, the strokes of the drawings
+//   - labels.name, templates.name and attachments.name
+//   - notes.encrypted, encrypted by the clients already
+var EncryptedColumns = []string{
+	"notes.title", "notes.body", "notes_items.text", "notes_revisions.snapshot", "notes_references.title",
+	"templates.title", "templates.body", "webhooks_deliveries.payload", "reminders_events.message",
+}
+
+// HashedColumns are the encrypted columns looked up by the keyed hashes of their values, which are kept in the
+// column of the same name ending with _hash, as table.column
+var HashedColumns = []string{"notes_references.title"}
+
+// EncryptedItemColumns are the json encoded items whose texts are encrypted at rest, as table.column
+var EncryptedItemColumns = []string{"templates.items"}
+
+// EncryptedValue is a value of an encrypted column, Value is nil for NULL
+type EncryptedValue struct {
+	ID    int32
+	Value *string
+	// the keyed hash of the value, for HashedColumns only
+	Hash *string
+}
+
+// KeyRotationReport counts what rotating the keys re-encrypted
+type KeyRotationReport struct {
+	// by column of EncryptedColumns and EncryptedItemColumns
+	Values map[string]int
+	// by column of HashedColumns
+	Hashes map[string]int
+	// the attachments and their thumbnails
+	Blobs int
+}
+
+// KeyRotationRepository reads and writes the encrypted columns of any user
+type KeyRotationRepository interface {
+	// FetchValues returns up to limit values of a column of EncryptedColumns or EncryptedItemColumns, of the rows
+	// after the row afterID in the id order
+	FetchValues(ctx context.Context, column string, afterID int32, limit int) ([]EncryptedValue, error)
+	// UpdateValue replaces the value of the row unless it changed meanwhile, it tells whether it replaced it
+	UpdateValue(ctx context.Context, column string, id int32, old, value string) (bool, error)
+	// FetchHashes returns up to limit values of a column of HashedColumns along with their hashes, of the rows
+	// after the row afterID in the id order
+	FetchHashes(ctx context.Context, column string, afterID int32, limit int) ([]EncryptedValue, error)
+	// UpdateHash replaces the hash of the row unless its value changed meanwhile, it tells whether it replaced it
+	UpdateHash(ctx context.Context, column string, id int32, value, hash string) (bool, error)
+	// FetchAttachments returns up to limit attachments after the attachment afterID in the id order
+	FetchAttachments(ctx context.Context, afterID int32, limit int) ([]Attachment, error)
+}
+
+// KeyRotationUsecase represent the key rotation's usecase contract
+type KeyRotationUsecase interface {
+	// Rotate re-encrypts by the primary key the values and the blobs encrypted by other keys or stored in clear,
+	// and hashes again the values hashed by other keys, batchSize rows at once
+	Rotate(c context.Context, batchSize int) (*KeyRotationReport, error)
+}
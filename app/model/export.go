@@ -0,0 +1,34 @@
+package model
+
+import (
+	"context"
+	"io"
+)
+
+// AccountExport is all the data of a user, the export dumps it as librenote.json
+type AccountExport struct {
+	ExportedAt string         `json:"exported_at"`
+	User       UserDetails    `json:"user"`
+	Labels     []Label        `json:"labels"`
+	Notes      []ExportedNote `json:"notes"`
+	Webhooks   []Webhook      `json:"webhooks"`
+	// the encrypted notes can't be read back without it
+	KeyEnvelope *KeyEnvelope `json:"key_envelope,omitempty"`
+}
+
+// ExportedNote is a note along with the data attached to it
+type ExportedNote struct {
+	Note
+	Labels      []string       `json:"labels"`
+	Attachments []Attachment   `json:"attachments"`
+	Reminders   []Reminder     `json:"reminders"`
+	Revisions   []NoteRevision `json:"revisions"`
+}
+
+// ExportUsecase represent the export's usecase contract
+type ExportUsecase interface {
+	// Export collects the data of the user, so failures are known before writing anything
+	Export(c context.Context, userID int32) (*AccountExport, error)
+	// WriteZip streams the export as a zip of Markdown notes, attachments and librenote.json
+	WriteZip(c context.Context, export *AccountExport, w io.Writer) error
+}
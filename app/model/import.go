@@ -0,0 +1,71 @@
+package model
+
+import (
+	"context"
+	"io"
+	"io/fs"
+)
+
+// ImportedNote is a note read from an export of another application, not stored yet
+type ImportedNote struct {
+	// Source is the file of the export the note was read from
+	Source      string
+	Note        Note
+	Labels      []string
+	Attachments []ImportedAttachment
+}
+
+type ImportedAttachment struct {
+	Name string
+	Size int64
+	Open func() (io.ReadCloser, error)
+}
+
+// ImportBatch is what an importer read from an export
+type ImportBatch struct {
+	Notes []ImportedNote
+	// Labels lists every label of the export, including the ones without notes
+	Labels  []string
+	Skipped []ImportSkip
+}
+
+// Importer reads the export of another application
+type Importer interface {
+	// Name is the source of the import endpoint and command, like keep
+	Name() string
+	// Parse reports the files which aren't notes as skipped, an error means nothing could be read
+	Parse(fsys fs.FS) (*ImportBatch, error)
+}
+
+// ImportReport tells what an import created, or would create on a dry run
+type ImportReport struct {
+	Source      string       `json:"source"`
+	DryRun      bool         `json:"dry_run"`
+	Notes       int          `json:"notes"`
+	Items       int          `json:"items"`
+	Labels      []string     `json:"labels"`
+	Attachments int          `json:"attachments"`
+	Imported    []ImportItem `json:"imported"`
+	Skipped     []ImportSkip `json:"skipped"`
+}
+
+// ImportItem is a note created by an import
+type ImportItem struct {
+	Source string  `json:"source"`
+	Title  *string `json:"title"`
+	// not set on a dry run
+	NoteID int32 `json:"note_id,omitempty"`
+}
+
+// ImportSkip is a file of the export, a note or an attachment which couldn't be imported
+type ImportSkip struct {
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+}
+
+// ImportUsecase represent the import's usecase contract
+type ImportUsecase interface {
+	// Sources names the importers
+	Sources() []string
+	Import(c context.Context, source string, userID int32, fsys fs.FS, dryRun bool) (*ImportReport, error)
+}
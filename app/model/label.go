@@ -1,10 +1,53 @@
 package model
 
+import (
+	"context"
+)
+
 type Label struct {
-	ID        int32  `json:"id"`
-	Name      string `json:"name"`
-	UserID    int32  `json:"user_id"`
+	ID     int32  `json:"id"`
+	Name   string `json:"name"`
+	UserID int32  `json:"user_id"`
+	// ParentID is nil for a top level label
+	ParentID  *int32 `json:"parent_id"`
 	IsTrashed int8   `json:"is_trashed"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
+
+// LabelNode is a label of the label tree with its sub labels
+type LabelNode struct {
+	Label
+	Children []LabelNode `json:"children"`
+}
+
+// LabelRepository represent the label's repository contract
+type LabelRepository interface {
+	CreateLabel(ctx context.Context, label *Label) error
+	GetLabel(ctx context.Context, id, userID int32) (Label, error)
+	FetchLabels(ctx context.Context, userID int32) ([]Label, error)
+	UpdateLabel(ctx context.Context, label *Label) error
+	// DeleteLabel moves the sub labels of the label to its parent, the notes and the templates lose the label
+	DeleteLabel(ctx context.Context, id, userID int32) error
+	// FetchNotesLabels returns the labels of all the notes of the user
+	FetchNotesLabels(ctx context.Context, userID int32) ([]NotesLabel, error)
+	// FetchNoteLabelIDs returns the ids of the labels of the note, by name
+	FetchNoteLabelIDs(ctx context.Context, noteID, userID int32) ([]int32, error)
+	// AddNoteLabel is a no-op when the note already has the label
+	AddNoteLabel(ctx context.Context, noteID, labelID int32) error
+}
+
+// LabelUsecase represent the label's usecase contract
+type LabelUsecase interface {
+	Create(c context.Context, m *Label) error
+	Fetch(c context.Context, userID int32) ([]Label, error)
+	// Tree returns the top level labels with their sub labels
+	Tree(c context.Context, userID int32) ([]LabelNode, error)
+	Get(c context.Context, id, userID int32) (*Label, error)
+	// Update renames the label, Move changes its parent
+	Update(c context.Context, m *Label) error
+	Move(c context.Context, m *Label) error
+	Delete(c context.Context, id, userID int32) error
+	// FetchNotes returns the notes with the label or any of its sub labels
+	FetchNotes(c context.Context, id, userID int32, page, pageSize int) ([]Note, int, error)
+}
@@ -14,6 +14,80 @@ type UserUsecase struct {
 	mock.Mock
 }
 
+// AddColor provides a mock function with given fields: c, m
+func (_m *UserUsecase) AddColor(c context.Context, m *model.CustomColor) error {
+	ret := _m.Called(c, m)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.CustomColor) error); ok {
+		r0 = rf(c, m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteColor provides a mock function with given fields: c, id, userID
+func (_m *UserUsecase) DeleteColor(c context.Context, id int32, userID int32) error {
+	ret := _m.Called(c, id, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32, int32) error); ok {
+		r0 = rf(c, id, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchColors provides a mock function with given fields: c, userID
+func (_m *UserUsecase) FetchColors(c context.Context, userID int32) ([]model.CustomColor, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 []model.CustomColor
+	if rf, ok := ret.Get(0).(func(context.Context, int32) []model.CustomColor); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.CustomColor)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetKeyEnvelope provides a mock function with given fields: c, userID
+func (_m *UserUsecase) GetKeyEnvelope(c context.Context, userID int32) (*model.KeyEnvelope, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 *model.KeyEnvelope
+	if rf, ok := ret.Get(0).(func(context.Context, int32) *model.KeyEnvelope); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.KeyEnvelope)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUser provides a mock function with given fields: c, id
 func (_m *UserUsecase) GetUser(c context.Context, id int32) (*model.User, error) {
 	ret := _m.Called(c, id)
@@ -81,6 +155,34 @@ func (_m *UserUsecase) Login(c context.Context, email string, password string) (
 	return r0, r1
 }
 
+// Logout provides a mock function with given fields: c, userID
+func (_m *UserUsecase) Logout(c context.Context, userID int32) {
+	_m.Called(c, userID)
+}
+
+// Meta provides a mock function with given fields: c, userID
+func (_m *UserUsecase) Meta(c context.Context, userID int32) (*model.Meta, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 *model.Meta
+	if rf, ok := ret.Get(0).(func(context.Context, int32) *model.Meta); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Meta)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Registration provides a mock function with given fields: c, m
 func (_m *UserUsecase) Registration(c context.Context, m *model.User) error {
 	ret := _m.Called(c, m)
@@ -95,6 +197,20 @@ func (_m *UserUsecase) Registration(c context.Context, m *model.User) error {
 	return r0
 }
 
+// SaveKeyEnvelope provides a mock function with given fields: c, m
+func (_m *UserUsecase) SaveKeyEnvelope(c context.Context, m *model.KeyEnvelope) error {
+	ret := _m.Called(c, m)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.KeyEnvelope) error); ok {
+		r0 = rf(c, m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: c, m, p
 func (_m *UserUsecase) Update(c context.Context, m *model.User, p model.Password) error {
 	ret := _m.Called(c, m, p)
@@ -1,39 +1,85 @@
 package model
 
 import (
-	"database/sql"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 )
 
-// var Colors = map[string]string{
-//	"red":       "red",
-//	"orange":    "orange",
-//	"yellow":    "yellow",
-//	"green":     "green",
-//	"teal":      "teal",
-//	"blue":      "blue",
-//	"dark blue": "dark blue",
-//	"purple":    "purple",
-//	"pink":      "pink",
-//	"brown":     "brown",
-//	"gray":      "gray",
-// }
-//
-// var NoteTypes = map[string]string{
-//	"note": "note",
-//	"list": "list",
-// }
+// Color is a named note color, the name is stored with the notes and the clients show the hex code
+type Color struct {
+	Name string `json:"name"`
+	Hex  string `json:"hex"`
+}
+
+// Colors is the palette of the note colors, in the order the clients offer them
+var Colors = []Color{
+	{Name: "red", Hex: "#f28b82"},
+	{Name: "orange", Hex: "#fbbc04"},
+	{Name: "yellow", Hex: "#fff475"},
+	{Name: "green", Hex: "#ccff90"},
+	{Name: "teal", Hex: "#a7ffeb"},
+	{Name: "blue", Hex: "#cbf0f8"},
+	{Name: "dark blue", Hex: "#aecbfa"},
+	{Name: "purple", Hex: "#d7aefb"},
+	{Name: "pink", Hex: "#fdcfe8"},
+	{Name: "brown", Hex: "#e6c9a8"},
+	{Name: "gray", Hex: "#e8eaed"},
+}
+
+// NoteTypes are the kinds of notes
+var NoteTypes = []string{"note", "list", "drawing"}
+
+// IsColor tells whether the name is a color of the palette
+func IsColor(name string) bool {
+	for _, color := range Colors {
+		if color.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsNoteType tells whether t is a kind of notes
+func IsNoteType(t string) bool {
+	for _, noteType := range NoteTypes {
+		if noteType == t {
+			return true
+		}
+	}
+
+	return false
+}
 
 type Note struct {
-	ID         int32          `json:"id"`
-	UserID     int32          `json:"user_id"`
-	Title      sql.NullString `json:"title"`
-	Color      string         `json:"color"`
-	Type       string         `json:"type"`
-	IsPinned   int8           `json:"is_pinned"`
-	IsArchived int8           `json:"is_archived"`
-	IsTrashed  int8           `json:"is_trashed"`
-	CreatedAt  string         `json:"created_at"`
-	UpdatedAt  string         `json:"updated_at"`
+	ID         int32       `json:"id"`
+	UserID     int32       `json:"user_id"`
+	Title      *string     `json:"title"`
+	Body       string      `json:"body"`
+	Color      string      `json:"color"`
+	Type       string      `json:"type"`
+	IsPinned   int8        `json:"is_pinned"`
+	IsArchived int8        `json:"is_archived"`
+	IsTrashed  int8        `json:"is_trashed"`
+	CreatedAt  string      `json:"created_at"`
+	UpdatedAt  string      `json:"updated_at"`
+	Items      []NotesItem `json:"items"`
+	// sorts the notes of the user, within the pinned and the unpinned ones
+	Position string `json:"position"`
+	// only set on drawing notes
+	Drawing *Drawing `json:"drawing,omitempty"`
+	// only set on end-to-end encrypted notes, their title, body and items are empty
+	Encrypted *EncryptedContent `json:"encrypted,omitempty"`
+	// the body rendered as sanitized HTML, only set when asked for
+	BodyHTML string `json:"body_html,omitempty"`
+	// destinations of the links of the body, the repository stores them along with the note
+	Links []string `json:"-"`
+	// references of the body to other notes, stored along with the note too
+	References []NoteReference `json:"-"`
+	// labels of the user the new note gets on creation, the other ones are skipped
+	LabelIDs []int32 `json:"-"`
 }
 
 type NotesItem struct {
@@ -41,10 +87,257 @@ type NotesItem struct {
 	NoteID    int32   `json:"note_id"`
 	Text      *string `json:"text"`
 	IsChecked int8    `json:"is_checked"`
+	Position  string  `json:"position"`
 	CreatedAt string  `json:"created_at"`
 }
 
+// Drawing is the content of a drawing note, vector strokes on a canvas
+type Drawing struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// #rrggbb, empty for white
+	Background string   `json:"background"`
+	Strokes    []Stroke `json:"strokes"`
+}
+
+// Stroke is a polyline, Points holds its coordinates flattened as [x1, y1, x2, y2, ...]
+type Stroke struct {
+	// #rrggbb or #rrggbbaa
+	Color  string    `json:"color"`
+	Width  float64   `json:"width"`
+	Points []float64 `json:"points"`
+}
+
+// Value stores the drawing as json
+func (d Drawing) Value() (driver.Value, error) {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func (d *Drawing) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	case []byte:
+		return json.Unmarshal(v, d)
+	}
+
+	return errors.New("unsupported drawing column type")
+}
+
+// EncryptedContent is the content of an end-to-end encrypted note, the client encrypts it with the master key of
+// the key envelope. The server stores it as it is and can't read it
+type EncryptedContent struct {
+	// base64 encoded
+	Ciphertext string `json:"ciphertext"`
+	// like AES-GCM or XChaCha20-Poly1305, with its base64 encoded nonce
+	Algorithm string `json:"algorithm"`
+	Nonce     string `json:"nonce"`
+	// the version of the master key the content is encrypted with
+	KeyVersion int32 `json:"key_version"`
+}
+
+// Value stores the encrypted content as json
+func (e EncryptedContent) Value() (driver.Value, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func (e *EncryptedContent) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	case []byte:
+		return json.Unmarshal(v, e)
+	}
+
+	return errors.New("unsupported encrypted column type")
+}
+
 type NotesLabel struct {
 	NoteID  int32 `json:"note_id"`
 	LabelID int32 `json:"label_id"`
 }
+
+// NoteReference is a reference of a note to another one, either by id or by title
+type NoteReference struct {
+	NoteID   int32
+	TargetID *int32
+	// lower cased, titles match case insensitively
+	Title *string
+	// keyed hash of the title when the encryption at rest is enabled, as the title is encrypted
+	TitleHash *string
+}
+
+// NoteSummary identifies a note in the backlinks and the graph of the notes
+type NoteSummary struct {
+	ID        int32   `json:"id"`
+	Title     *string `json:"title"`
+	Type      string  `json:"type"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+// NoteGraph is the references between the notes of an account, trashed notes are left out
+type NoteGraph struct {
+	Nodes []NoteSummary `json:"nodes"`
+	Edges []GraphEdge   `json:"edges"`
+}
+
+// GraphEdge is a reference of the note From to the note To
+type GraphEdge struct {
+	From int32 `json:"from"`
+	To   int32 `json:"to"`
+}
+
+// NoteFilter narrows down the notes of a user while fetching
+type NoteFilter struct {
+	UserID     int32
+	IsArchived int8
+	IsTrashed  int8
+	// LabelIDs keeps the notes having any of the labels, when not empty
+	LabelIDs []int32
+}
+
+// Actions of the bulk note operations
+const (
+	BulkPin         = "pin"
+	BulkUnpin       = "unpin"
+	BulkArchive     = "archive"
+	BulkUnarchive   = "unarchive"
+	BulkTrash       = "trash"
+	BulkRestore     = "restore"
+	BulkRecolor     = "recolor"
+	BulkAddLabel    = "add_label"
+	BulkRemoveLabel = "remove_label"
+	BulkDelete      = "delete"
+)
+
+// Statuses of the notes in the report of a bulk operation
+const (
+	BulkOK       = "ok"
+	BulkNotFound = "not_found"
+)
+
+// BulkOperation applies the action to the notes of IDs, or to the notes Filter matches when IDs is empty.
+// Color is the new color of the recolor action, LabelID the label of the label actions
+type BulkOperation struct {
+	UserID    int32
+	Action    string
+	IDs       []int32
+	Filter    *NoteFilter
+	Color     string
+	LabelID   int32
+	UpdatedAt string
+	// Revision builds the revision of a note changed by the action, the latest KeepRevisions are kept
+	Revision      func(note *Note) (*NoteRevision, error)
+	KeepRevisions int
+}
+
+// BulkResult is the outcome of a bulk operation on a note
+type BulkResult struct {
+	ID     int32  `json:"id"`
+	Status string `json:"status"`
+}
+
+// NoteRevision is a point in time copy of a note and its items
+type NoteRevision struct {
+	ID     int32 `json:"id"`
+	NoteID int32 `json:"note_id"`
+	// json encoded NoteSnapshot, as stored in the database
+	Snapshot  string        `json:"-"`
+	Content   *NoteSnapshot `json:"content,omitempty"`
+	CreatedAt string        `json:"created_at"`
+}
+
+// NoteSnapshot holds the user editable state of a note
+type NoteSnapshot struct {
+	Title      *string        `json:"title"`
+	Body       string         `json:"body,omitempty"`
+	Color      string         `json:"color"`
+	Type       string         `json:"type"`
+	IsPinned   int8           `json:"is_pinned"`
+	IsArchived int8           `json:"is_archived"`
+	Items      []SnapshotItem `json:"items"`
+	Drawing    *Drawing       `json:"drawing,omitempty"`
+	// the revisions of an encrypted note hold the ciphertexts
+	Encrypted *EncryptedContent `json:"encrypted,omitempty"`
+}
+
+type SnapshotItem struct {
+	Text      string `json:"text"`
+	IsChecked int8   `json:"is_checked"`
+}
+
+// RevisionDiff represent the changes between two revisions of a note
+type RevisionDiff struct {
+	From   int32         `json:"from"`
+	To     int32         `json:"to"`
+	Fields []FieldChange `json:"fields"`
+	Items  []ItemChange  `json:"items"`
+}
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// ItemChange operation is one of added, removed, checked or unchecked
+type ItemChange struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// NoteRepository represent the note's repository contract
+type NoteRepository interface {
+	CreateNote(ctx context.Context, note *Note, revision *NoteRevision) error
+	GetNote(ctx context.Context, id, userID int32) (Note, error)
+	FetchNotes(ctx context.Context, filter NoteFilter, limit, offset int) ([]Note, int, error)
+	UpdateNote(ctx context.Context, note *Note, revision *NoteRevision, keepRevisions int) error
+	DeleteNote(ctx context.Context, id, userID int32) error
+	FetchRevisions(ctx context.Context, noteID int32) ([]NoteRevision, error)
+	GetRevision(ctx context.Context, noteID, id int32) (NoteRevision, error)
+	// FetchBacklinks returns the notes referencing the note by its id or its lower cased title, which is matched
+	// against the hashes of the titles too
+	FetchBacklinks(ctx context.Context, id, userID int32, title string) ([]NoteSummary, error)
+	// FetchGraph returns the notes of the user which aren't trashed and their references
+	FetchGraph(ctx context.Context, userID int32) ([]NoteSummary, []NoteReference, error)
+	// BulkNotes applies the operation to the notes of op.IDs in a single transaction, a note of
+	// another user is reported as not found. A label of another user fails it with sql.ErrNoRows
+	BulkNotes(ctx context.Context, op BulkOperation) ([]BulkResult, error)
+	// NextPosition returns the smallest position after the given one among the notes of the user but
+	// the note exceptID, empty when there's none
+	NextPosition(ctx context.Context, userID, exceptID int32, after string) (string, error)
+	UpdatePosition(ctx context.Context, id, userID int32, position string) error
+	UpdateItemPosition(ctx context.Context, noteID, itemID int32, position string) error
+}
+
+// NoteUsecase represent the note's usecase contract
+type NoteUsecase interface {
+	Create(c context.Context, m *Note) error
+	Fetch(c context.Context, filter NoteFilter, page, pageSize int) ([]Note, int, error)
+	Get(c context.Context, id, userID int32) (*Note, error)
+	Update(c context.Context, m *Note) error
+	Delete(c context.Context, id, userID int32) error
+	FetchRevisions(c context.Context, noteID, userID int32) ([]NoteRevision, error)
+	GetRevision(c context.Context, noteID, userID, revisionID int32) (*NoteRevision, error)
+	DiffRevisions(c context.Context, noteID, userID, from, to int32) (*RevisionDiff, error)
+	RestoreRevision(c context.Context, noteID, userID, revisionID int32) (*Note, error)
+	RenderDrawing(c context.Context, id, userID int32, size int) ([]byte, error)
+	Backlinks(c context.Context, id, userID int32) ([]NoteSummary, error)
+	Graph(c context.Context, userID int32) (*NoteGraph, error)
+	// Bulk refuses operations on more than limit notes
+	Bulk(c context.Context, op *BulkOperation, limit int) ([]BulkResult, error)
+	// Move puts the note right after the note afterID, first when afterID is nil
+	Move(c context.Context, id, userID int32, afterID *int32) (*Note, error)
+	// MoveItem puts the item right after the item afterID of the note, first when afterID is nil
+	MoveItem(c context.Context, noteID, userID, itemID int32, afterID *int32) (*Note, error)
+}
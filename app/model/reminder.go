@@ -0,0 +1,73 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	ReminderPending = "pending"
+	ReminderDone    = "done"
+	ReminderFailed  = "failed"
+)
+
+type Reminder struct {
+	ID     int32 `json:"id"`
+	NoteID int32 `json:"note_id"`
+	UserID int32 `json:"user_id"`
+	// next occurrence in UTC
+	RemindAt string `json:"remind_at"`
+	// IANA time zone name, recurrences keep the wall clock time of this zone
+	Timezone string `json:"timezone"`
+	// RRULE subset like FREQ=WEEKLY;INTERVAL=2;UNTIL=20301231, empty for one-time reminder
+	Recurrence string `json:"recurrence"`
+	Channel    string `json:"channel"`
+	Target     string `json:"target"`
+	Status     string `json:"status"`
+	Attempts   int    `json:"attempts"`
+	// time of the next delivery attempt in UTC, moves forward on retries
+	DueAt     string `json:"-"`
+	LastError string `json:"last_error"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// ReminderEvent is an in-app notification produced by a due reminder
+type ReminderEvent struct {
+	ID         int32  `json:"id"`
+	UserID     int32  `json:"user_id"`
+	ReminderID int32  `json:"reminder_id"`
+	NoteID     int32  `json:"note_id"`
+	Message    string `json:"message"`
+	IsRead     int8   `json:"is_read"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// ReminderChannel delivers a due reminder through a medium like webhook or email
+type ReminderChannel interface {
+	Name() string
+	Deliver(ctx context.Context, reminder *Reminder, note *Note) error
+}
+
+// ReminderRepository represent the reminder's repository contract
+type ReminderRepository interface {
+	CreateReminder(ctx context.Context, reminder *Reminder) error
+	FetchReminders(ctx context.Context, noteID int32) ([]Reminder, error)
+	DeleteReminder(ctx context.Context, id, noteID int32) error
+	FetchDueReminders(ctx context.Context, now string, limit int) ([]Reminder, error)
+	ClaimReminder(ctx context.Context, id int32, dueAt, leaseUntil string) (bool, error)
+	UpdateReminder(ctx context.Context, reminder *Reminder) error
+	CreateEvent(ctx context.Context, event *ReminderEvent) error
+	FetchEvents(ctx context.Context, userID int32, unreadOnly bool) ([]ReminderEvent, error)
+	MarkEventRead(ctx context.Context, id, userID int32) error
+}
+
+// ReminderUsecase represent the reminder's usecase contract
+type ReminderUsecase interface {
+	Create(c context.Context, m *Reminder) error
+	Fetch(c context.Context, noteID, userID int32) ([]Reminder, error)
+	Delete(c context.Context, id, noteID, userID int32) error
+	FetchEvents(c context.Context, userID int32, unreadOnly bool) ([]ReminderEvent, error)
+	MarkEventRead(c context.Context, id, userID int32) error
+	DeliverDue(c context.Context, now time.Time) (int, error)
+}
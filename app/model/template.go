@@ -0,0 +1,43 @@
+package model
+
+import (
+	"context"
+)
+
+// Template is a blueprint of notes made from a note. Its title, body and items may hold variables like {{date}},
+// they are replaced when a note is created from the template
+type Template struct {
+	ID        int32          `json:"id"`
+	UserID    int32          `json:"user_id"`
+	Name      string         `json:"name"`
+	Title     *string        `json:"title"`
+	Body      string         `json:"body"`
+	Color     string         `json:"color"`
+	Type      string         `json:"type"`
+	Items     []SnapshotItem `json:"items"`
+	Drawing   *Drawing       `json:"drawing,omitempty"`
+	LabelIDs  []int32        `json:"label_ids"`
+	CreatedAt string         `json:"created_at"`
+	UpdatedAt string         `json:"updated_at"`
+}
+
+// TemplateRepository represent the template's repository contract
+type TemplateRepository interface {
+	// CreateTemplate stores the template along with its labels
+	CreateTemplate(ctx context.Context, template *Template) error
+	GetTemplate(ctx context.Context, id, userID int32) (Template, error)
+	FetchTemplates(ctx context.Context, userID int32) ([]Template, error)
+	DeleteTemplate(ctx context.Context, id, userID int32) error
+}
+
+// TemplateUsecase represent the template's usecase contract
+type TemplateUsecase interface {
+	// CreateFromNote copies the content and the labels of the note into the template
+	CreateFromNote(c context.Context, noteID int32, m *Template) error
+	Fetch(c context.Context, userID int32) ([]Template, error)
+	Get(c context.Context, id, userID int32) (*Template, error)
+	Delete(c context.Context, id, userID int32) error
+	// Instantiate creates a note from the template. The variables are added to the built-in ones, date and time
+	// are formatted in the timezone, UTC when empty
+	Instantiate(c context.Context, id, userID int32, variables map[string]string, timezone string) (*Note, error)
+}
@@ -25,12 +25,73 @@ type UserDetails struct {
 	DarkModeEnabled int8   `json:"dark_mode_enabled"`
 }
 
+// CustomColor is a note color the user added to the palette
+type CustomColor struct {
+	ID        int32  `json:"id"`
+	UserID    int32  `json:"user_id"`
+	Name      string `json:"name"`
+	Hex       string `json:"hex"`
+	CreatedAt string `json:"created_at"`
+}
+
+// KeyEnvelope holds the master key of the encrypted notes, wrapped with a key the client derives from a passphrase.
+// The server never sees the passphrase nor the master key
+type KeyEnvelope struct {
+	UserID int32 `json:"user_id"`
+	// base64 encoded
+	WrappedKey string `json:"wrapped_key"`
+	// how the master key is wrapped, like AES-KW or AES-GCM, with its base64 encoded nonce when it takes one
+	Algorithm string `json:"algorithm"`
+	Nonce     string `json:"nonce"`
+	// derives the wrapping key from the passphrase, like argon2id or pbkdf2-sha256, with its parameters
+	KDF         string `json:"kdf"`
+	Salt        string `json:"salt"`
+	Iterations  int32  `json:"iterations"`
+	Memory      int32  `json:"memory"`
+	Parallelism int32  `json:"parallelism"`
+	// increases with every new master key, a new passphrase wraps the same master key again
+	KeyVersion int32  `json:"key_version"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// LoginFailures counts the failed logins of a user since the last successful one
+type LoginFailures struct {
+	UserID   int32 `json:"user_id"`
+	Attempts int32 `json:"attempts"`
+	// nil when the account isn't locked
+	LockedUntil *string `json:"locked_until"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+// Meta describes the values the notes of the user accept
+type Meta struct {
+	Colors       []Color       `json:"colors"`
+	CustomColors []CustomColor `json:"custom_colors"`
+	NoteTypes    []string      `json:"note_types"`
+}
+
 // UserRepository represent the user's repository contract
 type UserRepository interface {
 	CreateUser(tx context.Context, user *User) error
 	GetUser(tx context.Context, id int32) (User, error)
 	GetUserByEmail(tx context.Context, email string) (User, error)
 	UpdateUser(tx context.Context, user *User) error
+	CreateColor(tx context.Context, color *CustomColor) error
+	FetchColors(tx context.Context, userID int32) ([]CustomColor, error)
+	DeleteColor(tx context.Context, id, userID int32) error
+	GetKeyEnvelope(tx context.Context, userID int32) (KeyEnvelope, error)
+	// SaveKeyEnvelope replaces the key envelope of the user, if any
+	SaveKeyEnvelope(tx context.Context, envelope *KeyEnvelope) error
+	GetLoginFailures(tx context.Context, userID int32) (LoginFailures, error)
+	// AddLoginFailure counts a failed login of the user at once, it returns the failed attempts
+	AddLoginFailure(tx context.Context, userID int32, updatedAt string) (int32, error)
+	// LockLogin locks the account until lockedUntil, unless it's already locked for longer
+	LockLogin(tx context.Context, userID int32, lockedUntil string) error
+	DeleteLoginFailures(tx context.Context, userID int32) error
+	// IsAdmin tells if the user can read the audit log of all the accounts, set by the admin command
+	IsAdmin(tx context.Context, userID int32) (bool, error)
+	SetAdmin(tx context.Context, userID int32, admin bool) error
 }
 
 // Password struct
@@ -44,7 +105,16 @@ type Password struct {
 type UserUsecase interface {
 	Registration(c context.Context, m *User) (err error)
 	Login(c context.Context, email, password string) (token string, err error)
+	// Logout records the end of the session, the token stays valid until it expires
+	Logout(c context.Context, userID int32)
 	GetUserDetails(c context.Context, id int32) (user *UserDetails, err error)
 	GetUser(c context.Context, id int32) (user *User, err error)
 	Update(c context.Context, m *User, p Password) error
+	Meta(c context.Context, userID int32) (*Meta, error)
+	FetchColors(c context.Context, userID int32) ([]CustomColor, error)
+	AddColor(c context.Context, m *CustomColor) error
+	DeleteColor(c context.Context, id, userID int32) error
+	GetKeyEnvelope(c context.Context, userID int32) (*KeyEnvelope, error)
+	// SaveKeyEnvelope refuses to go back to an older master key
+	SaveKeyEnvelope(c context.Context, m *KeyEnvelope) error
 }
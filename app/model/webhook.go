@@ -0,0 +1,88 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// events published to the webhooks
+const (
+	EventNoteCreated    = "note.created"
+	EventNoteUpdated    = "note.updated"
+	EventNoteDeleted    = "note.deleted"
+	EventLabelCreated   = "label.created"
+	EventLabelUpdated   = "label.updated"
+	EventLabelDeleted   = "label.deleted"
+	EventAccountUpdated = "account.updated"
+	EventAccountDeleted = "account.deleted"
+	// EventPing is only sent by the test endpoint
+	EventPing = "ping"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	ID     int32  `json:"id"`
+	UserID int32  `json:"user_id"`
+	URL    string `json:"url"`
+	// key of the HMAC-SHA256 payload signature
+	Secret string `json:"-"`
+	// subscribed event names, * subscribes to all
+	Events    []string `json:"events"`
+	IsActive  int8     `json:"is_active"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// WebhookDelivery is a queued event for a webhook, kept afterwards as the delivery log
+type WebhookDelivery struct {
+	ID        int32  `json:"id"`
+	WebhookID int32  `json:"webhook_id"`
+	UserID    int32  `json:"user_id"`
+	Event     string `json:"event"`
+	Payload   string `json:"payload"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+	// time of the next delivery attempt in UTC
+	DueAt          string `json:"-"`
+	ResponseStatus int    `json:"response_status"`
+	LastError      string `json:"last_error"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+// EventPublisher notifies the subscribers about changes of a user's data
+type EventPublisher interface {
+	Publish(c context.Context, userID int32, event string, data interface{})
+}
+
+// WebhookRepository represent the webhook's repository contract
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *Webhook) error
+	GetWebhook(ctx context.Context, id, userID int32) (Webhook, error)
+	FetchWebhooks(ctx context.Context, userID int32) ([]Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *Webhook) error
+	DeleteWebhook(ctx context.Context, id, userID int32) error
+	CreateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	FetchDeliveries(ctx context.Context, webhookID int32, limit, offset int) ([]WebhookDelivery, int, error)
+	FetchDueDeliveries(ctx context.Context, now string, limit int) ([]WebhookDelivery, error)
+	ClaimDelivery(ctx context.Context, id int32, dueAt, leaseUntil string) (bool, error)
+	UpdateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+}
+
+// WebhookUsecase represent the webhook's usecase contract
+type WebhookUsecase interface {
+	EventPublisher
+	Create(c context.Context, m *Webhook) error
+	Fetch(c context.Context, userID int32) ([]Webhook, error)
+	Get(c context.Context, id, userID int32) (*Webhook, error)
+	Update(c context.Context, m *Webhook) error
+	Delete(c context.Context, id, userID int32) error
+	FetchDeliveries(c context.Context, id, userID int32, page, pageSize int) ([]WebhookDelivery, int, error)
+	SendTest(c context.Context, id, userID int32) (*WebhookDelivery, error)
+	DeliverDue(c context.Context, now time.Time) (int, error)
+}
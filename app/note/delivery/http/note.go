@@ -0,0 +1,534 @@
+package http
+
+import (
+	"librenote/app/markdown"
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/app/validation"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NoteHandler represent the http handler for note
+type NoteHandler struct {
+	NUseCase model.NoteUsecase
+}
+
+func NewNoteHandler(e *echo.Echo, us model.NoteUsecase) {
+	handler := &NoteHandler{
+		NUseCase: us,
+	}
+
+	notes := e.Group("/api/v1/notes")
+	_ = middlewares.AttachJwtToGroup(notes)
+	notes.GET("", handler.FetchNotes)
+	notes.POST("", handler.CreateNote)
+	notes.GET("/graph", handler.Graph)
+	notes.POST("/bulk", handler.BulkNotes)
+	notes.GET("/:id", handler.GetNote)
+	notes.PUT("/:id", handler.UpdateNote)
+	notes.DELETE("/:id", handler.DeleteNote)
+	notes.GET("/:id/revisions", handler.FetchRevisions)
+	notes.GET("/:id/revisions/diff", handler.DiffRevisions)
+	notes.GET("/:id/revisions/:revision_id", handler.GetRevision)
+	notes.POST("/:id/revisions/:revision_id/restore", handler.RestoreRevision)
+	notes.GET("/:id/drawing.png", handler.RenderDrawing)
+	notes.GET("/:id/backlinks", handler.FetchBacklinks)
+	notes.PUT("/:id/position", handler.MoveNote)
+	notes.PUT("/:id/items/:item_id/position", handler.MoveItem)
+}
+
+func (n *NoteHandler) FetchNotes(c echo.Context) error {
+	var fReq fetchNotesReq
+
+	err := c.Bind(&fReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&fReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	cfg := config.Get().App
+
+	if fReq.Page == 0 {
+		fReq.Page = 1
+	}
+
+	if fReq.PageSize == 0 {
+		fReq.PageSize = cfg.DefaultPageSize
+	}
+
+	if cfg.MaxPageSize > 0 && fReq.PageSize > cfg.MaxPageSize {
+		fReq.PageSize = cfg.MaxPageSize
+	}
+
+	filter := model.NoteFilter{
+		UserID:     middlewares.GetUserID(c),
+		IsArchived: fReq.IsArchived,
+		IsTrashed:  fReq.IsTrashed,
+	}
+
+	ctx := c.Request().Context()
+
+	notes, count, err := n.NUseCase.Fetch(ctx, filter, fReq.Page, fReq.PageSize)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	if fReq.Render == "html" {
+		for i := range notes {
+			renderBody(&notes[i])
+		}
+	}
+
+	return c.JSON(response.RespondPage("request success", notes, count, fReq.Page, fReq.PageSize))
+}
+
+func (n *NoteHandler) CreateNote(c echo.Context) error {
+	var nReq noteReq
+
+	err := c.Bind(&nReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&nReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	note := model.Note{
+		UserID:    middlewares.GetUserID(c),
+		CreatedAt: nowTime,
+	}
+	applyNoteReq(&note, &nReq, nowTime)
+
+	ctx := c.Request().Context()
+
+	err = n.NUseCase.Create(ctx, &note)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("note created", note))
+}
+
+func (n *NoteHandler) GetNote(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var gReq getNoteReq
+
+	err = c.Bind(&gReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&gReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	ctx := c.Request().Context()
+
+	note, err := n.NUseCase.Get(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	if gReq.Render == "html" {
+		renderBody(note)
+	}
+
+	return c.JSON(response.RespondSuccess("request success", note))
+}
+
+func (n *NoteHandler) UpdateNote(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var nReq noteReq
+
+	err = c.Bind(&nReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&nReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	ctx := c.Request().Context()
+
+	note, err := n.NUseCase.Get(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	applyNoteReq(note, &nReq, time.Now().UTC().Format("2006-01-02 15:04:05"))
+
+	err = n.NUseCase.Update(ctx, note)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("updated successfully", note))
+}
+
+func (n *NoteHandler) DeleteNote(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	err = n.NUseCase.Delete(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (n *NoteHandler) FetchRevisions(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	revisions, err := n.NUseCase.FetchRevisions(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", revisions))
+}
+
+func (n *NoteHandler) GetRevision(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	revisionID, err := middlewares.ParamID(c, "revision_id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	revision, err := n.NUseCase.GetRevision(ctx, id, middlewares.GetUserID(c), revisionID)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", revision))
+}
+
+func (n *NoteHandler) DiffRevisions(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var dReq diffRevisionsReq
+
+	err = c.Bind(&dReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&dReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	ctx := c.Request().Context()
+
+	diff, err := n.NUseCase.DiffRevisions(ctx, id, middlewares.GetUserID(c), dReq.From, dReq.To)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", diff))
+}
+
+func (n *NoteHandler) RestoreRevision(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	revisionID, err := middlewares.ParamID(c, "revision_id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	note, err := n.NUseCase.RestoreRevision(ctx, id, middlewares.GetUserID(c), revisionID)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("restored successfully", note))
+}
+
+// applyNoteReq copy the request payload into the note, items are listed as a whole, the existing ones by id
+func applyNoteReq(note *model.Note, nReq *noteReq, nowTime string) {
+	note.Title = nReq.Title
+	note.Body = nReq.Body
+	note.Color = nReq.Color
+	note.Type = nReq.Type
+	note.IsPinned = nReq.IsPinned
+	note.IsArchived = nReq.IsArchived
+	note.IsTrashed = nReq.IsTrashed
+	note.UpdatedAt = nowTime
+	note.Items = make([]model.NotesItem, 0, len(nReq.Items))
+
+	for i := range nReq.Items {
+		note.Items = append(note.Items, model.NotesItem{
+			ID:        nReq.Items[i].ID,
+			Text:      &nReq.Items[i].Text,
+			IsChecked: nReq.Items[i].IsChecked,
+			CreatedAt: nowTime,
+		})
+	}
+
+	note.Drawing = nil
+	if nReq.Drawing != nil {
+		note.Drawing = &model.Drawing{
+			Width:      nReq.Drawing.Width,
+			Height:     nReq.Drawing.Height,
+			Background: nReq.Drawing.Background,
+			Strokes:    make([]model.Stroke, 0, len(nReq.Drawing.Strokes)),
+		}
+
+		for _, stroke := range nReq.Drawing.Strokes {
+			note.Drawing.Strokes = append(note.Drawing.Strokes, model.Stroke(stroke))
+		}
+	}
+
+	note.Encrypted = nil
+	if nReq.Encrypted != nil {
+		encrypted := model.EncryptedContent(*nReq.Encrypted)
+		note.Encrypted = &encrypted
+	}
+}
+
+// renderBody sets the sanitized HTML of the Markdown body, so that every client shows the same
+func renderBody(note *model.Note) {
+	note.BodyHTML = markdown.Render(note.Body)
+}
+
+// RenderDrawing serves a drawing note as png, `?size=` scales its longest side for previews
+func (n *NoteHandler) RenderDrawing(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var rReq renderDrawingReq
+
+	err = c.Bind(&rReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&rReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	ctx := c.Request().Context()
+
+	image, err := n.NUseCase.RenderDrawing(ctx, id, middlewares.GetUserID(c), rReq.Size)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.Blob(http.StatusOK, "image/png", image)
+}
+
+// FetchBacklinks lists the notes referencing the note, by its id or its title
+func (n *NoteHandler) FetchBacklinks(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	backlinks, err := n.NUseCase.Backlinks(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", backlinks))
+}
+
+// Graph returns the notes of the account as nodes and their references as edges
+func (n *NoteHandler) Graph(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	graph, err := n.NUseCase.Graph(ctx, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", graph))
+}
+
+// BulkNotes applies an action to many notes at once, the notes are limited like the notes of a page
+func (n *NoteHandler) BulkNotes(c echo.Context) error {
+	var bReq bulkNotesReq
+
+	err := c.Bind(&bReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&bReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	op := model.BulkOperation{
+		UserID:    middlewares.GetUserID(c),
+		Action:    bReq.Action,
+		IDs:       bReq.IDs,
+		Color:     bReq.Color,
+		LabelID:   bReq.LabelID,
+		UpdatedAt: time.Now().UTC().Format("2006-01-02 15:04:05"),
+	}
+
+	if bReq.Filter != nil {
+		op.Filter = &model.NoteFilter{
+			IsArchived: bReq.Filter.IsArchived,
+			IsTrashed:  bReq.Filter.IsTrashed,
+			LabelIDs:   bReq.Filter.LabelIDs,
+		}
+	}
+
+	ctx := c.Request().Context()
+
+	results, err := n.NUseCase.Bulk(ctx, &op, config.Get().App.MaxPageSize)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("bulk operation done", results))
+}
+
+func (n *NoteHandler) MoveNote(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var mReq moveReq
+
+	err = c.Bind(&mReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&mReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	ctx := c.Request().Context()
+
+	note, err := n.NUseCase.Move(ctx, id, middlewares.GetUserID(c), mReq.AfterID)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("note moved", note))
+}
+
+func (n *NoteHandler) MoveItem(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	itemID, err := middlewares.ParamID(c, "item_id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var mReq moveReq
+
+	err = c.Bind(&mReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&mReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	ctx := c.Request().Context()
+
+	note, err := n.NUseCase.MoveItem(ctx, id, middlewares.GetUserID(c), itemID, mReq.AfterID)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("item moved", note))
+}
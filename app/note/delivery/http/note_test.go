@@ -0,0 +1,561 @@
+package http_test
+
+import (
+	"encoding/json"
+	"io"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	noteHttp "librenote/app/note/delivery/http"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var BaseURLV1 = "/api/v1"
+
+func buildEchoAuthorizedRequest(t *testing.T, method, path, token string, payload io.Reader) (
+	echo.Context, *httptest.ResponseRecorder) {
+	var req *http.Request
+
+	var err error
+
+	if payload != nil {
+		req, err = http.NewRequest(method, path, payload)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	} else {
+		req, err = http.NewRequest(method, path, nil)
+	}
+
+	assert.NoError(t, err)
+
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+	res := httptest.NewRecorder()
+	e := echo.New()
+	ctx := e.NewContext(req, res)
+
+	return ctx, res
+}
+
+// nolint:unparam
+func getToken(userID int32) string {
+	jwtCfg := config.Get().Jwt
+	claims := &middlewares.JwtCustomClaims{
+		UserID: userID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(jwtCfg.ExpireTime).Unix(),
+		},
+	}
+	unsignedToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token, _ := unsignedToken.SignedString([]byte(jwtCfg.SecretKey))
+
+	return token
+}
+
+func attachJWTMiddleware(hfc echo.HandlerFunc) echo.HandlerFunc {
+	mhfc := middleware.JWTWithConfig(
+		middleware.JWTConfig{
+			Claims:     &middlewares.JwtCustomClaims{},
+			SigningKey: []byte(config.Get().Jwt.SecretKey),
+		})(hfc)
+
+	return mhfc
+}
+
+func mockNote() model.Note {
+	title, text := "Groceries", "milk"
+
+	return model.Note{
+		ID:     1,
+		UserID: 1,
+		Title:  &title,
+		Type:   "list",
+		Items:  []model.NotesItem{{ID: 1, NoteID: 1, Text: &text}},
+	}
+}
+
+func TestCreateNote(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes"
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("Create", mock.Anything, mock.AnythingOfType("*model.Note")).Return(nil)
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		body := `{"title": "Groceries", "type": "list", "items": [{"text": "milk"}, {"text": "eggs", "is_checked": 1}]}`
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+		handle := attachJWTMiddleware(handler.CreateNote)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		var r response.Response
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+		assert.True(t, r.Success)
+
+		resultsMap := r.Results.(map[string]interface{})
+		assert.Equal(t, "Groceries", resultsMap["title"])
+		assert.Len(t, resultsMap["items"], 2)
+
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-type", func(t *testing.T) {
+		body := `{"title": "Groceries", "type": "board"}`
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+		handle := attachJWTMiddleware(handler.CreateNote)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("invalid-color", func(t *testing.T) {
+		body := `{"title": "Groceries", "type": "list", "color": "#ff0000"}`
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+		handle := attachJWTMiddleware(handler.CreateNote)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Contains(t, res.Body.String(), "Must be a color of the palette or a custom color")
+	})
+
+	t.Run("drawing", func(t *testing.T) {
+		body := `{"type": "drawing", "drawing": {"width": 800, "height": 600,
+			"strokes": [{"color": "#000000", "width": 2.5, "points": [10, 10, 20, 25.5]}]}}`
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+		handle := attachJWTMiddleware(handler.CreateNote)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		var r response.Response
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+
+		drawing := r.Results.(map[string]interface{})["drawing"].(map[string]interface{})
+		assert.Equal(t, float64(800), drawing["width"])
+		assert.Len(t, drawing["strokes"], 1)
+	})
+
+	t.Run("drawing-missing", func(t *testing.T) {
+		for _, body := range []string{
+			`{"type": "drawing"}`,
+			`{"type": "drawing", "drawing": {"width": 0, "height": 600, "strokes": []}}`,
+			`{"type": "drawing", "drawing": {"width": 8, "height": 6, "strokes": [{"color": "#000000", "width": 0,
+				"points": [1, 1]}]}}`,
+		} {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+			handle := attachJWTMiddleware(handler.CreateNote)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, http.StatusBadRequest, res.Code, body)
+		}
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		body := `{"type": "note", "encrypted": {"ciphertext": "c2VjcmV0", "algorithm": "AES-GCM",
+			"nonce": "bm9uY2U=", "key_version": 1}}`
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+		handle := attachJWTMiddleware(handler.CreateNote)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		var r response.Response
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+
+		encrypted := r.Results.(map[string]interface{})["encrypted"].(map[string]interface{})
+		assert.Equal(t, "c2VjcmV0", encrypted["ciphertext"])
+		assert.Equal(t, float64(1), encrypted["key_version"])
+	})
+
+	t.Run("encrypted-invalid", func(t *testing.T) {
+		for _, body := range []string{
+			`{"type": "note", "encrypted": {"ciphertext": "not base64!", "algorithm": "AES-GCM", "key_version": 1}}`,
+			`{"type": "note", "encrypted": {"ciphertext": "c2VjcmV0", "key_version": 1}}`,
+			`{"type": "note", "encrypted": {"ciphertext": "c2VjcmV0", "algorithm": "AES-GCM"}}`,
+		} {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+			handle := attachJWTMiddleware(handler.CreateNote)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, http.StatusBadRequest, res.Code, body)
+		}
+	})
+}
+
+func TestRenderDrawing(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id/drawing.png"
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("RenderDrawing", mock.Anything, int32(1), int32(1), 128).Return([]byte("png"), nil).Once()
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint+"?size=128", getToken(1), nil)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("1")
+	handle := attachJWTMiddleware(handler.RenderDrawing)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "image/png", res.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "png", res.Body.String())
+	mockUsecase.AssertExpectations(t)
+
+	ctx, res = buildEchoAuthorizedRequest(t, echo.GET, endPoint+"?size=10000", getToken(1), nil)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("1")
+	handle = attachJWTMiddleware(handler.RenderDrawing)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestGetNote(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id"
+	note := mockNote()
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("Get", mock.Anything, int32(1), int32(1)).Return(&note, nil).Once()
+	mockUsecase.On("Get", mock.Anything, int32(2), int32(1)).Return(nil, response.ErrNotFound).Once()
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+		handle := attachJWTMiddleware(handler.GetNote)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.NotContains(t, res.Body.String(), "body_html")
+	})
+
+	t.Run("render-html", func(t *testing.T) {
+		withBody := mockNote()
+		withBody.Body = "**milk** <script>alert(1)</script>"
+		mockUsecase.On("Get", mock.Anything, int32(3), int32(1)).Return(&withBody, nil).Once()
+
+		ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint+"?render=html", getToken(1), nil)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("3")
+		handle := attachJWTMiddleware(handler.GetNote)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		var r struct {
+			Result model.Note `json:"result"`
+		}
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+		assert.Equal(t, "<p><strong>milk</strong> &lt;script&gt;alert(1)&lt;/script&gt;</p>\n", r.Result.BodyHTML)
+	})
+
+	t.Run("invalid-render", func(t *testing.T) {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint+"?render=pdf", getToken(1), nil)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+		handle := attachJWTMiddleware(handler.GetNote)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("2")
+		handle := attachJWTMiddleware(handler.GetNote)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("invalid-id", func(t *testing.T) {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("abc")
+		handle := attachJWTMiddleware(handler.GetNote)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestFetchNotes(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes?page=2&page_size=1"
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("Fetch", mock.Anything, model.NoteFilter{UserID: 1}, 2, 1).
+		Return([]model.Note{mockNote()}, 3, nil).Once()
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+	handle := attachJWTMiddleware(handler.FetchNotes)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var r response.Response
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+	assert.Equal(t, 3, *r.Count)
+	assert.Equal(t, 1, *r.Previous)
+	assert.Equal(t, 3, *r.Next)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestDeleteNote(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id"
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("Delete", mock.Anything, int32(1), int32(1)).Return(nil).Once()
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.DELETE, endPoint, getToken(1), nil)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("1")
+	handle := attachJWTMiddleware(handler.DeleteNote)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusNoContent, res.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestDiffRevisions(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id/revisions/diff"
+	diff := model.RevisionDiff{From: 1, To: 2}
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("DiffRevisions", mock.Anything, int32(1), int32(1), int32(1), int32(2)).
+		Return(&diff, nil).Once()
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint+"?from=1&to=2", getToken(1), nil)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+		handle := attachJWTMiddleware(handler.DiffRevisions)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	t.Run("missing-to", func(t *testing.T) {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint+"?from=1", getToken(1), nil)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+		handle := attachJWTMiddleware(handler.DiffRevisions)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestRestoreRevision(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id/revisions/:revision_id/restore"
+	note := mockNote()
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("RestoreRevision", mock.Anything, int32(1), int32(1), int32(5)).Return(&note, nil).Once()
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), nil)
+	ctx.SetParamNames("id", "revision_id")
+	ctx.SetParamValues("1", "5")
+	handle := attachJWTMiddleware(handler.RestoreRevision)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestFetchBacklinks(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id/backlinks"
+	title := "Plan"
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("Backlinks", mock.Anything, int32(1), int32(1)).
+		Return([]model.NoteSummary{{ID: 2, Title: &title, Type: "note"}}, nil).Once()
+	mockUsecase.On("Backlinks", mock.Anything, int32(2), int32(1)).Return(nil, response.ErrNotFound).Once()
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	for id, code := range map[string]int{"1": http.StatusOK, "2": http.StatusNotFound, "abc": http.StatusBadRequest} {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues(id)
+		handle := attachJWTMiddleware(handler.FetchBacklinks)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, code, res.Code)
+	}
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGraph(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/graph"
+	graph := &model.NoteGraph{
+		Nodes: []model.NoteSummary{{ID: 1, Type: "note"}, {ID: 2, Type: "list"}},
+		Edges: []model.GraphEdge{{From: 1, To: 2}},
+	}
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("Graph", mock.Anything, int32(1)).Return(graph, nil).Once()
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+	handle := attachJWTMiddleware(handler.Graph)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var r struct {
+		Result model.NoteGraph `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+	assert.Equal(t, *graph, r.Result)
+}
+
+func TestBulkNotes(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/bulk"
+	results := []model.BulkResult{{ID: 1, Status: model.BulkOK}, {ID: 2, Status: model.BulkNotFound}}
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("Bulk", mock.Anything, mock.MatchedBy(func(op *model.BulkOperation) bool {
+		return op.UserID == 1 && op.Action == model.BulkPin && len(op.IDs) == 2 && op.Filter == nil
+	}), config.Get().App.MaxPageSize).Return(results, nil).Once()
+	mockUsecase.On("Bulk", mock.Anything, mock.MatchedBy(func(op *model.BulkOperation) bool {
+		return op.Action == model.BulkTrash && op.Filter != nil && op.Filter.IsArchived == 1
+	}), mock.Anything).Return([]model.BulkResult{}, nil).Once()
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	cases := map[string]struct {
+		payload string
+		code    int
+	}{
+		"ids":              {payload: `{"action":"pin","ids":[1,2]}`, code: http.StatusOK},
+		"filter":           {payload: `{"action":"trash","filter":{"is_archived":1}}`, code: http.StatusOK},
+		"ids-and-filter":   {payload: `{"action":"pin","ids":[1],"filter":{}}`, code: http.StatusBadRequest},
+		"no-notes":         {payload: `{"action":"pin"}`, code: http.StatusBadRequest},
+		"unknown-action":   {payload: `{"action":"share","ids":[1]}`, code: http.StatusBadRequest},
+		"label-missing":    {payload: `{"action":"add_label","ids":[1]}`, code: http.StatusBadRequest},
+		"invalid-color":    {payload: `{"action":"recolor","ids":[1],"color":"Sea/Green"}`, code: http.StatusBadRequest},
+		"invalid-note-ids": {payload: `{"action":"pin","ids":[0]}`, code: http.StatusBadRequest},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(c.payload))
+			handle := attachJWTMiddleware(handler.BulkNotes)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, c.code, res.Code)
+		})
+	}
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestMoveNote(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id/position"
+	note := mockNote()
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("Move", mock.Anything, int32(1), int32(1), mock.MatchedBy(func(afterID *int32) bool {
+		return afterID != nil && *afterID == 2
+	})).Return(&note, nil).Once()
+	mockUsecase.On("Move", mock.Anything, int32(1), int32(1), (*int32)(nil)).Return(&note, nil).Once()
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	cases := map[string]struct {
+		payload string
+		code    int
+	}{
+		"after":   {payload: `{"after_id":2}`, code: http.StatusOK},
+		"first":   {payload: `{"after_id":null}`, code: http.StatusOK},
+		"invalid": {payload: `{"after_id":0}`, code: http.StatusBadRequest},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.PUT, endPoint, getToken(1), strings.NewReader(c.payload))
+			ctx.SetParamNames("id")
+			ctx.SetParamValues("1")
+			handle := attachJWTMiddleware(handler.MoveNote)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, c.code, res.Code)
+		})
+	}
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestMoveItem(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id/items/:item_id/position"
+	note := mockNote()
+
+	mockUsecase := new(mocks.NoteUsecase)
+	mockUsecase.On("MoveItem", mock.Anything, int32(1), int32(1), int32(3), (*int32)(nil)).Return(&note, nil).Once()
+
+	handler := noteHttp.NoteHandler{
+		NUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.PUT, endPoint, getToken(1), strings.NewReader(`{}`))
+	ctx.SetParamNames("id", "item_id")
+	ctx.SetParamValues("1", "3")
+	handle := attachJWTMiddleware(handler.MoveItem)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+	mockUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,85 @@
+package http
+
+type noteItemReq struct {
+	// ID of an existing item of the note, the items without one are new
+	ID        int32  `json:"id" validate:"min=0"`
+	Text      string `json:"text" validate:"required,max=1000"`
+	IsChecked int8   `json:"is_checked" validate:"min=0,max=1"`
+}
+
+type strokeReq struct {
+	Color  string    `json:"color" validate:"required,max=9"`
+	Width  float64   `json:"width" validate:"gt=0,max=100"`
+	Points []float64 `json:"points" validate:"required,min=2"`
+}
+
+type drawingReq struct {
+	Width      int         `json:"width" validate:"min=1,max=4096"`
+	Height     int         `json:"height" validate:"min=1,max=4096"`
+	Background string      `json:"background" validate:"omitempty,max=9"`
+	Strokes    []strokeReq `json:"strokes" validate:"dive"`
+}
+
+// encryptedReq is the content of an end-to-end encrypted note, encrypted by the client
+type encryptedReq struct {
+	Ciphertext string `json:"ciphertext" validate:"required,base64"`
+	Algorithm  string `json:"algorithm" validate:"required,max=50"`
+	Nonce      string `json:"nonce" validate:"omitempty,base64,max=255"`
+	KeyVersion int32  `json:"key_version" validate:"min=1"`
+}
+
+type noteReq struct {
+	Title      *string       `json:"title" validate:"omitempty,max=255"`
+	Body       string        `json:"body" validate:"max=100000"`
+	Color      string        `json:"color" validate:"notecolor"`
+	Type       string        `json:"type" validate:"required,notetype"`
+	IsPinned   int8          `json:"is_pinned" validate:"min=0,max=1"`
+	IsArchived int8          `json:"is_archived" validate:"min=0,max=1"`
+	IsTrashed  int8          `json:"is_trashed" validate:"min=0,max=1"`
+	Items      []noteItemReq `json:"items" validate:"dive"`
+	Drawing    *drawingReq   `json:"drawing" validate:"required_if=Type drawing,omitempty"`
+	Encrypted  *encryptedReq `json:"encrypted" validate:"omitempty"`
+}
+
+type fetchNotesReq struct {
+	Page       int  `json:"page" query:"page" validate:"omitempty,min=1"`
+	PageSize   int  `json:"page_size" query:"page_size" validate:"omitempty,min=1"`
+	IsArchived int8 `json:"is_archived" query:"is_archived" validate:"min=0,max=1"`
+	IsTrashed  int8 `json:"is_trashed" query:"is_trashed" validate:"min=0,max=1"`
+	// html adds the rendered body to the notes
+	Render string `json:"render" query:"render" validate:"omitempty,oneof=html"`
+}
+
+type getNoteReq struct {
+	Render string `json:"render" query:"render" validate:"omitempty,oneof=html"`
+}
+
+type diffRevisionsReq struct {
+	From int32 `json:"from" query:"from" validate:"required,min=1"`
+	To   int32 `json:"to" query:"to" validate:"required,min=1"`
+}
+
+type renderDrawingReq struct {
+	Size int `json:"size" query:"size" validate:"min=0,max=4096"`
+}
+
+type bulkFilterReq struct {
+	IsArchived int8    `json:"is_archived" validate:"min=0,max=1"`
+	IsTrashed  int8    `json:"is_trashed" validate:"min=0,max=1"`
+	LabelIDs   []int32 `json:"label_ids" validate:"dive,min=1"`
+}
+
+type bulkNotesReq struct {
+	Action string `json:"action" validate:"required,oneof=pin unpin archive unarchive trash restore recolor add_label remove_label delete"` // nolint:lll
+	// either the ids of the notes or a filter matching them
+	IDs    []int32        `json:"ids" validate:"required_without=Filter,excluded_with=Filter,dive,min=1"`
+	Filter *bulkFilterReq `json:"filter" validate:"required_without=IDs,omitempty"`
+	// the color of recolor, empty clears the color
+	Color   string `json:"color" validate:"notecolor"`
+	LabelID int32  `json:"label_id" validate:"required_if=Action add_label,required_if=Action remove_label,min=0"`
+}
+
+type moveReq struct {
+	// the note or the item goes right after this one, first when it's null
+	AfterID *int32 `json:"after_id" validate:"omitempty,min=1"`
+}
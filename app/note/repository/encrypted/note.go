@@ -0,0 +1,327 @@
+package encrypted
+
+import (
+	"context"
+	"librenote/app/encryption"
+	"librenote/app/model"
+	"sort"
+)
+
+// noteRepository encrypts the titles, the bodies, the texts of the items, the revisions and the titles referenced by
+// the bodies of the notes stored by another repository, and decrypts them back on reading. The referenced titles are
+// stored along with their keyed hashes, the backlinks are looked up by them. The links of the bodies stay in clear,
+// and so do the drawings. model.EncryptedColumns lists all the clear columns
+type noteRepository struct {
+	model.NoteRepository
+	keyring *encryption.Keyring
+}
+
+// NewNoteRepository wraps the repository of any database
+func NewNoteRepository(repo model.NoteRepository, keyring *encryption.Keyring) model.NoteRepository {
+	return &noteRepository{
+		NoteRepository: repo,
+		keyring:        keyring,
+	}
+}
+
+func (r *noteRepository) CreateNote(ctx context.Context, note *model.Note, revision *model.NoteRevision) error {
+	encNote, encRevision, err := r.encrypt(note, revision)
+	if err != nil {
+		return err
+	}
+
+	if err = r.NoteRepository.CreateNote(ctx, encNote, encRevision); err != nil {
+		return err
+	}
+
+	copyIDs(note, revision, encNote, encRevision)
+
+	return nil
+}
+
+func (r *noteRepository) UpdateNote(ctx context.Context, note *model.Note, revision *model.NoteRevision,
+	keepRevisions int) error {
+	encNote, encRevision, err := r.encrypt(note, revision)
+	if err != nil {
+		return err
+	}
+
+	if err = r.NoteRepository.UpdateNote(ctx, encNote, encRevision, keepRevisions); err != nil {
+		return err
+	}
+
+	copyIDs(note, revision, encNote, encRevision)
+
+	return nil
+}
+
+// encrypt returns encrypted copies, the note and the revision are kept as they are for the caller
+func (r *noteRepository) encrypt(note *model.Note, revision *model.NoteRevision) (*model.Note,
+	*model.NoteRevision, error) {
+	encNote := *note
+	encRevision := *revision
+
+	var err error
+
+	if note.Title != nil {
+		title, err := r.keyring.EncryptString(*note.Title)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		encNote.Title = &title
+	}
+
+	if encNote.Body, err = r.keyring.EncryptString(note.Body); err != nil {
+		return nil, nil, err
+	}
+
+	encNote.Items = make([]model.NotesItem, len(note.Items))
+	copy(encNote.Items, note.Items)
+
+	for i, item := range note.Items {
+		if item.Text == nil {
+			continue
+		}
+
+		text, err := r.keyring.EncryptString(*item.Text)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		encNote.Items[i].Text = &text
+	}
+
+	if encNote.References, err = r.encryptReferences(note.References); err != nil {
+		return nil, nil, err
+	}
+
+	if encRevision.Snapshot, err = r.keyring.EncryptString(revision.Snapshot); err != nil {
+		return nil, nil, err
+	}
+
+	return &encNote, &encRevision, nil
+}
+
+// encryptReferences encrypts the referenced titles, their hashes match the hash of the title of the notes
+func (r *noteRepository) encryptReferences(refs []model.NoteReference) ([]model.NoteReference, error) {
+	if refs == nil {
+		return nil, nil
+	}
+
+	encRefs := make([]model.NoteReference, len(refs))
+	copy(encRefs, refs)
+
+	for i, ref := range refs {
+		if ref.Title == nil {
+			continue
+		}
+
+		title, err := r.keyring.EncryptString(*ref.Title)
+		if err != nil {
+			return nil, err
+		}
+
+		hash := r.keyring.Hash(*ref.Title)
+		encRefs[i].Title = &title
+		encRefs[i].TitleHash = &hash
+	}
+
+	return encRefs, nil
+}
+
+// copyIDs copies back what the repository set on storing
+func copyIDs(note *model.Note, revision *model.NoteRevision, encNote *model.Note, encRevision *model.NoteRevision) {
+	note.ID = encNote.ID
+
+	for i := range note.Items {
+		note.Items[i].ID = encNote.Items[i].ID
+		note.Items[i].NoteID = encNote.Items[i].NoteID
+	}
+
+	revision.ID = encRevision.ID
+	revision.NoteID = encRevision.NoteID
+}
+
+func (r *noteRepository) decryptNote(note *model.Note) error {
+	if err := r.decryptText(&note.Title); err != nil {
+		return err
+	}
+
+	var err error
+	if note.Body, err = r.keyring.DecryptString(note.Body); err != nil {
+		return err
+	}
+
+	for i := range note.Items {
+		if err = r.decryptText(&note.Items[i].Text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decryptText decrypts the nullable texts, like the titles
+func (r *noteRepository) decryptText(text **string) error {
+	if *text == nil {
+		return nil
+	}
+
+	s, err := r.keyring.DecryptString(**text)
+	if err != nil {
+		return err
+	}
+
+	*text = &s
+
+	return nil
+}
+
+func (r *noteRepository) GetNote(ctx context.Context, id, userID int32) (model.Note, error) {
+	note, err := r.NoteRepository.GetNote(ctx, id, userID)
+	if err != nil {
+		return note, err
+	}
+
+	return note, r.decryptNote(&note)
+}
+
+func (r *noteRepository) FetchNotes(ctx context.Context, filter model.NoteFilter, limit, offset int) (
+	[]model.Note, int, error) {
+	notes, total, err := r.NoteRepository.FetchNotes(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range notes {
+		if err = r.decryptNote(&notes[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return notes, total, nil
+}
+
+func (r *noteRepository) FetchRevisions(ctx context.Context, noteID int32) ([]model.NoteRevision, error) {
+	revisions, err := r.NoteRepository.FetchRevisions(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range revisions {
+		if revisions[i].Snapshot, err = r.keyring.DecryptString(revisions[i].Snapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	return revisions, nil
+}
+
+func (r *noteRepository) GetRevision(ctx context.Context, noteID, id int32) (model.NoteRevision, error) {
+	revision, err := r.NoteRepository.GetRevision(ctx, noteID, id)
+	if err != nil {
+		return revision, err
+	}
+
+	revision.Snapshot, err = r.keyring.DecryptString(revision.Snapshot)
+
+	return revision, err
+}
+
+// FetchBacklinks looks the title up by its hash by every key, the references stored before the rotation of the keys
+// are hashed by a previous one
+func (r *noteRepository) FetchBacklinks(ctx context.Context, id, userID int32, title string) (
+	[]model.NoteSummary, error) {
+	if title == "" {
+		summaries, err := r.NoteRepository.FetchBacklinks(ctx, id, userID, title)
+		if err != nil {
+			return nil, err
+		}
+
+		return summaries, r.decryptSummaries(summaries)
+	}
+
+	summaries := make([]model.NoteSummary, 0)
+	seen := make(map[int32]bool)
+
+	for _, hash := range r.keyring.Hashes(title) {
+		found, err := r.NoteRepository.FetchBacklinks(ctx, id, userID, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, summary := range found {
+			if !seen[summary.ID] {
+				seen[summary.ID] = true
+				summaries = append(summaries, summary)
+			}
+		}
+	}
+
+	// in the order of the repository, the last updated first
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].UpdatedAt != summaries[j].UpdatedAt {
+			return summaries[i].UpdatedAt > summaries[j].UpdatedAt
+		}
+
+		return summaries[i].ID > summaries[j].ID
+	})
+
+	return summaries, r.decryptSummaries(summaries)
+}
+
+func (r *noteRepository) FetchGraph(ctx context.Context, userID int32) ([]model.NoteSummary,
+	[]model.NoteReference, error) {
+	summaries, references, err := r.NoteRepository.FetchGraph(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = r.decryptSummaries(summaries); err != nil {
+		return nil, nil, err
+	}
+
+	for i := range references {
+		if err = r.decryptText(&references[i].Title); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return summaries, references, nil
+}
+
+// BulkNotes decrypts the notes changed by the operation before their revisions are built, then encrypts the revisions
+func (r *noteRepository) BulkNotes(ctx context.Context, op model.BulkOperation) ([]model.BulkResult, error) {
+	if newRevision := op.Revision; newRevision != nil {
+		op.Revision = func(note *model.Note) (*model.NoteRevision, error) {
+			decrypted := *note
+			decrypted.Items = append([]model.NotesItem(nil), note.Items...)
+
+			if err := r.decryptNote(&decrypted); err != nil {
+				return nil, err
+			}
+
+			revision, err := newRevision(&decrypted)
+			if err != nil {
+				return nil, err
+			}
+
+			revision.Snapshot, err = r.keyring.EncryptString(revision.Snapshot)
+
+			return revision, err
+		}
+	}
+
+	return r.NoteRepository.BulkNotes(ctx, op)
+}
+
+func (r *noteRepository) decryptSummaries(summaries []model.NoteSummary) error {
+	for i := range summaries {
+		if err := r.decryptText(&summaries[i].Title); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
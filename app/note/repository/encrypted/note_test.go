@@ -0,0 +1,208 @@
+package encrypted_test
+
+import (
+	"context"
+	"librenote/app/encryption"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/note/repository/encrypted"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func newKeyring(t *testing.T) *encryption.Keyring {
+	key, err := encryption.GenerateKey()
+	assert.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(key)
+	assert.NoError(t, err)
+
+	return keyring
+}
+
+func TestCreateNote(t *testing.T) {
+	keyring := newKeyring(t)
+	note := &model.Note{
+		UserID: 1, Title: strPtr("Groceries"), Body: "from the shop", Type: "list",
+		Items:      []model.NotesItem{{Text: strPtr("milk")}},
+		Links:      []string{"https://shop.example"},
+		References: []model.NoteReference{{Title: strPtr("shop")}},
+	}
+	revision := &model.NoteRevision{Snapshot: `{"title":"Groceries"}`}
+
+	var stored *model.Note
+
+	mockRepo := new(mocks.NoteRepository)
+	mockRepo.On("CreateNote", mock.Anything, mock.AnythingOfType("*model.Note"),
+		mock.MatchedBy(func(r *model.NoteRevision) bool {
+			return keyring.IsCurrent(r.Snapshot)
+		})).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*model.Note)
+		stored.ID = 3
+		stored.Items[0].ID = 5
+		stored.Items[0].NoteID = 3
+		args.Get(2).(*model.NoteRevision).ID = 7
+	}).Return(nil).Once()
+
+	r := encrypted.NewNoteRepository(mockRepo, keyring)
+	assert.NoError(t, r.CreateNote(context.TODO(), note, revision))
+	mockRepo.AssertExpectations(t)
+
+	assert.True(t, keyring.IsCurrent(*stored.Title))
+	assert.True(t, keyring.IsCurrent(stored.Body))
+	assert.True(t, keyring.IsCurrent(*stored.Items[0].Text))
+	assert.Equal(t, note.Links, stored.Links)
+	assert.True(t, keyring.IsCurrent(*stored.References[0].Title))
+	assert.Equal(t, keyring.Hash("shop"), *stored.References[0].TitleHash)
+	assert.Equal(t, "shop", *note.References[0].Title)
+	assert.Nil(t, note.References[0].TitleHash)
+
+	// the caller's note is kept in clear
+	assert.Equal(t, "Groceries", *note.Title)
+	assert.Equal(t, "milk", *note.Items[0].Text)
+	assert.Equal(t, `{"title":"Groceries"}`, revision.Snapshot)
+	assert.Equal(t, int32(3), note.ID)
+	assert.Equal(t, int32(5), note.Items[0].ID)
+	assert.Equal(t, int32(7), revision.ID)
+}
+
+func TestGetNote(t *testing.T) {
+	keyring := newKeyring(t)
+
+	title, err := keyring.EncryptString("Groceries")
+	assert.NoError(t, err)
+
+	body, err := keyring.EncryptString("from the shop")
+	assert.NoError(t, err)
+
+	snapshot, err := keyring.EncryptString(`{"title":"Groceries"}`)
+	assert.NoError(t, err)
+
+	mockRepo := new(mocks.NoteRepository)
+	mockRepo.On("GetNote", mock.Anything, int32(3), int32(1)).Return(model.Note{
+		ID: 3, UserID: 1, Title: &title, Body: body, Items: []model.NotesItem{{Text: strPtr("stored in clear")}},
+	}, nil).Once()
+	mockRepo.On("FetchRevisions", mock.Anything, int32(3)).
+		Return([]model.NoteRevision{{ID: 7, NoteID: 3, Snapshot: snapshot}}, nil).Once()
+	ref, err := keyring.EncryptString("groceries")
+	assert.NoError(t, err)
+
+	target := int32(3)
+
+	mockRepo.On("FetchGraph", mock.Anything, int32(1)).
+		Return([]model.NoteSummary{{ID: 3, Title: &title}, {ID: 4}},
+			[]model.NoteReference{{NoteID: 4, Title: &ref}, {NoteID: 4, TargetID: &target}}, nil).Once()
+
+	r := encrypted.NewNoteRepository(mockRepo, keyring)
+
+	note, err := r.GetNote(context.TODO(), 3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Groceries", *note.Title)
+	assert.Equal(t, "from the shop", note.Body)
+	assert.Equal(t, "stored in clear", *note.Items[0].Text)
+
+	revisions, err := r.FetchRevisions(context.TODO(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"title":"Groceries"}`, revisions[0].Snapshot)
+
+	summaries, refs, err := r.FetchGraph(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Groceries", *summaries[0].Title)
+	assert.Nil(t, summaries[1].Title)
+	assert.Equal(t, "groceries", *refs[0].Title)
+	assert.Nil(t, refs[1].Title)
+
+	// another keyring can't read the notes
+	other := encrypted.NewNoteRepository(mockRepo, newKeyring(t))
+	mockRepo.On("GetNote", mock.Anything, int32(3), int32(1)).Return(model.Note{ID: 3, Title: &title}, nil).Once()
+
+	_, err = other.GetNote(context.TODO(), 3, 1)
+	assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+}
+
+func TestFetchBacklinks(t *testing.T) {
+	oldKey, err := encryption.GenerateKey()
+	assert.NoError(t, err)
+
+	newKey, err := encryption.GenerateKey()
+	assert.NoError(t, err)
+
+	oldKeyring, err := encryption.NewKeyring(oldKey)
+	assert.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(oldKey, newKey)
+	assert.NoError(t, err)
+
+	title, err := keyring.EncryptString("Plan")
+	assert.NoError(t, err)
+
+	// the references hashed before the rotation are found by the previous key
+	mockRepo := new(mocks.NoteRepository)
+	mockRepo.On("FetchBacklinks", mock.Anything, int32(4), int32(1), keyring.Hash("groceries")).
+		Return([]model.NoteSummary{{ID: 2, Title: &title, UpdatedAt: "2021-01-02 00:00:00"}}, nil).Once()
+	mockRepo.On("FetchBacklinks", mock.Anything, int32(4), int32(1), oldKeyring.Hash("groceries")).
+		Return([]model.NoteSummary{
+			{ID: 5, UpdatedAt: "2021-01-03 00:00:00"},
+			{ID: 2, Title: &title, UpdatedAt: "2021-01-02 00:00:00"},
+		}, nil).Once()
+
+	r := encrypted.NewNoteRepository(mockRepo, keyring)
+
+	summaries, err := r.FetchBacklinks(context.TODO(), 4, 1, "groceries")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, int32(5), summaries[0].ID)
+	assert.Equal(t, int32(2), summaries[1].ID)
+	assert.Equal(t, "Plan", *summaries[1].Title)
+
+	// an untitled note has backlinks by id only
+	mockRepo.On("FetchBacklinks", mock.Anything, int32(4), int32(1), "").Return([]model.NoteSummary{}, nil).Once()
+
+	summaries, err = r.FetchBacklinks(context.TODO(), 4, 1, "")
+	assert.NoError(t, err)
+	assert.Empty(t, summaries)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBulkNotes(t *testing.T) {
+	keyring := newKeyring(t)
+
+	title, err := keyring.EncryptString("Groceries")
+	assert.NoError(t, err)
+
+	var snapshot string
+
+	mockRepo := new(mocks.NoteRepository)
+	mockRepo.On("BulkNotes", mock.Anything, mock.AnythingOfType("model.BulkOperation")).
+		Run(func(args mock.Arguments) {
+			op := args.Get(1).(model.BulkOperation)
+
+			revision, err := op.Revision(&model.Note{ID: 3, Title: &title, Items: []model.NotesItem{{Text: &title}}})
+			assert.NoError(t, err)
+
+			snapshot = revision.Snapshot
+		}).Return([]model.BulkResult{{ID: 3, Status: model.BulkOK}}, nil).Once()
+
+	r := encrypted.NewNoteRepository(mockRepo, keyring)
+	_, err = r.BulkNotes(context.TODO(), model.BulkOperation{UserID: 1, Action: model.BulkPin, IDs: []int32{3},
+		Revision: func(note *model.Note) (*model.NoteRevision, error) {
+			// the revision is built from the note in clear
+			return &model.NoteRevision{Snapshot: *note.Title + ", " + *note.Items[0].Text}, nil
+		}})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	assert.True(t, keyring.IsCurrent(snapshot))
+
+	plaintext, err := keyring.DecryptString(snapshot)
+	assert.NoError(t, err)
+	assert.Equal(t, "Groceries, Groceries", plaintext)
+}
@@ -0,0 +1,418 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"librenote/app/model"
+	noteRepo "librenote/app/note/repository/mysql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateNote(t *testing.T) {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	title, text, shop := "Groceries", "milk", "shop"
+	n := &model.Note{
+		UserID: 1, Title: &title, Body: "[shop](https://shop.example)", Color: "red", Type: "list",
+		Position: "i", CreatedAt: nowTime, UpdatedAt: nowTime,
+		Items: []model.NotesItem{{Text: &text, Position: "i", CreatedAt: nowTime}},
+		Links: []string{"https://shop.example"}, References: []model.NoteReference{{Title: &shop}},
+		LabelIDs: []int32{4},
+	}
+	rev := &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO notes ").
+		WithArgs(n.UserID, n.Title, n.Body, n.Color, n.Type, n.IsPinned, n.IsArchived, n.IsTrashed, nil,
+			nil, n.Position, n.CreatedAt, n.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO notes_items").
+		WithArgs(int32(7), n.Items[0].Text, n.Items[0].IsChecked, n.Items[0].Position, n.Items[0].CreatedAt).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec("INSERT INTO notes_links").WithArgs(int32(7), "https://shop.example").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO notes_references").WithArgs(int32(7), nil, &shop, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT IGNORE INTO notes_labels").WithArgs(int32(7), int32(4), int32(1)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO notes_revisions").
+		WithArgs(int32(7), rev.Snapshot, rev.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	nr := noteRepo.NewMysqlNoteRepository(db)
+	err = nr.CreateNote(context.TODO(), n, rev)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), n.ID)
+	assert.Equal(t, int32(3), n.Items[0].ID)
+	assert.Equal(t, int32(7), rev.NoteID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	noteRows := sqlmock.NewRows([]string{
+		"id", "user_id", "title", "body", "color", "type", "is_pinned", "is_archived", "is_trashed",
+		"drawing", "encrypted", "position", "created_at", "updated_at"}).
+		AddRow(1, 1, nil, "", "", "drawing", 1, 0, 0, `{"width":800,"height":600,"strokes":[]}`, nil, "i",
+			nowTime, nowTime)
+	itemRows := sqlmock.NewRows([]string{"id", "note_id", "text", "is_checked", "position", "created_at"}).
+		AddRow(1, 1, "hello", 0, "i", nowTime)
+
+	mock.ExpectQuery("SELECT (.+) FROM notes WHERE").WithArgs(int32(1), int32(1)).WillReturnRows(noteRows)
+	mock.ExpectQuery("SELECT (.+) FROM notes_items").WithArgs(int32(1)).WillReturnRows(itemRows)
+
+	nr := noteRepo.NewMysqlNoteRepository(db)
+	note, err := nr.GetNote(context.TODO(), 1, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, note.Title)
+	assert.Equal(t, int8(1), note.IsPinned)
+	assert.Equal(t, 800, note.Drawing.Width)
+	assert.Len(t, note.Items, 1)
+	assert.Equal(t, "hello", *note.Items[0].Text)
+}
+
+func TestFetchNotesByLabels(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	filter := model.NoteFilter{UserID: 1, LabelIDs: []int32{2, 5}}
+
+	noteRows := sqlmock.NewRows([]string{
+		"id", "user_id", "title", "body", "color", "type", "is_pinned", "is_archived", "is_trashed",
+		"drawing", "encrypted", "position", "created_at", "updated_at"}).
+		AddRow(3, 1, nil, "", "", "note", 0, 0, 0, nil,
+			`{"ciphertext":"c2VjcmV0","algorithm":"AES-GCM","nonce":"bm9uY2U=","key_version":1}`, "i", nowTime, nowTime)
+
+	mock.ExpectQuery("SELECT COUNT(.+) FROM notes WHERE (.+) label_id IN \\(\\?, \\?\\)").
+		WithArgs(int32(1), int8(0), int8(0), int32(2), int32(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT (.+) FROM notes WHERE (.+) label_id IN \\(\\?, \\?\\)(.+)LIMIT \\? OFFSET \\?").
+		WithArgs(int32(1), int8(0), int8(0), int32(2), int32(5), 10, 0).
+		WillReturnRows(noteRows)
+	mock.ExpectQuery("SELECT (.+) FROM notes_items").WithArgs(int32(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "note_id", "text", "is_checked", "position", "created_at"}))
+
+	nr := noteRepo.NewMysqlNoteRepository(db)
+	notes, count, err := nr.FetchNotes(context.TODO(), filter, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Len(t, notes, 1)
+	assert.Equal(t, "c2VjcmV0", notes[0].Encrypted.Ciphertext)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNote(t *testing.T) {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	n := &model.Note{
+		ID: 1, UserID: 1, Type: "drawing", CreatedAt: nowTime, UpdatedAt: nowTime,
+		Drawing: &model.Drawing{
+			Width: 10, Height: 10, Strokes: []model.Stroke{{Color: "#000000", Width: 1, Points: []float64{1, 1}}},
+		},
+	}
+	rev := &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE notes").
+		WithArgs(n.Title, n.Body, n.Color, n.Type, n.IsPinned, n.IsArchived, n.IsTrashed,
+			`{"width":10,"height":10,"background":"","strokes":[{"color":"#000000","width":1,"points":[1,1]}]}`,
+			nil, n.UpdatedAt, n.ID, n.UserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id FROM notes_items").WithArgs(n.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectExec("DELETE FROM notes_items").WithArgs(int32(2), n.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM notes_links").WithArgs(n.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM notes_references").WithArgs(n.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO notes_revisions").
+		WithArgs(n.ID, rev.Snapshot, rev.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectQuery("SELECT id FROM notes_revisions").WithArgs(n.ID, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectExec("DELETE FROM notes_revisions").WithArgs(n.ID, int32(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	nr := noteRepo.NewMysqlNoteRepository(db)
+	err = nr.UpdateNote(context.TODO(), n, rev, 10)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNoteItems(t *testing.T) {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	kept, added, foreign := "kept", "added", "foreign"
+	n := &model.Note{
+		ID: 1, UserID: 1, Type: "list", CreatedAt: nowTime, UpdatedAt: nowTime,
+		Items: []model.NotesItem{
+			{ID: 2, Text: &kept, IsChecked: 1, Position: "i", CreatedAt: nowTime},
+			{Text: &added, Position: "k", CreatedAt: nowTime},
+			{ID: 9, Text: &foreign, Position: "k", CreatedAt: nowTime},
+		},
+	}
+	rev := &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE notes").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id FROM notes_items").WithArgs(n.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectExec("DELETE FROM notes_items").WithArgs(int32(1), n.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE notes_items").WithArgs(&kept, int8(1), "i", int32(2), n.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO notes_items").WithArgs(n.ID, &added, int8(0), "k", nowTime).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec("INSERT INTO notes_items").WithArgs(n.ID, &foreign, int8(0), "k", nowTime).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("DELETE FROM notes_links").WithArgs(n.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM notes_references").WithArgs(n.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO notes_revisions").WithArgs(n.ID, rev.Snapshot, rev.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectQuery("SELECT id FROM notes_revisions").WithArgs(n.ID, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	nr := noteRepo.NewMysqlNoteRepository(db)
+	err = nr.UpdateNote(context.TODO(), n, rev, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), n.Items[0].ID)
+	assert.Equal(t, int32(3), n.Items[1].ID)
+	assert.Equal(t, int32(4), n.Items[2].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteNote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM notes").WithArgs(int32(1), int32(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("DELETE FROM notes_items").WithArgs(int32(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM notes_links").WithArgs(int32(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM notes_references").WithArgs(int32(1)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM notes_labels").WithArgs(int32(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM notes_revisions").WithArgs(int32(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM reminders_events").WithArgs(int32(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM reminders").WithArgs(int32(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM notes").WithArgs(int32(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		nr := noteRepo.NewMysqlNoteRepository(db)
+		assert.NoError(t, nr.DeleteNote(context.TODO(), 1, 1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not-owner", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM notes").WithArgs(int32(1), int32(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		nr := noteRepo.NewMysqlNoteRepository(db)
+		assert.ErrorIs(t, nr.DeleteNote(context.TODO(), 1, 2), sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFetchBacklinks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	rows := sqlmock.NewRows([]string{"id", "title", "type", "updated_at"}).
+		AddRow(2, "Plan", "note", nowTime).
+		AddRow(3, nil, "list", nowTime)
+
+	mock.ExpectQuery("SELECT (.+) FROM notes n WHERE (.+) notes_references").
+		WithArgs(int32(1), int32(4), int32(4), "groceries", "groceries").WillReturnRows(rows)
+
+	nr := noteRepo.NewMysqlNoteRepository(db)
+	notes, err := nr.FetchBacklinks(context.TODO(), 4, 1, "groceries")
+	assert.NoError(t, err)
+	assert.Len(t, notes, 2)
+	assert.Equal(t, "Plan", *notes[0].Title)
+	assert.Nil(t, notes[1].Title)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchGraph(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	noteRows := sqlmock.NewRows([]string{"id", "title", "type", "updated_at"}).
+		AddRow(1, "Groceries", "list", nowTime).
+		AddRow(2, "Plan", "note", nowTime)
+	refRows := sqlmock.NewRows([]string{"note_id", "target_id", "title"}).
+		AddRow(2, nil, "groceries").
+		AddRow(2, 1, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM notes WHERE").WithArgs(int32(1)).WillReturnRows(noteRows)
+	mock.ExpectQuery("SELECT (.+) FROM notes_references").WithArgs(int32(1)).WillReturnRows(refRows)
+
+	nr := noteRepo.NewMysqlNoteRepository(db)
+	notes, refs, err := nr.FetchGraph(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, notes, 2)
+	assert.Len(t, refs, 2)
+	assert.Equal(t, "groceries", *refs[0].Title)
+	assert.Nil(t, refs[0].TargetID)
+	assert.Equal(t, int32(1), *refs[1].TargetID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBulkNotes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	t.Run("pin", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM notes").WithArgs(int32(1), int32(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("UPDATE notes SET is_pinned").WithArgs(int8(1), "2022-06-01 10:00:00", int32(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT id FROM notes").WithArgs(int32(2), int32(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		nr := noteRepo.NewMysqlNoteRepository(db)
+		op := model.BulkOperation{UserID: 1, Action: model.BulkPin, IDs: []int32{1, 2}, UpdatedAt: "2022-06-01 10:00:00"}
+		results, err := nr.BulkNotes(context.TODO(), op)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.BulkResult{{ID: 1, Status: model.BulkOK}, {ID: 2, Status: model.BulkNotFound}}, results)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("revision", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM notes").WithArgs(int32(1), int32(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("UPDATE notes SET is_archived").WithArgs(int8(1), "2022-06-01 10:00:00", int32(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT (.+) FROM notes WHERE").WithArgs(int32(1), int32(1)).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"id", "user_id", "title", "body", "color", "type", "is_pinned", "is_archived", "is_trashed",
+				"drawing", "encrypted", "position", "created_at", "updated_at"}).
+				AddRow(1, 1, "Groceries", "", "", "note", 0, 1, 0, nil, nil, "i", "2022-06-01 09:00:00",
+					"2022-06-01 10:00:00"))
+		mock.ExpectQuery("SELECT (.+) FROM notes_items").WithArgs(int32(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "note_id", "text", "is_checked", "position", "created_at"}))
+		mock.ExpectExec("INSERT INTO notes_revisions").WithArgs(int32(1), "Groceries archived", "2022-06-01 10:00:00").
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		mock.ExpectQuery("SELECT id FROM notes_revisions").WithArgs(int32(1), 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectExec("DELETE FROM notes_revisions").WithArgs(int32(1), int32(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		nr := noteRepo.NewMysqlNoteRepository(db)
+		results, err := nr.BulkNotes(context.TODO(), model.BulkOperation{
+			UserID: 1, Action: model.BulkArchive, IDs: []int32{1}, UpdatedAt: "2022-06-01 10:00:00", KeepRevisions: 10,
+			Revision: func(note *model.Note) (*model.NoteRevision, error) {
+				return &model.NoteRevision{Snapshot: *note.Title + " archived"}, nil
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []model.BulkResult{{ID: 1, Status: model.BulkOK}}, results)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("add-label", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM labels").WithArgs(int32(3), int32(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectQuery("SELECT id FROM notes").WithArgs(int32(1), int32(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("INSERT IGNORE INTO notes_labels").WithArgs(int32(1), int32(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		nr := noteRepo.NewMysqlNoteRepository(db)
+		results, err := nr.BulkNotes(context.TODO(),
+			model.BulkOperation{UserID: 1, Action: model.BulkAddLabel, IDs: []int32{1}, LabelID: 3})
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("label-of-other-user", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM labels").WithArgs(int32(3), int32(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		nr := noteRepo.NewMysqlNoteRepository(db)
+		_, err := nr.BulkNotes(context.TODO(),
+			model.BulkOperation{UserID: 2, Action: model.BulkRemoveLabel, IDs: []int32{1}, LabelID: 3})
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPositions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT COALESCE\\(MIN\\(position\\), ''\\) FROM notes").WithArgs(int32(1), int32(2), "i").
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("r"))
+	mock.ExpectExec("UPDATE notes SET position").WithArgs("l", int32(2), int32(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE notes_items SET position").WithArgs("c", int32(5), int32(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	nr := noteRepo.NewMysqlNoteRepository(db)
+	next, err := nr.NextPosition(context.TODO(), 1, 2, "i")
+	assert.NoError(t, err)
+	assert.Equal(t, "r", next)
+	assert.NoError(t, nr.UpdatePosition(context.TODO(), 2, 1, "l"))
+	assert.NoError(t, nr.UpdateItemPosition(context.TODO(), 2, 5, "c"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
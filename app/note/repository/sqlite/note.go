@@ -0,0 +1,873 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"librenote/app/model"
+	"strings"
+)
+
+type noteRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteNoteRepository(db *sql.DB) model.NoteRepository {
+	return &noteRepository{
+		db: db,
+	}
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+const createNote = `INSERT INTO notes (
+  user_id, title, body, color, type, is_pinned, is_archived, is_trashed, drawing, encrypted, position, created_at,
+  updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+func (r *noteRepository) CreateNote(ctx context.Context, note *model.Note, revision *model.NoteRevision) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, createNote,
+		note.UserID,
+		note.Title,
+		note.Body,
+		note.Color,
+		note.Type,
+		note.IsPinned,
+		note.IsArchived,
+		note.IsTrashed,
+		note.Drawing,
+		note.Encrypted,
+		note.Position,
+		note.CreatedAt,
+		note.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	note.ID = int32(id)
+
+	if err = createItems(ctx, tx, note); err != nil {
+		return err
+	}
+
+	if err = createLinks(ctx, tx, note); err != nil {
+		return err
+	}
+
+	if err = createReferences(ctx, tx, note); err != nil {
+		return err
+	}
+
+	if err = createLabels(ctx, tx, note); err != nil {
+		return err
+	}
+
+	revision.NoteID = note.ID
+	if err = createRevision(ctx, tx, revision); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const createNoteItem = `INSERT INTO notes_items (
+  note_id, text, is_checked, position, created_at
+) VALUES (?, ?, ?, ?, ?)
+`
+
+func createItems(ctx context.Context, q querier, note *model.Note) error {
+	for i := range note.Items {
+		note.Items[i].NoteID = note.ID
+
+		if err := createItem(ctx, q, &note.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createItem(ctx context.Context, q querier, item *model.NotesItem) error {
+	res, err := q.ExecContext(ctx, createNoteItem,
+		item.NoteID,
+		item.Text,
+		item.IsChecked,
+		item.Position,
+		item.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	item.ID = int32(id)
+
+	return nil
+}
+
+const createNoteLink = `INSERT INTO notes_links (note_id, url) VALUES (?, ?)`
+
+func createLinks(ctx context.Context, q querier, note *model.Note) error {
+	for _, link := range note.Links {
+		if _, err := q.ExecContext(ctx, createNoteLink, note.ID, link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const createNoteReference = `INSERT INTO notes_references (note_id, target_id, title, title_hash) VALUES (?, ?, ?, ?)`
+
+func createReferences(ctx context.Context, q querier, note *model.Note) error {
+	for _, ref := range note.References {
+		if _, err := q.ExecContext(ctx, createNoteReference, note.ID, ref.TargetID, ref.Title,
+			ref.TitleHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const createNoteLabel = `INSERT OR IGNORE INTO notes_labels (note_id, label_id)
+SELECT ?, id FROM labels WHERE id = ? AND user_id = ?`
+
+func createLabels(ctx context.Context, q querier, note *model.Note) error {
+	for _, labelID := range note.LabelIDs {
+		if _, err := q.ExecContext(ctx, createNoteLabel, note.ID, labelID, note.UserID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const createNoteRevision = `INSERT INTO notes_revisions (
+  note_id, snapshot, created_at
+) VALUES (?, ?, ?)
+`
+
+func createRevision(ctx context.Context, q querier, revision *model.NoteRevision) error {
+	res, err := q.ExecContext(ctx, createNoteRevision,
+		revision.NoteID,
+		revision.Snapshot,
+		revision.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	revision.ID = int32(id)
+
+	return nil
+}
+
+const getNote = `SELECT id, user_id, title, COALESCE(body, ''), COALESCE(color, ''), type, is_pinned, is_archived,
+is_trashed, drawing, encrypted, position, created_at, updated_at FROM notes WHERE id = ? AND user_id = ? LIMIT 1
+`
+
+func (r *noteRepository) GetNote(ctx context.Context, id, userID int32) (model.Note, error) {
+	return getNoteRow(ctx, r.db, id, userID)
+}
+
+// getNoteRow reads a note along with its items
+func getNoteRow(ctx context.Context, q querier, id, userID int32) (model.Note, error) {
+	row := q.QueryRowContext(ctx, getNote, id, userID)
+
+	var i model.Note
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Body,
+		&i.Color,
+		&i.Type,
+		&i.IsPinned,
+		&i.IsArchived,
+		&i.IsTrashed,
+		&i.Drawing,
+		&i.Encrypted,
+		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	if err != nil {
+		return i, err
+	}
+
+	i.Items, err = fetchItems(ctx, q, i.ID)
+
+	return i, err
+}
+
+const countNotes = `SELECT COUNT(*) FROM notes `
+
+const fetchNotes = `SELECT id, user_id, title, COALESCE(body, ''), COALESCE(color, ''), type, is_pinned, is_archived,
+is_trashed, drawing, encrypted, position, created_at, updated_at FROM notes `
+
+const notesWhere = `WHERE user_id = ? AND is_archived = ? AND is_trashed = ?`
+
+const notesLabelsWhere = ` AND id IN (SELECT note_id FROM notes_labels WHERE label_id IN (%s))`
+
+const notesOrder = `
+ORDER BY is_pinned DESC, position, updated_at DESC, id DESC LIMIT ? OFFSET ?`
+
+// notesFilter returns the where clause of the filter and its arguments
+func notesFilter(filter model.NoteFilter) (string, []interface{}) {
+	where := notesWhere
+	args := []interface{}{filter.UserID, filter.IsArchived, filter.IsTrashed}
+
+	if len(filter.LabelIDs) > 0 {
+		placeholders := make([]string, len(filter.LabelIDs))
+		for i, id := range filter.LabelIDs {
+			args = append(args, id)
+			placeholders[i] = "?"
+		}
+
+		where += fmt.Sprintf(notesLabelsWhere, strings.Join(placeholders, ", "))
+	}
+
+	return where, args
+}
+
+func (r *noteRepository) FetchNotes(ctx context.Context, filter model.NoteFilter, limit, offset int) (
+	[]model.Note, int, error) {
+	var count int
+
+	where, args := notesFilter(filter)
+
+	err := r.db.QueryRowContext(ctx, countNotes+where, args...).Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, fetchNotes+where+notesOrder, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	notes := make([]model.Note, 0)
+
+	for rows.Next() {
+		var i model.Note
+		if err = rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Body,
+			&i.Color,
+			&i.Type,
+			&i.IsPinned,
+			&i.IsArchived,
+			&i.IsTrashed,
+			&i.Drawing,
+			&i.Encrypted,
+			&i.Position,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+
+		notes = append(notes, i)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	for idx := range notes {
+		notes[idx].Items, err = fetchItems(ctx, r.db, notes[idx].ID)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return notes, count, nil
+}
+
+const fetchNoteItems = `SELECT id, note_id, text, is_checked, position, created_at FROM notes_items
+WHERE note_id = ? ORDER BY position, id
+`
+
+func fetchItems(ctx context.Context, q querier, noteID int32) ([]model.NotesItem, error) {
+	rows, err := q.QueryContext(ctx, fetchNoteItems, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	items := make([]model.NotesItem, 0)
+
+	for rows.Next() {
+		var i model.NotesItem
+		if err = rows.Scan(
+			&i.ID,
+			&i.NoteID,
+			&i.Text,
+			&i.IsChecked,
+			&i.Position,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const updateNote = `UPDATE notes
+SET title = ?,
+body = ?,
+color = ?,
+type = ?,
+is_pinned = ?,
+is_archived = ?,
+is_trashed = ?,
+drawing = ?,
+encrypted = ?,
+updated_at = ?
+WHERE id = ? AND user_id = ?
+`
+
+const deleteNoteItems = `DELETE FROM notes_items WHERE note_id = ?`
+
+func (r *noteRepository) UpdateNote(ctx context.Context, note *model.Note, revision *model.NoteRevision,
+	keepRevisions int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, updateNote,
+		note.Title,
+		note.Body,
+		note.Color,
+		note.Type,
+		note.IsPinned,
+		note.IsArchived,
+		note.IsTrashed,
+		note.Drawing,
+		note.Encrypted,
+		note.UpdatedAt,
+		note.ID,
+		note.UserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return errors.New("nothing changed")
+	}
+
+	if err = saveItems(ctx, tx, note); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, deleteNoteLinks, note.ID); err != nil {
+		return err
+	}
+
+	if err = createLinks(ctx, tx, note); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, deleteNoteReferences, note.ID); err != nil {
+		return err
+	}
+
+	if err = createReferences(ctx, tx, note); err != nil {
+		return err
+	}
+
+	revision.NoteID = note.ID
+	if err = createRevision(ctx, tx, revision); err != nil {
+		return err
+	}
+
+	if err = pruneRevisions(ctx, tx, note.ID, keepRevisions); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const fetchNoteItemIDs = `SELECT id FROM notes_items WHERE note_id = ? ORDER BY id`
+
+const updateNoteItem = `UPDATE notes_items SET text = ?, is_checked = ?, position = ? WHERE id = ? AND note_id = ?`
+
+const deleteNoteItem = `DELETE FROM notes_items WHERE id = ? AND note_id = ?`
+
+// saveItems stores the items of an updated note by id, so they keep it as long as they are listed.
+// The items without a stored id are created, the stored ones that aren't listed anymore are deleted
+func saveItems(ctx context.Context, q querier, note *model.Note) error {
+	storedIDs, err := fetchItemIDs(ctx, q, note.ID)
+	if err != nil {
+		return err
+	}
+
+	listed := make(map[int32]bool, len(note.Items))
+	for _, item := range note.Items {
+		listed[item.ID] = true
+	}
+
+	stored := make(map[int32]bool, len(storedIDs))
+
+	for _, id := range storedIDs {
+		if listed[id] {
+			stored[id] = true
+
+			continue
+		}
+
+		if _, err = q.ExecContext(ctx, deleteNoteItem, id, note.ID); err != nil {
+			return err
+		}
+	}
+
+	for i := range note.Items {
+		item := &note.Items[i]
+		item.NoteID = note.ID
+
+		if !stored[item.ID] {
+			if err = createItem(ctx, q, item); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// an item listed twice is updated once, the copy is a new item
+		delete(stored, item.ID)
+
+		_, err = q.ExecContext(ctx, updateNoteItem, item.Text, item.IsChecked, item.Position, item.ID, item.NoteID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func fetchItemIDs(ctx context.Context, q querier, noteID int32) ([]int32, error) {
+	rows, err := q.QueryContext(ctx, fetchNoteItemIDs, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	ids := make([]int32, 0)
+
+	for rows.Next() {
+		var id int32
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+const oldestKeptRevision = `SELECT id FROM notes_revisions WHERE note_id = ? ORDER BY id DESC LIMIT 1 OFFSET ?`
+
+const deleteOldRevisions = `DELETE FROM notes_revisions WHERE note_id = ? AND id <= ?`
+
+// pruneRevisions keeps only the latest `keep` revisions of a note
+func pruneRevisions(ctx context.Context, q querier, noteID int32, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	var id int32
+
+	err := q.QueryRowContext(ctx, oldestKeptRevision, noteID, keep).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = q.ExecContext(ctx, deleteOldRevisions, noteID, id)
+
+	return err
+}
+
+const getNoteID = `SELECT id FROM notes WHERE id = ? AND user_id = ? LIMIT 1`
+
+const deleteNoteLinks = `DELETE FROM notes_links WHERE note_id = ?`
+
+const deleteNoteReferences = `DELETE FROM notes_references WHERE note_id = ?`
+
+const deleteNoteLabels = `DELETE FROM notes_labels WHERE note_id = ?`
+
+const deleteNoteRevisions = `DELETE FROM notes_revisions WHERE note_id = ?`
+
+const deleteNoteReminderEvents = `DELETE FROM reminders_events WHERE note_id = ?`
+
+const deleteNoteReminders = `DELETE FROM reminders WHERE note_id = ?`
+
+const deleteNote = `DELETE FROM notes WHERE id = ?`
+
+func (r *noteRepository) DeleteNote(ctx context.Context, id, userID int32) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	if err = tx.QueryRowContext(ctx, getNoteID, id, userID).Scan(&id); err != nil {
+		return err
+	}
+
+	if err = deleteNoteRows(ctx, tx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteNoteRows deletes the note along with the rows referring to it
+func deleteNoteRows(ctx context.Context, q querier, id int32) error {
+	for _, query := range []string{
+		deleteNoteItems, deleteNoteLinks, deleteNoteReferences, deleteNoteLabels, deleteNoteRevisions,
+		deleteNoteReminderEvents, deleteNoteReminders, deleteNote,
+	} {
+		if _, err := q.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const fetchRevisions = `SELECT id, note_id, snapshot, created_at FROM notes_revisions
+WHERE note_id = ? ORDER BY id DESC
+`
+
+func (r *noteRepository) FetchRevisions(ctx context.Context, noteID int32) ([]model.NoteRevision, error) {
+	rows, err := r.db.QueryContext(ctx, fetchRevisions, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	revisions := make([]model.NoteRevision, 0)
+
+	for rows.Next() {
+		var i model.NoteRevision
+		if err = rows.Scan(
+			&i.ID,
+			&i.NoteID,
+			&i.Snapshot,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, i)
+	}
+
+	return revisions, rows.Err()
+}
+
+const getRevision = `SELECT id, note_id, snapshot, created_at FROM notes_revisions
+WHERE note_id = ? AND id = ? LIMIT 1
+`
+
+func (r *noteRepository) GetRevision(ctx context.Context, noteID, id int32) (model.NoteRevision, error) {
+	row := r.db.QueryRowContext(ctx, getRevision, noteID, id)
+
+	var i model.NoteRevision
+	err := row.Scan(
+		&i.ID,
+		&i.NoteID,
+		&i.Snapshot,
+		&i.CreatedAt,
+	)
+
+	return i, err
+}
+
+const fetchBacklinks = `SELECT id, title, type, updated_at FROM notes n
+WHERE user_id = ? AND is_trashed = 0 AND id <> ? AND EXISTS (
+  SELECT 1 FROM notes_references r WHERE r.note_id = n.id AND (r.target_id = ? OR r.title = ? OR r.title_hash = ?)
+) ORDER BY updated_at DESC, id DESC
+`
+
+func (r *noteRepository) FetchBacklinks(ctx context.Context, id, userID int32, title string) (
+	[]model.NoteSummary, error) {
+	rows, err := r.db.QueryContext(ctx, fetchBacklinks, userID, id, id, title, title)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSummaries(rows)
+}
+
+func scanSummaries(rows *sql.Rows) ([]model.NoteSummary, error) {
+	defer rows.Close()
+
+	notes := make([]model.NoteSummary, 0)
+
+	for rows.Next() {
+		var i model.NoteSummary
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Type,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		notes = append(notes, i)
+	}
+
+	return notes, rows.Err()
+}
+
+const fetchGraphNotes = `SELECT id, title, type, updated_at FROM notes
+WHERE user_id = ? AND is_trashed = 0 ORDER BY id
+`
+
+const fetchGraphReferences = `SELECT r.note_id, r.target_id, r.title FROM notes_references r
+JOIN notes n ON n.id = r.note_id WHERE n.user_id = ? AND n.is_trashed = 0 ORDER BY r.id
+`
+
+func (r *noteRepository) FetchGraph(ctx context.Context, userID int32) (
+	[]model.NoteSummary, []model.NoteReference, error) {
+	rows, err := r.db.QueryContext(ctx, fetchGraphNotes, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notes, err := scanSummaries(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err = r.db.QueryContext(ctx, fetchGraphReferences, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	refs := make([]model.NoteReference, 0)
+
+	for rows.Next() {
+		var i model.NoteReference
+		if err = rows.Scan(
+			&i.NoteID,
+			&i.TargetID,
+			&i.Title,
+		); err != nil {
+			return nil, nil, err
+		}
+
+		refs = append(refs, i)
+	}
+
+	return notes, refs, rows.Err()
+}
+
+const getLabelID = `SELECT id FROM labels WHERE id = ? AND user_id = ? LIMIT 1`
+
+const bulkUpdateNote = `UPDATE notes SET %s = ?, updated_at = ? WHERE id = ?`
+
+const addNoteLabel = `INSERT OR IGNORE INTO notes_labels (note_id, label_id) VALUES (?, ?)`
+
+const removeNoteLabel = `DELETE FROM notes_labels WHERE note_id = ? AND label_id = ?`
+
+func (r *noteRepository) BulkNotes(ctx context.Context, op model.BulkOperation) ([]model.BulkResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	if op.Action == model.BulkAddLabel || op.Action == model.BulkRemoveLabel {
+		if err = tx.QueryRowContext(ctx, getLabelID, op.LabelID, op.UserID).Scan(&op.LabelID); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]model.BulkResult, 0, len(op.IDs))
+
+	for _, id := range op.IDs {
+		found, err := bulkNote(ctx, tx, op, id)
+		if err != nil {
+			return nil, err
+		}
+
+		status := model.BulkOK
+		if !found {
+			status = model.BulkNotFound
+		}
+
+		results = append(results, model.BulkResult{ID: id, Status: status})
+	}
+
+	return results, tx.Commit()
+}
+
+// bulkNote applies the operation to a note, it reports false when the user has no such note
+func bulkNote(ctx context.Context, q querier, op model.BulkOperation, id int32) (bool, error) {
+	err := q.QueryRowContext(ctx, getNoteID, id, op.UserID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	switch op.Action {
+	case model.BulkAddLabel:
+		_, err = q.ExecContext(ctx, addNoteLabel, id, op.LabelID)
+	case model.BulkRemoveLabel:
+		_, err = q.ExecContext(ctx, removeNoteLabel, id, op.LabelID)
+	case model.BulkDelete:
+		err = deleteNoteRows(ctx, q, id)
+	default:
+		column, value, ok := bulkColumn(op)
+		if !ok {
+			return false, fmt.Errorf("unknown bulk action %s", op.Action)
+		}
+
+		if _, err = q.ExecContext(ctx, fmt.Sprintf(bulkUpdateNote, column), value, op.UpdatedAt, id); err != nil {
+			return false, err
+		}
+
+		err = bulkRevision(ctx, q, op, id)
+	}
+
+	return true, err
+}
+
+// bulkRevision records the note changed by the action as a revision, then prunes the old ones as updating a note
+// does
+func bulkRevision(ctx context.Context, q querier, op model.BulkOperation, id int32) error {
+	if op.Revision == nil {
+		return nil
+	}
+
+	note, err := getNoteRow(ctx, q, id, op.UserID)
+	if err != nil {
+		return err
+	}
+
+	revision, err := op.Revision(&note)
+	if err != nil {
+		return err
+	}
+
+	revision.NoteID = id
+	revision.CreatedAt = op.UpdatedAt
+
+	if err = createRevision(ctx, q, revision); err != nil {
+		return err
+	}
+
+	return pruneRevisions(ctx, q, id, op.KeepRevisions)
+}
+
+// bulkColumn returns the column the action sets and its new value
+func bulkColumn(op model.BulkOperation) (string, interface{}, bool) {
+	switch op.Action {
+	case model.BulkPin, model.BulkUnpin:
+		return "is_pinned", boolInt(op.Action == model.BulkPin), true
+	case model.BulkArchive, model.BulkUnarchive:
+		return "is_archived", boolInt(op.Action == model.BulkArchive), true
+	case model.BulkTrash, model.BulkRestore:
+		return "is_trashed", boolInt(op.Action == model.BulkTrash), true
+	case model.BulkRecolor:
+		return "color", op.Color, true
+	}
+
+	return "", nil, false
+}
+
+func boolInt(b bool) int8 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+const nextPosition = `SELECT COALESCE(MIN(position), '') FROM notes WHERE user_id = ? AND id <> ? AND position > ?`
+
+func (r *noteRepository) NextPosition(ctx context.Context, userID, exceptID int32, after string) (string, error) {
+	var position string
+	err := r.db.QueryRowContext(ctx, nextPosition, userID, exceptID, after).Scan(&position)
+
+	return position, err
+}
+
+const updatePosition = `UPDATE notes SET position = ? WHERE id = ? AND user_id = ?`
+
+func (r *noteRepository) UpdatePosition(ctx context.Context, id, userID int32, position string) error {
+	_, err := r.db.ExecContext(ctx, updatePosition, position, id, userID)
+
+	return err
+}
+
+const updateItemPosition = `UPDATE notes_items SET position = ? WHERE id = ? AND note_id = ?`
+
+func (r *noteRepository) UpdateItemPosition(ctx context.Context, noteID, itemID int32, position string) error {
+	_, err := r.db.ExecContext(ctx, updateItemPosition, position, itemID, noteID)
+
+	return err
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"librenote/app/model"
+	"librenote/app/response"
+	"net/http"
+)
+
+// Bulk resolves the filter to the ids of the notes it matches, then applies the action to all of them at once
+func (u *noteUsecase) Bulk(c context.Context, op *model.BulkOperation, limit int) ([]model.BulkResult, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if op.Action == model.BulkRecolor {
+		if err := u.checkColor(ctx, &model.Note{UserID: op.UserID, Color: op.Color}); err != nil {
+			return nil, err
+		}
+	}
+
+	ids, err := u.bulkIDs(ctx, op, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return make([]model.BulkResult, 0), nil
+	}
+
+	op.IDs = ids
+	op.Revision = newRevision
+	op.KeepRevisions = u.maxRevisions
+
+	results, err := u.repo.BulkNotes(ctx, *op)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, response.WrapError(errors.New("label not found"), http.StatusBadRequest)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	u.publishBulk(ctx, op, results)
+
+	return results, nil
+}
+
+// bulkIDs returns the ids of the operation without duplicates, or the ids of the notes the filter matches
+func (u *noteUsecase) bulkIDs(ctx context.Context, op *model.BulkOperation, limit int) ([]int32, error) {
+	if len(op.IDs) == 0 && op.Filter != nil {
+		filter := *op.Filter
+		filter.UserID = op.UserID
+
+		notes, count, err := u.repo.FetchNotes(ctx, filter, limit, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if count > limit {
+			return nil, response.WrapError(
+				fmt.Errorf("the filter matches %d notes, at most %d notes can be changed at once", count, limit),
+				http.StatusBadRequest)
+		}
+
+		ids := make([]int32, 0, len(notes))
+		for _, note := range notes {
+			ids = append(ids, note.ID)
+		}
+
+		return ids, nil
+	}
+
+	seen := make(map[int32]bool, len(op.IDs))
+	ids := make([]int32, 0, len(op.IDs))
+
+	for _, id := range op.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) > limit {
+		return nil, response.WrapError(fmt.Errorf("at most %d notes can be changed at once", limit),
+			http.StatusBadRequest)
+	}
+
+	return ids, nil
+}
+
+// publishBulk tells about the changed notes, changing the labels of a note doesn't change the note itself
+func (u *noteUsecase) publishBulk(ctx context.Context, op *model.BulkOperation, results []model.BulkResult) {
+	if op.Action == model.BulkAddLabel || op.Action == model.BulkRemoveLabel {
+		return
+	}
+
+	for _, result := range results {
+		if result.Status != model.BulkOK {
+			continue
+		}
+
+		if op.Action == model.BulkDelete {
+			u.events.Publish(ctx, op.UserID, model.EventNoteDeleted, map[string]int32{"id": result.ID})
+
+			continue
+		}
+
+		if note, err := u.repo.GetNote(ctx, result.ID, op.UserID); err == nil {
+			u.events.Publish(ctx, op.UserID, model.EventNoteUpdated, note)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/note/usecase"
+	"librenote/app/response"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBulk(t *testing.T) {
+	t.Run("ids", func(t *testing.T) {
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockNoteRepo.On("BulkNotes", mock.Anything, mock.MatchedBy(func(op model.BulkOperation) bool {
+			if op.Revision == nil || op.KeepRevisions != 10 {
+				return false
+			}
+
+			// the revision holds the changed note
+			revision, err := op.Revision(&model.Note{ID: 1, Body: "hello", IsArchived: 1, UpdatedAt: "2022-06-01"})
+
+			return assert.ObjectsAreEqual([]int32{3, 1}, op.IDs) && err == nil &&
+				strings.Contains(revision.Snapshot, `"is_archived":1`)
+		})).Return([]model.BulkResult{{ID: 3, Status: model.BulkNotFound}, {ID: 1, Status: model.BulkOK}}, nil).Once()
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(mockNote(), nil).Once()
+
+		events := new(mocks.EventPublisher)
+		events.On("Publish", mock.Anything, int32(1), model.EventNoteUpdated, mock.AnythingOfType("model.Note")).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), events, time.Second*2, 10, 0)
+
+		results, err := u.Bulk(context.TODO(), &model.BulkOperation{
+			UserID: 1, Action: model.BulkArchive, IDs: []int32{3, 1, 3},
+		}, 2)
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		mockNoteRepo.AssertExpectations(t)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("too-many-ids", func(t *testing.T) {
+		u := usecase.NewNoteUsecase(new(mocks.NoteRepository), new(mocks.UserRepository), noEvents(),
+			time.Second*2, 10, 0)
+
+		_, err := u.Bulk(context.TODO(), &model.BulkOperation{
+			UserID: 1, Action: model.BulkDelete, IDs: []int32{1, 2, 3},
+		}, 2)
+		code, _ := response.RespondError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		filter := model.NoteFilter{UserID: 1, IsTrashed: 1}
+
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockNoteRepo.On("FetchNotes", mock.Anything, filter, 2, 0).
+			Return([]model.Note{{ID: 4}, {ID: 5}}, 2, nil).Once()
+		mockNoteRepo.On("BulkNotes", mock.Anything, mock.MatchedBy(func(op model.BulkOperation) bool {
+			return assert.ObjectsAreEqual([]int32{4, 5}, op.IDs)
+		})).Return([]model.BulkResult{{ID: 4, Status: model.BulkOK}, {ID: 5, Status: model.BulkOK}}, nil).Once()
+
+		events := new(mocks.EventPublisher)
+		events.On("Publish", mock.Anything, int32(1), model.EventNoteDeleted, mock.Anything).Twice()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), events, time.Second*2, 10, 0)
+
+		// the filter is always narrowed down to the notes of the user
+		results, err := u.Bulk(context.TODO(), &model.BulkOperation{
+			UserID: 1, Action: model.BulkDelete, Filter: &model.NoteFilter{UserID: 2, IsTrashed: 1},
+		}, 2)
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		mockNoteRepo.AssertExpectations(t)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("filter-over-limit", func(t *testing.T) {
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockNoteRepo.On("FetchNotes", mock.Anything, model.NoteFilter{UserID: 1}, 2, 0).
+			Return([]model.Note{{ID: 4}, {ID: 5}}, 3, nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+
+		_, err := u.Bulk(context.TODO(), &model.BulkOperation{
+			UserID: 1, Action: model.BulkTrash, Filter: &model.NoteFilter{},
+		}, 2)
+		code, _ := response.RespondError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		mockNoteRepo.AssertNotCalled(t, "BulkNotes", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown-label", func(t *testing.T) {
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockNoteRepo.On("BulkNotes", mock.Anything, mock.AnythingOfType("model.BulkOperation")).
+			Return(nil, sql.ErrNoRows).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+
+		_, err := u.Bulk(context.TODO(), &model.BulkOperation{
+			UserID: 1, Action: model.BulkAddLabel, IDs: []int32{1}, LabelID: 9,
+		}, 2)
+		code, _ := response.RespondError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("unknown-color", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("FetchColors", mock.Anything, int32(1)).Return([]model.CustomColor{}, nil).Once()
+
+		u := usecase.NewNoteUsecase(new(mocks.NoteRepository), mockUserRepo, noEvents(), time.Second*2, 10, 0)
+
+		_, err := u.Bulk(context.TODO(), &model.BulkOperation{
+			UserID: 1, Action: model.BulkRecolor, IDs: []int32{1}, Color: "mint",
+		}, 2)
+		code, _ := response.RespondError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
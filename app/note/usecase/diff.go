@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"librenote/app/model"
+	"reflect"
+)
+
+const (
+	itemAdded     = "added"
+	itemRemoved   = "removed"
+	itemChecked   = "checked"
+	itemUnchecked = "unchecked"
+)
+
+// diffFields list the note attributes which differ between two snapshots
+func diffFields(from, to *model.NoteSnapshot) []model.FieldChange {
+	changes := make([]model.FieldChange, 0)
+
+	if !sameTitle(from.Title, to.Title) {
+		changes = append(changes, model.FieldChange{Field: "title", From: from.Title, To: to.Title})
+	}
+
+	if from.Body != to.Body {
+		changes = append(changes, model.FieldChange{Field: "body", From: from.Body, To: to.Body})
+	}
+
+	if from.Color != to.Color {
+		changes = append(changes, model.FieldChange{Field: "color", From: from.Color, To: to.Color})
+	}
+
+	if from.Type != to.Type {
+		changes = append(changes, model.FieldChange{Field: "type", From: from.Type, To: to.Type})
+	}
+
+	if from.IsPinned != to.IsPinned {
+		changes = append(changes, model.FieldChange{Field: "is_pinned", From: from.IsPinned, To: to.IsPinned})
+	}
+
+	if from.IsArchived != to.IsArchived {
+		changes = append(changes, model.FieldChange{Field: "is_archived", From: from.IsArchived, To: to.IsArchived})
+	}
+
+	// strokes aren't diffed one by one, the stroke counts give an idea of the change
+	if !reflect.DeepEqual(from.Drawing, to.Drawing) {
+		changes = append(changes, model.FieldChange{
+			Field: "drawing", From: strokeCount(from.Drawing), To: strokeCount(to.Drawing),
+		})
+	}
+
+	// the ciphertexts can't be read, their lengths give an idea of the change
+	if !reflect.DeepEqual(from.Encrypted, to.Encrypted) {
+		changes = append(changes, model.FieldChange{
+			Field: "ciphertext", From: ciphertextLength(from.Encrypted), To: ciphertextLength(to.Encrypted),
+		})
+	}
+
+	return changes
+}
+
+func ciphertextLength(e *model.EncryptedContent) int {
+	if e == nil {
+		return 0
+	}
+
+	return len(e.Ciphertext)
+}
+
+func strokeCount(d *model.Drawing) int {
+	if d == nil {
+		return 0
+	}
+
+	return len(d.Strokes)
+}
+
+func sameTitle(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// diffItems compares two item lists by their text using the longest common subsequence,
+// items present in both lists are reported only when their checked state changed
+func diffItems(from, to []model.SnapshotItem) []model.ItemChange {
+	// lcs[i][j] holds the common subsequence length of from[i:] and to[j:]
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			switch {
+			case from[i].Text == to[j].Text:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	changes := make([]model.ItemChange, 0)
+	i, j := 0, 0
+
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i].Text == to[j].Text:
+			if from[i].IsChecked != to[j].IsChecked {
+				op := itemUnchecked
+				if to[j].IsChecked == 1 {
+					op = itemChecked
+				}
+
+				changes = append(changes, model.ItemChange{Op: op, Text: to[j].Text})
+			}
+
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			changes = append(changes, model.ItemChange{Op: itemRemoved, Text: from[i].Text})
+			i++
+		default:
+			changes = append(changes, model.ItemChange{Op: itemAdded, Text: to[j].Text})
+			j++
+		}
+	}
+
+	for ; i < len(from); i++ {
+		changes = append(changes, model.ItemChange{Op: itemRemoved, Text: from[i].Text})
+	}
+
+	for ; j < len(to); j++ {
+		changes = append(changes, model.ItemChange{Op: itemAdded, Text: to[j].Text})
+	}
+
+	return changes
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"librenote/app/drawing"
+	"librenote/app/model"
+	"librenote/app/response"
+	"net/http"
+)
+
+const typeDrawing = "drawing"
+
+// checkDrawing requires the strokes on drawing notes and drops them from other types
+func (u *noteUsecase) checkDrawing(m *model.Note) error {
+	if m.Type != typeDrawing {
+		m.Drawing = nil
+		return nil
+	}
+
+	if m.Drawing == nil {
+		return response.WrapError(errors.New("drawing is required for drawing notes"), http.StatusBadRequest)
+	}
+
+	if len(m.Items) > 0 {
+		return response.WrapError(errors.New("drawing notes can't have items"), http.StatusBadRequest)
+	}
+
+	if err := drawing.Validate(m.Drawing); err != nil {
+		return response.WrapError(err, http.StatusBadRequest)
+	}
+
+	encoded, err := json.Marshal(m.Drawing)
+	if err != nil {
+		return err
+	}
+
+	if u.maxDrawingBytes > 0 && int64(len(encoded)) > u.maxDrawingBytes {
+		return response.WrapError(fmt.Errorf("drawing is larger than %d bytes", u.maxDrawingBytes),
+			http.StatusRequestEntityTooLarge)
+	}
+
+	return nil
+}
+
+// RenderDrawing returns the drawing as png, scaled to fit `size` pixels when it isn't 0
+func (u *noteUsecase) RenderDrawing(c context.Context, id, userID int32, size int) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	note, err := u.getNote(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if note.Type != typeDrawing || note.Drawing == nil {
+		return nil, response.WrapError(errors.New("note is not a drawing"), http.StatusNotFound)
+	}
+
+	img, err := drawing.Render(ctx, note.Drawing, size)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		if errors.Is(err, drawing.ErrTooComplex) {
+			return nil, response.WrapError(err, http.StatusUnprocessableEntity)
+		}
+
+		return nil, response.WrapError(err, http.StatusBadRequest)
+	}
+
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
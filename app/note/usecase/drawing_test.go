@@ -0,0 +1,113 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/note/usecase"
+	"librenote/app/response"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockDrawingNote() model.Note {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	return model.Note{
+		ID:        2,
+		UserID:    1,
+		Type:      "drawing",
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+		Drawing: &model.Drawing{Width: 400, Height: 200, Strokes: []model.Stroke{
+			{Color: "#000000", Width: 4, Points: []float64{10, 10, 390, 190}},
+		}},
+	}
+}
+
+func TestCreateDrawing(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockNoteRepo := new(mocks.NoteRepository)
+		note := mockDrawingNote()
+
+		mockNoteRepo.On("NextPosition", mock.Anything, int32(1), int32(0), "").Return("", nil).Once()
+		mockNoteRepo.On("CreateNote", mock.Anything, &note, mock.AnythingOfType("*model.NoteRevision")).
+			Return(nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 1024)
+		assert.NoError(t, u.Create(context.TODO(), &note))
+		mockNoteRepo.AssertExpectations(t)
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		note := mockDrawingNote()
+		for i := 0; i < 100; i++ {
+			note.Drawing.Strokes = append(note.Drawing.Strokes, note.Drawing.Strokes[0])
+		}
+
+		u := usecase.NewNoteUsecase(new(mocks.NoteRepository), new(mocks.UserRepository), noEvents(), time.Second*2, 10, 1024)
+		err := u.Create(context.TODO(), &note)
+
+		code, _ := response.RespondError(err)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, change := range map[string]func(n *model.Note){
+			"missing":       func(n *model.Note) { n.Drawing = nil },
+			"with-items":    func(n *model.Note) { n.Items = mockNote().Items },
+			"out-of-canvas": func(n *model.Note) { n.Drawing.Strokes[0].Points = []float64{500, 10} },
+		} {
+			note := mockDrawingNote()
+			change(&note)
+
+			u := usecase.NewNoteUsecase(new(mocks.NoteRepository), new(mocks.UserRepository), noEvents(),
+				time.Second*2, 10, 1024)
+			err := u.Create(context.TODO(), &note)
+
+			code, _ := response.RespondError(err)
+			assert.Equal(t, http.StatusBadRequest, code, name)
+		}
+	})
+
+	t.Run("other-type", func(t *testing.T) {
+		mockNoteRepo := new(mocks.NoteRepository)
+		note := mockNote()
+		note.Drawing = mockDrawingNote().Drawing
+
+		mockNoteRepo.On("NextPosition", mock.Anything, int32(1), int32(0), "").Return("", nil).Once()
+		mockNoteRepo.On("CreateNote", mock.Anything, mock.MatchedBy(func(n *model.Note) bool {
+			return n.Drawing == nil
+		}), mock.AnythingOfType("*model.NoteRevision")).Return(nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 1024)
+		assert.NoError(t, u.Create(context.TODO(), &note))
+		mockNoteRepo.AssertExpectations(t)
+	})
+}
+
+func TestRenderDrawing(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+	mockNoteRepo.On("GetNote", mock.Anything, int32(2), int32(1)).Return(mockDrawingNote(), nil)
+	mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(mockNote(), nil)
+
+	u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+
+	data, err := u.RenderDrawing(context.TODO(), 2, 1, 100)
+	assert.NoError(t, err)
+
+	img, err := png.DecodeConfig(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 100, img.Width)
+	assert.Equal(t, 50, img.Height)
+
+	_, err = u.RenderDrawing(context.TODO(), 1, 1, 0)
+	code, _ := response.RespondError(err)
+	assert.Equal(t, http.StatusNotFound, code)
+}
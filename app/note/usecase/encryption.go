@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"errors"
+	"librenote/app/model"
+	"librenote/app/response"
+	"net/http"
+)
+
+// checkEncrypted keeps the content of an encrypted note in its ciphertext only, so nothing of it is stored in
+// clear nor indexed, like the links and the references of the body
+func checkEncrypted(m *model.Note) error {
+	if m.Encrypted == nil {
+		return nil
+	}
+
+	if m.Type == typeDrawing {
+		return response.WrapError(errors.New("drawing notes can't be encrypted"), http.StatusBadRequest)
+	}
+
+	if m.Title != nil || m.Body != "" || len(m.Items) > 0 {
+		return response.WrapError(errors.New("an encrypted note keeps its title, body and items in the ciphertext"),
+			http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// checkEncryptionChange refuses to encrypt a note or to decrypt it, the revisions of a note would keep its
+// content in clear
+func checkEncryptionChange(current, m *model.Note) error {
+	if (current.Encrypted == nil) != (m.Encrypted == nil) {
+		return response.WrapError(errors.New("a note can't be encrypted or decrypted, create a new note instead"),
+			http.StatusConflict)
+	}
+
+	return nil
+}
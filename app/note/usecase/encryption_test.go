@@ -0,0 +1,113 @@
+package usecase_test
+
+import (
+	"context"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/note/usecase"
+	"librenote/app/response"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockEncryptedNote() model.Note {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	return model.Note{
+		ID:        3,
+		UserID:    1,
+		Color:     "red",
+		Type:      "note",
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+		Items:     []model.NotesItem{},
+		Encrypted: &model.EncryptedContent{
+			Ciphertext: "c2VjcmV0", Algorithm: "AES-GCM", Nonce: "bm9uY2U=", KeyVersion: 1,
+		},
+	}
+}
+
+func TestCreateEncrypted(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockNoteRepo := new(mocks.NoteRepository)
+		note := mockEncryptedNote()
+
+		mockNoteRepo.On("NextPosition", mock.Anything, int32(1), int32(0), "").Return("", nil).Once()
+		mockNoteRepo.On("CreateNote", mock.Anything, mock.MatchedBy(func(n *model.Note) bool {
+			return n.Encrypted.Ciphertext == "c2VjcmV0" && len(n.Links) == 0 && len(n.References) == 0
+		}), mock.MatchedBy(func(r *model.NoteRevision) bool {
+			return r.Snapshot != "" && r.Snapshot != "{}"
+		})).Return(nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+		assert.NoError(t, u.Create(context.TODO(), &note))
+		mockNoteRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, change := range map[string]func(n *model.Note){
+			"with-title": func(n *model.Note) { n.Title = strPtr("Groceries") },
+			"with-body":  func(n *model.Note) { n.Body = "see [[Groceries]]" },
+			"with-items": func(n *model.Note) { n.Items = mockNote().Items },
+			"drawing":    func(n *model.Note) { n.Type, n.Drawing = "drawing", mockDrawingNote().Drawing },
+		} {
+			note := mockEncryptedNote()
+			change(&note)
+
+			mockNoteRepo := new(mocks.NoteRepository)
+			u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+			err := u.Create(context.TODO(), &note)
+
+			code, _ := response.RespondError(err)
+			assert.Equal(t, http.StatusBadRequest, code, name)
+			mockNoteRepo.AssertNotCalled(t, "CreateNote", mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func TestUpdateEncrypted(t *testing.T) {
+	t.Run("new-ciphertext", func(t *testing.T) {
+		note := mockEncryptedNote()
+		note.Encrypted.Ciphertext = "bmV3IHNlY3JldA=="
+
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockNoteRepo.On("GetNote", mock.Anything, int32(3), int32(1)).Return(mockEncryptedNote(), nil).Once()
+		mockNoteRepo.On("UpdateNote", mock.Anything, &note, mock.AnythingOfType("*model.NoteRevision"), 10).
+			Return(nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+		assert.NoError(t, u.Update(context.TODO(), &note))
+		mockNoteRepo.AssertExpectations(t)
+	})
+
+	t.Run("switch", func(t *testing.T) {
+		for name, c := range map[string]struct{ current, note model.Note }{
+			"encrypt": {current: mockNote(), note: func() model.Note {
+				n := mockEncryptedNote()
+				n.ID = 1
+
+				return n
+			}()},
+			"decrypt": {current: mockEncryptedNote(), note: func() model.Note {
+				n := mockNote()
+				n.ID = 3
+
+				return n
+			}()},
+		} {
+			mockNoteRepo := new(mocks.NoteRepository)
+			mockNoteRepo.On("GetNote", mock.Anything, c.note.ID, int32(1)).Return(c.current, nil).Once()
+
+			u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+			err := u.Update(context.TODO(), &c.note)
+
+			code, _ := response.RespondError(err)
+			assert.Equal(t, http.StatusConflict, code, name)
+			mockNoteRepo.AssertNotCalled(t, "UpdateNote", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"librenote/app/markdown"
+	"librenote/app/model"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// noteURL is a link of a body to another note, which references it like [[#id]]
+var noteURL = regexp.MustCompile(`^/notes/(\d{1,10})$`)
+
+func (u *noteUsecase) Backlinks(c context.Context, id, userID int32) ([]model.NoteSummary, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	note, err := u.getNote(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var title string
+	if note.Title != nil {
+		title = normalizeTitle(*note.Title)
+	}
+
+	return u.repo.FetchBacklinks(ctx, id, userID, title)
+}
+
+// Graph links the notes by their references, a reference by title links to every note of that title
+func (u *noteUsecase) Graph(c context.Context, userID int32) (*model.NoteGraph, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	notes, refs, err := u.repo.FetchGraph(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int32]bool, len(notes))
+	titles := make(map[string][]int32)
+
+	for _, note := range notes {
+		ids[note.ID] = true
+
+		if note.Title != nil {
+			title := normalizeTitle(*note.Title)
+			titles[title] = append(titles[title], note.ID)
+		}
+	}
+
+	graph := &model.NoteGraph{Nodes: notes, Edges: make([]model.GraphEdge, 0)}
+	seen := make(map[model.GraphEdge]bool)
+
+	for _, ref := range refs {
+		var targets []int32
+
+		switch {
+		case ref.TargetID != nil && ids[*ref.TargetID]:
+			targets = []int32{*ref.TargetID}
+		case ref.Title != nil:
+			targets = titles[*ref.Title]
+		}
+
+		for _, target := range targets {
+			edge := model.GraphEdge{From: ref.NoteID, To: target}
+			if target != ref.NoteID && !seen[edge] {
+				seen[edge] = true
+				graph.Edges = append(graph.Edges, edge)
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+// noteReferences lists the references of the body to other notes, [[title]], [[#id]] and links to /notes/:id
+func noteReferences(m *model.Note) []model.NoteReference {
+	refs := make([]model.NoteReference, 0)
+	ids := make(map[int32]bool)
+
+	var ownTitle string
+	if m.Title != nil {
+		ownTitle = normalizeTitle(*m.Title)
+	}
+
+	addID := func(id int32) {
+		if id != m.ID && !ids[id] {
+			ids[id] = true
+			refs = append(refs, model.NoteReference{NoteID: m.ID, TargetID: &id})
+		}
+	}
+
+	for _, ref := range markdown.References(m.Body) {
+		if ref.NoteID != 0 {
+			addID(ref.NoteID)
+
+			continue
+		}
+
+		if title := normalizeTitle(ref.Title); title != ownTitle {
+			refs = append(refs, model.NoteReference{NoteID: m.ID, Title: &title})
+		}
+	}
+
+	for _, link := range m.Links {
+		if match := noteURL.FindStringSubmatch(link); match != nil {
+			if id, err := strconv.ParseInt(match[1], 10, 32); err == nil && id > 0 {
+				addID(int32(id))
+			}
+		}
+	}
+
+	return refs
+}
+
+// normalizeTitle makes the titles compare case insensitively
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
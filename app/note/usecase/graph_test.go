@@ -0,0 +1,86 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/note/usecase"
+	"librenote/app/response"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestUpdateStoresReferences(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+	current := mockNote()
+	note := mockNote()
+	note.Body = "Buy for [[The Plan]], [[the plan ]] and [[groceries]], see [[#7]] and [#1](/notes/1) " +
+		"[#7](/notes/7) [#9](/notes/9)"
+
+	mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(current, nil).Once()
+	mockNoteRepo.On("UpdateNote", mock.Anything, mock.MatchedBy(func(n *model.Note) bool {
+		return assert.ObjectsAreEqual([]model.NoteReference{
+			{NoteID: 1, Title: strPtr("the plan")},
+			{NoteID: 1, TargetID: int32Ptr(7)},
+			{NoteID: 1, TargetID: int32Ptr(9)},
+		}, n.References)
+	}), mock.AnythingOfType("*model.NoteRevision"), 10).Return(nil).Once()
+
+	u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+	assert.NoError(t, u.Update(context.TODO(), &note))
+	mockNoteRepo.AssertExpectations(t)
+}
+
+func TestBacklinks(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+	backlinks := []model.NoteSummary{{ID: 2, Title: strPtr("Plan"), Type: "note"}}
+
+	mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(mockNote(), nil).Once()
+	mockNoteRepo.On("FetchBacklinks", mock.Anything, int32(1), int32(1), "groceries").Return(backlinks, nil).Once()
+	mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(2)).Return(model.Note{}, sql.ErrNoRows).Once()
+
+	u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+
+	res, err := u.Backlinks(context.TODO(), 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, backlinks, res)
+
+	_, err = u.Backlinks(context.TODO(), 1, 2)
+	assert.ErrorIs(t, err, response.ErrNotFound)
+	mockNoteRepo.AssertExpectations(t)
+}
+
+func TestGraph(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+	notes := []model.NoteSummary{
+		{ID: 1, Title: strPtr("Groceries")},
+		{ID: 2, Title: strPtr("Plan")},
+		{ID: 3, Title: strPtr("plan")},
+		{ID: 4},
+	}
+	refs := []model.NoteReference{
+		{NoteID: 1, Title: strPtr("plan")},
+		{NoteID: 4, TargetID: int32Ptr(1)},
+		{NoteID: 4, Title: strPtr("groceries")},
+		{NoteID: 4, TargetID: int32Ptr(8)},
+		{NoteID: 2, Title: strPtr("missing")},
+		{NoteID: 3, Title: strPtr("plan")},
+	}
+
+	mockNoteRepo.On("FetchGraph", mock.Anything, int32(1)).Return(notes, refs, nil).Once()
+
+	u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+	graph, err := u.Graph(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, notes, graph.Nodes)
+	assert.Equal(t, []model.GraphEdge{{From: 1, To: 2}, {From: 1, To: 3}, {From: 4, To: 1}, {From: 3, To: 2}},
+		graph.Edges)
+}
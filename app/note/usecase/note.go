@@ -0,0 +1,411 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"librenote/app/markdown"
+	"librenote/app/model"
+	"librenote/app/response"
+	"net/http"
+	"reflect"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLinkLength is the size of notes_links.url
+const maxLinkLength = 2048
+
+type noteUsecase struct {
+	repo            model.NoteRepository
+	userRepo        model.UserRepository
+	events          model.EventPublisher
+	contextTimeout  time.Duration
+	maxRevisions    int
+	maxDrawingBytes int64
+}
+
+// NewNoteUsecase maxDrawingBytes limits the encoded strokes of a drawing, so a drawing can always be sent back
+func NewNoteUsecase(repo model.NoteRepository, userRepo model.UserRepository, events model.EventPublisher,
+	timeout time.Duration, maxRevisions int, maxDrawingBytes int64) model.NoteUsecase {
+	return &noteUsecase{
+		repo:            repo,
+		userRepo:        userRepo,
+		events:          events,
+		contextTimeout:  timeout,
+		maxRevisions:    maxRevisions,
+		maxDrawingBytes: maxDrawingBytes,
+	}
+}
+
+func (u *noteUsecase) Create(c context.Context, m *model.Note) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err := checkEncrypted(m); err != nil {
+		return err
+	}
+
+	if err := u.checkDrawing(m); err != nil {
+		return err
+	}
+
+	if err := u.checkColor(ctx, m); err != nil {
+		return err
+	}
+
+	revision, err := newRevision(m)
+	if err != nil {
+		return err
+	}
+
+	if m.Position, err = u.firstPosition(ctx, m.UserID); err != nil {
+		return err
+	}
+
+	positionItems(m)
+	m.Links = bodyLinks(m.Body)
+	m.References = noteReferences(m)
+
+	if err = u.repo.CreateNote(ctx, m, revision); err != nil {
+		return err
+	}
+
+	u.events.Publish(ctx, m.UserID, model.EventNoteCreated, m)
+
+	return nil
+}
+
+func (u *noteUsecase) Fetch(c context.Context, filter model.NoteFilter, page, pageSize int) (
+	[]model.Note, int, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if page < 1 || pageSize < 1 {
+		return nil, 0, response.ErrInvalidPage
+	}
+
+	return u.repo.FetchNotes(ctx, filter, pageSize, (page-1)*pageSize)
+}
+
+func (u *noteUsecase) Get(c context.Context, id, userID int32) (*model.Note, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.getNote(ctx, id, userID)
+}
+
+func (u *noteUsecase) getNote(ctx context.Context, id, userID int32) (*model.Note, error) {
+	note, err := u.repo.GetNote(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, response.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &note, nil
+}
+
+func (u *noteUsecase) Update(c context.Context, m *model.Note) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	current, err := u.getNote(ctx, m.ID, m.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err = checkEncryptionChange(current, m); err != nil {
+		return err
+	}
+
+	if err = checkEncrypted(m); err != nil {
+		return err
+	}
+
+	if err = u.checkDrawing(m); err != nil {
+		return err
+	}
+
+	// the position only changes by moving the note
+	m.Position = current.Position
+
+	// a note keeps a custom color the user has deleted since
+	if m.Color != current.Color {
+		if err = u.checkColor(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	return u.update(ctx, current, m)
+}
+
+// checkColor accepts a custom color of the user besides the palette
+func (u *noteUsecase) checkColor(ctx context.Context, m *model.Note) error {
+	if m.Color == "" || model.IsColor(m.Color) {
+		return nil
+	}
+
+	colors, err := u.userRepo.FetchColors(ctx, m.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, color := range colors {
+		if color.Name == m.Color {
+			return nil
+		}
+	}
+
+	return response.WrapError(fmt.Errorf("unknown color %s", m.Color), http.StatusBadRequest)
+}
+
+// update stores the note along with a new revision, unless nothing has changed
+func (u *noteUsecase) update(ctx context.Context, current, m *model.Note) error {
+	if current.IsTrashed == m.IsTrashed && reflect.DeepEqual(snapshotOf(current), snapshotOf(m)) {
+		return nil
+	}
+
+	revision, err := newRevision(m)
+	if err != nil {
+		return err
+	}
+
+	keepItems(current, m)
+	positionItems(m)
+	m.Links = bodyLinks(m.Body)
+	m.References = noteReferences(m)
+
+	if err = u.repo.UpdateNote(ctx, m, revision, u.maxRevisions); err != nil {
+		return err
+	}
+
+	u.events.Publish(ctx, m.UserID, model.EventNoteUpdated, m)
+
+	return nil
+}
+
+// keepItems matches the items to the current ones by id, they keep their creation time. The items with an id
+// unknown to the note are new
+func keepItems(current, m *model.Note) {
+	createdAt := make(map[int32]string, len(current.Items))
+	for _, item := range current.Items {
+		createdAt[item.ID] = item.CreatedAt
+	}
+
+	for i := range m.Items {
+		item := &m.Items[i]
+
+		created, ok := createdAt[item.ID]
+		if !ok {
+			item.ID = 0
+
+			continue
+		}
+
+		// an item listed twice keeps its id once
+		delete(createdAt, item.ID)
+		item.CreatedAt = created
+	}
+}
+
+func (u *noteUsecase) Delete(c context.Context, id, userID int32) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	err := u.repo.DeleteNote(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return response.ErrNotFound
+		}
+
+		return err
+	}
+
+	u.events.Publish(ctx, userID, model.EventNoteDeleted, map[string]int32{"id": id})
+
+	return nil
+}
+
+func (u *noteUsecase) FetchRevisions(c context.Context, noteID, userID int32) ([]model.NoteRevision, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if _, err := u.getNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FetchRevisions(ctx, noteID)
+}
+
+func (u *noteUsecase) GetRevision(c context.Context, noteID, userID, revisionID int32) (*model.NoteRevision, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if _, err := u.getNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+
+	return u.getRevision(ctx, noteID, revisionID)
+}
+
+func (u *noteUsecase) getRevision(ctx context.Context, noteID, revisionID int32) (*model.NoteRevision, error) {
+	revision, err := u.repo.GetRevision(ctx, noteID, revisionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, response.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	var content model.NoteSnapshot
+	if err = json.Unmarshal([]byte(revision.Snapshot), &content); err != nil {
+		return nil, err
+	}
+
+	revision.Content = &content
+
+	return &revision, nil
+}
+
+func (u *noteUsecase) DiffRevisions(c context.Context, noteID, userID, from, to int32) (*model.RevisionDiff, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if _, err := u.getNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := u.getRevision(ctx, noteID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := u.getRevision(ctx, noteID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.RevisionDiff{
+		From:   from,
+		To:     to,
+		Fields: diffFields(fromRevision.Content, toRevision.Content),
+		Items:  diffItems(fromRevision.Content.Items, toRevision.Content.Items),
+	}, nil
+}
+
+func (u *noteUsecase) RestoreRevision(c context.Context, noteID, userID, revisionID int32) (*model.Note, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	current, err := u.getNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := u.getRevision(ctx, noteID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	restored := *current
+	restored.Title = revision.Content.Title
+	restored.Body = revision.Content.Body
+	restored.Color = revision.Content.Color
+	restored.Type = revision.Content.Type
+	restored.IsPinned = revision.Content.IsPinned
+	restored.IsArchived = revision.Content.IsArchived
+	restored.Drawing = revision.Content.Drawing
+	restored.Encrypted = revision.Content.Encrypted
+	restored.UpdatedAt = nowTime
+	restored.Items = make([]model.NotesItem, 0, len(revision.Content.Items))
+
+	// the revisions don't store the item ids, the items of the same text keep theirs
+	ids := make(map[string][]int32, len(current.Items))
+
+	for _, item := range current.Items {
+		if item.Text != nil {
+			ids[*item.Text] = append(ids[*item.Text], item.ID)
+		}
+	}
+
+	for _, item := range revision.Content.Items {
+		text := item.Text
+
+		var id int32
+		if same := ids[text]; len(same) > 0 {
+			id, ids[text] = same[0], same[1:]
+		}
+
+		restored.Items = append(restored.Items, model.NotesItem{
+			ID:        id,
+			Text:      &text,
+			IsChecked: item.IsChecked,
+			CreatedAt: nowTime,
+		})
+	}
+
+	if err = u.update(ctx, current, &restored); err != nil {
+		return nil, err
+	}
+
+	return &restored, nil
+}
+
+// snapshotOf copy the user editable state of a note
+func snapshotOf(m *model.Note) model.NoteSnapshot {
+	snapshot := model.NoteSnapshot{
+		Title:      m.Title,
+		Body:       m.Body,
+		Color:      m.Color,
+		Type:       m.Type,
+		IsPinned:   m.IsPinned,
+		IsArchived: m.IsArchived,
+		Items:      make([]model.SnapshotItem, 0, len(m.Items)),
+		Drawing:    m.Drawing,
+		Encrypted:  m.Encrypted,
+	}
+
+	for _, item := range m.Items {
+		var text string
+		if item.Text != nil {
+			text = *item.Text
+		}
+
+		snapshot.Items = append(snapshot.Items, model.SnapshotItem{Text: text, IsChecked: item.IsChecked})
+	}
+
+	return snapshot
+}
+
+func newRevision(m *model.Note) (*model.NoteRevision, error) {
+	snapshot, err := json.Marshal(snapshotOf(m))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.NoteRevision{
+		NoteID:    m.ID,
+		Snapshot:  string(snapshot),
+		CreatedAt: m.UpdatedAt,
+	}, nil
+}
+
+// bodyLinks lists the links of the body to store, the ones too long for the links table are left out
+func bodyLinks(body string) []string {
+	links := make([]string, 0)
+
+	for _, link := range markdown.Links(body) {
+		if utf8.RuneCountInString(link) <= maxLinkLength {
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
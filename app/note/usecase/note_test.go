@@ -0,0 +1,307 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/note/usecase"
+	"librenote/app/response"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// noEvents accepts any published event
+func noEvents() *mocks.EventPublisher {
+	events := new(mocks.EventPublisher)
+	events.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	return events
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func mockNote() model.Note {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	return model.Note{
+		ID:        1,
+		UserID:    1,
+		Title:     strPtr("Groceries"),
+		Color:     "red",
+		Type:      "list",
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+		Items: []model.NotesItem{
+			{ID: 1, NoteID: 1, Text: strPtr("milk"), CreatedAt: nowTime},
+			{ID: 2, NoteID: 1, Text: strPtr("eggs"), IsChecked: 1, CreatedAt: nowTime},
+		},
+	}
+}
+
+func mockRevision(t *testing.T, id int32, snapshot model.NoteSnapshot) model.NoteRevision {
+	j, err := json.Marshal(snapshot)
+	assert.NoError(t, err)
+
+	return model.NoteRevision{ID: id, NoteID: 1, Snapshot: string(j)}
+}
+
+func TestCreate(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+	note := mockNote()
+
+	// the new note comes first
+	mockNoteRepo.On("NextPosition", mock.Anything, int32(1), int32(0), "").Return("a0", nil).Once()
+	mockNoteRepo.On("CreateNote", mock.Anything, mock.AnythingOfType("*model.Note"),
+		mock.MatchedBy(func(r *model.NoteRevision) bool {
+			var s model.NoteSnapshot
+			return json.Unmarshal([]byte(r.Snapshot), &s) == nil && len(s.Items) == 2 && *s.Title == "Groceries"
+		})).Return(nil).Once()
+
+	events := new(mocks.EventPublisher)
+	events.On("Publish", mock.Anything, int32(1), model.EventNoteCreated, &note).Once()
+
+	u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), events, time.Second*2, 10, 0)
+	assert.NoError(t, u.Create(context.TODO(), &note))
+	assert.Equal(t, "Zz", note.Position)
+	assert.Equal(t, "a0", note.Items[0].Position)
+	assert.Equal(t, "a1", note.Items[1].Position)
+	mockNoteRepo.AssertExpectations(t)
+	events.AssertExpectations(t)
+}
+
+func TestCreateStoresLinks(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+	note := mockNote()
+	note.Body = "See [docs](https://docs.example) and [bad](javascript:alert(1)), https://docs.example again"
+
+	mockNoteRepo.On("NextPosition", mock.Anything, int32(1), int32(0), "").Return("", nil).Once()
+	mockNoteRepo.On("CreateNote", mock.Anything, mock.MatchedBy(func(n *model.Note) bool {
+		return len(n.Links) == 1 && n.Links[0] == "https://docs.example"
+	}), mock.AnythingOfType("*model.NoteRevision")).Return(nil).Once()
+
+	u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+	assert.NoError(t, u.Create(context.TODO(), &note))
+	mockNoteRepo.AssertExpectations(t)
+}
+
+func TestFetch(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+	filter := model.NoteFilter{UserID: 1}
+
+	t.Run("success", func(t *testing.T) {
+		mockNoteRepo.On("FetchNotes", mock.Anything, filter, 10, 10).
+			Return([]model.Note{mockNote()}, 11, nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+		notes, count, err := u.Fetch(context.TODO(), filter, 2, 10)
+
+		assert.NoError(t, err)
+		assert.Len(t, notes, 1)
+		assert.Equal(t, 11, count)
+		mockNoteRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid-page", func(t *testing.T) {
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+		_, _, err := u.Fetch(context.TODO(), filter, 0, 10)
+
+		assert.ErrorIs(t, err, response.ErrInvalidPage)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+
+	t.Run("changed", func(t *testing.T) {
+		note := mockNote()
+		note.Items[0].IsChecked = 1
+
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(mockNote(), nil).Once()
+		mockNoteRepo.On("UpdateNote", mock.Anything, &note, mock.AnythingOfType("*model.NoteRevision"), 10).
+			Return(nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+		assert.NoError(t, u.Update(context.TODO(), &note))
+		mockNoteRepo.AssertExpectations(t)
+	})
+
+	t.Run("items-matched-by-id", func(t *testing.T) {
+		note := mockNote()
+		note.Items = []model.NotesItem{
+			{ID: 2, Text: strPtr("eggs"), IsChecked: 1, CreatedAt: "2030-01-01 00:00:00"},
+			{ID: 2, Text: strPtr("eggs again")},
+			{ID: 9, Text: strPtr("of another note")},
+			{Text: strPtr("bread")},
+		}
+
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(mockNote(), nil).Once()
+		mockNoteRepo.On("UpdateNote", mock.Anything, &note, mock.AnythingOfType("*model.NoteRevision"), 10).
+			Return(nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+		assert.NoError(t, u.Update(context.TODO(), &note))
+		assert.Equal(t, int32(2), note.Items[0].ID)
+		assert.Equal(t, mockNote().Items[1].CreatedAt, note.Items[0].CreatedAt)
+		assert.Equal(t, int32(0), note.Items[1].ID)
+		assert.Equal(t, int32(0), note.Items[2].ID)
+		assert.Equal(t, int32(0), note.Items[3].ID)
+		mockNoteRepo.AssertExpectations(t)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		note := mockNote()
+		mockNoteRepo := new(mocks.NoteRepository)
+
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(mockNote(), nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+		assert.NoError(t, u.Update(context.TODO(), &note))
+		mockNoteRepo.AssertNotCalled(t, "UpdateNote", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		note := mockNote()
+
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(model.Note{}, sql.ErrNoRows).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+		assert.ErrorIs(t, u.Update(context.TODO(), &note), response.ErrNotFound)
+	})
+}
+
+func TestCustomColors(t *testing.T) {
+	colors := []model.CustomColor{{ID: 1, UserID: 1, Name: "sea green", Hex: "#2e8b57"}}
+
+	t.Run("create", func(t *testing.T) {
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockNoteRepo.On("NextPosition", mock.Anything, int32(1), int32(0), "").Return("", nil).Once()
+		mockNoteRepo.On("CreateNote", mock.Anything, mock.AnythingOfType("*model.Note"),
+			mock.AnythingOfType("*model.NoteRevision")).Return(nil).Once()
+
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("FetchColors", mock.Anything, int32(1)).Return(colors, nil).Twice()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, mockUserRepo, noEvents(), time.Second*2, 10, 0)
+
+		note := mockNote()
+		note.Color = "sea green"
+		assert.NoError(t, u.Create(context.TODO(), &note))
+
+		note.Color = "mint"
+		code, _ := response.RespondError(u.Create(context.TODO(), &note))
+		assert.Equal(t, http.StatusBadRequest, code)
+		mockNoteRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("update-keeps-deleted-color", func(t *testing.T) {
+		current := mockNote()
+		current.Color = "mint"
+		note := current
+		note.Title = strPtr("Shopping")
+
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(current, nil).Once()
+		mockNoteRepo.On("UpdateNote", mock.Anything, &note, mock.AnythingOfType("*model.NoteRevision"), 10).
+			Return(nil).Once()
+
+		mockUserRepo := new(mocks.UserRepository)
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, mockUserRepo, noEvents(), time.Second*2, 10, 0)
+		assert.NoError(t, u.Update(context.TODO(), &note))
+		mockNoteRepo.AssertExpectations(t)
+		mockUserRepo.AssertNotCalled(t, "FetchColors", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+	mockNoteRepo.On("DeleteNote", mock.Anything, int32(2), int32(1)).Return(sql.ErrNoRows).Once()
+
+	u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+	assert.ErrorIs(t, u.Delete(context.TODO(), 2, 1), response.ErrNotFound)
+	mockNoteRepo.AssertExpectations(t)
+}
+
+func TestGetRevision(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+	snapshot := model.NoteSnapshot{Title: strPtr("Groceries"), Type: "list", Items: []model.SnapshotItem{{Text: "milk"}}}
+
+	mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(mockNote(), nil).Once()
+	mockNoteRepo.On("GetRevision", mock.Anything, int32(1), int32(3)).Return(mockRevision(t, 3, snapshot), nil).Once()
+
+	u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+	revision, err := u.GetRevision(context.TODO(), 1, 1, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, *revision.Content)
+	mockNoteRepo.AssertExpectations(t)
+}
+
+func TestDiffRevisions(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+	from := model.NoteSnapshot{
+		Title: strPtr("Groceries"),
+		Color: "red",
+		Type:  "list",
+		Items: []model.SnapshotItem{{Text: "milk"}, {Text: "bread"}, {Text: "eggs"}},
+	}
+	to := model.NoteSnapshot{
+		Title:    strPtr("Shopping"),
+		Color:    "red",
+		Type:     "list",
+		IsPinned: 1,
+		Items:    []model.SnapshotItem{{Text: "milk", IsChecked: 1}, {Text: "eggs"}, {Text: "butter"}},
+	}
+
+	mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(mockNote(), nil).Once()
+	mockNoteRepo.On("GetRevision", mock.Anything, int32(1), int32(1)).Return(mockRevision(t, 1, from), nil).Once()
+	mockNoteRepo.On("GetRevision", mock.Anything, int32(1), int32(2)).Return(mockRevision(t, 2, to), nil).Once()
+
+	u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+	diff, err := u.DiffRevisions(context.TODO(), 1, 1, 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.FieldChange{
+		{Field: "title", From: from.Title, To: to.Title},
+		{Field: "is_pinned", From: int8(0), To: int8(1)},
+	}, diff.Fields)
+	assert.Equal(t, []model.ItemChange{
+		{Op: "checked", Text: "milk"},
+		{Op: "removed", Text: "bread"},
+		{Op: "added", Text: "butter"},
+	}, diff.Items)
+	mockNoteRepo.AssertExpectations(t)
+}
+
+func TestRestoreRevision(t *testing.T) {
+	mockNoteRepo := new(mocks.NoteRepository)
+	snapshot := model.NoteSnapshot{Title: strPtr("Old title"), Color: "blue", Type: "note",
+		Items: []model.SnapshotItem{{Text: "old text"}, {Text: "eggs"}}}
+
+	mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(mockNote(), nil).Once()
+	mockNoteRepo.On("GetRevision", mock.Anything, int32(1), int32(4)).Return(mockRevision(t, 4, snapshot), nil).Once()
+	mockNoteRepo.On("UpdateNote", mock.Anything, mock.AnythingOfType("*model.Note"),
+		mock.AnythingOfType("*model.NoteRevision"), 10).Return(nil).Once()
+
+	u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+	note, err := u.RestoreRevision(context.TODO(), 1, 1, 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Old title", *note.Title)
+	assert.Equal(t, "blue", note.Color)
+	assert.Len(t, note.Items, 2)
+	assert.Equal(t, "old text", *note.Items[0].Text)
+	assert.Equal(t, int32(0), note.Items[0].ID)
+	// the item left as it was keeps its id
+	assert.Equal(t, int32(2), note.Items[1].ID)
+	mockNoteRepo.AssertExpectations(t)
+}
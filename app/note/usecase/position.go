@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"librenote/app/model"
+	"librenote/app/position"
+	"librenote/app/response"
+	"net/http"
+)
+
+// maxPositionLength is the size of the position columns
+const maxPositionLength = 255
+
+var errNoRoom = response.WrapError(errors.New("no room left at the position, move a neighbour first"),
+	http.StatusConflict)
+
+// firstPosition returns a position before all the notes of the user, new notes come first
+func (u *noteUsecase) firstPosition(ctx context.Context, userID int32) (string, error) {
+	first, err := u.repo.NextPosition(ctx, userID, 0, "")
+	if err != nil {
+		return "", err
+	}
+
+	key, err := position.Between("", first)
+	if errors.Is(err, position.ErrNoRoom) || len(key) > maxPositionLength {
+		return "", errNoRoom
+	}
+
+	return key, err
+}
+
+// positionItems orders the items as they are listed
+func positionItems(m *model.Note) {
+	keys := position.Spread(len(m.Items))
+	for i := range m.Items {
+		m.Items[i].Position = keys[i]
+	}
+}
+
+// Move only changes the position of the note, a note can't be moved after a note of the other section
+func (u *noteUsecase) Move(c context.Context, id, userID int32, afterID *int32) (*model.Note, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	note, err := u.getNote(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var after string
+
+	if afterID != nil {
+		if *afterID == id {
+			return nil, response.WrapError(errors.New("a note can't be moved after itself"), http.StatusBadRequest)
+		}
+
+		previous, err := u.getNote(ctx, *afterID, userID)
+		if errors.Is(err, response.ErrNotFound) {
+			return nil, response.WrapError(errors.New("the note to move after is not found"), http.StatusBadRequest)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if previous.IsPinned != note.IsPinned {
+			return nil, response.WrapError(errors.New("a note can't be moved between pinned and unpinned notes"),
+				http.StatusConflict)
+		}
+
+		after = previous.Position
+	}
+
+	next, err := u.repo.NextPosition(ctx, userID, id, after)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := position.Between(after, next)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) > maxPositionLength {
+		return nil, errNoRoom
+	}
+
+	if err = u.repo.UpdatePosition(ctx, id, userID, key); err != nil {
+		return nil, err
+	}
+
+	note.Position = key
+	u.events.Publish(ctx, userID, model.EventNoteUpdated, note)
+
+	return note, nil
+}
+
+func (u *noteUsecase) MoveItem(c context.Context, noteID, userID, itemID int32, afterID *int32) (*model.Note, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	note, err := u.getNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the other items, in their order
+	moved := -1
+	others := make([]model.NotesItem, 0, len(note.Items))
+
+	for i, item := range note.Items {
+		if item.ID == itemID {
+			moved = i
+		} else {
+			others = append(others, item)
+		}
+	}
+
+	if moved < 0 {
+		return nil, response.ErrNotFound
+	}
+
+	// the item goes before others[next]
+	next := 0
+
+	if afterID != nil {
+		next = -1
+
+		for i, item := range others {
+			if item.ID == *afterID {
+				next = i + 1
+			}
+		}
+
+		if next < 0 {
+			return nil, response.WrapError(errors.New("the item to move after is not found"), http.StatusBadRequest)
+		}
+	}
+
+	var previousKey, nextKey string
+	if next > 0 {
+		previousKey = others[next-1].Position
+	}
+
+	if next < len(others) {
+		nextKey = others[next].Position
+	}
+
+	key, err := position.Between(previousKey, nextKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) > maxPositionLength {
+		return nil, errNoRoom
+	}
+
+	if err = u.repo.UpdateItemPosition(ctx, noteID, itemID, key); err != nil {
+		return nil, err
+	}
+
+	item := note.Items[moved]
+	item.Position = key
+	note.Items = append(others[:next], append([]model.NotesItem{item}, others[next:]...)...)
+
+	u.events.Publish(ctx, userID, model.EventNoteUpdated, note)
+
+	return note, nil
+}
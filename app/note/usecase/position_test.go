@@ -0,0 +1,142 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/note/usecase"
+	"librenote/app/response"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMove(t *testing.T) {
+	positioned := func(id int32, position string, pinned int8) model.Note {
+		note := mockNote()
+		note.ID, note.Position, note.IsPinned = id, position, pinned
+
+		return note
+	}
+
+	t.Run("after", func(t *testing.T) {
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(positioned(1, "Zz", 0), nil).Once()
+		mockNoteRepo.On("GetNote", mock.Anything, int32(2), int32(1)).Return(positioned(2, "a0", 0), nil).Once()
+		mockNoteRepo.On("NextPosition", mock.Anything, int32(1), int32(1), "a0").Return("a2", nil).Once()
+		mockNoteRepo.On("UpdatePosition", mock.Anything, int32(1), int32(1), "a1").Return(nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+
+		afterID := int32(2)
+		note, err := u.Move(context.TODO(), 1, 1, &afterID)
+		assert.NoError(t, err)
+		assert.Equal(t, "a1", note.Position)
+		mockNoteRepo.AssertExpectations(t)
+	})
+
+	t.Run("first", func(t *testing.T) {
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(positioned(1, "a2", 0), nil).Once()
+		mockNoteRepo.On("NextPosition", mock.Anything, int32(1), int32(1), "").Return("a0", nil).Once()
+		mockNoteRepo.On("UpdatePosition", mock.Anything, int32(1), int32(1), "Zz").Return(nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+
+		_, err := u.Move(context.TODO(), 1, 1, nil)
+		assert.NoError(t, err)
+		mockNoteRepo.AssertExpectations(t)
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		cases := map[string]struct {
+			afterID int32
+			after   model.Note
+			err     error
+			code    int
+		}{
+			"itself":        {afterID: 1, code: http.StatusBadRequest},
+			"missing-after": {afterID: 2, err: sql.ErrNoRows, code: http.StatusBadRequest},
+			"other-section": {afterID: 2, after: positioned(2, "a0", 1), code: http.StatusConflict},
+		}
+
+		for name, c := range cases {
+			mockNoteRepo := new(mocks.NoteRepository)
+			mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(positioned(1, "Zz", 0), nil).Once()
+			mockNoteRepo.On("GetNote", mock.Anything, int32(2), int32(1)).Return(c.after, c.err).Maybe()
+
+			u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+
+			_, err := u.Move(context.TODO(), 1, 1, &c.afterID)
+			code, _ := response.RespondError(err)
+			assert.Equal(t, c.code, code, name)
+			mockNoteRepo.AssertNotCalled(t, "UpdatePosition", mock.Anything, mock.Anything, mock.Anything,
+				mock.Anything)
+		}
+	})
+}
+
+func TestMoveItem(t *testing.T) {
+	listNote := func() model.Note {
+		note := mockNote()
+		note.Items = []model.NotesItem{
+			{ID: 1, NoteID: 1, Text: strPtr("milk"), Position: "a0"},
+			{ID: 2, NoteID: 1, Text: strPtr("eggs"), Position: "a1"},
+			{ID: 3, NoteID: 1, Text: strPtr("tea"), Position: "a2"},
+		}
+
+		return note
+	}
+
+	cases := map[string]struct {
+		itemID   int32
+		afterID  *int32
+		position string
+		order    []int32
+	}{
+		"first":  {itemID: 3, position: "Zz", order: []int32{3, 1, 2}},
+		"middle": {itemID: 3, afterID: int32Ptr(1), position: "a0V", order: []int32{1, 3, 2}},
+		"last":   {itemID: 1, afterID: int32Ptr(3), position: "a3", order: []int32{2, 3, 1}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockNoteRepo := new(mocks.NoteRepository)
+			mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(listNote(), nil).Once()
+			mockNoteRepo.On("UpdateItemPosition", mock.Anything, int32(1), c.itemID, c.position).Return(nil).Once()
+
+			u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+
+			note, err := u.MoveItem(context.TODO(), 1, 1, c.itemID, c.afterID)
+			assert.NoError(t, err)
+
+			order := make([]int32, 0, len(note.Items))
+			for _, item := range note.Items {
+				order = append(order, item.ID)
+			}
+
+			assert.Equal(t, c.order, order)
+			mockNoteRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("missing-item", func(t *testing.T) {
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(listNote(), nil).Once()
+
+		u := usecase.NewNoteUsecase(mockNoteRepo, new(mocks.UserRepository), noEvents(), time.Second*2, 10, 0)
+
+		_, err := u.MoveItem(context.TODO(), 1, 1, 9, nil)
+		assert.ErrorIs(t, err, response.ErrNotFound)
+
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(listNote(), nil).Once()
+
+		_, err = u.MoveItem(context.TODO(), 1, 1, 1, int32Ptr(9))
+		code, _ := response.RespondError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
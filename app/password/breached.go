@@ -0,0 +1,195 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1" // nolint:gosec
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	hashLength = 2 * sha1.Size
+	// prefixLength of the k-anonymity ranges of Pwned Passwords, the hashes are split by their first 5 hex digits
+	prefixLength = 5
+	// below this, the sorted file is scanned instead of halved
+	scanLength = 4096
+	// a HASH:COUNT line is about 50 bytes
+	maxLineLength = 128
+)
+
+// Breached tells if a password is one of the breached passwords, they are looked up in files and never loaded
+// in memory as there are hundreds of millions of them
+type Breached interface {
+	Contains(password string) (bool, error)
+}
+
+// OpenBreached opens the SHA-1 hashes of breached passwords of path, it's either
+//   - a file of HASH:COUNT lines ordered by hash, like the Pwned Passwords download ordered by hash.
+//     The hashes are binary searched in it
+//   - or a directory of the k-anonymity ranges, like the Pwned Passwords downloader saves them. A file per
+//     5 hex digits prefix, like 21BD1.txt, of SUFFIX:COUNT lines where the suffix is the 35 other digits
+//
+// The count is optional. The file stays open, the ranges are opened as they are looked up
+func OpenBreached(path string) (Breached, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return breachedRanges(path), nil
+	}
+
+	f, err := os.Open(path) // nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := bufio.NewReader(io.LimitReader(f, maxLineLength)).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		_ = f.Close()
+
+		return nil, err
+	}
+
+	if first != "" && !isHash(hashOf(first)) {
+		_ = f.Close()
+
+		return nil, fmt.Errorf("%s: not a file of SHA-1 hashes ordered by hash", path)
+	}
+
+	return &breachedFile{f: f, size: info.Size()}, nil
+}
+
+// sha1Hex returns the upper case hex SHA-1 of the password, as in Pwned Passwords
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password)) // nolint:gosec
+
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// hashOf returns the upper case hash part of a HASH:COUNT line
+func hashOf(line string) string {
+	if i := strings.IndexByte(line, ':'); i >= 0 {
+		line = line[:i]
+	}
+
+	return strings.ToUpper(strings.TrimSpace(line))
+}
+
+func isHash(s string) bool {
+	if len(s) != hashLength {
+		return false
+	}
+
+	_, err := hex.DecodeString(s)
+
+	return err == nil
+}
+
+// breachedFile is a file of HASH:COUNT lines ordered by hash
+type breachedFile struct {
+	f    *os.File
+	size int64
+}
+
+func (b *breachedFile) Contains(password string) (bool, error) {
+	hash := sha1Hex(password)
+
+	// the line of the hash, if any, starts within [lo, hi), lo is the start of a line
+	lo, hi := int64(0), b.size
+	for hi-lo > scanLength {
+		mid := lo + (hi-lo)/2
+
+		start, line, err := b.lineAfter(mid)
+		if err != nil {
+			return false, err
+		}
+
+		switch key := hashOf(line); {
+		case start >= hi:
+			hi = mid + 1
+		case key == hash:
+			return true, nil
+		case key < hash:
+			lo = start
+		default:
+			hi = start
+		}
+	}
+
+	// the lines are ordered, the scan stops at the first greater hash
+	scanner := bufio.NewScanner(io.NewSectionReader(b.f, lo, b.size-lo))
+	for scanner.Scan() {
+		if key := hashOf(scanner.Text()); key >= hash {
+			return key == hash, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// lineAfter returns the first line starting after offset, and its start. The start is the size of the file
+// when there is none
+func (b *breachedFile) lineAfter(offset int64) (int64, string, error) {
+	buf := make([]byte, 2*maxLineLength)
+
+	n, err := b.f.ReadAt(buf, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, "", err
+	}
+
+	buf = buf[:n]
+
+	i := bytes.IndexByte(buf, '\n')
+	if i < 0 && offset+int64(n) < b.size {
+		return 0, "", fmt.Errorf("%s: line longer than %d bytes", b.f.Name(), maxLineLength)
+	}
+
+	if i < 0 || offset+int64(i)+1 >= b.size {
+		return b.size, "", nil
+	}
+
+	line := buf[i+1:]
+	if j := bytes.IndexByte(line, '\n'); j >= 0 {
+		line = line[:j]
+	} else if offset+int64(n) < b.size {
+		return 0, "", fmt.Errorf("%s: line longer than %d bytes", b.f.Name(), maxLineLength)
+	}
+
+	return offset + int64(i) + 1, string(line), nil
+}
+
+// breachedRanges is a directory of a SUFFIX:COUNT file per hash prefix
+type breachedRanges string
+
+func (dir breachedRanges) Contains(password string) (bool, error) {
+	hash := sha1Hex(password)
+
+	f, err := os.Open(filepath.Join(string(dir), hash[:prefixLength]+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if hashOf(scanner.Text()) == hash[prefixLength:] {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
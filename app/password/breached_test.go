@@ -0,0 +1,98 @@
+package password_test
+
+import (
+	"crypto/sha1" // nolint:gosec
+	"fmt"
+	"librenote/app/password"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// breachedHashes returns the HASH:COUNT lines of the passwords and of n other ones, ordered by hash
+func breachedHashes(n int, passwords ...string) []string {
+	random := rand.New(rand.NewSource(1)) // nolint:gosec
+
+	lines := make([]string, 0, n+len(passwords))
+	for _, pass := range passwords {
+		lines = append(lines, fmt.Sprintf("%X:%d", sha1.Sum([]byte(pass)), random.Intn(1000000))) // nolint:gosec
+	}
+
+	for i := 0; i < n; i++ {
+		lines = append(lines, fmt.Sprintf("%X:%d", sha1.Sum([]byte(fmt.Sprint(i))), random.Intn(1000000))) // nolint:gosec
+	}
+
+	sort.Strings(lines)
+
+	return lines
+}
+
+func TestOpenBreachedFile(t *testing.T) {
+	passwords := []string{"password123", "letmein!", "qwerty"}
+	lines := breachedHashes(5000, passwords...)
+
+	// the passwords of the first and the last hashes are found too
+	byHash := map[string]string{}
+	for i := 0; i < 5000; i++ {
+		byHash[fmt.Sprintf("%X", sha1.Sum([]byte(fmt.Sprint(i))))] = fmt.Sprint(i) // nolint:gosec
+	}
+
+	for _, pass := range passwords {
+		byHash[fmt.Sprintf("%X", sha1.Sum([]byte(pass)))] = pass // nolint:gosec
+	}
+
+	edges := []string{byHash[lines[0][:40]], byHash[lines[len(lines)-1][:40]]}
+
+	for name, eol := range map[string]string{"lf": "\n", "crlf": "\r\n"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pwned-passwords-sha1-ordered-by-hash.txt")
+			assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, eol)+eol), 0o600))
+
+			breached, err := password.OpenBreached(path)
+			assert.NoError(t, err)
+
+			for _, pass := range append(append(edges, passwords...), "1", "4999") {
+				found, err := breached.Contains(pass)
+				assert.NoError(t, err)
+				assert.True(t, found, pass)
+			}
+
+			for _, pass := range []string{"correct horse battery", "5000", ""} {
+				found, err := breached.Contains(pass)
+				assert.NoError(t, err)
+				assert.False(t, found, pass)
+			}
+		})
+	}
+}
+
+func TestOpenBreachedRanges(t *testing.T) {
+	dir := t.TempDir()
+
+	ranges := map[string][]string{}
+	for _, line := range breachedHashes(200, "password123", "letmein!") {
+		ranges[line[:5]] = append(ranges[line[:5]], line[5:])
+	}
+
+	for prefix, suffixes := range ranges {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, prefix+".txt"), []byte(strings.Join(suffixes, "\r\n")), 0o600))
+	}
+
+	breached, err := password.OpenBreached(dir)
+	assert.NoError(t, err)
+
+	for _, pass := range []string{"password123", "letmein!", "42"} {
+		found, err := breached.Contains(pass)
+		assert.NoError(t, err)
+		assert.True(t, found, pass)
+	}
+
+	found, err := breached.Contains("correct horse battery")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,191 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"librenote/infrastructure/config"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// the hashing algorithms of the config
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// ErrUnknownHash is returned on verifying a hash of none of the supported algorithms
+var ErrUnknownHash = errors.New("unknown password hash")
+
+// Algorithm hashes the passwords into encoded hashes telling their algorithm and parameters
+type Algorithm interface {
+	// Hash returns the encoded hash of the password with a random salt
+	Hash(password string) (string, error)
+	// Identifies tells if the encoded hash is one of the algorithm
+	Identifies(encoded string) bool
+	// Verify tells if the password matches the encoded hash, and if the hash has other parameters than the
+	// algorithm's ones
+	Verify(password, encoded string) (ok, outdated bool, err error)
+}
+
+// Hasher hashes the passwords by the algorithm of the config and verifies the hashes of all the algorithms, so the
+// hashes of a previous config are upgraded on the next successful login
+type Hasher struct {
+	current    Algorithm
+	algorithms []Algorithm
+}
+
+// NewHasher returns the hasher of the config
+func NewHasher(cfg config.PasswordConfig) (*Hasher, error) {
+	a := &Argon2id{Memory: cfg.Argon2Memory, Iterations: cfg.Argon2Iterations, Parallelism: cfg.Argon2Parallelism}
+	b := &Bcrypt{Cost: cfg.BcryptCost}
+
+	switch cfg.Hasher {
+	case AlgorithmArgon2id:
+		return &Hasher{current: a, algorithms: []Algorithm{a, b}}, nil
+	case AlgorithmBcrypt:
+		return &Hasher{current: b, algorithms: []Algorithm{b, a}}, nil
+	}
+
+	return nil, fmt.Errorf("unknown password hasher %s", cfg.Hasher)
+}
+
+// Hash returns the encoded hash of the password by the current algorithm
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify tells if the password matches the encoded hash, and if the hash should be replaced by a new one as it's
+// of another algorithm or parameters than the current ones
+func (h *Hasher) Verify(password, encoded string) (ok, rehash bool, err error) {
+	for _, a := range h.algorithms {
+		if !a.Identifies(encoded) {
+			continue
+		}
+
+		var outdated bool
+
+		ok, outdated, err = a.Verify(password, encoded)
+
+		return ok, ok && (outdated || a != h.current), err
+	}
+
+	return false, false, ErrUnknownHash
+}
+
+// Argon2id hashes into the PHC string format, $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+// with the salt and the hash base64 encoded without padding
+type Argon2id struct {
+	// in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+const (
+	argon2idPrefix = "$argon2id$"
+	argon2SaltSize = 16
+	argon2KeySize  = 32
+)
+
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.Iterations, a.Memory, a.Parallelism, argon2KeySize)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, a.Memory, a.Iterations,
+		a.Parallelism, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (a *Argon2id) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+func (a *Argon2id) Verify(password, encoded string) (ok, outdated bool, err error) {
+	// "", "argon2id", "v=19", "m=65536,t=3,p=2", salt, hash
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, false, errors.New("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, false, fmt.Errorf("invalid argon2id hash version: %w", err)
+	}
+
+	if version != argon2.Version {
+		return false, false, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+
+	var (
+		memory, iterations uint32
+		parallelism        uint8
+	)
+
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism); err != nil {
+		return false, false, fmt.Errorf("invalid argon2id hash parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, fmt.Errorf("invalid argon2id hash salt: %w", err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false, fmt.Errorf("invalid argon2id hash: %w", err)
+	}
+
+	other := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return false, false, nil
+	}
+
+	outdated = memory != a.Memory || iterations != a.Iterations || parallelism != a.Parallelism ||
+		len(salt) != argon2SaltSize || len(key) != argon2KeySize
+
+	return true, outdated, nil
+}
+
+// Bcrypt hashes into the modular crypt format of bcrypt, $2a$<cost>$<salt and hash>
+type Bcrypt struct {
+	Cost int
+}
+
+func (b *Bcrypt) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+
+	return string(hash), err
+}
+
+func (b *Bcrypt) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+func (b *Bcrypt) Verify(password, encoded string) (ok, outdated bool, err error) {
+	err = bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, false, nil
+	}
+
+	if err != nil {
+		return false, false, err
+	}
+
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return false, false, err
+	}
+
+	return true, cost != b.Cost, nil
+}
@@ -0,0 +1,104 @@
+package password_test
+
+import (
+	"librenote/app/password"
+	"librenote/infrastructure/config"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newHasher(t *testing.T, cfg config.PasswordConfig) *password.Hasher {
+	hasher, err := password.NewHasher(cfg)
+	assert.NoError(t, err)
+
+	return hasher
+}
+
+func TestArgon2id(t *testing.T) {
+	cfg := config.PasswordConfig{
+		Hasher: password.AlgorithmArgon2id, Argon2Memory: 1024, Argon2Iterations: 1, Argon2Parallelism: 1,
+		BcryptCost: bcrypt.MinCost,
+	}
+	hasher := newHasher(t, cfg)
+
+	hash, err := hasher.Hash("super_password")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.Len(t, strings.Split(hash, "$"), 6)
+
+	other, err := hasher.Hash("super_password")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+
+	ok, rehash, err := hasher.Verify("super_password", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, rehash)
+
+	ok, rehash, err = hasher.Verify("superpassword", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.False(t, rehash)
+
+	// the hashes of other parameters are still verified
+	cfg.Argon2Iterations = 2
+	ok, rehash, err = newHasher(t, cfg).Verify("super_password", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, rehash)
+
+	_, _, err = hasher.Verify("super_password", "$argon2id$v=19$m=1024,t=1$c2FsdA$aGFzaA")
+	assert.Error(t, err)
+
+	_, _, err = hasher.Verify("super_password", "$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$aGFzaA")
+	assert.EqualError(t, err, "unsupported argon2id version 16")
+}
+
+func TestBcrypt(t *testing.T) {
+	cfg := config.PasswordConfig{
+		Hasher: password.AlgorithmBcrypt, Argon2Memory: 1024, Argon2Iterations: 1, Argon2Parallelism: 1,
+		BcryptCost: bcrypt.MinCost,
+	}
+	hasher := newHasher(t, cfg)
+
+	hash, err := hasher.Hash("super_password")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$2a$04$"))
+
+	ok, rehash, err := hasher.Verify("super_password", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, rehash)
+
+	ok, _, err = hasher.Verify("superpassword", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	cfg.BcryptCost = 5
+	_, rehash, err = newHasher(t, cfg).Verify("super_password", hash)
+	assert.NoError(t, err)
+	assert.True(t, rehash)
+
+	// the bcrypt hashes are upgraded to argon2id
+	cfg.Hasher = password.AlgorithmArgon2id
+	ok, rehash, err = newHasher(t, cfg).Verify("super_password", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, rehash)
+
+	// but not the wrong passwords
+	_, rehash, err = newHasher(t, cfg).Verify("superpassword", hash)
+	assert.NoError(t, err)
+	assert.False(t, rehash)
+}
+
+func TestNewHasher(t *testing.T) {
+	_, err := password.NewHasher(config.PasswordConfig{Hasher: "md5"})
+	assert.EqualError(t, err, "unknown password hasher md5")
+
+	_, _, err = newHasher(t, config.PasswordConfig{Hasher: password.AlgorithmBcrypt}).Verify("password", "5f4dcc3b")
+	assert.ErrorIs(t, err, password.ErrUnknownHash)
+}
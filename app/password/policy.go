@@ -0,0 +1,130 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"librenote/infrastructure/config"
+	"strings"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+// the parts of the email and the full name shorter than this are allowed in the passwords
+const minPersonalLength = 3
+
+// ErrBreached is returned for the passwords found in the breached passwords file
+var ErrBreached = errors.New("the password appeared in a data breach, choose another one")
+
+// ErrPersonal is returned for the passwords containing the email or the full name
+var ErrPersonal = errors.New("the password must not contain your email or name")
+
+// Policy checks the new passwords against the password config
+type Policy struct {
+	cfg      config.PasswordConfig
+	breached Breached
+}
+
+// NewPolicy opens the breached passwords of the config, when there are some
+func NewPolicy(cfg config.PasswordConfig) (*Policy, error) {
+	p := &Policy{cfg: cfg}
+
+	if cfg.BreachedFile == "" {
+		return p, nil
+	}
+
+	breached, err := OpenBreached(cfg.BreachedFile)
+	if err != nil {
+		return nil, err
+	}
+
+	p.breached = breached
+
+	return p, nil
+}
+
+// Check returns why the password of the user of email and fullName is refused, nil when it's accepted
+func (p *Policy) Check(password, email, fullName string) error {
+	var missing []string
+
+	if len([]rune(password)) < p.cfg.MinLength {
+		missing = append(missing, fmt.Sprintf("at least %d characters", p.cfg.MinLength))
+	}
+
+	var upper, lower, digit, symbol bool
+
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if p.cfg.RequireUpper && !upper {
+		missing = append(missing, "an uppercase letter")
+	}
+
+	if p.cfg.RequireLower && !lower {
+		missing = append(missing, "a lowercase letter")
+	}
+
+	if p.cfg.RequireDigit && !digit {
+		missing = append(missing, "a digit")
+	}
+
+	if p.cfg.RequireSymbol && !symbol {
+		missing = append(missing, "a symbol")
+	}
+
+	if len(missing) > 0 {
+		return errors.New("the password must have " + strings.Join(missing, ", "))
+	}
+
+	if !p.cfg.AllowPersonal && isPersonal(password, email, fullName) {
+		return ErrPersonal
+	}
+
+	if p.breached == nil {
+		return nil
+	}
+
+	// the password meets the policy otherwise, it's accepted when the breached passwords can't be read
+	breached, err := p.breached.Contains(password)
+	if err != nil {
+		logrus.Errorf("failed to look up the breached passwords: %s", err)
+	}
+
+	if breached {
+		return ErrBreached
+	}
+
+	return nil
+}
+
+// isPersonal tells if the password contains the email, its name part or a word of the full name
+func isPersonal(password, email, fullName string) bool {
+	password = strings.ToLower(password)
+
+	parts := strings.Fields(strings.ToLower(fullName))
+	if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+		parts = append(parts, email)
+
+		if i := strings.LastIndexByte(email, '@'); i > 0 {
+			parts = append(parts, email[:i])
+		}
+	}
+
+	for _, part := range parts {
+		if len([]rune(part)) >= minPersonalLength && strings.Contains(password, part) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package password_test
+
+import (
+	"crypto/sha1" // nolint:gosec
+	"fmt"
+	"librenote/app/password"
+	"librenote/infrastructure/config"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	policy, err := password.NewPolicy(config.PasswordConfig{
+		MinLength: 10, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true,
+	})
+	assert.NoError(t, err)
+
+	err = policy.Check("short", "mrtest@example.com", "Mr. Test")
+	assert.EqualError(t, err,
+		"the password must have at least 10 characters, an uppercase letter, a digit, a symbol")
+
+	// the characters are counted, not the bytes
+	err = policy.Check("Pässwörd1!", "mrtest@example.com", "Mr. Test")
+	assert.NoError(t, err)
+
+	for _, pass := range []string{"Correct-Horse1-MRTEST", "Mrtest@Example.com1", "Staple-Test-42"} {
+		err = policy.Check(pass, "mrtest@example.com", "Mr. Test")
+		assert.ErrorIs(t, err, password.ErrPersonal, pass)
+	}
+
+	// the name parts too short to matter
+	err = policy.Check("Correct-Horse-Mr-42", "mr@example.com", "Mr. Te")
+	assert.NoError(t, err)
+
+	policy, err = password.NewPolicy(config.PasswordConfig{MinLength: 8, AllowPersonal: true})
+	assert.NoError(t, err)
+
+	err = policy.Check("mrtest@example.com", "mrtest@example.com", "Mr. Test")
+	assert.NoError(t, err)
+}
+
+func TestBreached(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	hashes := []string{
+		fmt.Sprintf("%X:3861493", sha1.Sum([]byte("password123"))), // nolint:gosec
+		fmt.Sprintf("%X:12", sha1.Sum([]byte("letmein!"))),         // nolint:gosec
+	}
+	sort.Strings(hashes)
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Join(hashes, "\n")), 0o600))
+
+	policy, err := password.NewPolicy(config.PasswordConfig{MinLength: 8, BreachedFile: path})
+	assert.NoError(t, err)
+
+	for _, pass := range []string{"password123", "letmein!"} {
+		assert.ErrorIs(t, policy.Check(pass, "mrtest@example.com", "Mr. Test"), password.ErrBreached)
+	}
+
+	assert.NoError(t, policy.Check("correct horse battery", "mrtest@example.com", "Mr. Test"))
+
+	assert.NoError(t, os.WriteFile(path, []byte("password123:12\n"), 0o600))
+
+	_, err = password.NewPolicy(config.PasswordConfig{BreachedFile: path})
+	assert.EqualError(t, err, path+": not a file of SHA-1 hashes ordered by hash")
+
+	_, err = password.NewPolicy(config.PasswordConfig{BreachedFile: filepath.Join(t.TempDir(), "missing.txt")})
+	assert.Error(t, err)
+}
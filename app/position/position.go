@@ -0,0 +1,262 @@
+// Package position makes sortable keys for ordering rows by hand. There's always a key between two others,
+// so moving a row changes the key of that row only.
+//
+// A key is an integer followed by a fraction, both in base 62. The first character of the integer tells its
+// length: "a" to "z" start the positive integers of 1 to 26 digits, "Z" to "A" the negative ones, so "a0" is
+// followed by "a1" and preceded by "Zz". Adding a row before the first or after the last one steps the integer,
+// the keys then grow by a character every 62^n rows instead of every few rows. The fraction only grows when
+// rows are moved between the same two rows over and over.
+package position
+
+import (
+	"errors"
+	"strings"
+)
+
+// digits of the keys, in their byte order as the position columns are compared as bytes
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// zero is the key of the first row of an empty list
+const zero = "a0"
+
+var (
+	ErrInvalidKey = errors.New("invalid position key")
+	ErrOrder      = errors.New("position keys out of order")
+	ErrNoRoom     = errors.New("no position key left before the first one")
+)
+
+// smallest integer, there's no key before it without a fraction so it's kept out
+var smallest = "A" + strings.Repeat(digits[:1], 26)
+
+// Valid tells whether the key is an integer followed by a fraction that doesn't end with a zero, like "a5" or
+// "b1Ci" but not "a", "a50" or "a5V0"
+func Valid(key string) bool {
+	integer, ok := integerPart(key)
+	if !ok || integer == smallest {
+		return false
+	}
+
+	fraction := key[len(integer):]
+
+	return validDigits(key[1:]) && (fraction == "" || fraction[len(fraction)-1] != digits[0])
+}
+
+// integerLength returns the length of the integer starting with head, along with its head
+func integerLength(head byte) (int, bool) {
+	switch {
+	case head >= 'a' && head <= 'z':
+		return int(head-'a') + 2, true
+	case head >= 'A' && head <= 'Z':
+		return int('Z'-head) + 2, true
+	}
+
+	return 0, false
+}
+
+func integerPart(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+
+	n, ok := integerLength(key[0])
+	if !ok || n > len(key) {
+		return "", false
+	}
+
+	return key[:n], true
+}
+
+func validDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(digits, s[i]) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Between returns a key sorting after a and before b. An empty a means the start, an empty b the end
+func Between(a, b string) (string, error) {
+	if (a != "" && !Valid(a)) || (b != "" && !Valid(b)) {
+		return "", ErrInvalidKey
+	}
+
+	if a != "" && b != "" && a >= b {
+		return "", ErrOrder
+	}
+
+	switch {
+	case a == "" && b == "":
+		return zero, nil
+	case a == "":
+		integer, _ := integerPart(b)
+		if integer == smallest {
+			return integer + midpoint("", b[len(integer):]), nil
+		}
+
+		// b has a fraction, its integer alone sorts before it
+		if integer < b {
+			return integer, nil
+		}
+
+		previous, ok := decrement(integer)
+		if !ok {
+			return "", ErrNoRoom
+		}
+
+		return previous, nil
+	case b == "":
+		integer, _ := integerPart(a)
+		if next, ok := increment(integer); ok {
+			return next, nil
+		}
+
+		return integer + midpoint(a[len(integer):], ""), nil
+	}
+
+	integer, _ := integerPart(a)
+	if integerB, _ := integerPart(b); integerB == integer {
+		return integer + midpoint(a[len(integer):], b[len(integer):]), nil
+	}
+
+	if next, ok := increment(integer); ok && next < b {
+		return next, nil
+	}
+
+	return integer + midpoint(a[len(integer):], ""), nil
+}
+
+// midpoint returns a fraction between the fractions a and b, an empty b is the end
+func midpoint(a, b string) string {
+	if b != "" {
+		// the common prefix is kept, a missing digit of a counts as a zero
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:])
+		}
+	}
+
+	lo := 0
+	if a != "" {
+		lo = strings.IndexByte(digits, a[0])
+	}
+
+	hi := len(digits)
+	if b != "" {
+		hi = strings.IndexByte(digits, b[0])
+	}
+
+	if hi-lo > 1 {
+		return string(digits[(lo+hi)/2])
+	}
+
+	// the first digits are consecutive, b without its next digits still sorts after a
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	return string(digits[lo]) + midpoint(suffix(a, 1), "")
+}
+
+// increment returns the next integer, there's none after the largest one
+func increment(integer string) (string, bool) {
+	head, digs := integer[0], []byte(integer[1:])
+
+	for i := len(digs) - 1; i >= 0; i-- {
+		if d := strings.IndexByte(digits, digs[i]) + 1; d < len(digits) {
+			digs[i] = digits[d]
+
+			return string(head) + string(digs), true
+		}
+
+		digs[i] = digits[0]
+	}
+
+	// all the digits wrapped around, the integer gets a digit more or a digit less
+	switch head {
+	case 'Z':
+		return zero, true
+	case 'z':
+		return "", false
+	}
+
+	head++
+	if head > 'a' {
+		digs = append(digs, digits[0])
+	} else {
+		digs = digs[1:]
+	}
+
+	return string(head) + string(digs), true
+}
+
+// decrement returns the previous integer, there's none before the smallest one
+func decrement(integer string) (string, bool) {
+	head, digs := integer[0], []byte(integer[1:])
+	last := digits[len(digits)-1]
+
+	for i := len(digs) - 1; i >= 0; i-- {
+		if d := strings.IndexByte(digits, digs[i]) - 1; d >= 0 {
+			digs[i] = digits[d]
+
+			return string(head) + string(digs), true
+		}
+
+		digs[i] = last
+	}
+
+	switch head {
+	case 'a':
+		return "Z" + string(last), true
+	case 'A':
+		return "", false
+	}
+
+	head--
+	if head < 'Z' {
+		digs = append(digs, last)
+	} else {
+		digs = digs[1:]
+	}
+
+	previous := string(head) + string(digs)
+	if previous == smallest {
+		return "", false
+	}
+
+	return previous, true
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+
+	return digits[0]
+}
+
+func suffix(s string, i int) string {
+	if i < len(s) {
+		return s[i:]
+	}
+
+	return ""
+}
+
+// Spread returns n ascending keys, they are consecutive integers so they are as short as n allows
+func Spread(n int) []string {
+	keys := make([]string, n)
+
+	key := zero
+	for i := range keys {
+		keys[i] = key
+		key, _ = increment(key)
+	}
+
+	return keys
+}
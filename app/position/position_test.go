@@ -0,0 +1,117 @@
+package position_test
+
+import (
+	"librenote/app/position"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBetween(t *testing.T) {
+	cases := map[string]struct {
+		a, b string
+		key  string
+	}{
+		"empty":           {"", "", "a0"},
+		"start":           {"", "a0", "Zz"},
+		"end":             {"a0", "", "a1"},
+		"start-fraction":  {"", "a0V", "a0"},
+		"integer-wraps":   {"az", "", "b00"},
+		"integer-shrinks": {"", "b00", "az"},
+		"negative-wraps":  {"", "Y00", "Xzzz"},
+		"negative-grows":  {"Zz", "", "a0"},
+		"between":         {"a0", "a2", "a1"},
+		"consecutive":     {"a0", "a1", "a0V"},
+		"same-integer":    {"a1V", "a1i", "a1b"},
+		"common-prefix":   {"a1V1", "a1V2", "a1V1V"},
+		"largest":         {"z" + strings.Repeat("z", 26), "", "z" + strings.Repeat("z", 26) + "V"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			key, err := position.Between(c.a, c.b)
+			assert.NoError(t, err)
+			assert.Equal(t, c.key, key)
+			assert.True(t, position.Valid(key))
+		})
+	}
+
+	_, err := position.Between("a1", "a0")
+	assert.ErrorIs(t, err, position.ErrOrder)
+
+	for _, key := range []string{"a", "a50", "a5V0", "0i", "i", "a-", "A" + strings.Repeat("0", 26)} {
+		_, err = position.Between(key, "")
+		assert.ErrorIs(t, err, position.ErrInvalidKey, key)
+	}
+}
+
+func TestBetweenRepeated(t *testing.T) {
+	// moving rows to the same place over and over keeps the keys ordered and short
+	a, b := "a0", "a1"
+
+	for i := 0; i < 200; i++ {
+		key, err := position.Between(a, b)
+		assert.NoError(t, err)
+		assert.True(t, a < key && key < b, "%s < %s < %s", a, key, b)
+
+		if i%2 == 0 {
+			a = key
+		} else {
+			b = key
+		}
+	}
+
+	assert.Less(t, len(a), 120)
+}
+
+func TestBetweenFirst(t *testing.T) {
+	// the new notes come first, the keys grow by a character every 62^n notes
+	first := ""
+
+	for i := 0; i < 100000; i++ {
+		key, err := position.Between("", first)
+		assert.NoError(t, err)
+
+		if first != "" && key >= first {
+			t.Fatalf("%s isn't before %s", key, first)
+		}
+
+		first = key
+	}
+
+	assert.LessOrEqual(t, len(first), 4)
+
+	last := ""
+
+	for i := 0; i < 100000; i++ {
+		key, err := position.Between(last, "")
+		assert.NoError(t, err)
+
+		if last != "" && key <= last {
+			t.Fatalf("%s isn't after %s", key, last)
+		}
+
+		last = key
+	}
+
+	assert.LessOrEqual(t, len(last), 4)
+}
+
+func TestSpread(t *testing.T) {
+	assert.Empty(t, position.Spread(0))
+	assert.Equal(t, []string{"a0"}, position.Spread(1))
+	assert.Equal(t, []string{"a0", "a1", "a2"}, position.Spread(3))
+
+	keys := position.Spread(1000)
+	assert.Len(t, keys, 1000)
+
+	for i, key := range keys {
+		assert.True(t, position.Valid(key), key)
+		assert.LessOrEqual(t, len(key), 3)
+
+		if i > 0 {
+			assert.Less(t, keys[i-1], key)
+		}
+	}
+}
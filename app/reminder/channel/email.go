@@ -0,0 +1,66 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"librenote/app/model"
+	"librenote/infrastructure/config"
+	"net/smtp"
+	"strings"
+)
+
+type emailChannel struct {
+	cfg config.MailConfig
+}
+
+// NewEmailChannel sends due reminders through the configured smtp server
+func NewEmailChannel(cfg config.MailConfig) model.ReminderChannel {
+	return &emailChannel{
+		cfg: cfg,
+	}
+}
+
+func (e *emailChannel) Name() string {
+	return "email"
+}
+
+func (e *emailChannel) Deliver(_ context.Context, reminder *model.Reminder, note *model.Note) error {
+	var auth smtp.Auth
+	if e.cfg.Username != "" {
+		auth = smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + e.cfg.From,
+		"To: " + reminder.Target,
+		"Subject: Reminder: " + summary(note),
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		mailBody(note),
+	}, "\r\n")
+
+	addr := fmt.Sprintf("%s:%d", e.cfg.Host, e.cfg.Port)
+
+	return smtp.SendMail(addr, auth, e.cfg.From, []string{reminder.Target}, []byte(msg))
+}
+
+func mailBody(note *model.Note) string {
+	lines := make([]string, 0, len(note.Items))
+
+	for _, item := range note.Items {
+		if item.Text == nil {
+			continue
+		}
+
+		switch {
+		case note.Type != "list":
+			lines = append(lines, *item.Text)
+		case item.IsChecked == 1:
+			lines = append(lines, "[x] "+*item.Text)
+		default:
+			lines = append(lines, "[ ] "+*item.Text)
+		}
+	}
+
+	return strings.Join(lines, "\r\n")
+}
@@ -0,0 +1,32 @@
+package channel
+
+import (
+	"context"
+	"librenote/app/model"
+	"time"
+)
+
+type eventChannel struct {
+	repo model.ReminderRepository
+}
+
+// NewEventChannel stores due reminders as in-app events of the note's owner
+func NewEventChannel(repo model.ReminderRepository) model.ReminderChannel {
+	return &eventChannel{
+		repo: repo,
+	}
+}
+
+func (e *eventChannel) Name() string {
+	return "event"
+}
+
+func (e *eventChannel) Deliver(ctx context.Context, reminder *model.Reminder, note *model.Note) error {
+	return e.repo.CreateEvent(ctx, &model.ReminderEvent{
+		UserID:     reminder.UserID,
+		ReminderID: reminder.ID,
+		NoteID:     note.ID,
+		Message:    "Reminder: " + summary(note),
+		CreatedAt:  time.Now().UTC().Format("2006-01-02 15:04:05"),
+	})
+}
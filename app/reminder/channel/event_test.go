@@ -0,0 +1,27 @@
+package channel_test
+
+import (
+	"context"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/reminder/channel"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEventDeliver(t *testing.T) {
+	text := "call   the\nplumber"
+	note := &model.Note{ID: 1, UserID: 1, Type: "list", Items: []model.NotesItem{{Text: &text}}}
+
+	mockRepo := new(mocks.ReminderRepository)
+	mockRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(e *model.ReminderEvent) bool {
+		return e.UserID == 1 && e.ReminderID == 3 && e.NoteID == 1 && e.Message == "Reminder: call the plumber"
+	})).Return(nil).Once()
+
+	reminder := &model.Reminder{ID: 3, NoteID: 1, UserID: 1, Channel: "event"}
+
+	assert.NoError(t, channel.NewEventChannel(mockRepo).Deliver(context.TODO(), reminder, note))
+	mockRepo.AssertExpectations(t)
+}
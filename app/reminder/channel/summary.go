@@ -0,0 +1,31 @@
+package channel
+
+import (
+	"librenote/app/model"
+	"strings"
+)
+
+const summaryLength = 80
+
+// summary is a single line describing the note, its title or else the first item
+func summary(note *model.Note) string {
+	text := ""
+
+	switch {
+	case note.Title != nil && strings.TrimSpace(*note.Title) != "":
+		text = *note.Title
+	case len(note.Items) > 0 && note.Items[0].Text != nil:
+		text = *note.Items[0].Text
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return "untitled note"
+	}
+
+	if runes := []rune(text); len(runes) > summaryLength {
+		return string(runes[:summaryLength]) + "..."
+	}
+
+	return text
+}
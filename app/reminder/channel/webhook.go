@@ -0,0 +1,65 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"librenote/app/model"
+	"librenote/infrastructure/httpclient"
+	"net/http"
+	"time"
+)
+
+type webhookChannel struct {
+	client *http.Client
+}
+
+// NewWebhookChannel posts due reminders as json to the reminder's target url,
+// it must be a public address unless private ones are allowed
+func NewWebhookChannel(timeout time.Duration, allowPrivate bool) model.ReminderChannel {
+	return &webhookChannel{
+		client: httpclient.New(timeout, allowPrivate),
+	}
+}
+
+type webhookPayload struct {
+	Event    string          `json:"event"`
+	Reminder *model.Reminder `json:"reminder"`
+	Note     *model.Note     `json:"note"`
+}
+
+func (w *webhookChannel) Name() string {
+	return "webhook"
+}
+
+func (w *webhookChannel) Deliver(ctx context.Context, reminder *model.Reminder, note *model.Note) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:    "reminder.due",
+		Reminder: reminder,
+		Note:     note,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reminder.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
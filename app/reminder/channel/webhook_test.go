@@ -0,0 +1,63 @@
+package channel_test
+
+import (
+	"context"
+	"encoding/json"
+	"librenote/app/model"
+	"librenote/app/reminder/channel"
+	"librenote/infrastructure/httpclient"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookDeliver(t *testing.T) {
+	title := "Groceries"
+	note := &model.Note{ID: 1, UserID: 1, Title: &title, Type: "note"}
+
+	t.Run("success", func(t *testing.T) {
+		var payload map[string]interface{}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		reminder := &model.Reminder{ID: 3, NoteID: 1, UserID: 1, Channel: "webhook", Target: server.URL}
+
+		err := channel.NewWebhookChannel(time.Second, true).Deliver(context.TODO(), reminder, note)
+		assert.NoError(t, err)
+		assert.Equal(t, "reminder.due", payload["event"])
+		assert.Equal(t, "Groceries", payload["note"].(map[string]interface{})["title"])
+	})
+
+	t.Run("error-status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		reminder := &model.Reminder{ID: 3, NoteID: 1, UserID: 1, Channel: "webhook", Target: server.URL}
+
+		err := channel.NewWebhookChannel(time.Second, true).Deliver(context.TODO(), reminder, note)
+		assert.EqualError(t, err, "webhook responded with status 500")
+	})
+
+	t.Run("private-target", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		reminder := &model.Reminder{ID: 3, NoteID: 1, UserID: 1, Channel: "webhook", Target: server.URL}
+
+		err := channel.NewWebhookChannel(time.Second, false).Deliver(context.TODO(), reminder, note)
+		assert.ErrorIs(t, err, httpclient.ErrNotPublic)
+	})
+}
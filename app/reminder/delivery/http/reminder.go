@@ -0,0 +1,166 @@
+package http
+
+import (
+	"errors"
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/app/validation"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ReminderHandler represent the http handler for reminder
+type ReminderHandler struct {
+	RUseCase model.ReminderUsecase
+}
+
+func NewReminderHandler(e *echo.Echo, us model.ReminderUsecase) {
+	handler := &ReminderHandler{
+		RUseCase: us,
+	}
+
+	reminders := e.Group("/api/v1/notes/:id/reminders")
+	_ = middlewares.AttachJwtToGroup(reminders)
+	reminders.GET("", handler.FetchReminders)
+	reminders.POST("", handler.CreateReminder)
+	reminders.DELETE("/:reminder_id", handler.DeleteReminder)
+
+	events := e.Group("/api/v1/reminders/events")
+	_ = middlewares.AttachJwtToGroup(events)
+	events.GET("", handler.FetchEvents)
+	events.POST("/:event_id/read", handler.MarkEventRead)
+}
+
+func (r *ReminderHandler) FetchReminders(c echo.Context) error {
+	noteID, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	reminders, err := r.RUseCase.Fetch(ctx, noteID, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", reminders))
+}
+
+func (r *ReminderHandler) CreateReminder(c echo.Context) error {
+	noteID, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var rReq reminderReq
+
+	err = c.Bind(&rReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&rReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	remindAt, err := time.Parse(time.RFC3339, rReq.RemindAt)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest,
+			errors.New("remind_at must be a RFC 3339 time like 2022-01-31T09:00:00+06:00")))
+	}
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	reminder := model.Reminder{
+		NoteID:     noteID,
+		UserID:     middlewares.GetUserID(c),
+		RemindAt:   remindAt.UTC().Format("2006-01-02 15:04:05"),
+		Timezone:   rReq.Timezone,
+		Recurrence: rReq.Recurrence,
+		Channel:    rReq.Channel,
+		Target:     rReq.Target,
+		CreatedAt:  nowTime,
+		UpdatedAt:  nowTime,
+	}
+
+	ctx := c.Request().Context()
+
+	err = r.RUseCase.Create(ctx, &reminder)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("reminder created", reminder))
+}
+
+func (r *ReminderHandler) DeleteReminder(c echo.Context) error {
+	noteID, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	id, err := middlewares.ParamID(c, "reminder_id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	err = r.RUseCase.Delete(ctx, id, noteID, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (r *ReminderHandler) FetchEvents(c echo.Context) error {
+	var fReq fetchEventsReq
+
+	err := c.Bind(&fReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&fReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	ctx := c.Request().Context()
+
+	events, err := r.RUseCase.FetchEvents(ctx, middlewares.GetUserID(c), fReq.Unread == 1)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", events))
+}
+
+func (r *ReminderHandler) MarkEventRead(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "event_id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	err = r.RUseCase.MarkEventRead(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,170 @@
+package http_test
+
+import (
+	"encoding/json"
+	"io"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	reminderHttp "librenote/app/reminder/delivery/http"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var BaseURLV1 = "/api/v1"
+
+func buildEchoAuthorizedRequest(t *testing.T, method, path, token string, payload io.Reader) (
+	echo.Context, *httptest.ResponseRecorder) {
+	var req *http.Request
+
+	var err error
+
+	if payload != nil {
+		req, err = http.NewRequest(method, path, payload)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	} else {
+		req, err = http.NewRequest(method, path, nil)
+	}
+
+	assert.NoError(t, err)
+
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+	res := httptest.NewRecorder()
+	e := echo.New()
+	ctx := e.NewContext(req, res)
+
+	return ctx, res
+}
+
+// nolint:unparam
+func getToken(userID int32) string {
+	jwtCfg := config.Get().Jwt
+	claims := &middlewares.JwtCustomClaims{
+		UserID: userID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(jwtCfg.ExpireTime).Unix(),
+		},
+	}
+	unsignedToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token, _ := unsignedToken.SignedString([]byte(jwtCfg.SecretKey))
+
+	return token
+}
+
+func attachJWTMiddleware(hfc echo.HandlerFunc) echo.HandlerFunc {
+	mhfc := middleware.JWTWithConfig(
+		middleware.JWTConfig{
+			Claims:     &middlewares.JwtCustomClaims{},
+			SigningKey: []byte(config.Get().Jwt.SecretKey),
+		})(hfc)
+
+	return mhfc
+}
+
+func TestCreateReminder(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id/reminders"
+
+	mockUsecase := new(mocks.ReminderUsecase)
+	mockUsecase.On("Create", mock.Anything, mock.MatchedBy(func(r *model.Reminder) bool {
+		return r.NoteID == 1 && r.UserID == 1 && r.RemindAt == "2022-01-31 03:00:00"
+	})).Return(nil).Once()
+
+	handler := reminderHttp.ReminderHandler{
+		RUseCase: mockUsecase,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		body := `{"remind_at": "2022-01-31T09:00:00+06:00", "timezone": "Asia/Dhaka", "recurrence": "FREQ=DAILY",
+			"channel": "event"}`
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+		handle := attachJWTMiddleware(handler.CreateReminder)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		var r response.Response
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+		assert.True(t, r.Success)
+		assert.Equal(t, "Asia/Dhaka", r.Results.(map[string]interface{})["timezone"])
+
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"webhook-without-target": `{"remind_at": "2022-01-31T09:00:00Z", "channel": "webhook"}`,
+			"unknown-channel":        `{"remind_at": "2022-01-31T09:00:00Z", "channel": "sms"}`,
+			"bad-time":               `{"remind_at": "31/01/2022 09:00", "channel": "event"}`,
+		} {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+			ctx.SetParamNames("id")
+			ctx.SetParamValues("1")
+			handle := attachJWTMiddleware(handler.CreateReminder)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, http.StatusBadRequest, res.Code, name)
+		}
+	})
+}
+
+func TestDeleteReminder(t *testing.T) {
+	endPoint := BaseURLV1 + "/notes/:id/reminders/:reminder_id"
+
+	mockUsecase := new(mocks.ReminderUsecase)
+	mockUsecase.On("Delete", mock.Anything, int32(2), int32(1), int32(1)).Return(nil).Once()
+	mockUsecase.On("Delete", mock.Anything, int32(3), int32(1), int32(1)).Return(response.ErrNotFound).Once()
+
+	handler := reminderHttp.ReminderHandler{
+		RUseCase: mockUsecase,
+	}
+
+	for reminderID, code := range map[string]int{"2": http.StatusNoContent, "3": http.StatusNotFound} {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.DELETE, endPoint, getToken(1), nil)
+		ctx.SetParamNames("id", "reminder_id")
+		ctx.SetParamValues("1", reminderID)
+		handle := attachJWTMiddleware(handler.DeleteReminder)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, code, res.Code)
+	}
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestFetchEvents(t *testing.T) {
+	endPoint := BaseURLV1 + "/reminders/events?unread=1"
+
+	mockUsecase := new(mocks.ReminderUsecase)
+	mockUsecase.On("FetchEvents", mock.Anything, int32(1), true).
+		Return([]model.ReminderEvent{{ID: 1, UserID: 1, Message: "Reminder: Groceries"}}, nil).Once()
+
+	handler := reminderHttp.ReminderHandler{
+		RUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+	handle := attachJWTMiddleware(handler.FetchEvents)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var r response.Response
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+	assert.Len(t, r.Results, 1)
+
+	mockUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,15 @@
+package http
+
+type reminderReq struct {
+	// RFC 3339 time with offset, e.g. 2022-01-31T09:00:00+06:00
+	RemindAt   string `json:"remind_at" validate:"required"`
+	Timezone   string `json:"timezone" validate:"omitempty,max=64"`
+	Recurrence string `json:"recurrence" validate:"omitempty,max=255"`
+	Channel    string `json:"channel" validate:"required,oneof=webhook email event"`
+	// url of a webhook, email reminders are sent to the address of the account
+	Target string `json:"target" validate:"required_if=Channel webhook,max=1000"`
+}
+
+type fetchEventsReq struct {
+	Unread int8 `json:"unread" query:"unread" validate:"min=0,max=1"`
+}
@@ -0,0 +1,55 @@
+package encrypted
+
+import (
+	"context"
+	"librenote/app/encryption"
+	"librenote/app/model"
+)
+
+// reminderRepository encrypts the messages of the events stored by another repository, as they hold the titles of
+// the notes, and decrypts them back on reading. The reminders themselves stay in clear
+type reminderRepository struct {
+	model.ReminderRepository
+	keyring *encryption.Keyring
+}
+
+// NewReminderRepository wraps the repository of any database
+func NewReminderRepository(repo model.ReminderRepository, keyring *encryption.Keyring) model.ReminderRepository {
+	return &reminderRepository{
+		ReminderRepository: repo,
+		keyring:            keyring,
+	}
+}
+
+func (r *reminderRepository) CreateEvent(ctx context.Context, event *model.ReminderEvent) error {
+	encEvent := *event
+
+	var err error
+	if encEvent.Message, err = r.keyring.EncryptString(event.Message); err != nil {
+		return err
+	}
+
+	if err = r.ReminderRepository.CreateEvent(ctx, &encEvent); err != nil {
+		return err
+	}
+
+	event.ID = encEvent.ID
+
+	return nil
+}
+
+func (r *reminderRepository) FetchEvents(ctx context.Context, userID int32, unreadOnly bool) (
+	[]model.ReminderEvent, error) {
+	events, err := r.ReminderRepository.FetchEvents(ctx, userID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		if events[i].Message, err = r.keyring.DecryptString(events[i].Message); err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
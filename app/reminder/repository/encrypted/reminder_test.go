@@ -0,0 +1,50 @@
+package encrypted_test
+
+import (
+	"context"
+	"librenote/app/encryption"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/reminder/repository/encrypted"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEvents(t *testing.T) {
+	key, err := encryption.GenerateKey()
+	assert.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(key)
+	assert.NoError(t, err)
+
+	event := &model.ReminderEvent{UserID: 1, ReminderID: 2, NoteID: 3, Message: "Reminder: Groceries"}
+
+	var message string
+
+	mockRepo := new(mocks.ReminderRepository)
+	mockRepo.On("CreateEvent", mock.Anything, mock.AnythingOfType("*model.ReminderEvent")).
+		Run(func(args mock.Arguments) {
+			stored := args.Get(1).(*model.ReminderEvent)
+			stored.ID = 6
+			message = stored.Message
+		}).Return(nil).Once()
+
+	r := encrypted.NewReminderRepository(mockRepo, keyring)
+	assert.NoError(t, r.CreateEvent(context.TODO(), event))
+	assert.True(t, keyring.IsCurrent(message))
+	assert.Equal(t, "Reminder: Groceries", event.Message)
+	assert.Equal(t, int32(6), event.ID)
+
+	// the events stored before the encryption was enabled are read as they are
+	mockRepo.On("FetchEvents", mock.Anything, int32(1), true).Return([]model.ReminderEvent{
+		{ID: 6, Message: message}, {ID: 5, Message: "Reminder: untitled note"},
+	}, nil).Once()
+
+	events, err := r.FetchEvents(context.TODO(), 1, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "Reminder: Groceries", events[0].Message)
+	assert.Equal(t, "Reminder: untitled note", events[1].Message)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,121 @@
+package pgsql_test
+
+import (
+	"context"
+	"database/sql"
+	"librenote/app/model"
+	reminderRepo "librenote/app/reminder/repository/pgsql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var reminderColumns = []string{
+	"id", "note_id", "user_id", "remind_at", "timezone", "recurrence", "channel", "target", "status", "attempts",
+	"due_at", "last_error", "created_at", "updated_at",
+}
+
+func TestCreateReminder(t *testing.T) {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	m := &model.Reminder{
+		NoteID: 1, UserID: 1, RemindAt: nowTime, Timezone: "UTC", Channel: "event", Status: model.ReminderPending,
+		DueAt: nowTime, CreatedAt: nowTime, UpdatedAt: nowTime,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	prep := mock.ExpectPrepare("INSERT INTO reminders")
+	prep.ExpectQuery().
+		WithArgs(m.NoteID, m.UserID, m.RemindAt, m.Timezone, m.Recurrence, m.Channel, m.Target, m.Status,
+			m.Attempts, m.DueAt, m.LastError, m.CreatedAt, m.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+	rr := reminderRepo.NewPgsqlReminderRepository(db)
+	assert.NoError(t, rr.CreateReminder(context.TODO(), m))
+	assert.Equal(t, int32(4), m.ID)
+}
+
+func TestFetchDueReminders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	rows := sqlmock.NewRows(reminderColumns).
+		AddRow(1, 1, 1, nowTime, "UTC", "", "event", "", "pending", 0, nowTime, "", nowTime, nowTime)
+
+	mock.ExpectQuery("SELECT (.+) FROM reminders WHERE status = 'pending' AND due_at <= ").
+		WithArgs(nowTime, 10).WillReturnRows(rows)
+
+	rr := reminderRepo.NewPgsqlReminderRepository(db)
+	reminders, err := rr.FetchDueReminders(context.TODO(), nowTime, 10)
+	assert.NoError(t, err)
+	assert.Len(t, reminders, 1)
+	assert.Equal(t, nowTime, reminders[0].DueAt)
+}
+
+func TestClaimReminder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rr := reminderRepo.NewPgsqlReminderRepository(db)
+
+	t.Run("claimed", func(t *testing.T) {
+		mock.ExpectExec("UPDATE reminders SET due_at").WithArgs("lease", int32(1), "due").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ok, err := rr.ClaimReminder(context.TODO(), 1, "due", "lease")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("claimed-by-other", func(t *testing.T) {
+		mock.ExpectExec("UPDATE reminders SET due_at").WithArgs("lease", int32(1), "due").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ok, err := rr.ClaimReminder(context.TODO(), 1, "due", "lease")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestDeleteReminder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rr := reminderRepo.NewPgsqlReminderRepository(db)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM reminders_events").WithArgs(int32(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM reminders").WithArgs(int32(2), int32(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, rr.DeleteReminder(context.TODO(), 2, 1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM reminders_events").WithArgs(int32(2)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM reminders").WithArgs(int32(2), int32(3)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, rr.DeleteReminder(context.TODO(), 2, 3), sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
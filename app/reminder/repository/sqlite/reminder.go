@@ -0,0 +1,288 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"librenote/app/model"
+)
+
+type reminderRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteReminderRepository(db *sql.DB) model.ReminderRepository {
+	return &reminderRepository{
+		db: db,
+	}
+}
+
+const createReminder = `INSERT INTO reminders (
+  note_id, user_id, remind_at, timezone, recurrence, channel, target, status, attempts, due_at, last_error,
+  created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+func (r *reminderRepository) CreateReminder(ctx context.Context, reminder *model.Reminder) error {
+	stmt, err := r.db.PrepareContext(ctx, createReminder)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+	res, err := stmt.ExecContext(ctx,
+		reminder.NoteID,
+		reminder.UserID,
+		reminder.RemindAt,
+		reminder.Timezone,
+		reminder.Recurrence,
+		reminder.Channel,
+		reminder.Target,
+		reminder.Status,
+		reminder.Attempts,
+		reminder.DueAt,
+		reminder.LastError,
+		reminder.CreatedAt,
+		reminder.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	reminder.ID = int32(id)
+
+	return nil
+}
+
+const reminderColumns = `id, note_id, user_id, remind_at, timezone, recurrence, channel, target, status, attempts,
+due_at, last_error, created_at, updated_at`
+
+func scanReminders(rows *sql.Rows) ([]model.Reminder, error) {
+	defer rows.Close()
+
+	reminders := make([]model.Reminder, 0)
+
+	for rows.Next() {
+		var i model.Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.NoteID,
+			&i.UserID,
+			&i.RemindAt,
+			&i.Timezone,
+			&i.Recurrence,
+			&i.Channel,
+			&i.Target,
+			&i.Status,
+			&i.Attempts,
+			&i.DueAt,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		reminders = append(reminders, i)
+	}
+
+	return reminders, rows.Err()
+}
+
+const fetchReminders = `SELECT ` + reminderColumns + ` FROM reminders WHERE note_id = ? ORDER BY remind_at`
+
+func (r *reminderRepository) FetchReminders(ctx context.Context, noteID int32) ([]model.Reminder, error) {
+	rows, err := r.db.QueryContext(ctx, fetchReminders, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanReminders(rows)
+}
+
+const deleteReminderEvents = `DELETE FROM reminders_events WHERE reminder_id = ?`
+
+const deleteReminder = `DELETE FROM reminders WHERE id = ? AND note_id = ?`
+
+func (r *reminderRepository) DeleteReminder(ctx context.Context, id, noteID int32) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.ExecContext(ctx, deleteReminderEvents, id); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, deleteReminder, id, noteID)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+const fetchDueReminders = `SELECT ` + reminderColumns + ` FROM reminders
+WHERE status = 'pending' AND due_at <= ? ORDER BY due_at LIMIT ?
+`
+
+func (r *reminderRepository) FetchDueReminders(ctx context.Context, now string, limit int) ([]model.Reminder, error) {
+	rows, err := r.db.QueryContext(ctx, fetchDueReminders, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanReminders(rows)
+}
+
+const claimReminder = `UPDATE reminders SET due_at = ? WHERE id = ? AND due_at = ? AND status = 'pending'`
+
+func (r *reminderRepository) ClaimReminder(ctx context.Context, id int32, dueAt, leaseUntil string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, claimReminder, leaseUntil, id, dueAt)
+	if err != nil {
+		return false, err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affect == 1, nil
+}
+
+const updateReminder = `UPDATE reminders
+SET remind_at = ?,
+status = ?,
+attempts = ?,
+due_at = ?,
+last_error = ?,
+updated_at = ?
+WHERE id = ?
+`
+
+func (r *reminderRepository) UpdateReminder(ctx context.Context, reminder *model.Reminder) error {
+	stmt, err := r.db.PrepareContext(ctx, updateReminder)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+	res, err := stmt.ExecContext(ctx,
+		reminder.RemindAt,
+		reminder.Status,
+		reminder.Attempts,
+		reminder.DueAt,
+		reminder.LastError,
+		reminder.UpdatedAt,
+		reminder.ID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return errors.New("nothing changed")
+	}
+
+	return nil
+}
+
+const createEvent = `INSERT INTO reminders_events (
+  user_id, reminder_id, note_id, message, is_read, created_at
+) VALUES (?, ?, ?, ?, ?, ?)
+`
+
+func (r *reminderRepository) CreateEvent(ctx context.Context, event *model.ReminderEvent) error {
+	res, err := r.db.ExecContext(ctx, createEvent,
+		event.UserID,
+		event.ReminderID,
+		event.NoteID,
+		event.Message,
+		event.IsRead,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	event.ID = int32(id)
+
+	return nil
+}
+
+const fetchEvents = `SELECT id, user_id, reminder_id, note_id, message, is_read, created_at FROM reminders_events
+WHERE user_id = ? AND is_read <= ? ORDER BY id DESC
+`
+
+func (r *reminderRepository) FetchEvents(ctx context.Context, userID int32, unreadOnly bool) (
+	[]model.ReminderEvent, error) {
+	maxIsRead := 1
+	if unreadOnly {
+		maxIsRead = 0
+	}
+
+	rows, err := r.db.QueryContext(ctx, fetchEvents, userID, maxIsRead)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	events := make([]model.ReminderEvent, 0)
+
+	for rows.Next() {
+		var i model.ReminderEvent
+		if err = rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ReminderID,
+			&i.NoteID,
+			&i.Message,
+			&i.IsRead,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		events = append(events, i)
+	}
+
+	return events, rows.Err()
+}
+
+const markEventRead = `UPDATE reminders_events SET is_read = 1 WHERE id = ? AND user_id = ?`
+
+// MarkEventRead is idempotent, unknown or foreign events are silently ignored
+func (r *reminderRepository) MarkEventRead(ctx context.Context, id, userID int32) error {
+	_, err := r.db.ExecContext(ctx, markEventRead, id, userID)
+
+	return err
+}
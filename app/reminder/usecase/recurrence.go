@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recurrence is the supported subset of an iCalendar RRULE, FREQ, INTERVAL and UNTIL
+type recurrence struct {
+	freq     string
+	interval int
+	until    time.Time
+}
+
+// parseRecurrence returns nil for an empty rule, which means a one-time reminder
+func parseRecurrence(rule string) (*recurrence, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return nil, nil
+	}
+
+	r := &recurrence{interval: 1}
+
+	for _, part := range strings.Split(rule, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid recurrence part %q", part)
+		}
+
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch key {
+		case "FREQ":
+			switch value {
+			case "HOURLY", "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				r.freq = value
+			default:
+				return nil, fmt.Errorf("unsupported recurrence frequency %q", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid recurrence interval %q", value)
+			}
+
+			r.interval = interval
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+
+			r.until = until
+		default:
+			return nil, fmt.Errorf("unsupported recurrence part %q", key)
+		}
+	}
+
+	if r.freq == "" {
+		return nil, errors.New("recurrence FREQ is required")
+	}
+
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// the whole day is included
+				t = t.Add(24*time.Hour - time.Second)
+			}
+
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid recurrence until %q", value)
+}
+
+// maxSkipped bounds the search of the next valid occurrence, like a Feb 29 of a yearly rule
+const maxSkipped = 1000
+
+// next returns the first occurrence after `after`, following the wall clock of loc so that
+// a daily 09:00 reminder stays at 09:00 across daylight saving changes.
+// As in RFC 5545, the monthly and yearly occurrences keep the day of current and the months without it are
+// skipped, a reminder of Jan 31 goes on Mar 31. The missed occurrences are skipped at once.
+// ok is false when the rule has no more occurrences
+func (r *recurrence) next(current, after time.Time, loc *time.Location) (next time.Time, ok bool) {
+	start := current.In(loc)
+
+	for n, skipped := r.elapsed(start, after.In(loc)), 0; skipped < maxSkipped; n, skipped = n+1, skipped+1 {
+		next, ok = r.occurrence(start, n)
+		if !ok || !next.After(after) {
+			continue
+		}
+
+		if !r.until.IsZero() && next.After(r.until) {
+			return time.Time{}, false
+		}
+
+		return next.UTC(), true
+	}
+
+	return time.Time{}, false
+}
+
+// elapsed returns the occurrences from start to after, the next one can't be before them
+func (r *recurrence) elapsed(start, after time.Time) int {
+	if !after.After(start) {
+		return 0
+	}
+
+	var units int
+
+	switch r.freq {
+	case "HOURLY":
+		units = int(after.Sub(start) / time.Hour)
+	case "DAILY":
+		units = civilDays(after) - civilDays(start)
+	case "WEEKLY":
+		units = (civilDays(after) - civilDays(start)) / 7
+	case "MONTHLY":
+		units = (after.Year()-start.Year())*12 + int(after.Month()-start.Month())
+	case "YEARLY":
+		units = after.Year() - start.Year()
+	}
+
+	return units / r.interval
+}
+
+// occurrence returns the nth occurrence from start, ok is false when its day doesn't exist in its month
+func (r *recurrence) occurrence(start time.Time, n int) (next time.Time, ok bool) {
+	year, month, day := start.Date()
+	hour, min, sec := start.Clock()
+	steps := n * r.interval
+
+	switch r.freq {
+	case "HOURLY":
+		return start.Add(time.Duration(steps) * time.Hour), true
+	case "DAILY":
+		day += steps
+	case "WEEKLY":
+		day += 7 * steps
+	case "MONTHLY":
+		months := int(month) - 1 + steps
+		year, month = year+months/12, time.Month(months%12+1)
+
+		if day > daysIn(year, month) {
+			return time.Time{}, false
+		}
+	case "YEARLY":
+		year += steps
+
+		if day > daysIn(year, month) {
+			return time.Time{}, false
+		}
+	}
+
+	return time.Date(year, month, day, hour, min, sec, start.Nanosecond(), start.Location()), true
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// civilDays counts the days of the calendar date of t since the epoch
+func civilDays(t time.Time) int {
+	year, month, day := t.Date()
+
+	return int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
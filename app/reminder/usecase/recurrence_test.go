@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	r, err := parseRecurrence("")
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	r, err = parseRecurrence("RRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=20301231")
+	assert.NoError(t, err)
+	assert.Equal(t, "WEEKLY", r.freq)
+	assert.Equal(t, 2, r.interval)
+	assert.Equal(t, time.Date(2030, 12, 31, 23, 59, 59, 0, time.UTC), r.until)
+
+	for _, rule := range []string{"INTERVAL=2", "FREQ=SECONDLY", "FREQ=DAILY;INTERVAL=0", "FREQ=DAILY;BYDAY=MO",
+		"FREQ=DAILY;UNTIL=tomorrow", "FREQ"} {
+		_, err = parseRecurrence(rule)
+		assert.Error(t, err, rule)
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+
+	t.Run("keeps-wall-clock-across-dst", func(t *testing.T) {
+		r, _ := parseRecurrence("FREQ=DAILY")
+		// 09:00 CEST, the clocks go back on 2026-10-25
+		current := time.Date(2026, 10, 24, 7, 0, 0, 0, time.UTC)
+
+		next, ok := r.next(current, current, berlin)
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2026, 10, 25, 8, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("skips-missed-occurrences", func(t *testing.T) {
+		r, _ := parseRecurrence("FREQ=WEEKLY")
+		current := time.Date(2022, 1, 3, 9, 0, 0, 0, time.UTC)
+		now := time.Date(2022, 1, 20, 0, 0, 0, 0, time.UTC)
+
+		next, ok := r.next(current, now, time.UTC)
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2022, 1, 24, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("stops-after-until", func(t *testing.T) {
+		r, _ := parseRecurrence("FREQ=MONTHLY;UNTIL=20220131")
+		current := time.Date(2022, 1, 10, 9, 0, 0, 0, time.UTC)
+
+		_, ok := r.next(current, current, time.UTC)
+		assert.False(t, ok)
+	})
+
+	t.Run("monthly-keeps-day", func(t *testing.T) {
+		r, _ := parseRecurrence("FREQ=MONTHLY")
+		current := time.Date(2022, 1, 31, 9, 0, 0, 0, time.UTC)
+
+		next, ok := r.next(current, current, time.UTC)
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2022, 3, 31, 9, 0, 0, 0, time.UTC), next)
+
+		next, ok = r.next(next, next, time.UTC)
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2022, 5, 31, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("monthly-interval-across-years", func(t *testing.T) {
+		r, _ := parseRecurrence("FREQ=MONTHLY;INTERVAL=5")
+		current := time.Date(2022, 1, 31, 9, 0, 0, 0, time.UTC)
+
+		// Jun 30, Nov 30, Apr 30, Sep 30 and Feb are skipped
+		next, ok := r.next(current, current, time.UTC)
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2024, 7, 31, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("yearly-leap-day", func(t *testing.T) {
+		r, _ := parseRecurrence("FREQ=YEARLY")
+		current := time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC)
+
+		next, ok := r.next(current, current, time.UTC)
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("catches-up-at-once", func(t *testing.T) {
+		r, _ := parseRecurrence("FREQ=HOURLY")
+		current := time.Date(1970, 1, 1, 9, 30, 0, 0, time.UTC)
+		now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+		next, ok := r.next(current, now, time.UTC)
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC), next)
+
+		r, _ = parseRecurrence("FREQ=DAILY;INTERVAL=3")
+		next, ok = r.next(current.In(berlin), now, berlin)
+		assert.True(t, ok)
+		assert.True(t, next.After(now) && next.Sub(now) <= 72*time.Hour, next)
+		assert.Equal(t, 10, next.In(berlin).Hour())
+	})
+}
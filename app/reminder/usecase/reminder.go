@@ -0,0 +1,310 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/httpclient"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// maxErrorLength keeps last_error within its column
+const maxErrorLength = 1000
+
+type reminderUsecase struct {
+	repo           model.ReminderRepository
+	noteRepo       model.NoteRepository
+	userRepo       model.UserRepository
+	channels       map[string]model.ReminderChannel
+	contextTimeout time.Duration
+	cfg            config.ReminderConfig
+}
+
+func NewReminderUsecase(repo model.ReminderRepository, noteRepo model.NoteRepository, userRepo model.UserRepository,
+	channels []model.ReminderChannel, timeout time.Duration, cfg config.ReminderConfig) model.ReminderUsecase {
+	u := &reminderUsecase{
+		repo:           repo,
+		noteRepo:       noteRepo,
+		userRepo:       userRepo,
+		channels:       make(map[string]model.ReminderChannel),
+		contextTimeout: timeout,
+		cfg:            cfg,
+	}
+
+	for _, channel := range channels {
+		u.channels[channel.Name()] = channel
+	}
+
+	return u
+}
+
+// Create expects RemindAt in UTC, Timezone is only used to keep the wall clock time of recurrences
+func (u *reminderUsecase) Create(c context.Context, m *model.Reminder) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if _, err := u.getNote(ctx, m.NoteID, m.UserID); err != nil {
+		return err
+	}
+
+	if _, ok := u.channels[m.Channel]; !ok {
+		return response.WrapError(fmt.Errorf("channel %s is not available", m.Channel), http.StatusBadRequest)
+	}
+
+	if m.Timezone == "" {
+		m.Timezone = "UTC"
+	}
+
+	if _, err := time.LoadLocation(m.Timezone); err != nil {
+		return response.WrapError(fmt.Errorf("unknown timezone %s", m.Timezone), http.StatusBadRequest)
+	}
+
+	if _, err := parseRecurrence(m.Recurrence); err != nil {
+		return response.WrapError(err, http.StatusBadRequest)
+	}
+
+	if err := u.checkTarget(ctx, m); err != nil {
+		return err
+	}
+
+	m.Status = model.ReminderPending
+	m.Attempts = 0
+	m.DueAt = m.RemindAt
+	m.LastError = ""
+
+	return u.repo.CreateReminder(ctx, m)
+}
+
+func (u *reminderUsecase) checkTarget(ctx context.Context, m *model.Reminder) error {
+	switch m.Channel {
+	case "webhook":
+		if err := httpclient.CheckURL(m.Target, u.cfg.WebhookAllowPrivate); err != nil {
+			return response.WrapError(fmt.Errorf("webhook target %s", err), http.StatusBadRequest)
+		}
+	case "email":
+		// the server only mails the account, it isn't a relay to any address
+		user, err := u.userRepo.GetUser(ctx, m.UserID)
+		if err != nil {
+			return err
+		}
+
+		if m.Target != "" && !strings.EqualFold(m.Target, user.Email) {
+			return response.WrapError(errors.New("email reminders are sent to the address of the account"),
+				http.StatusBadRequest)
+		}
+
+		m.Target = user.Email
+	case "event":
+		m.Target = ""
+	}
+
+	return nil
+}
+
+func (u *reminderUsecase) Fetch(c context.Context, noteID, userID int32) ([]model.Reminder, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if _, err := u.getNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FetchReminders(ctx, noteID)
+}
+
+func (u *reminderUsecase) Delete(c context.Context, id, noteID, userID int32) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if _, err := u.getNote(ctx, noteID, userID); err != nil {
+		return err
+	}
+
+	err := u.repo.DeleteReminder(ctx, id, noteID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return response.ErrNotFound
+	}
+
+	return err
+}
+
+func (u *reminderUsecase) FetchEvents(c context.Context, userID int32, unreadOnly bool) ([]model.ReminderEvent, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.repo.FetchEvents(ctx, userID, unreadOnly)
+}
+
+func (u *reminderUsecase) MarkEventRead(c context.Context, id, userID int32) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.repo.MarkEventRead(ctx, id, userID)
+}
+
+func (u *reminderUsecase) getNote(ctx context.Context, id, userID int32) (*model.Note, error) {
+	note, err := u.noteRepo.GetNote(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, response.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &note, nil
+}
+
+// DeliverDue delivers a batch of reminders which are due at `now` and returns the number of successful deliveries.
+// Every reminder is claimed before delivery, so several instances can share the same database
+func (u *reminderUsecase) DeliverDue(c context.Context, now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	reminders, err := u.repo.FetchDueReminders(ctx, now.UTC().Format(timeLayout), u.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+
+	for i := range reminders {
+		ok, err := u.deliver(c, &reminders[i], now)
+		if err != nil {
+			return delivered, err
+		}
+
+		if ok {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+// deliver sends a single reminder, the returned error is only about bookkeeping,
+// a failed delivery is scheduled for a retry instead
+func (u *reminderUsecase) deliver(c context.Context, reminder *model.Reminder, now time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout+u.cfg.WebhookTimeout)
+	defer cancel()
+
+	// hold the reminder while delivering, an instance dying midway makes it due again once the lease expires
+	lease := now.Add(u.cfg.WebhookTimeout + u.cfg.RetryBackoff).UTC().Format(timeLayout)
+
+	claimed, err := u.repo.ClaimReminder(ctx, reminder.ID, reminder.DueAt, lease)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	deliverErr := u.send(ctx, reminder)
+	if deliverErr == nil {
+		u.advance(reminder, now)
+	} else {
+		u.retry(reminder, now, deliverErr)
+	}
+
+	reminder.UpdatedAt = now.UTC().Format(timeLayout)
+	if err = u.repo.UpdateReminder(ctx, reminder); err != nil {
+		return false, err
+	}
+
+	return deliverErr == nil, nil
+}
+
+func (u *reminderUsecase) send(ctx context.Context, reminder *model.Reminder) error {
+	channel, ok := u.channels[reminder.Channel]
+	if !ok {
+		return fmt.Errorf("channel %s is not available", reminder.Channel)
+	}
+
+	note, err := u.noteRepo.GetNote(ctx, reminder.NoteID, reminder.UserID)
+	if err != nil {
+		return err
+	}
+
+	// trashed notes stay quiet
+	if note.IsTrashed == 1 {
+		return nil
+	}
+
+	if reminder.Channel != "email" {
+		return channel.Deliver(ctx, reminder, &note)
+	}
+
+	// the mails go to the current address of the account, whatever the reminder was created with
+	user, err := u.userRepo.GetUser(ctx, reminder.UserID)
+	if err != nil {
+		return err
+	}
+
+	mailed := *reminder
+	mailed.Target = user.Email
+
+	return channel.Deliver(ctx, &mailed, &note)
+}
+
+// advance moves a recurring reminder to its next occurrence, or marks it done
+func (u *reminderUsecase) advance(reminder *model.Reminder, now time.Time) {
+	reminder.Attempts = 0
+	reminder.LastError = ""
+	reminder.Status = model.ReminderDone
+
+	rule, err := parseRecurrence(reminder.Recurrence)
+	if err != nil || rule == nil {
+		return
+	}
+
+	loc, err := time.LoadLocation(reminder.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	current, err := time.Parse(timeLayout, reminder.RemindAt)
+	if err != nil {
+		return
+	}
+
+	next, ok := rule.next(current, now, loc)
+	if !ok {
+		return
+	}
+
+	reminder.Status = model.ReminderPending
+	reminder.RemindAt = next.Format(timeLayout)
+	reminder.DueAt = reminder.RemindAt
+}
+
+// retry schedules another attempt with exponential backoff, until MaxAttempts is reached
+func (u *reminderUsecase) retry(reminder *model.Reminder, now time.Time, err error) {
+	reminder.Attempts++
+	reminder.LastError = err.Error()
+
+	if len(reminder.LastError) > maxErrorLength {
+		reminder.LastError = reminder.LastError[:maxErrorLength]
+	}
+
+	if reminder.Attempts >= u.cfg.MaxAttempts {
+		lastError := reminder.LastError
+
+		// a recurring reminder gives up on this occurrence only
+		u.advance(reminder, now)
+		reminder.LastError = lastError
+
+		if reminder.Status == model.ReminderDone {
+			reminder.Status = model.ReminderFailed
+		}
+
+		return
+	}
+
+	backoff := u.cfg.RetryBackoff << (reminder.Attempts - 1)
+	reminder.DueAt = now.Add(backoff).UTC().Format(timeLayout)
+}
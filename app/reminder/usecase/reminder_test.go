@@ -0,0 +1,222 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/reminder/usecase"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var reminderCfg = config.ReminderConfig{
+	PollInterval:   time.Second,
+	RetryBackoff:   time.Minute,
+	WebhookTimeout: time.Second,
+	BatchSize:      10,
+	MaxAttempts:    3,
+}
+
+func mockChannel(name string) *mocks.ReminderChannel {
+	channel := new(mocks.ReminderChannel)
+	channel.On("Name").Return(name)
+
+	return channel
+}
+
+func TestCreate(t *testing.T) {
+	note := model.Note{ID: 1, UserID: 1, Type: "note"}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(mocks.ReminderRepository)
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(note, nil).Once()
+		mockUserRepo.On("GetUser", mock.Anything, int32(1)).Return(model.User{ID: 1, Email: "me@example.com"}, nil).Once()
+		mockRepo.On("CreateReminder", mock.Anything, mock.AnythingOfType("*model.Reminder")).Return(nil).Once()
+
+		u := usecase.NewReminderUsecase(mockRepo, mockNoteRepo, mockUserRepo,
+			[]model.ReminderChannel{mockChannel("email")}, time.Second, reminderCfg)
+
+		m := &model.Reminder{NoteID: 1, UserID: 1, RemindAt: "2022-01-31 09:00:00", Channel: "email"}
+		assert.NoError(t, u.Create(context.TODO(), m))
+		assert.Equal(t, "me@example.com", m.Target)
+		assert.Equal(t, "UTC", m.Timezone)
+		assert.Equal(t, model.ReminderPending, m.Status)
+		assert.Equal(t, m.RemindAt, m.DueAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, m := range map[string]*model.Reminder{
+			"unknown-channel":  {NoteID: 1, UserID: 1, Channel: "email"},
+			"unknown-timezone": {NoteID: 1, UserID: 1, Channel: "webhook", Timezone: "Mars/Olympus"},
+			"bad-recurrence":   {NoteID: 1, UserID: 1, Channel: "webhook", Recurrence: "FREQ=SECONDLY"},
+			"bad-target":       {NoteID: 1, UserID: 1, Channel: "webhook", Target: "ftp://example.com"},
+			"private-target":   {NoteID: 1, UserID: 1, Channel: "webhook", Target: "http://169.254.169.254/latest"},
+		} {
+			mockRepo := new(mocks.ReminderRepository)
+			mockNoteRepo := new(mocks.NoteRepository)
+			mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(note, nil).Once()
+
+			u := usecase.NewReminderUsecase(mockRepo, mockNoteRepo, new(mocks.UserRepository),
+				[]model.ReminderChannel{mockChannel("webhook")}, time.Second, reminderCfg)
+
+			code, _ := response.RespondError(u.Create(context.TODO(), m))
+			assert.Equal(t, 400, code, name)
+			mockRepo.AssertNotCalled(t, "CreateReminder", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("foreign-email", func(t *testing.T) {
+		mockRepo := new(mocks.ReminderRepository)
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(note, nil).Once()
+		mockUserRepo.On("GetUser", mock.Anything, int32(1)).Return(model.User{ID: 1, Email: "me@example.com"}, nil).Once()
+
+		u := usecase.NewReminderUsecase(mockRepo, mockNoteRepo, mockUserRepo,
+			[]model.ReminderChannel{mockChannel("email")}, time.Second, reminderCfg)
+
+		m := &model.Reminder{NoteID: 1, UserID: 1, Channel: "email", Target: "someone@example.org"}
+		code, _ := response.RespondError(u.Create(context.TODO(), m))
+		assert.Equal(t, 400, code)
+		mockRepo.AssertNotCalled(t, "CreateReminder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("note-not-found", func(t *testing.T) {
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(2)).Return(model.Note{}, sql.ErrNoRows).Once()
+
+		u := usecase.NewReminderUsecase(new(mocks.ReminderRepository), mockNoteRepo, new(mocks.UserRepository),
+			nil, time.Second, reminderCfg)
+
+		err := u.Create(context.TODO(), &model.Reminder{NoteID: 1, UserID: 2, Channel: "event"})
+		assert.ErrorIs(t, err, response.ErrNotFound)
+	})
+}
+
+func TestDeliverDue(t *testing.T) {
+	now := time.Date(2022, 1, 31, 9, 0, 30, 0, time.UTC)
+	nowTime := now.Format("2006-01-02 15:04:05")
+	note := model.Note{ID: 1, UserID: 1, Type: "note"}
+
+	dueReminder := func(recurrence string, attempts int) model.Reminder {
+		return model.Reminder{
+			ID: 1, NoteID: 1, UserID: 1, RemindAt: "2022-01-31 09:00:00", Timezone: "UTC", Recurrence: recurrence,
+			Channel: "webhook", Status: model.ReminderPending, Attempts: attempts, DueAt: "2022-01-31 09:00:00",
+		}
+	}
+
+	setup := func(reminder model.Reminder, claimed bool, deliverErr error) (
+		model.ReminderUsecase, *mocks.ReminderRepository, *mocks.ReminderChannel) {
+		mockRepo := new(mocks.ReminderRepository)
+		mockNoteRepo := new(mocks.NoteRepository)
+		channel := mockChannel("webhook")
+
+		mockRepo.On("FetchDueReminders", mock.Anything, nowTime, 10).Return([]model.Reminder{reminder}, nil).Once()
+		mockRepo.On("ClaimReminder", mock.Anything, int32(1), reminder.DueAt, "2022-01-31 09:01:31").
+			Return(claimed, nil).Once()
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(note, nil)
+		channel.On("Deliver", mock.Anything, mock.AnythingOfType("*model.Reminder"), mock.AnythingOfType("*model.Note")).
+			Return(deliverErr)
+
+		u := usecase.NewReminderUsecase(mockRepo, mockNoteRepo, new(mocks.UserRepository),
+			[]model.ReminderChannel{channel}, time.Second, reminderCfg)
+
+		return u, mockRepo, channel
+	}
+
+	t.Run("one-time-done", func(t *testing.T) {
+		u, mockRepo, _ := setup(dueReminder("", 0), true, nil)
+		mockRepo.On("UpdateReminder", mock.Anything, mock.MatchedBy(func(r *model.Reminder) bool {
+			return r.Status == model.ReminderDone && r.Attempts == 0
+		})).Return(nil).Once()
+
+		delivered, err := u.DeliverDue(context.TODO(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("recurring-advanced", func(t *testing.T) {
+		u, mockRepo, _ := setup(dueReminder("FREQ=DAILY", 0), true, nil)
+		mockRepo.On("UpdateReminder", mock.Anything, mock.MatchedBy(func(r *model.Reminder) bool {
+			return r.Status == model.ReminderPending && r.RemindAt == "2022-02-01 09:00:00" && r.DueAt == r.RemindAt
+		})).Return(nil).Once()
+
+		delivered, err := u.DeliverDue(context.TODO(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("retry-with-backoff", func(t *testing.T) {
+		u, mockRepo, _ := setup(dueReminder("", 1), true, errors.New("webhook responded with status 500"))
+		mockRepo.On("UpdateReminder", mock.Anything, mock.MatchedBy(func(r *model.Reminder) bool {
+			// second failure waits twice the backoff
+			return r.Status == model.ReminderPending && r.Attempts == 2 && r.DueAt == "2022-01-31 09:02:30" &&
+				r.LastError == "webhook responded with status 500"
+		})).Return(nil).Once()
+
+		delivered, err := u.DeliverDue(context.TODO(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("failed-after-max-attempts", func(t *testing.T) {
+		u, mockRepo, _ := setup(dueReminder("", 2), true, errors.New("timeout"))
+		mockRepo.On("UpdateReminder", mock.Anything, mock.MatchedBy(func(r *model.Reminder) bool {
+			return r.Status == model.ReminderFailed && r.LastError == "timeout"
+		})).Return(nil).Once()
+
+		_, err := u.DeliverDue(context.TODO(), now)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("claimed-by-other", func(t *testing.T) {
+		u, mockRepo, channel := setup(dueReminder("", 0), false, nil)
+
+		delivered, err := u.DeliverDue(context.TODO(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		channel.AssertNotCalled(t, "Deliver", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "UpdateReminder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("email-to-account", func(t *testing.T) {
+		reminder := dueReminder("", 0)
+		reminder.Channel, reminder.Target = "email", "someone@example.org"
+
+		mockRepo := new(mocks.ReminderRepository)
+		mockNoteRepo := new(mocks.NoteRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		channel := mockChannel("email")
+
+		mockRepo.On("FetchDueReminders", mock.Anything, nowTime, 10).Return([]model.Reminder{reminder}, nil).Once()
+		mockRepo.On("ClaimReminder", mock.Anything, int32(1), reminder.DueAt, mock.Anything).Return(true, nil).Once()
+		mockRepo.On("UpdateReminder", mock.Anything, mock.AnythingOfType("*model.Reminder")).Return(nil).Once()
+		mockNoteRepo.On("GetNote", mock.Anything, int32(1), int32(1)).Return(note, nil).Once()
+		mockUserRepo.On("GetUser", mock.Anything, int32(1)).Return(model.User{ID: 1, Email: "me@example.com"}, nil).Once()
+		channel.On("Deliver", mock.Anything, mock.MatchedBy(func(r *model.Reminder) bool {
+			return r.Target == "me@example.com"
+		}), mock.AnythingOfType("*model.Note")).Return(nil).Once()
+
+		u := usecase.NewReminderUsecase(mockRepo, mockNoteRepo, mockUserRepo,
+			[]model.ReminderChannel{channel}, time.Second, reminderCfg)
+
+		delivered, err := u.DeliverDue(context.TODO(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		channel.AssertExpectations(t)
+	})
+}
@@ -3,6 +3,7 @@ package response
 import (
 	"errors"
 	"net/http"
+	"time"
 )
 
 var (
@@ -73,3 +74,36 @@ func WrapError(err error, statusCode int) error {
 		StatusCode: statusCode,
 	}
 }
+
+type retryErr struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+// implements error interface
+func (e retryErr) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap Implements the errors.Unwrap interface
+func (e retryErr) Unwrap() error {
+	return e.Err
+}
+
+// TooManyRequests wraps err with the 429 status code and the delay the client waits before retrying
+func TooManyRequests(err error, retryAfter time.Duration) error {
+	return retryErr{
+		Err:        WrapError(err, http.StatusTooManyRequests),
+		RetryAfter: retryAfter,
+	}
+}
+
+// RetryAfter returns the delay of an error made by TooManyRequests
+func RetryAfter(err error) (time.Duration, bool) {
+	retryErr := &retryErr{}
+	if errors.As(err, retryErr) {
+		return retryErr.RetryAfter, true
+	}
+
+	return 0, false
+}
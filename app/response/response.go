@@ -34,3 +34,27 @@ func RespondLoginSuccess(token string) (int, Response) {
 func RespondEmpty() (int, string) {
 	return http.StatusNoContent, ""
 }
+
+// RespondPage returns a page of results along with the pagination details
+func RespondPage(msg string, results interface{}, count, page, pageSize int) (int, Response) {
+	resp := Response{
+		Success:  true,
+		Message:  msg,
+		Count:    &count,
+		PageSize: &pageSize,
+		Current:  &page,
+		Results:  results,
+	}
+
+	if page > 1 {
+		previous := page - 1
+		resp.Previous = &previous
+	}
+
+	if page*pageSize < count {
+		next := page + 1
+		resp.Next = &next
+	}
+
+	return http.StatusOK, resp
+}
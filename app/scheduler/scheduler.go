@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Job delivers whatever became due at `now`, like reminders or webhook events
+type Job interface {
+	DeliverDue(c context.Context, now time.Time) (int, error)
+}
+
+// Scheduler periodically runs a job
+type Scheduler struct {
+	name     string
+	job      Job
+	interval time.Duration
+}
+
+func NewScheduler(name string, job Job, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		name:     name,
+		job:      job,
+		interval: interval,
+	}
+}
+
+// Run blocks until ctx is canceled. Due work is read from the database on every tick,
+// so whatever became due while the server was down is delivered right after start
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	delivered, err := s.job.DeliverDue(ctx, time.Now())
+	if err != nil && ctx.Err() == nil {
+		logrus.Errorf("failed to deliver %s: %s", s.name, err)
+	}
+
+	if delivered > 0 {
+		logrus.Infof("delivered %d %s", delivered, s.name)
+	}
+}
@@ -2,26 +2,86 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"librenote/app/model"
-	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"librenote/app"
+	attachmentDelivery "librenote/app/attachment/delivery/http"
+	attachmentMysqlRepo "librenote/app/attachment/repository/mysql"
+	attachmentPgsqlRepo "librenote/app/attachment/repository/pgsql"
+	attachmentSqliteRepo "librenote/app/attachment/repository/sqlite"
+	attachmentUseCase "librenote/app/attachment/usecase"
+	auditDelivery "librenote/app/audit/delivery/http"
+	auditMysqlRepo "librenote/app/audit/repository/mysql"
+	auditPgsqlRepo "librenote/app/audit/repository/pgsql"
+	auditSqliteRepo "librenote/app/audit/repository/sqlite"
+	auditUseCase "librenote/app/audit/usecase"
+	"librenote/app/encryption"
+	encryptionMysqlRepo "librenote/app/encryption/repository/mysql"
+	encryptionPgsqlRepo "librenote/app/encryption/repository/pgsql"
+	encryptionSqliteRepo "librenote/app/encryption/repository/sqlite"
+	encryptionUseCase "librenote/app/encryption/usecase"
+	"librenote/app/event"
+	exportDelivery "librenote/app/export/delivery/http"
+	exportUseCase "librenote/app/export/usecase"
+	importDelivery "librenote/app/importer/delivery/http"
+	"librenote/app/importer/enex"
+	"librenote/app/importer/keep"
+	"librenote/app/importer/markdown"
+	"librenote/app/importer/standardnotes"
+	importUseCase "librenote/app/importer/usecase"
+	labelDelivery "librenote/app/label/delivery/http"
+	labelMysqlRepo "librenote/app/label/repository/mysql"
+	labelPgsqlRepo "librenote/app/label/repository/pgsql"
+	labelSqliteRepo "librenote/app/label/repository/sqlite"
+	labelUseCase "librenote/app/label/usecase"
+	noteDelivery "librenote/app/note/delivery/http"
+	noteEncryptedRepo "librenote/app/note/repository/encrypted"
+	noteMysqlRepo "librenote/app/note/repository/mysql"
+	notePgsqlRepo "librenote/app/note/repository/pgsql"
+	noteSqliteRepo "librenote/app/note/repository/sqlite"
+	noteUseCase "librenote/app/note/usecase"
+	"librenote/app/password"
+	reminderChannel "librenote/app/reminder/channel"
+	reminderDelivery "librenote/app/reminder/delivery/http"
+	reminderEncryptedRepo "librenote/app/reminder/repository/encrypted"
+	reminderMysqlRepo "librenote/app/reminder/repository/mysql"
+	reminderPgsqlRepo "librenote/app/reminder/repository/pgsql"
+	reminderSqliteRepo "librenote/app/reminder/repository/sqlite"
+	reminderUseCase "librenote/app/reminder/usecase"
+	"librenote/app/scheduler"
 	systemDelivery "librenote/app/system/delivery/http"
 	systemRepo "librenote/app/system/repository"
 	systemUseCase "librenote/app/system/usecase"
+	templateDelivery "librenote/app/template/delivery/http"
+	templateEncryptedRepo "librenote/app/template/repository/encrypted"
+	templateMysqlRepo "librenote/app/template/repository/mysql"
+	templatePgsqlRepo "librenote/app/template/repository/pgsql"
+	templateSqliteRepo "librenote/app/template/repository/sqlite"
+	templateUseCase "librenote/app/template/usecase"
 	userDelivery "librenote/app/user/delivery/http"
 	userMysqlRepo "librenote/app/user/repository/mysql"
 	userPgsqlRepo "librenote/app/user/repository/pgsql"
 	userSqliteRepo "librenote/app/user/repository/sqlite"
 	userUseCase "librenote/app/user/usecase"
+	webhookDelivery "librenote/app/webhook/delivery/http"
+	webhookEncryptedRepo "librenote/app/webhook/repository/encrypted"
+	webhookMysqlRepo "librenote/app/webhook/repository/mysql"
+	webhookPgsqlRepo "librenote/app/webhook/repository/pgsql"
+	webhookSqliteRepo "librenote/app/webhook/repository/sqlite"
+	webhookUseCase "librenote/app/webhook/usecase"
+	"librenote/infrastructure/certs"
 	"librenote/infrastructure/config"
 	"librenote/infrastructure/db"
+	"librenote/infrastructure/logger"
 	"librenote/infrastructure/middlewares"
+	"librenote/infrastructure/storage"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
@@ -40,12 +100,27 @@ func (s *Server) Serve() {
 		defer db.Close()
 	}
 
-	e := setupAPIServer(cfg)
+	e, schedulers := setupAPIServer(cfg)
+
+	schedulerCtx, stopSchedulers := context.WithCancel(context.Background())
+	defer stopSchedulers()
+
+	for _, s := range schedulers {
+		go s.Run(schedulerCtx)
+	}
+
+	tlsCerts, err := certs.New(config.Get().TLS)
+	if err != nil {
+		logrus.Errorln(err)
+		os.Exit(1)
+	}
+
+	redirect := redirectServer(cfg, tlsCerts)
 
 	go func() {
 		printBanner()
 
-		if err := e.Start(fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)); err != nil {
+		if err := start(e, fmt.Sprintf("%s:%d", cfg.Host, cfg.Port), tlsCerts); err != nil {
 			logrus.Errorf(err.Error())
 		}
 	}()
@@ -59,69 +134,291 @@ func (s *Server) Serve() {
 
 	<-sigCh
 	logrus.Info("shutting down the server...")
+	stopSchedulers()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if redirect != nil {
+		_ = redirect.Shutdown(ctx)
+	}
+
 	if err := e.Shutdown(ctx); err != nil {
 		logrus.Fatalf("failed to gracefully shutdown the server: %s", err)
 	}
 }
 
-func setupAPIServer(cfg config.AppConfig) *echo.Echo {
+// start serves over https when there are certificates
+func start(e *echo.Echo, addr string, tlsCerts *certs.Manager) error {
+	if tlsCerts == nil {
+		return e.Start(addr)
+	}
+
+	e.TLSServer.Addr = addr
+	e.TLSServer.TLSConfig = tlsCerts.TLSConfig()
+
+	return e.StartServer(e.TLSServer)
+}
+
+// redirectServer starts the http listener redirecting to https, nil when there is none
+func redirectServer(cfg config.AppConfig, tlsCerts *certs.Manager) *http.Server {
+	addr := config.Get().TLS.RedirectAddr
+	if tlsCerts == nil || addr == "" {
+		return nil
+	}
+
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      tlsCerts.HTTPHandler(cfg.Port),
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.Errorf("redirect listener: %s", err)
+		}
+	}()
+
+	return server
+}
+
+func setupAPIServer(cfg config.AppConfig) (*echo.Echo, []*scheduler.Scheduler) {
 	e := echo.New()
 	e.HideBanner = true
+	e.Logger.SetOutput(logrus.StandardLogger().WriterLevel(logrus.InfoLevel))
 	e.Server.ReadTimeout = cfg.ReadTimeout
 	e.Server.WriteTimeout = cfg.WriteTimeout
 	e.Server.IdleTimeout = cfg.IdleTimeout
-	contextTimeout := cfg.ContextTimeout
+	e.TLSServer.ReadTimeout = cfg.ReadTimeout
+	e.TLSServer.WriteTimeout = cfg.WriteTimeout
+	e.TLSServer.IdleTimeout = cfg.IdleTimeout
 
 	if err := middlewares.Attach(e); err != nil {
 		logrus.Errorln(err)
 		os.Exit(1)
 	}
 
+	u := NewUsecases(cfg)
+
+	// delivery
+	systemDelivery.NewSystemHandler(e, u.System)
+	userDelivery.NewUserHandler(e, u.User)
+	noteDelivery.NewNoteHandler(e, u.Note)
+	labelDelivery.NewLabelHandler(e, u.Label)
+	templateDelivery.NewTemplateHandler(e, u.Template)
+	reminderDelivery.NewReminderHandler(e, u.Reminder)
+	webhookDelivery.NewWebhookHandler(e, u.Webhook)
+	attachmentDelivery.NewAttachmentHandler(e, u.Attachment)
+	importDelivery.NewImportHandler(e, u.Import)
+	exportDelivery.NewExportHandler(e, u.Export)
+	auditDelivery.NewAuditHandler(e, u.Audit)
+
+	return e, []*scheduler.Scheduler{
+		scheduler.NewScheduler("reminders", u.Reminder, config.Get().Reminder.PollInterval),
+		scheduler.NewScheduler("webhook events", u.Webhook, config.Get().Webhook.PollInterval),
+	}
+}
+
+// Usecases are the use cases wired to the repositories of the configured database,
+// the commands share them with the API server
+type Usecases struct {
+	UserRepo   model.UserRepository
+	System     systemUseCase.SystemUsecase
+	User       model.UserUsecase
+	Note       model.NoteUsecase
+	Label      model.LabelUsecase
+	Template   model.TemplateUsecase
+	Reminder   model.ReminderUsecase
+	Webhook    model.WebhookUsecase
+	Attachment model.AttachmentUsecase
+	Import     model.ImportUsecase
+	Export     model.ExportUsecase
+	Audit      model.AuditUsecase
+}
+
+// NewUsecases expects the database to be connected
+func NewUsecases(cfg config.AppConfig) *Usecases {
 	dbClient := db.GetClient()
 	dbType := config.Get().Database.Type
+	contextTimeout := cfg.ContextTimeout
 
 	// repository
 	sysRepo := systemRepo.NewSystemRepository(dbClient)
 
 	var uRepo model.UserRepository
 
+	var nRepo model.NoteRepository
+
+	var rRepo model.ReminderRepository
+
+	var wRepo model.WebhookRepository
+
+	var aRepo model.AttachmentRepository
+
+	var lRepo model.LabelRepository
+
+	var tRepo model.TemplateRepository
+
+	var auRepo model.AuditRepository
+
 	switch dbType {
 	case "postgres":
 		uRepo = userPgsqlRepo.NewPgsqlUserRepository(dbClient)
+		nRepo = notePgsqlRepo.NewPgsqlNoteRepository(dbClient)
+		rRepo = reminderPgsqlRepo.NewPgsqlReminderRepository(dbClient)
+		wRepo = webhookPgsqlRepo.NewPgsqlWebhookRepository(dbClient)
+		aRepo = attachmentPgsqlRepo.NewPgsqlAttachmentRepository(dbClient)
+		lRepo = labelPgsqlRepo.NewPgsqlLabelRepository(dbClient)
+		tRepo = templatePgsqlRepo.NewPgsqlTemplateRepository(dbClient)
+		auRepo = auditPgsqlRepo.NewPgsqlAuditRepository(dbClient)
 	case "mysql":
 		uRepo = userMysqlRepo.NewMysqlUserRepository(dbClient)
+		nRepo = noteMysqlRepo.NewMysqlNoteRepository(dbClient)
+		rRepo = reminderMysqlRepo.NewMysqlReminderRepository(dbClient)
+		wRepo = webhookMysqlRepo.NewMysqlWebhookRepository(dbClient)
+		aRepo = attachmentMysqlRepo.NewMysqlAttachmentRepository(dbClient)
+		lRepo = labelMysqlRepo.NewMysqlLabelRepository(dbClient)
+		tRepo = templateMysqlRepo.NewMysqlTemplateRepository(dbClient)
+		auRepo = auditMysqlRepo.NewMysqlAuditRepository(dbClient)
 	default:
 		uRepo = userSqliteRepo.NewSqliteUserRepository(dbClient)
+		nRepo = noteSqliteRepo.NewSqliteNoteRepository(dbClient)
+		rRepo = reminderSqliteRepo.NewSqliteReminderRepository(dbClient)
+		wRepo = webhookSqliteRepo.NewSqliteWebhookRepository(dbClient)
+		aRepo = attachmentSqliteRepo.NewSqliteAttachmentRepository(dbClient)
+		lRepo = labelSqliteRepo.NewSqliteLabelRepository(dbClient)
+		tRepo = templateSqliteRepo.NewSqliteTemplateRepository(dbClient)
+		auRepo = auditSqliteRepo.NewSqliteAuditRepository(dbClient)
+	}
+
+	blobs := blobStore()
+
+	keyring, err := encryption.Load(config.Get().Encryption)
+	if err != nil {
+		logrus.Errorln(err)
+		os.Exit(1)
+	}
+
+	if keyring != nil {
+		nRepo = noteEncryptedRepo.NewNoteRepository(nRepo, keyring)
+		tRepo = templateEncryptedRepo.NewTemplateRepository(tRepo, keyring)
+		wRepo = webhookEncryptedRepo.NewWebhookRepository(wRepo, keyring)
+		rRepo = reminderEncryptedRepo.NewReminderRepository(rRepo, keyring)
+		blobs = encryption.NewBlobStore(blobs, keyring)
+	}
+
+	policy, err := password.NewPolicy(config.Get().Password)
+	if err != nil {
+		logrus.Errorln(err)
+		os.Exit(1)
+	}
+
+	hasher, err := password.NewHasher(config.Get().Password)
+	if err != nil {
+		logrus.Errorln(err)
+		os.Exit(1)
 	}
 
 	// use cases
-	sysUseCase := systemUseCase.NewSystemUsecase(sysRepo)
-	uUseCase := userUseCase.NewUserUsecase(uRepo, contextTimeout)
+	wUseCase := webhookUseCase.NewWebhookUsecase(wRepo, contextTimeout, config.Get().Webhook)
+	aUseCase := attachmentUseCase.NewAttachmentUsecase(aRepo, nRepo, blobs, contextTimeout, config.Get().Storage)
+	events := event.NewPublisher(wUseCase, aUseCase)
+	auUseCase := auditUseCase.NewAuditUsecase(auRepo, uRepo, contextTimeout)
+	nUseCase := noteUseCase.NewNoteUsecase(nRepo, uRepo, events, contextTimeout, cfg.MaxNoteRevisions,
+		cfg.RequestBodyLimitBytes)
 
-	// delivery
-	systemDelivery.NewSystemHandler(e, sysUseCase)
-	userDelivery.NewUserHandler(e, uUseCase)
+	return &Usecases{
+		UserRepo: uRepo,
+		System:   systemUseCase.NewSystemUsecase(sysRepo),
+		User: userUseCase.NewUserUsecase(uRepo, events, auUseCase, policy, hasher, contextTimeout,
+			config.Get().RateLimit),
+		Note:     nUseCase,
+		Label:    labelUseCase.NewLabelUsecase(lRepo, nRepo, events, contextTimeout),
+		Template: templateUseCase.NewTemplateUsecase(tRepo, nUseCase, lRepo, contextTimeout, cfg.DateFormat),
+		Reminder: reminderUseCase.NewReminderUsecase(rRepo, nRepo, uRepo, reminderChannels(rRepo), contextTimeout,
+			config.Get().Reminder),
+		Webhook:    wUseCase,
+		Attachment: aUseCase,
+		Import: importUseCase.NewImportUsecase(nUseCase, aUseCase, lRepo, contextTimeout, keep.NewImporter(),
+			markdown.NewImporter(), enex.NewImporter(), standardnotes.NewImporter()),
+		Export: exportUseCase.NewExportUsecase(uRepo, nRepo, lRepo, aRepo, rRepo, wRepo, blobs,
+			contextTimeout),
+		Audit: auUseCase,
+	}
+}
+
+// NewKeyRotation re-encrypts the data of the configured database and storage by the primary key of the keyring,
+// it expects the database to be connected
+func NewKeyRotation(cfg config.AppConfig, keyring *encryption.Keyring) model.KeyRotationUsecase {
+	dbClient := db.GetClient()
+
+	var repo model.KeyRotationRepository
 
-	return e
+	switch config.Get().Database.Type {
+	case "postgres":
+		repo = encryptionPgsqlRepo.NewPgsqlKeyRotationRepository(dbClient)
+	case "mysql":
+		repo = encryptionMysqlRepo.NewMysqlKeyRotationRepository(dbClient)
+	default:
+		repo = encryptionSqliteRepo.NewSqliteKeyRotationRepository(dbClient)
+	}
+
+	return encryptionUseCase.NewKeyRotationUsecase(repo, keyring, encryption.NewBlobStore(blobStore(), keyring),
+		cfg.ContextTimeout)
+}
+
+func reminderChannels(rRepo model.ReminderRepository) []model.ReminderChannel {
+	reminderCfg := config.Get().Reminder
+	channels := []model.ReminderChannel{
+		reminderChannel.NewWebhookChannel(reminderCfg.WebhookTimeout, reminderCfg.WebhookAllowPrivate),
+		reminderChannel.NewEventChannel(rRepo),
+	}
+
+	if mailCfg := config.Get().Mail; mailCfg.Host != "" {
+		channels = append(channels, reminderChannel.NewEmailChannel(mailCfg))
+	}
+
+	return channels
 }
 
+func blobStore() model.BlobStore {
+	storageCfg := config.Get().Storage
+
+	if storageCfg.Driver == "s3" {
+		s3Store, err := storage.NewS3Store(storageCfg.S3)
+		if err != nil {
+			logrus.Errorln(err)
+			os.Exit(1)
+		}
+
+		return s3Store
+	}
+
+	return storage.NewLocalStore(storageCfg.Path)
+}
+
+// printBanner prints the banner to the logs, or only logs the version when they're structured
 func printBanner() {
-	log.SetFlags(0)
-	log.Println("_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/")
-	log.Println("_/                                                                                          _/")
-	log.Println("_/                                                                                          _/")
-	log.Println("_/    _/        _/  _/                            _/      _/              _/                _/")
-	log.Println("_/     _/            _/_/_/    _/  _/_/    _/_/    _/_/    _/    _/_/    _/_/_/_/    _/_/   _/")
-	log.Println("_/    _/        _/  _/    _/  _/_/      _/_/_/_/  _/  _/  _/  _/    _/    _/      _/_/_/_/  _/")
-	log.Println("_/   _/        _/  _/    _/  _/        _/        _/    _/_/  _/    _/    _/      _/         _/")
-	log.Println("_/  _/_/_/_/  _/  _/_/_/    _/          _/_/_/  _/      _/    _/_/        _/_/    _/_/_/    _/")
-	log.Println("_/                                                                                          _/")
-	log.Println("_/                                                                                          _/")
-	log.Printf("_/                   Version: %-18s Build time: %-18s             _/", app.Version, app.BuildTime)
-	log.Println("_/                                                                                          _/")
-	log.Println("_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/")
+	if logger.IsJSON() {
+		logrus.WithFields(logrus.Fields{"version": app.Version, "build_time": app.BuildTime}).Info("starting")
+		return
+	}
+
+	out := logrus.StandardLogger().Out
+
+	fmt.Fprintln(out, "_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/")
+	fmt.Fprintln(out, "_/                                                                                          _/")
+	fmt.Fprintln(out, "_/                                                                                          _/")
+	fmt.Fprintln(out, "_/    _/        _/  _/                            _/      _/              _/                _/")
+	fmt.Fprintln(out, "_/     _/            _/_/_/    _/  _/_/    _/_/    _/_/    _/    _/_/    _/_/_/_/    _/_/   _/")
+	fmt.Fprintln(out, "_/    _/        _/  _/    _/  _/_/      _/_/_/_/  _/  _/  _/  _/    _/    _/      _/_/_/_/  _/")
+	fmt.Fprintln(out, "_/   _/        _/  _/    _/  _/        _/        _/    _/_/  _/    _/    _/      _/         _/")
+	fmt.Fprintln(out, "_/  _/_/_/_/  _/  _/_/_/    _/          _/_/_/  _/      _/    _/_/        _/_/    _/_/_/    _/")
+	fmt.Fprintln(out, "_/                                                                                          _/")
+	fmt.Fprintln(out, "_/                                                                                          _/")
+	fmt.Fprintf(out, "_/                   Version: %-18s Build time: %-18s             _/\n", app.Version, app.BuildTime)
+	fmt.Fprintln(out, "_/                                                                                          _/")
+	fmt.Fprintln(out, "_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/_/")
 }
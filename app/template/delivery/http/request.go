@@ -0,0 +1,13 @@
+package http
+
+type createTemplateReq struct {
+	// the note the template is made from
+	NoteID int32  `json:"note_id" validate:"required,min=1"`
+	Name   string `json:"name" validate:"required,max=100"`
+}
+
+type instantiateTemplateReq struct {
+	// values of the variables besides the built-in date and time, like {"project": "librenote"} for {{project}}
+	Variables map[string]string `json:"variables" validate:"omitempty,max=20,dive,keys,max=30,endkeys,max=1000"`
+	Timezone  string            `json:"timezone" validate:"omitempty,max=64"`
+}
@@ -0,0 +1,144 @@
+package http
+
+import (
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/app/validation"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TemplateHandler represent the http handler for template
+type TemplateHandler struct {
+	TUseCase model.TemplateUsecase
+}
+
+func NewTemplateHandler(e *echo.Echo, us model.TemplateUsecase) {
+	handler := &TemplateHandler{
+		TUseCase: us,
+	}
+
+	templates := e.Group("/api/v1/templates")
+	_ = middlewares.AttachJwtToGroup(templates)
+
+	templates.GET("", handler.FetchTemplates)
+	templates.POST("", handler.CreateTemplate)
+	templates.GET("/:id", handler.GetTemplate)
+	templates.DELETE("/:id", handler.DeleteTemplate)
+	templates.POST("/:id/notes", handler.InstantiateTemplate)
+}
+
+func (t *TemplateHandler) FetchTemplates(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	templates, err := t.TUseCase.Fetch(ctx, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", templates))
+}
+
+// CreateTemplate makes a template from a note of the user
+func (t *TemplateHandler) CreateTemplate(c echo.Context) error {
+	var tReq createTemplateReq
+
+	err := c.Bind(&tReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&tReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	template := model.Template{
+		Name:      tReq.Name,
+		UserID:    middlewares.GetUserID(c),
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+	}
+
+	ctx := c.Request().Context()
+
+	err = t.TUseCase.CreateFromNote(ctx, tReq.NoteID, &template)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("template created", template))
+}
+
+func (t *TemplateHandler) GetTemplate(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	template, err := t.TUseCase.Get(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", template))
+}
+
+func (t *TemplateHandler) DeleteTemplate(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	err = t.TUseCase.Delete(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// InstantiateTemplate creates a note from the template, replacing its variables
+func (t *TemplateHandler) InstantiateTemplate(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var tReq instantiateTemplateReq
+
+	err = c.Bind(&tReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&tReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	ctx := c.Request().Context()
+
+	note, err := t.TUseCase.Instantiate(ctx, id, middlewares.GetUserID(c), tReq.Variables, tReq.Timezone)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("note created", note))
+}
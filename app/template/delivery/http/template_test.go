@@ -0,0 +1,154 @@
+package http_test
+
+import (
+	"io"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	templateHttp "librenote/app/template/delivery/http"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var BaseURLV1 = "/api/v1"
+
+func buildEchoAuthorizedRequest(t *testing.T, method, path, token string, payload io.Reader) (
+	echo.Context, *httptest.ResponseRecorder) {
+	var req *http.Request
+
+	var err error
+
+	if payload != nil {
+		req, err = http.NewRequest(method, path, payload)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	} else {
+		req, err = http.NewRequest(method, path, nil)
+	}
+
+	assert.NoError(t, err)
+
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+	res := httptest.NewRecorder()
+	e := echo.New()
+	ctx := e.NewContext(req, res)
+
+	return ctx, res
+}
+
+// nolint:unparam
+func getToken(userID int32) string {
+	jwtCfg := config.Get().Jwt
+	claims := &middlewares.JwtCustomClaims{
+		UserID: userID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(jwtCfg.ExpireTime).Unix(),
+		},
+	}
+	unsignedToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token, _ := unsignedToken.SignedString([]byte(jwtCfg.SecretKey))
+
+	return token
+}
+
+func attachJWTMiddleware(hfc echo.HandlerFunc) echo.HandlerFunc {
+	mhfc := middleware.JWTWithConfig(
+		middleware.JWTConfig{
+			Claims:     &middlewares.JwtCustomClaims{},
+			SigningKey: []byte(config.Get().Jwt.SecretKey),
+		})(hfc)
+
+	return mhfc
+}
+
+func TestCreateTemplate(t *testing.T) {
+	endPoint := BaseURLV1 + "/templates"
+
+	mockUsecase := new(mocks.TemplateUsecase)
+	mockUsecase.On("CreateFromNote", mock.Anything, int32(3), mock.MatchedBy(func(m *model.Template) bool {
+		return m.UserID == 1 && m.Name == "weekly review"
+	})).Return(nil).Once()
+
+	handler := templateHttp.TemplateHandler{
+		TUseCase: mockUsecase,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		body := `{"note_id": 3, "name": "weekly review"}`
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+		handle := attachJWTMiddleware(handler.CreateTemplate)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"missing-note": `{"name": "weekly review"}`,
+			"missing-name": `{"note_id": 3}`,
+			"long-name":    `{"note_id": 3, "name": "` + strings.Repeat("a", 101) + `"}`,
+		} {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+			handle := attachJWTMiddleware(handler.CreateTemplate)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, http.StatusBadRequest, res.Code, name)
+		}
+	})
+}
+
+func TestInstantiateTemplate(t *testing.T) {
+	endPoint := BaseURLV1 + "/templates/:id/notes"
+
+	mockUsecase := new(mocks.TemplateUsecase)
+	mockUsecase.On("Instantiate", mock.Anything, int32(2), int32(1), map[string]string{"project": "librenote"},
+		"Europe/Paris").Return(&model.Note{ID: 5, UserID: 1, Type: "list"}, nil).Once()
+	mockUsecase.On("Instantiate", mock.Anything, int32(3), int32(1), map[string]string(nil), "").
+		Return(nil, response.ErrNotFound).Once()
+
+	handler := templateHttp.TemplateHandler{
+		TUseCase: mockUsecase,
+	}
+
+	for id, c := range map[string]struct {
+		body string
+		code int
+	}{
+		"2": {body: `{"variables": {"project": "librenote"}, "timezone": "Europe/Paris"}`, code: http.StatusOK},
+		"3": {body: `{}`, code: http.StatusNotFound},
+	} {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(c.body))
+		ctx.SetParamNames("id")
+		ctx.SetParamValues(id)
+		handle := attachJWTMiddleware(handler.InstantiateTemplate)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, c.code, res.Code, id)
+	}
+
+	mockUsecase.AssertExpectations(t)
+
+	t.Run("invalid", func(t *testing.T) {
+		body := `{"variables": {"` + strings.Repeat("a", 31) + `": "librenote"}}`
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("2")
+		handle := attachJWTMiddleware(handler.InstantiateTemplate)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+}
@@ -0,0 +1,117 @@
+package encrypted
+
+import (
+	"context"
+	"librenote/app/encryption"
+	"librenote/app/model"
+)
+
+// templateRepository encrypts the titles, the bodies and the texts of the items of the templates stored by another
+// repository, and decrypts them back on reading. The names stay in clear, they are unique by user
+type templateRepository struct {
+	model.TemplateRepository
+	keyring *encryption.Keyring
+}
+
+// NewTemplateRepository wraps the repository of any database
+func NewTemplateRepository(repo model.TemplateRepository, keyring *encryption.Keyring) model.TemplateRepository {
+	return &templateRepository{
+		TemplateRepository: repo,
+		keyring:            keyring,
+	}
+}
+
+func (r *templateRepository) CreateTemplate(ctx context.Context, template *model.Template) error {
+	encTemplate, err := r.encrypt(template)
+	if err != nil {
+		return err
+	}
+
+	if err = r.TemplateRepository.CreateTemplate(ctx, encTemplate); err != nil {
+		return err
+	}
+
+	template.ID = encTemplate.ID
+
+	return nil
+}
+
+// encrypt returns an encrypted copy, the template is kept as it is for the caller
+func (r *templateRepository) encrypt(template *model.Template) (*model.Template, error) {
+	encTemplate := *template
+
+	var err error
+
+	if template.Title != nil {
+		title, err := r.keyring.EncryptString(*template.Title)
+		if err != nil {
+			return nil, err
+		}
+
+		encTemplate.Title = &title
+	}
+
+	if encTemplate.Body, err = r.keyring.EncryptString(template.Body); err != nil {
+		return nil, err
+	}
+
+	encTemplate.Items = make([]model.SnapshotItem, len(template.Items))
+
+	for i, item := range template.Items {
+		encTemplate.Items[i] = item
+
+		if encTemplate.Items[i].Text, err = r.keyring.EncryptString(item.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	return &encTemplate, nil
+}
+
+func (r *templateRepository) decrypt(template *model.Template) error {
+	if template.Title != nil {
+		title, err := r.keyring.DecryptString(*template.Title)
+		if err != nil {
+			return err
+		}
+
+		template.Title = &title
+	}
+
+	var err error
+	if template.Body, err = r.keyring.DecryptString(template.Body); err != nil {
+		return err
+	}
+
+	for i := range template.Items {
+		if template.Items[i].Text, err = r.keyring.DecryptString(template.Items[i].Text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *templateRepository) GetTemplate(ctx context.Context, id, userID int32) (model.Template, error) {
+	template, err := r.TemplateRepository.GetTemplate(ctx, id, userID)
+	if err != nil {
+		return template, err
+	}
+
+	return template, r.decrypt(&template)
+}
+
+func (r *templateRepository) FetchTemplates(ctx context.Context, userID int32) ([]model.Template, error) {
+	templates, err := r.TemplateRepository.FetchTemplates(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range templates {
+		if err = r.decrypt(&templates[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return templates, nil
+}
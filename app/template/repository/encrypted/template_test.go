@@ -0,0 +1,90 @@
+package encrypted_test
+
+import (
+	"context"
+	"librenote/app/encryption"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/template/repository/encrypted"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func newKeyring(t *testing.T) *encryption.Keyring {
+	key, err := encryption.GenerateKey()
+	assert.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(key)
+	assert.NoError(t, err)
+
+	return keyring
+}
+
+func TestCreateTemplate(t *testing.T) {
+	keyring := newKeyring(t)
+	template := &model.Template{
+		UserID: 1, Name: "groceries", Title: strPtr("Groceries"), Body: "on {{date}}", Type: "list",
+		Items: []model.SnapshotItem{{Text: "milk", IsChecked: 1}},
+	}
+
+	var stored *model.Template
+
+	mockRepo := new(mocks.TemplateRepository)
+	mockRepo.On("CreateTemplate", mock.Anything, mock.AnythingOfType("*model.Template")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*model.Template)
+		stored.ID = 4
+	}).Return(nil).Once()
+
+	r := encrypted.NewTemplateRepository(mockRepo, keyring)
+	assert.NoError(t, r.CreateTemplate(context.TODO(), template))
+	mockRepo.AssertExpectations(t)
+
+	assert.True(t, keyring.IsCurrent(*stored.Title))
+	assert.True(t, keyring.IsCurrent(stored.Body))
+	assert.True(t, keyring.IsCurrent(stored.Items[0].Text))
+	assert.Equal(t, int8(1), stored.Items[0].IsChecked)
+	assert.Equal(t, "groceries", stored.Name)
+
+	// the caller's template is kept in clear
+	assert.Equal(t, "Groceries", *template.Title)
+	assert.Equal(t, "milk", template.Items[0].Text)
+	assert.Equal(t, int32(4), template.ID)
+}
+
+func TestFetchTemplates(t *testing.T) {
+	keyring := newKeyring(t)
+
+	title, err := keyring.EncryptString("Groceries")
+	assert.NoError(t, err)
+
+	text, err := keyring.EncryptString("milk")
+	assert.NoError(t, err)
+
+	mockRepo := new(mocks.TemplateRepository)
+	mockRepo.On("FetchTemplates", mock.Anything, int32(1)).Return([]model.Template{
+		{ID: 4, Title: &title, Body: "stored in clear", Items: []model.SnapshotItem{{Text: text}}},
+		{ID: 5},
+	}, nil).Once()
+
+	r := encrypted.NewTemplateRepository(mockRepo, keyring)
+
+	templates, err := r.FetchTemplates(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Groceries", *templates[0].Title)
+	assert.Equal(t, "stored in clear", templates[0].Body)
+	assert.Equal(t, "milk", templates[0].Items[0].Text)
+	assert.Nil(t, templates[1].Title)
+
+	// another keyring can't read the templates
+	mockRepo.On("GetTemplate", mock.Anything, int32(4), int32(1)).Return(model.Template{ID: 4, Title: &title}, nil).
+		Once()
+
+	_, err = encrypted.NewTemplateRepository(mockRepo, newKeyring(t)).GetTemplate(context.TODO(), 4, 1)
+	assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+}
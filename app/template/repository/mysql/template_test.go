@@ -0,0 +1,120 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"librenote/app/model"
+	templateRepo "librenote/app/template/repository/mysql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var templateColumns = []string{"id", "user_id", "name", "title", "body", "color", "type", "items", "drawing",
+	"created_at", "updated_at"}
+
+func TestCreateTemplate(t *testing.T) {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	title := "Groceries of {{date}}"
+	m := &model.Template{
+		UserID:    1,
+		Name:      "groceries",
+		Title:     &title,
+		Type:      "list",
+		Items:     []model.SnapshotItem{{Text: "milk"}},
+		LabelIDs:  []int32{4, 5},
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO templates").
+		WithArgs(m.UserID, m.Name, m.Title, m.Body, m.Color, m.Type, `[{"text":"milk","is_checked":0}]`, nil,
+			m.CreatedAt, m.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("INSERT INTO templates_labels").WithArgs(int64(2), int32(4)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO templates_labels").WithArgs(int64(2), int32(5)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tr := templateRepo.NewMysqlTemplateRepository(db)
+	assert.NoError(t, tr.CreateTemplate(context.TODO(), m))
+	assert.Equal(t, int32(2), m.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTemplate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM templates WHERE id = \\? AND user_id = \\?").WithArgs(int32(2), int32(1)).
+			WillReturnRows(sqlmock.NewRows(templateColumns).AddRow(2, 1, "groceries", "Groceries", "", "green", "list",
+				`[{"text":"milk","is_checked":1}]`, nil, nowTime, nowTime))
+		mock.ExpectQuery("SELECT tl.label_id FROM templates_labels").WithArgs(int32(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"label_id"}).AddRow(5).AddRow(4))
+
+		tr := templateRepo.NewMysqlTemplateRepository(db)
+		template, err := tr.GetTemplate(context.TODO(), 2, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "groceries", template.Name)
+		assert.Equal(t, []model.SnapshotItem{{Text: "milk", IsChecked: 1}}, template.Items)
+		assert.Nil(t, template.Drawing)
+		assert.Equal(t, []int32{5, 4}, template.LabelIDs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM templates WHERE id = \\? AND user_id = \\?").WithArgs(int32(2), int32(3)).
+			WillReturnRows(sqlmock.NewRows(templateColumns))
+
+		tr := templateRepo.NewMysqlTemplateRepository(db)
+		_, err := tr.GetTemplate(context.TODO(), 2, 3)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteTemplate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM templates").WithArgs(int32(2), int32(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectExec("DELETE FROM templates_labels").WithArgs(int32(2)).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("DELETE FROM templates WHERE").WithArgs(int32(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tr := templateRepo.NewMysqlTemplateRepository(db)
+		assert.NoError(t, tr.DeleteTemplate(context.TODO(), 2, 1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not-owner", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM templates").WithArgs(int32(2), int32(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		tr := templateRepo.NewMysqlTemplateRepository(db)
+		assert.ErrorIs(t, tr.DeleteTemplate(context.TODO(), 2, 3), sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
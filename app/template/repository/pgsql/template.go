@@ -0,0 +1,209 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"librenote/app/model"
+)
+
+type templateRepository struct {
+	db *sql.DB
+}
+
+func NewPgsqlTemplateRepository(db *sql.DB) model.TemplateRepository {
+	return &templateRepository{
+		db: db,
+	}
+}
+
+const createTemplate = `INSERT INTO templates (
+  user_id, name, title, body, color, type, items, drawing, created_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id
+`
+
+const addTemplateLabel = `INSERT INTO templates_labels (template_id, label_id) VALUES ($1, $2)`
+
+func (r *templateRepository) CreateTemplate(ctx context.Context, template *model.Template) error {
+	items, err := json.Marshal(template.Items)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	var id int32
+
+	err = tx.QueryRowContext(ctx, createTemplate,
+		template.UserID,
+		template.Name,
+		template.Title,
+		template.Body,
+		template.Color,
+		template.Type,
+		string(items),
+		template.Drawing,
+		template.CreatedAt,
+		template.UpdatedAt,
+	).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	for _, labelID := range template.LabelIDs {
+		if _, err = tx.ExecContext(ctx, addTemplateLabel, id, labelID); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	template.ID = id
+
+	return nil
+}
+
+const templateColumns = `id, user_id, name, title, body, COALESCE(color, ''), type, items, drawing, created_at::text,
+updated_at::text`
+
+const getTemplate = `SELECT ` + templateColumns + ` FROM templates WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+func (r *templateRepository) GetTemplate(ctx context.Context, id, userID int32) (model.Template, error) {
+	rows, err := r.db.QueryContext(ctx, getTemplate, id, userID)
+	if err != nil {
+		return model.Template{}, err
+	}
+
+	templates, err := scanTemplates(rows)
+	if err != nil {
+		return model.Template{}, err
+	}
+
+	if len(templates) == 0 {
+		return model.Template{}, sql.ErrNoRows
+	}
+
+	err = r.fetchLabelIDs(ctx, templates)
+
+	return templates[0], err
+}
+
+const fetchTemplates = `SELECT ` + templateColumns + ` FROM templates WHERE user_id = $1 ORDER BY name, id`
+
+func (r *templateRepository) FetchTemplates(ctx context.Context, userID int32) ([]model.Template, error) {
+	rows, err := r.db.QueryContext(ctx, fetchTemplates, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := scanTemplates(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, r.fetchLabelIDs(ctx, templates)
+}
+
+func scanTemplates(rows *sql.Rows) ([]model.Template, error) {
+	defer rows.Close()
+
+	templates := make([]model.Template, 0)
+
+	for rows.Next() {
+		var (
+			i     model.Template
+			items string
+		)
+
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Title,
+			&i.Body,
+			&i.Color,
+			&i.Type,
+			&items,
+			&i.Drawing,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(items), &i.Items); err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, i)
+	}
+
+	return templates, rows.Err()
+}
+
+const fetchTemplateLabels = `SELECT tl.label_id FROM templates_labels tl JOIN labels l ON l.id = tl.label_id
+WHERE tl.template_id = $1 ORDER BY l.name, l.id`
+
+func (r *templateRepository) fetchLabelIDs(ctx context.Context, templates []model.Template) error {
+	for idx := range templates {
+		rows, err := r.db.QueryContext(ctx, fetchTemplateLabels, templates[idx].ID)
+		if err != nil {
+			return err
+		}
+
+		templates[idx].LabelIDs = make([]int32, 0)
+
+		for rows.Next() {
+			var labelID int32
+			if err = rows.Scan(&labelID); err != nil {
+				rows.Close()
+
+				return err
+			}
+
+			templates[idx].LabelIDs = append(templates[idx].LabelIDs, labelID)
+		}
+
+		rows.Close()
+
+		if err = rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const getTemplateID = `SELECT id FROM templates WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+const deleteTemplateLabels = `DELETE FROM templates_labels WHERE template_id = $1`
+
+const deleteTemplate = `DELETE FROM templates WHERE id = $1`
+
+func (r *templateRepository) DeleteTemplate(ctx context.Context, id, userID int32) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	if err = tx.QueryRowContext(ctx, getTemplateID, id, userID).Scan(&id); err != nil {
+		return err
+	}
+
+	for _, query := range []string{deleteTemplateLabels, deleteTemplate} {
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"librenote/app/model"
+	"librenote/app/response"
+	"net/http"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+// the sizes the notes accept
+const (
+	maxTitleLength = 255
+	maxBodyLength  = 100000
+	maxItemLength  = 1000
+)
+
+// timeFormat formats the time variable, hh:mm
+const timeFormat = "15:04"
+
+// variablePattern matches the variables like {{date}} or {{ project_name }}
+var variablePattern = regexp.MustCompile(`\{\{\s*([a-z][a-z0-9_]*)\s*\}\}`)
+
+type templateUsecase struct {
+	repo           model.TemplateRepository
+	notes          model.NoteUsecase
+	labelRepo      model.LabelRepository
+	contextTimeout time.Duration
+	dateFormat     string
+}
+
+// NewTemplateUsecase notes are created through the note usecase, so they are checked, revisioned and published as
+// the notes created through the API. dateFormat formats the date variable
+func NewTemplateUsecase(repo model.TemplateRepository, notes model.NoteUsecase, labelRepo model.LabelRepository,
+	timeout time.Duration, dateFormat string) model.TemplateUsecase {
+	return &templateUsecase{
+		repo:           repo,
+		notes:          notes,
+		labelRepo:      labelRepo,
+		contextTimeout: timeout,
+		dateFormat:     dateFormat,
+	}
+}
+
+func (u *templateUsecase) CreateFromNote(c context.Context, noteID int32, m *model.Template) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	note, err := u.notes.Get(ctx, noteID, m.UserID)
+	if err != nil {
+		if errors.Is(err, response.ErrNotFound) {
+			return response.WrapError(errors.New("note not found"), http.StatusBadRequest)
+		}
+
+		return err
+	}
+
+	// the server can't read the content to replace the variables
+	if note.Encrypted != nil {
+		return response.WrapError(errors.New("an encrypted note can't be a template"), http.StatusBadRequest)
+	}
+
+	templates, err := u.repo.FetchTemplates(ctx, m.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, template := range templates {
+		if template.Name == m.Name {
+			return response.ErrConflict
+		}
+	}
+
+	labelIDs, err := u.labelRepo.FetchNoteLabelIDs(ctx, note.ID, m.UserID)
+	if err != nil {
+		return err
+	}
+
+	m.Title = note.Title
+	m.Body = note.Body
+	m.Color = note.Color
+	m.Type = note.Type
+	m.Drawing = note.Drawing
+	m.Items = make([]model.SnapshotItem, 0, len(note.Items))
+	m.LabelIDs = labelIDs
+
+	for _, item := range note.Items {
+		var text string
+		if item.Text != nil {
+			text = *item.Text
+		}
+
+		m.Items = append(m.Items, model.SnapshotItem{Text: text, IsChecked: item.IsChecked})
+	}
+
+	return u.repo.CreateTemplate(ctx, m)
+}
+
+func (u *templateUsecase) Fetch(c context.Context, userID int32) ([]model.Template, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.repo.FetchTemplates(ctx, userID)
+}
+
+func (u *templateUsecase) Get(c context.Context, id, userID int32) (*model.Template, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.getTemplate(ctx, id, userID)
+}
+
+func (u *templateUsecase) getTemplate(ctx context.Context, id, userID int32) (*model.Template, error) {
+	template, err := u.repo.GetTemplate(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, response.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (u *templateUsecase) Delete(c context.Context, id, userID int32) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	err := u.repo.DeleteTemplate(ctx, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return response.ErrNotFound
+	}
+
+	return err
+}
+
+func (u *templateUsecase) Instantiate(c context.Context, id, userID int32, variables map[string]string,
+	timezone string) (*model.Note, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, response.WrapError(fmt.Errorf("unknown timezone %s", timezone), http.StatusBadRequest)
+	}
+
+	template, err := u.getTemplate(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	values := map[string]string{
+		"date": now.In(loc).Format(u.dateFormat),
+		"time": now.In(loc).Format(timeFormat),
+	}
+
+	for name, value := range variables {
+		values[name] = value
+	}
+
+	nowTime := now.UTC().Format("2006-01-02 15:04:05")
+	note := &model.Note{
+		UserID:    userID,
+		Color:     template.Color,
+		Type:      template.Type,
+		Drawing:   template.Drawing,
+		Items:     make([]model.NotesItem, 0, len(template.Items)),
+		LabelIDs:  template.LabelIDs,
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+	}
+
+	if template.Title != nil {
+		title := replaceVariables(*template.Title, values)
+		if err = checkLength("title", title, maxTitleLength); err != nil {
+			return nil, err
+		}
+
+		note.Title = &title
+	}
+
+	note.Body = replaceVariables(template.Body, values)
+	if err = checkLength("body", note.Body, maxBodyLength); err != nil {
+		return nil, err
+	}
+
+	for _, item := range template.Items {
+		text := replaceVariables(item.Text, values)
+		if err = checkLength("item", text, maxItemLength); err != nil {
+			return nil, err
+		}
+
+		note.Items = append(note.Items, model.NotesItem{Text: &text, IsChecked: item.IsChecked, CreatedAt: nowTime})
+	}
+
+	// the labels are stored along with the note
+	if err = u.notes.Create(ctx, note); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// replaceVariables replaces the variables having a value, the others are kept as they are
+func replaceVariables(s string, values map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(s, func(variable string) string {
+		if value, ok := values[variablePattern.FindStringSubmatch(variable)[1]]; ok {
+			return value
+		}
+
+		return variable
+	})
+}
+
+func checkLength(field, s string, max int) error {
+	if utf8.RuneCountInString(s) > max {
+		return response.WrapError(fmt.Errorf("the %s is longer than %d characters once the variables are replaced",
+			field, max), http.StatusBadRequest)
+	}
+
+	return nil
+}
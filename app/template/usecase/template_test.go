@@ -0,0 +1,195 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	"librenote/app/template/usecase"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func statusCode(err error) int {
+	code, _ := response.RespondError(err)
+
+	return code
+}
+
+func mockTemplate() model.Template {
+	return model.Template{
+		ID:     2,
+		UserID: 1,
+		Name:   "release",
+		Title:  strPtr("Release of {{ project }} on {{date}}"),
+		Body:   "Started at {{time}}, {{unknown}} stays",
+		Color:  "green",
+		Type:   "list",
+		Items: []model.SnapshotItem{
+			{Text: "tag {{project}}"},
+			{Text: "announce", IsChecked: 1},
+		},
+		LabelIDs: []int32{4, 5},
+	}
+}
+
+func TestCreateFromNote(t *testing.T) {
+	note := &model.Note{
+		ID:     3,
+		UserID: 1,
+		Title:  strPtr("Groceries"),
+		Color:  "green",
+		Type:   "list",
+		Items:  []model.NotesItem{{ID: 1, Text: strPtr("milk"), IsChecked: 1}, {ID: 2, Text: strPtr("eggs")}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockNotes := new(mocks.NoteUsecase)
+		mockNotes.On("Get", mock.Anything, int32(3), int32(1)).Return(note, nil).Once()
+
+		mockLabelRepo := new(mocks.LabelRepository)
+		mockLabelRepo.On("FetchNoteLabelIDs", mock.Anything, int32(3), int32(1)).Return([]int32{5, 6}, nil).Once()
+
+		mockRepo := new(mocks.TemplateRepository)
+		mockRepo.On("FetchTemplates", mock.Anything, int32(1)).Return([]model.Template{mockTemplate()}, nil).Once()
+		mockRepo.On("CreateTemplate", mock.Anything, mock.AnythingOfType("*model.Template")).Return(nil).Once()
+
+		u := usecase.NewTemplateUsecase(mockRepo, mockNotes, mockLabelRepo, time.Second*2, "2006-01-02")
+
+		template := &model.Template{UserID: 1, Name: "groceries"}
+		assert.NoError(t, u.CreateFromNote(context.TODO(), 3, template))
+		assert.Equal(t, "Groceries", *template.Title)
+		assert.Equal(t, "list", template.Type)
+		assert.Equal(t, []model.SnapshotItem{{Text: "milk", IsChecked: 1}, {Text: "eggs"}}, template.Items)
+		assert.Equal(t, []int32{5, 6}, template.LabelIDs)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("name-taken", func(t *testing.T) {
+		mockNotes := new(mocks.NoteUsecase)
+		mockNotes.On("Get", mock.Anything, int32(3), int32(1)).Return(note, nil).Once()
+
+		mockRepo := new(mocks.TemplateRepository)
+		mockRepo.On("FetchTemplates", mock.Anything, int32(1)).Return([]model.Template{mockTemplate()}, nil).Once()
+
+		u := usecase.NewTemplateUsecase(mockRepo, mockNotes, new(mocks.LabelRepository), time.Second*2, "2006-01-02")
+
+		err := u.CreateFromNote(context.TODO(), 3, &model.Template{UserID: 1, Name: "release"})
+		assert.ErrorIs(t, err, response.ErrConflict)
+		mockRepo.AssertNotCalled(t, "CreateTemplate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing-note", func(t *testing.T) {
+		mockNotes := new(mocks.NoteUsecase)
+		mockNotes.On("Get", mock.Anything, int32(9), int32(1)).Return(nil, response.ErrNotFound).Once()
+
+		mockRepo := new(mocks.TemplateRepository)
+		u := usecase.NewTemplateUsecase(mockRepo, mockNotes, new(mocks.LabelRepository), time.Second*2, "2006-01-02")
+
+		err := u.CreateFromNote(context.TODO(), 9, &model.Template{UserID: 1, Name: "groceries"})
+		assert.Equal(t, http.StatusBadRequest, statusCode(err))
+		mockRepo.AssertNotCalled(t, "CreateTemplate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("encrypted-note", func(t *testing.T) {
+		encrypted := &model.Note{ID: 4, UserID: 1, Type: "note", Encrypted: &model.EncryptedContent{
+			Ciphertext: "c2VjcmV0", Algorithm: "AES-GCM", KeyVersion: 1,
+		}}
+
+		mockNotes := new(mocks.NoteUsecase)
+		mockNotes.On("Get", mock.Anything, int32(4), int32(1)).Return(encrypted, nil).Once()
+
+		mockRepo := new(mocks.TemplateRepository)
+		u := usecase.NewTemplateUsecase(mockRepo, mockNotes, new(mocks.LabelRepository), time.Second*2, "2006-01-02")
+
+		err := u.CreateFromNote(context.TODO(), 4, &model.Template{UserID: 1, Name: "secret"})
+		assert.Equal(t, http.StatusBadRequest, statusCode(err))
+		mockRepo.AssertNotCalled(t, "CreateTemplate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestInstantiate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(mocks.TemplateRepository)
+		mockRepo.On("GetTemplate", mock.Anything, int32(2), int32(1)).Return(mockTemplate(), nil).Once()
+
+		var created *model.Note
+
+		mockNotes := new(mocks.NoteUsecase)
+		mockNotes.On("Create", mock.Anything, mock.AnythingOfType("*model.Note")).Run(func(args mock.Arguments) {
+			created = args.Get(1).(*model.Note)
+			created.ID = 7
+		}).Return(nil).Once()
+
+		mockLabelRepo := new(mocks.LabelRepository)
+		u := usecase.NewTemplateUsecase(mockRepo, mockNotes, mockLabelRepo, time.Second*2, "02/01/2006")
+
+		note, err := u.Instantiate(context.TODO(), 2, 1, map[string]string{"project": "librenote"}, "Asia/Tokyo")
+		assert.NoError(t, err)
+		assert.Same(t, created, note)
+
+		now := time.Now().In(time.FixedZone("JST", 9*60*60))
+		assert.Equal(t, "Release of librenote on "+now.Format("02/01/2006"), *note.Title)
+		assert.Regexp(t, `^Started at \d\d:\d\d, \{\{unknown\}\} stays$`, note.Body)
+		assert.Equal(t, "tag librenote", *note.Items[0].Text)
+		assert.Equal(t, int8(1), note.Items[1].IsChecked)
+		assert.Equal(t, "green", note.Color)
+		assert.Equal(t, "list", note.Type)
+		// the labels are created along with the note
+		assert.Equal(t, []int32{4, 5}, note.LabelIDs)
+		mockLabelRepo.AssertNotCalled(t, "AddNoteLabel", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		long := mockTemplate()
+		long.Title = strPtr("{{project}}")
+
+		cases := map[string]struct {
+			template  model.Template
+			err       error
+			variables map[string]string
+			timezone  string
+			code      int
+		}{
+			"missing":          {err: sql.ErrNoRows, code: http.StatusNotFound},
+			"unknown-timezone": {template: mockTemplate(), timezone: "Mars/Olympus", code: http.StatusBadRequest},
+			"long-title": {template: long, variables: map[string]string{"project": strings.Repeat("a", 256)},
+				code: http.StatusBadRequest},
+		}
+
+		for name, c := range cases {
+			mockRepo := new(mocks.TemplateRepository)
+			mockRepo.On("GetTemplate", mock.Anything, int32(2), int32(1)).Return(c.template, c.err).Maybe()
+
+			mockNotes := new(mocks.NoteUsecase)
+			u := usecase.NewTemplateUsecase(mockRepo, mockNotes, new(mocks.LabelRepository), time.Second*2,
+				"2006-01-02")
+
+			_, err := u.Instantiate(context.TODO(), 2, 1, c.variables, c.timezone)
+			assert.Equal(t, c.code, statusCode(err), name)
+			mockNotes.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockRepo := new(mocks.TemplateRepository)
+	mockRepo.On("DeleteTemplate", mock.Anything, int32(2), int32(1)).Return(nil).Once()
+	mockRepo.On("DeleteTemplate", mock.Anything, int32(3), int32(1)).Return(sql.ErrNoRows).Once()
+
+	u := usecase.NewTemplateUsecase(mockRepo, new(mocks.NoteUsecase), new(mocks.LabelRepository), time.Second*2,
+		"2006-01-02")
+
+	assert.NoError(t, u.Delete(context.TODO(), 2, 1))
+	assert.ErrorIs(t, u.Delete(context.TODO(), 3, 1), response.ErrNotFound)
+}
@@ -3,16 +3,35 @@ package http
 type registrationReq struct {
 	FullName string `json:"full_name" validate:"required,max=255"`
 	Email    string `json:"email" validate:"required,email,max=255"`
-	Password string `json:"password" validate:"required,min=8,max=100"`
+	Password string `json:"password" validate:"required,max=100"`
 }
 type loginReq struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	// the token is set in the session cookie rather than returned
+	Cookie bool `json:"cookie"`
 }
 
 type updateSettings struct {
-	OldPassword     string `json:"old_password" validate:"omitempty,min=8,max=10"`
-	NewPassword     string `json:"new_password" validate:"omitempty,min=8,max=100"`
+	OldPassword     string `json:"old_password" validate:"omitempty,max=100"`
+	NewPassword     string `json:"new_password" validate:"omitempty,max=100"`
 	ListViewEnabled *int8  `json:"list_view_enabled" validate:"required"`
 	DarkModeEnabled *int8  `json:"dark_mode_enabled" validate:"required"`
 }
+
+type colorReq struct {
+	Name string `json:"name" validate:"required,colorname"`
+	Hex  string `json:"hex" validate:"required,len=7,hexcolor"`
+}
+
+type keyEnvelopeReq struct {
+	WrappedKey  string `json:"wrapped_key" validate:"required,base64,max=4096"`
+	Algorithm   string `json:"algorithm" validate:"required,max=50"`
+	Nonce       string `json:"nonce" validate:"omitempty,base64,max=255"`
+	KDF         string `json:"kdf" validate:"required,max=50"`
+	Salt        string `json:"salt" validate:"required,base64,max=255"`
+	Iterations  int32  `json:"iterations" validate:"min=1"`
+	Memory      int32  `json:"memory" validate:"min=0"`
+	Parallelism int32  `json:"parallelism" validate:"min=0"`
+	KeyVersion  int32  `json:"key_version" validate:"min=1"`
+}
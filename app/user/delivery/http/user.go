@@ -7,9 +7,9 @@ import (
 	"librenote/app/validation"
 	"librenote/infrastructure/config"
 	"librenote/infrastructure/middlewares"
+	"net/http"
 	"time"
 
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 )
 
@@ -23,15 +23,31 @@ func NewUserHandler(e *echo.Echo, us model.UserUsecase) {
 		UUseCase: us,
 	}
 
+	// both share the limits, so the registration can't be used to probe the accounts
+	rateLimit := middlewares.RateLimit(config.Get().RateLimit)
+
 	v1 := e.Group("/api/v1")
-	v1.POST("/registration", handler.Registration)
-	v1.POST("/login", handler.Login)
+	v1.POST("/registration", handler.Registration, rateLimit)
+	v1.POST("/login", handler.Login, rateLimit)
+
+	logout := e.Group("/api/v1/logout")
+	_ = middlewares.AttachJwtToGroup(logout)
+	logout.POST("", handler.Logout)
 
 	me := e.Group("/api/v1/me")
 	_ = middlewares.AttachJwtToGroup(me)
 	me.GET("", handler.Me)
 	me.POST("", handler.UpdateSettings)
 	me.DELETE("", handler.DeleteMe)
+	me.GET("/colors", handler.FetchColors)
+	me.POST("/colors", handler.AddColor)
+	me.DELETE("/colors/:id", handler.DeleteColor)
+	me.GET("/key-envelope", handler.GetKeyEnvelope)
+	me.PUT("/key-envelope", handler.SaveKeyEnvelope)
+
+	meta := e.Group("/api/v1/meta")
+	_ = middlewares.AttachJwtToGroup(meta)
+	meta.GET("", handler.Meta)
 }
 
 func (u *UserHandler) Registration(c echo.Context) error {
@@ -92,20 +108,45 @@ func (u *UserHandler) Login(c echo.Context) error {
 		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
 	}
 
+	if lReq.Cookie && !config.Get().Jwt.Cookie.Enabled {
+		return c.JSON(response.RespondError(response.ErrBadRequest, errors.New("cookie sessions are disabled")))
+	}
+
 	ctx := c.Request().Context()
 
 	token, err := u.UUseCase.Login(ctx, lReq.Email, lReq.Password)
 	if err != nil {
+		if retryAfter, ok := response.RetryAfter(err); ok {
+			middlewares.SetRetryAfter(c, retryAfter)
+		}
+
 		return c.JSON(response.RespondError(err))
 	}
 
+	if lReq.Cookie {
+		middlewares.SetSessionCookie(c, token)
+
+		return c.JSON(response.RespondLoginSuccess(""))
+	}
+
 	return c.JSON(response.RespondLoginSuccess(token))
 }
 
+// Logout clears the session cookie, the bearer token clients forget their token
+func (u *UserHandler) Logout(c echo.Context) error {
+	u.UUseCase.Logout(c.Request().Context(), middlewares.GetUserID(c))
+
+	if config.Get().Jwt.Cookie.Enabled {
+		middlewares.ClearSessionCookie(c)
+	}
+
+	return c.JSON(response.RespondSuccess("logout successful", nil))
+}
+
 func (u *UserHandler) Me(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	details, err := u.UUseCase.GetUserDetails(ctx, getUserID(c))
+	details, err := u.UUseCase.GetUserDetails(ctx, middlewares.GetUserID(c))
 	if err != nil {
 		return c.JSON(response.RespondError(err))
 	}
@@ -113,11 +154,6 @@ func (u *UserHandler) Me(c echo.Context) error {
 	return c.JSON(response.RespondSuccess("request success", details))
 }
 
-func getUserID(c echo.Context) int32 {
-	token := c.Get("user").(*jwt.Token)
-	return token.Claims.(*middlewares.JwtCustomClaims).UserID
-}
-
 func (u *UserHandler) UpdateSettings(c echo.Context) error {
 	var usReq updateSettings
 
@@ -146,7 +182,7 @@ func (u *UserHandler) UpdateSettings(c echo.Context) error {
 
 	ctx := c.Request().Context()
 
-	user, err := u.UUseCase.GetUser(ctx, getUserID(c))
+	user, err := u.UUseCase.GetUser(ctx, middlewares.GetUserID(c))
 	if err != nil {
 		return c.JSON(response.RespondError(err))
 	}
@@ -174,7 +210,7 @@ func (u *UserHandler) UpdateSettings(c echo.Context) error {
 func (u *UserHandler) DeleteMe(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	user, err := u.UUseCase.GetUser(ctx, getUserID(c))
+	user, err := u.UUseCase.GetUser(ctx, middlewares.GetUserID(c))
 	if err != nil {
 		return c.JSON(response.RespondError(err))
 	}
@@ -186,5 +222,137 @@ func (u *UserHandler) DeleteMe(c echo.Context) error {
 		return c.JSON(response.RespondError(err))
 	}
 
+	if config.Get().Jwt.Cookie.Enabled {
+		middlewares.ClearSessionCookie(c)
+	}
+
 	return c.JSON(response.RespondEmpty())
 }
+
+// Meta describes the colors and types the notes of the user accept
+func (u *UserHandler) Meta(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	meta, err := u.UUseCase.Meta(ctx, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", meta))
+}
+
+func (u *UserHandler) FetchColors(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	colors, err := u.UUseCase.FetchColors(ctx, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", colors))
+}
+
+func (u *UserHandler) AddColor(c echo.Context) error {
+	var cReq colorReq
+
+	err := c.Bind(&cReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&cReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	color := model.CustomColor{
+		UserID:    middlewares.GetUserID(c),
+		Name:      cReq.Name,
+		Hex:       cReq.Hex,
+		CreatedAt: time.Now().UTC().Format("2006-01-02 15:04:05"),
+	}
+
+	ctx := c.Request().Context()
+
+	err = u.UUseCase.AddColor(ctx, &color)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("color added", color))
+}
+
+func (u *UserHandler) DeleteColor(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	err = u.UUseCase.DeleteColor(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (u *UserHandler) GetKeyEnvelope(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	envelope, err := u.UUseCase.GetKeyEnvelope(ctx, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", envelope))
+}
+
+// SaveKeyEnvelope stores the wrapped master key of the encrypted notes, replacing the previous one
+func (u *UserHandler) SaveKeyEnvelope(c echo.Context) error {
+	var kReq keyEnvelopeReq
+
+	err := c.Bind(&kReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&kReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	envelope := model.KeyEnvelope{
+		UserID:      middlewares.GetUserID(c),
+		WrappedKey:  kReq.WrappedKey,
+		Algorithm:   kReq.Algorithm,
+		Nonce:       kReq.Nonce,
+		KDF:         kReq.KDF,
+		Salt:        kReq.Salt,
+		Iterations:  kReq.Iterations,
+		Memory:      kReq.Memory,
+		Parallelism: kReq.Parallelism,
+		KeyVersion:  kReq.KeyVersion,
+		CreatedAt:   nowTime,
+		UpdatedAt:   nowTime,
+	}
+
+	ctx := c.Request().Context()
+
+	err = u.UUseCase.SaveKeyEnvelope(ctx, &envelope)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("key envelope saved", envelope))
+}
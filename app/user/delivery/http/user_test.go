@@ -2,6 +2,7 @@ package http_test
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"librenote/app/model"
 	"librenote/app/model/mocks"
@@ -77,6 +78,12 @@ func TestRegistration(t *testing.T) {
 		tempReq := regReq
 		tempReq.Password = "1234567"
 
+		// the password policy is checked by the usecase
+		mockUsecase := new(mocks.UserUsecase)
+		mockUsecase.On("Registration", mock.Anything, mock.AnythingOfType("*model.User")).
+			Return(response.WrapError(errors.New("the password must have at least 8 characters"),
+				http.StatusBadRequest)).Once()
+
 		j, err := json.Marshal(tempReq)
 		assert.NoError(t, err)
 		c, rec := buildEchoPostRequest(t, endPoint, strings.NewReader(string(j)))
@@ -87,8 +94,25 @@ func TestRegistration(t *testing.T) {
 		err = handler.Registration(c)
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "at least 8 characters")
 		mockUsecase.AssertExpectations(t)
 	})
+
+	t.Run("long password", func(t *testing.T) {
+		tempReq := regReq
+		tempReq.Password = strings.Repeat("a", 101)
+
+		j, err := json.Marshal(tempReq)
+		assert.NoError(t, err)
+		c, rec := buildEchoPostRequest(t, endPoint, strings.NewReader(string(j)))
+
+		handler := userHttp.UserHandler{
+			UUseCase: mockUsecase,
+		}
+		err = handler.Registration(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
 }
 
 func TestLogin(t *testing.T) {
@@ -133,6 +157,54 @@ func TestLogin(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		mockUsecase.AssertExpectations(t)
 	})
+
+	t.Run("locked", func(t *testing.T) {
+		lockedUsecase := new(mocks.UserUsecase)
+		lockedUsecase.On("Login", mock.Anything, "mrtest@example.com", "12345678").
+			Return("", response.TooManyRequests(errors.New("locked"), 90*time.Second+time.Millisecond)).Once()
+
+		j, err := json.Marshal(lReq)
+		assert.NoError(t, err)
+		c, rec := buildEchoPostRequest(t, endPoint, strings.NewReader(string(j)))
+
+		handler := userHttp.UserHandler{
+			UUseCase: lockedUsecase,
+		}
+		err = handler.Login(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "91", rec.Header().Get("Retry-After"))
+		lockedUsecase.AssertExpectations(t)
+	})
+	t.Run("cookie disabled", func(t *testing.T) {
+		c, rec := buildEchoPostRequest(t, endPoint,
+			strings.NewReader(`{"email":"mrtest@example.com","password":"12345678","cookie":true}`))
+
+		handler := userHttp.UserHandler{
+			UUseCase: new(mocks.UserUsecase),
+		}
+		err := handler.Login(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "cookie sessions are disabled")
+	})
+}
+
+func TestLogout(t *testing.T) {
+	mockUsecase := new(mocks.UserUsecase)
+	mockUsecase.On("Logout", mock.Anything, int32(1)).Once()
+
+	handler := userHttp.UserHandler{
+		UUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.POST, BaseURLV1+"/logout", getToken(1), nil)
+	handle := attachJWTMiddleware(handler.Logout)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), "logout successful")
+	mockUsecase.AssertExpectations(t)
 }
 
 func buildEchoPostRequest(t *testing.T, path string, payload io.Reader) (echo.Context, *httptest.ResponseRecorder) {
@@ -378,3 +450,135 @@ func TestDeleteMe(t *testing.T) {
 		mockUsecase.AssertExpectations(t)
 	})
 }
+
+func TestAddColor(t *testing.T) {
+	endPoint := BaseURLV1 + "/me/colors"
+
+	mockUsecase := new(mocks.UserUsecase)
+	mockUsecase.On("AddColor", mock.Anything, mock.MatchedBy(func(c *model.CustomColor) bool {
+		return c.UserID == 1 && c.Name == "sea green"
+	})).Return(nil).Once()
+
+	handler := userHttp.UserHandler{
+		UUseCase: mockUsecase,
+	}
+
+	cases := map[string]struct {
+		payload string
+		code    int
+	}{
+		"success":       {payload: `{"name":"sea green","hex":"#2e8b57"}`, code: http.StatusOK},
+		"upper-case":    {payload: `{"name":"Sea Green","hex":"#2e8b57"}`, code: http.StatusBadRequest},
+		"slash":         {payload: `{"name":"a/b","hex":"#2e8b57"}`, code: http.StatusBadRequest},
+		"short-hex":     {payload: `{"name":"sea","hex":"#fff"}`, code: http.StatusBadRequest},
+		"missing-hex":   {payload: `{"name":"sea"}`, code: http.StatusBadRequest},
+		"not-hex-color": {payload: `{"name":"sea","hex":"#zzzzzz"}`, code: http.StatusBadRequest},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(c.payload))
+			handle := attachJWTMiddleware(handler.AddColor)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, c.code, res.Code)
+		})
+	}
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestMeta(t *testing.T) {
+	endPoint := BaseURLV1 + "/meta"
+	meta := model.Meta{
+		Colors:       model.Colors,
+		CustomColors: []model.CustomColor{{ID: 1, UserID: 1, Name: "sea green", Hex: "#2e8b57"}},
+		NoteTypes:    model.NoteTypes,
+	}
+
+	mockUsecase := new(mocks.UserUsecase)
+	mockUsecase.On("Meta", mock.Anything, int32(1)).Return(&meta, nil).Once()
+
+	handler := userHttp.UserHandler{
+		UUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+	handle := attachJWTMiddleware(handler.Meta)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var r response.Response
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+
+	resultsMap := r.Results.(map[string]interface{})
+	assert.Len(t, resultsMap["colors"], len(model.Colors))
+	assert.Len(t, resultsMap["custom_colors"], 1)
+	assert.Equal(t, []interface{}{"note", "list", "drawing"}, resultsMap["note_types"])
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetKeyEnvelope(t *testing.T) {
+	endPoint := BaseURLV1 + "/me/key-envelope"
+
+	mockUsecase := new(mocks.UserUsecase)
+	mockUsecase.On("GetKeyEnvelope", mock.Anything, int32(1)).
+		Return(&model.KeyEnvelope{UserID: 1, WrappedKey: "d3JhcHBlZA==", KDF: "argon2id", KeyVersion: 1}, nil).Once()
+	mockUsecase.On("GetKeyEnvelope", mock.Anything, int32(2)).Return(nil, response.ErrNotFound).Once()
+
+	handler := userHttp.UserHandler{
+		UUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+	assert.NoError(t, attachJWTMiddleware(handler.GetKeyEnvelope)(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `"wrapped_key":"d3JhcHBlZA=="`)
+
+	ctx, res = buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(2), nil)
+	assert.NoError(t, attachJWTMiddleware(handler.GetKeyEnvelope)(ctx))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestSaveKeyEnvelope(t *testing.T) {
+	endPoint := BaseURLV1 + "/me/key-envelope"
+
+	mockUsecase := new(mocks.UserUsecase)
+	mockUsecase.On("SaveKeyEnvelope", mock.Anything, mock.MatchedBy(func(m *model.KeyEnvelope) bool {
+		return m.UserID == 1 && m.KDF == "argon2id" && m.KeyVersion == 1
+	})).Return(nil).Once()
+
+	handler := userHttp.UserHandler{
+		UUseCase: mockUsecase,
+	}
+
+	wrapped := `"wrapped_key":"d3JhcHBlZA==",`
+	kdf := `"algorithm":"AES-KW","kdf":"argon2id","salt":"c2FsdA==","iterations":3,"memory":65536,"parallelism":1,`
+
+	cases := map[string]struct {
+		payload string
+		code    int
+	}{
+		"success":        {payload: `{` + wrapped + kdf + `"key_version":1}`, code: http.StatusOK},
+		"missing-key":    {payload: `{` + kdf + `"key_version":1}`, code: http.StatusBadRequest},
+		"not-base64":     {payload: `{"wrapped_key":"!",` + kdf + `"key_version":1}`, code: http.StatusBadRequest},
+		"no-iterations":  {payload: `{` + wrapped + `"kdf":"pbkdf2","key_version":1}`, code: http.StatusBadRequest},
+		"no-key-version": {payload: `{` + wrapped + kdf + `"key_version":0}`, code: http.StatusBadRequest},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.PUT, endPoint, getToken(1), strings.NewReader(c.payload))
+			handle := attachJWTMiddleware(handler.SaveKeyEnvelope)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, c.code, res.Code)
+		})
+	}
+
+	mockUsecase.AssertExpectations(t)
+}
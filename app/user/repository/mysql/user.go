@@ -133,3 +133,201 @@ func (r *userRepository) UpdateUser(ctx context.Context, user *model.User) error
 
 	return nil
 }
+
+const createColor = `INSERT INTO users_colors (user_id, name, hex, created_at) VALUES (?, ?, ?, ?)`
+
+func (r *userRepository) CreateColor(ctx context.Context, color *model.CustomColor) error {
+	res, err := r.db.ExecContext(ctx, createColor, color.UserID, color.Name, color.Hex, color.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	color.ID = int32(id)
+
+	return nil
+}
+
+const fetchColors = `SELECT id, user_id, name, hex, created_at FROM users_colors WHERE user_id = ? ORDER BY id`
+
+func (r *userRepository) FetchColors(ctx context.Context, userID int32) ([]model.CustomColor, error) {
+	rows, err := r.db.QueryContext(ctx, fetchColors, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	colors := make([]model.CustomColor, 0)
+
+	for rows.Next() {
+		var i model.CustomColor
+		if err = rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Hex,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		colors = append(colors, i)
+	}
+
+	return colors, rows.Err()
+}
+
+const deleteColor = `DELETE FROM users_colors WHERE id = ? AND user_id = ?`
+
+func (r *userRepository) DeleteColor(ctx context.Context, id, userID int32) error {
+	res, err := r.db.ExecContext(ctx, deleteColor, id, userID)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+const getKeyEnvelope = `SELECT user_id, wrapped_key, algorithm, nonce, kdf, salt, iterations, memory, parallelism,
+key_version, created_at, updated_at FROM users_key_envelopes WHERE user_id = ? LIMIT 1`
+
+func (r *userRepository) GetKeyEnvelope(ctx context.Context, userID int32) (model.KeyEnvelope, error) {
+	var i model.KeyEnvelope
+	err := r.db.QueryRowContext(ctx, getKeyEnvelope, userID).Scan(
+		&i.UserID,
+		&i.WrappedKey,
+		&i.Algorithm,
+		&i.Nonce,
+		&i.KDF,
+		&i.Salt,
+		&i.Iterations,
+		&i.Memory,
+		&i.Parallelism,
+		&i.KeyVersion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+
+	return i, err
+}
+
+const saveKeyEnvelope = `INSERT INTO users_key_envelopes (
+  user_id, wrapped_key, algorithm, nonce, kdf, salt, iterations, memory, parallelism, key_version, created_at,
+  updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+wrapped_key = VALUES(wrapped_key),
+algorithm = VALUES(algorithm),
+nonce = VALUES(nonce),
+kdf = VALUES(kdf),
+salt = VALUES(salt),
+iterations = VALUES(iterations),
+memory = VALUES(memory),
+parallelism = VALUES(parallelism),
+key_version = VALUES(key_version),
+updated_at = VALUES(updated_at)`
+
+func (r *userRepository) SaveKeyEnvelope(ctx context.Context, envelope *model.KeyEnvelope) error {
+	_, err := r.db.ExecContext(ctx, saveKeyEnvelope,
+		envelope.UserID,
+		envelope.WrappedKey,
+		envelope.Algorithm,
+		envelope.Nonce,
+		envelope.KDF,
+		envelope.Salt,
+		envelope.Iterations,
+		envelope.Memory,
+		envelope.Parallelism,
+		envelope.KeyVersion,
+		envelope.CreatedAt,
+		envelope.UpdatedAt,
+	)
+
+	return err
+}
+
+const getLoginFailures = `SELECT user_id, attempts, locked_until, updated_at FROM users_login_failures
+WHERE user_id = ? LIMIT 1`
+
+func (r *userRepository) GetLoginFailures(ctx context.Context, userID int32) (model.LoginFailures, error) {
+	var i model.LoginFailures
+	err := r.db.QueryRowContext(ctx, getLoginFailures, userID).Scan(
+		&i.UserID,
+		&i.Attempts,
+		&i.LockedUntil,
+		&i.UpdatedAt,
+	)
+
+	return i, err
+}
+
+// the attempts are returned as the insert id, set by LAST_INSERT_ID(expr) in the same statement
+const addLoginFailure = `INSERT INTO users_login_failures (user_id, attempts, updated_at)
+VALUES (?, LAST_INSERT_ID(1), ?)
+ON DUPLICATE KEY UPDATE
+attempts = LAST_INSERT_ID(attempts + 1),
+updated_at = VALUES(updated_at)`
+
+func (r *userRepository) AddLoginFailure(ctx context.Context, userID int32, updatedAt string) (int32, error) {
+	res, err := r.db.ExecContext(ctx, addLoginFailure, userID, updatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	attempts, err := res.LastInsertId()
+
+	return int32(attempts), err
+}
+
+const lockLogin = `UPDATE users_login_failures SET locked_until = ?
+WHERE user_id = ? AND (locked_until IS NULL OR locked_until < ?)`
+
+func (r *userRepository) LockLogin(ctx context.Context, userID int32, lockedUntil string) error {
+	_, err := r.db.ExecContext(ctx, lockLogin, lockedUntil, userID, lockedUntil)
+
+	return err
+}
+
+const deleteLoginFailures = `DELETE FROM users_login_failures WHERE user_id = ?`
+
+func (r *userRepository) DeleteLoginFailures(ctx context.Context, userID int32) error {
+	_, err := r.db.ExecContext(ctx, deleteLoginFailures, userID)
+
+	return err
+}
+
+const getAdmin = `SELECT is_admin FROM users WHERE id = ? LIMIT 1`
+
+func (r *userRepository) IsAdmin(ctx context.Context, userID int32) (bool, error) {
+	var admin int8
+	err := r.db.QueryRowContext(ctx, getAdmin, userID).Scan(&admin)
+
+	return admin == 1, err
+}
+
+const setAdmin = `UPDATE users SET is_admin = ? WHERE id = ?`
+
+func (r *userRepository) SetAdmin(ctx context.Context, userID int32, admin bool) error {
+	var isAdmin int8
+	if admin {
+		isAdmin = 1
+	}
+
+	_, err := r.db.ExecContext(ctx, setAdmin, isAdmin, userID)
+
+	return err
+}
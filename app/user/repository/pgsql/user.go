@@ -133,3 +133,186 @@ func (r *userRepository) UpdateUser(ctx context.Context, user *model.User) error
 
 	return nil
 }
+
+const createColor = `INSERT INTO users_colors (user_id, name, hex, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
+
+func (r *userRepository) CreateColor(ctx context.Context, color *model.CustomColor) error {
+	return r.db.QueryRowContext(ctx, createColor, color.UserID, color.Name, color.Hex, color.CreatedAt).
+		Scan(&color.ID)
+}
+
+const fetchColors = `SELECT id, user_id, name, hex, created_at::text FROM users_colors WHERE user_id = $1 ORDER BY id`
+
+func (r *userRepository) FetchColors(ctx context.Context, userID int32) ([]model.CustomColor, error) {
+	rows, err := r.db.QueryContext(ctx, fetchColors, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	colors := make([]model.CustomColor, 0)
+
+	for rows.Next() {
+		var i model.CustomColor
+		if err = rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Hex,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		colors = append(colors, i)
+	}
+
+	return colors, rows.Err()
+}
+
+const deleteColor = `DELETE FROM users_colors WHERE id = $1 AND user_id = $2`
+
+func (r *userRepository) DeleteColor(ctx context.Context, id, userID int32) error {
+	res, err := r.db.ExecContext(ctx, deleteColor, id, userID)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+const getKeyEnvelope = `SELECT user_id, wrapped_key, algorithm, nonce, kdf, salt, iterations, memory, parallelism,
+key_version, created_at::text, updated_at::text FROM users_key_envelopes WHERE user_id = $1 LIMIT 1`
+
+func (r *userRepository) GetKeyEnvelope(ctx context.Context, userID int32) (model.KeyEnvelope, error) {
+	var i model.KeyEnvelope
+	err := r.db.QueryRowContext(ctx, getKeyEnvelope, userID).Scan(
+		&i.UserID,
+		&i.WrappedKey,
+		&i.Algorithm,
+		&i.Nonce,
+		&i.KDF,
+		&i.Salt,
+		&i.Iterations,
+		&i.Memory,
+		&i.Parallelism,
+		&i.KeyVersion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+
+	return i, err
+}
+
+const saveKeyEnvelope = `INSERT INTO users_key_envelopes (
+  user_id, wrapped_key, algorithm, nonce, kdf, salt, iterations, memory, parallelism, key_version, created_at,
+  updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT (user_id) DO UPDATE SET
+wrapped_key = EXCLUDED.wrapped_key,
+algorithm = EXCLUDED.algorithm,
+nonce = EXCLUDED.nonce,
+kdf = EXCLUDED.kdf,
+salt = EXCLUDED.salt,
+iterations = EXCLUDED.iterations,
+memory = EXCLUDED.memory,
+parallelism = EXCLUDED.parallelism,
+key_version = EXCLUDED.key_version,
+updated_at = EXCLUDED.updated_at`
+
+func (r *userRepository) SaveKeyEnvelope(ctx context.Context, envelope *model.KeyEnvelope) error {
+	_, err := r.db.ExecContext(ctx, saveKeyEnvelope,
+		envelope.UserID,
+		envelope.WrappedKey,
+		envelope.Algorithm,
+		envelope.Nonce,
+		envelope.KDF,
+		envelope.Salt,
+		envelope.Iterations,
+		envelope.Memory,
+		envelope.Parallelism,
+		envelope.KeyVersion,
+		envelope.CreatedAt,
+		envelope.UpdatedAt,
+	)
+
+	return err
+}
+
+const getLoginFailures = `SELECT user_id, attempts, locked_until::text, updated_at::text FROM users_login_failures
+WHERE user_id = $1 LIMIT 1`
+
+func (r *userRepository) GetLoginFailures(ctx context.Context, userID int32) (model.LoginFailures, error) {
+	var i model.LoginFailures
+	err := r.db.QueryRowContext(ctx, getLoginFailures, userID).Scan(
+		&i.UserID,
+		&i.Attempts,
+		&i.LockedUntil,
+		&i.UpdatedAt,
+	)
+
+	return i, err
+}
+
+const addLoginFailure = `INSERT INTO users_login_failures (user_id, attempts, updated_at)
+VALUES ($1, 1, $2)
+ON CONFLICT (user_id) DO UPDATE SET
+attempts = users_login_failures.attempts + 1,
+updated_at = EXCLUDED.updated_at
+RETURNING attempts`
+
+func (r *userRepository) AddLoginFailure(ctx context.Context, userID int32, updatedAt string) (int32, error) {
+	var attempts int32
+	err := r.db.QueryRowContext(ctx, addLoginFailure, userID, updatedAt).Scan(&attempts)
+
+	return attempts, err
+}
+
+const lockLogin = `UPDATE users_login_failures SET locked_until = $1
+WHERE user_id = $2 AND (locked_until IS NULL OR locked_until < $1)`
+
+func (r *userRepository) LockLogin(ctx context.Context, userID int32, lockedUntil string) error {
+	_, err := r.db.ExecContext(ctx, lockLogin, lockedUntil, userID)
+
+	return err
+}
+
+const deleteLoginFailures = `DELETE FROM users_login_failures WHERE user_id = $1`
+
+func (r *userRepository) DeleteLoginFailures(ctx context.Context, userID int32) error {
+	_, err := r.db.ExecContext(ctx, deleteLoginFailures, userID)
+
+	return err
+}
+
+const getAdmin = `SELECT is_admin FROM users WHERE id = $1 LIMIT 1`
+
+func (r *userRepository) IsAdmin(ctx context.Context, userID int32) (bool, error) {
+	var admin int8
+	err := r.db.QueryRowContext(ctx, getAdmin, userID).Scan(&admin)
+
+	return admin == 1, err
+}
+
+const setAdmin = `UPDATE users SET is_admin = $1 WHERE id = $2`
+
+func (r *userRepository) SetAdmin(ctx context.Context, userID int32, admin bool) error {
+	var isAdmin int8
+	if admin {
+		isAdmin = 1
+	}
+
+	_, err := r.db.ExecContext(ctx, setAdmin, isAdmin, userID)
+
+	return err
+}
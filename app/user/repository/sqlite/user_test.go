@@ -2,6 +2,7 @@ package sqlite_test
 
 import (
 	"context"
+	"database/sql"
 	"librenote/app/model"
 	userRepo "librenote/app/user/repository/sqlite"
 	"testing"
@@ -128,3 +129,128 @@ func TestUpdateUser(t *testing.T) {
 	err = ur.UpdateUser(context.TODO(), u)
 	assert.NoError(t, err)
 }
+
+func TestColors(t *testing.T) {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	c := &model.CustomColor{UserID: 1, Name: "sea green", Hex: "#2e8b57", CreatedAt: nowTime}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users_colors").WithArgs(c.UserID, c.Name, c.Hex, c.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectQuery("SELECT (.+) FROM users_colors WHERE").WithArgs(int32(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "hex", "created_at"}).
+			AddRow(4, 1, c.Name, c.Hex, nowTime))
+	mock.ExpectExec("DELETE FROM users_colors").WithArgs(int32(4), int32(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM users_colors").WithArgs(int32(4), int32(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ur := userRepo.NewSqliteUserRepository(db)
+	assert.NoError(t, ur.CreateColor(context.TODO(), c))
+	assert.Equal(t, int32(4), c.ID)
+
+	colors, err := ur.FetchColors(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.CustomColor{*c}, colors)
+
+	assert.NoError(t, ur.DeleteColor(context.TODO(), 4, 1))
+	assert.ErrorIs(t, ur.DeleteColor(context.TODO(), 4, 2), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyEnvelope(t *testing.T) {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	e := &model.KeyEnvelope{
+		UserID: 1, WrappedKey: "d3JhcHBlZA==", Algorithm: "AES-KW", KDF: "argon2id", Salt: "c2FsdA==",
+		Iterations: 3, Memory: 65536, Parallelism: 1, KeyVersion: 1, CreatedAt: nowTime, UpdatedAt: nowTime,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users_key_envelopes").WithArgs(e.UserID, e.WrappedKey, e.Algorithm, e.Nonce,
+		e.KDF, e.Salt, e.Iterations, e.Memory, e.Parallelism, e.KeyVersion, e.CreatedAt, e.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT (.+) FROM users_key_envelopes WHERE").WithArgs(int32(1)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"user_id", "wrapped_key", "algorithm", "nonce", "kdf", "salt", "iterations", "memory", "parallelism",
+			"key_version", "created_at", "updated_at"}).
+			AddRow(e.UserID, e.WrappedKey, e.Algorithm, e.Nonce, e.KDF, e.Salt, e.Iterations, e.Memory,
+				e.Parallelism, e.KeyVersion, e.CreatedAt, e.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM users_key_envelopes WHERE").WithArgs(int32(2)).
+		WillReturnError(sql.ErrNoRows)
+
+	ur := userRepo.NewSqliteUserRepository(db)
+	assert.NoError(t, ur.SaveKeyEnvelope(context.TODO(), e))
+
+	envelope, err := ur.GetKeyEnvelope(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, *e, envelope)
+
+	_, err = ur.GetKeyEnvelope(context.TODO(), 2)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginFailures(t *testing.T) {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("INSERT INTO users_login_failures (.+) RETURNING attempts").WithArgs(int32(1), nowTime).
+		WillReturnRows(sqlmock.NewRows([]string{"attempts"}).AddRow(5))
+	mock.ExpectExec("UPDATE users_login_failures SET locked_until").WithArgs(nowTime, int32(1), nowTime).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT (.+) FROM users_login_failures WHERE").WithArgs(int32(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "attempts", "locked_until", "updated_at"}).
+			AddRow(1, 5, nil, nowTime))
+	mock.ExpectExec("DELETE FROM users_login_failures WHERE").WithArgs(int32(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ur := userRepo.NewSqliteUserRepository(db)
+	attempts, err := ur.AddLoginFailure(context.TODO(), 1, nowTime)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), attempts)
+	assert.NoError(t, ur.LockLogin(context.TODO(), 1, nowTime))
+
+	failures, err := ur.GetLoginFailures(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), failures.Attempts)
+	assert.Nil(t, failures.LockedUntil)
+
+	assert.NoError(t, ur.DeleteLoginFailures(context.TODO(), 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdmin(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users SET is_admin").WithArgs(int8(1), int32(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT is_admin FROM users WHERE").WithArgs(int32(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"is_admin"}).AddRow(1))
+
+	ur := userRepo.NewSqliteUserRepository(db)
+	assert.NoError(t, ur.SetAdmin(context.TODO(), 1, true))
+
+	isAdmin, err := ur.IsAdmin(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.True(t, isAdmin)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,26 +4,56 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"librenote/app/model"
+	"librenote/app/password"
 	"librenote/app/response"
 	"librenote/infrastructure/config"
+	"librenote/infrastructure/logger"
 	"librenote/infrastructure/middlewares"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt"
-	"golang.org/x/crypto/bcrypt"
+)
+
+// maxCustomColors limits the colors a user can add to the palette
+const maxCustomColors = 50
+
+const timeLayout = "2006-01-02 15:04:05"
+
+var (
+	errLogin  = response.WrapError(errors.New("email/password is incorrect"), http.StatusUnauthorized)
+	errLocked = errors.New("too many failed logins, the account is locked")
 )
 
 type userUsecase struct {
 	repo           model.UserRepository
+	events         model.EventPublisher
+	audit          model.AuditRecorder
+	policy         *password.Policy
+	hasher         *password.Hasher
 	contextTimeout time.Duration
+	cfg            config.RateLimitConfig
+	dummyOnce      sync.Once
+	dummy          string
 }
 
-func NewUserUsecase(repo model.UserRepository, timeout time.Duration) model.UserUsecase {
+// NewUserUsecase locks the accounts after the failed logins of cfg, unless it's disabled or has no lockout threshold.
+// The new passwords are checked against the policy and hashed by the hasher
+func NewUserUsecase(repo model.UserRepository, events model.EventPublisher, audit model.AuditRecorder,
+	policy *password.Policy, hasher *password.Hasher, timeout time.Duration,
+	cfg config.RateLimitConfig) model.UserUsecase {
 	return &userUsecase{
 		repo:           repo,
+		events:         events,
+		audit:          audit,
+		policy:         policy,
+		hasher:         hasher,
 		contextTimeout: timeout,
+		cfg:            cfg,
 	}
 }
 
@@ -37,14 +67,16 @@ func (u *userUsecase) Registration(c context.Context, m *model.User) (err error)
 		return response.ErrConflict
 	}
 
+	if err = u.policy.Check(m.Hash, m.Email, m.FullName); err != nil {
+		return response.WrapError(err, http.StatusBadRequest)
+	}
+
 	// generate password salted hash
-	hash, err := bcrypt.GenerateFromPassword([]byte(m.Hash), bcrypt.DefaultCost)
+	m.Hash, err = u.hasher.Hash(m.Hash)
 	if err != nil {
 		return
 	}
 
-	m.Hash = string(hash)
-
 	// store
 	err = u.repo.CreateUser(ctx, m)
 
@@ -57,35 +89,183 @@ func (u *userUsecase) Login(c context.Context, email, password string) (token st
 
 	user, err := u.repo.GetUserByEmail(ctx, email)
 	if err != nil {
-		return "", response.WrapError(errors.New("email/password is incorrect"), http.StatusUnauthorized)
+		// the password is checked anyway, the unknown emails take as long as the known ones
+		if _, _, err = u.hasher.Verify(password, u.dummyHash()); err != nil {
+			return "", err
+		}
+
+		u.loginFailed(ctx, nil, email, "unknown email")
+
+		return "", errLogin
 	}
 
 	// check password
-	err = bcrypt.CompareHashAndPassword([]byte(user.Hash), []byte(password))
+	ok, rehash, err := u.hasher.Verify(password, user.Hash)
 	if err != nil {
-		return "", response.WrapError(errors.New("email/password is incorrect"), http.StatusUnauthorized)
+		return "", err
+	}
+
+	// a locked account answers as a wrong password, whether the password is right or not
+	failures, err := u.checkLockout(ctx, user.ID)
+	if errors.Is(err, errLocked) {
+		u.loginFailed(ctx, &user.ID, email, "locked")
+
+		return "", errLogin
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if !ok {
+		u.loginFailed(ctx, &user.ID, email, "wrong password")
+
+		if err = u.recordFailure(ctx, user.ID); err != nil {
+			return "", err
+		}
+
+		return "", errLogin
+	}
+
+	if failures.Attempts > 0 {
+		if err = u.repo.DeleteLoginFailures(ctx, user.ID); err != nil {
+			return "", err
+		}
 	}
 
 	// check user state
 	if user.IsActive == 0 || user.IsTrashed == 1 {
+		u.loginFailed(ctx, &user.ID, email, "inactive")
+
 		return "", response.WrapError(errors.New("user not exist or inactive"), http.StatusUnauthorized)
 	}
 
-	token, err = createToken(user.ID)
+	if rehash {
+		u.rehash(ctx, &user, password)
+	}
+
+	token, expiresAt, err := createToken(user.ID)
 	if err != nil {
 		return "", err
 	}
 
+	u.audit.Record(ctx, &model.AuditEvent{UserID: &user.ID, ActorID: &user.ID, Action: model.AuditLogin})
+	u.audit.Record(ctx, &model.AuditEvent{UserID: &user.ID, ActorID: &user.ID, Action: model.AuditTokenCreated,
+		Details: map[string]interface{}{"expires_at": expiresAt.UTC().Format(timeLayout)}})
+
 	return token, nil
 }
 
-func createToken(userID int32) (string, error) {
+func (u *userUsecase) Logout(c context.Context, userID int32) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	u.audit.Record(ctx, &model.AuditEvent{UserID: &userID, ActorID: &userID, Action: model.AuditLogout})
+}
+
+// rehash upgrades the hash of the password to the current algorithm and parameters, the failures are only logged
+func (u *userUsecase) rehash(ctx context.Context, user *model.User, password string) {
+	hash, err := u.hasher.Hash(password)
+	if err == nil {
+		user.Hash = hash
+		err = u.repo.UpdateUser(ctx, user)
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf("failed to rehash the password of the user %d: %s", user.ID, err)
+	}
+}
+
+// loginFailed records the failure, the actor is unknown as the password isn't checked or is wrong
+func (u *userUsecase) loginFailed(ctx context.Context, userID *int32, email, reason string) {
+	u.audit.Record(ctx, &model.AuditEvent{UserID: userID, Action: model.AuditLoginFailed,
+		Details: map[string]interface{}{"email": email, "reason": reason}})
+}
+
+func (u *userUsecase) lockoutEnabled() bool {
+	return !u.cfg.Disabled && u.cfg.LockoutThreshold > 0
+}
+
+// checkLockout returns the login failures of the user, or errLocked while the account is locked
+func (u *userUsecase) checkLockout(ctx context.Context, userID int32) (model.LoginFailures, error) {
+	if !u.lockoutEnabled() {
+		return model.LoginFailures{UserID: userID}, nil
+	}
+
+	failures, err := u.repo.GetLoginFailures(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.LoginFailures{UserID: userID}, nil
+	}
+
+	if err != nil {
+		return failures, err
+	}
+
+	if failures.LockedUntil != nil {
+		lockedUntil, err := time.Parse(timeLayout, *failures.LockedUntil)
+		if err != nil {
+			return failures, err
+		}
+
+		if time.Now().Before(lockedUntil) {
+			return failures, errLocked
+		}
+	}
+
+	return failures, nil
+}
+
+// recordFailure counts the failure and locks the account once the failures reach the threshold
+func (u *userUsecase) recordFailure(ctx context.Context, userID int32) error {
+	if !u.lockoutEnabled() {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	attempts, err := u.repo.AddLoginFailure(ctx, userID, now.Format(timeLayout))
+	if err != nil {
+		return err
+	}
+
+	over := int(attempts) - u.cfg.LockoutThreshold
+	if over < 0 {
+		return nil
+	}
+
+	return u.repo.LockLogin(ctx, userID, now.Add(u.lockout(over)).Format(timeLayout))
+}
+
+// lockout doubles the lockout duration for every failure over the threshold, up to the max lockout
+func (u *userUsecase) lockout(over int) time.Duration {
+	lockout := u.cfg.LockoutDuration
+	for ; over > 0 && lockout < u.cfg.MaxLockout; over-- {
+		if lockout > u.cfg.MaxLockout/2 {
+			return u.cfg.MaxLockout
+		}
+
+		lockout *= 2
+	}
+
+	return lockout
+}
+
+// dummyHash is verified for the unknown emails, it's hashed once by the configured hasher
+func (u *userUsecase) dummyHash() string {
+	u.dummyOnce.Do(func() {
+		u.dummy, _ = u.hasher.Hash("librenote dummy password")
+	})
+
+	return u.dummy
+}
+
+func createToken(userID int32) (string, time.Time, error) {
 	jwtCfg := config.Get().Jwt
+	expiresAt := time.Now().Add(jwtCfg.ExpireTime)
 
 	claims := &middlewares.JwtCustomClaims{
 		UserID: userID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(jwtCfg.ExpireTime).Unix(),
+			ExpiresAt: expiresAt.Unix(),
 		},
 	}
 
@@ -93,10 +273,10 @@ func createToken(userID int32) (string, error) {
 	token, err := unsignedToken.SignedString([]byte(jwtCfg.SecretKey))
 
 	if err != nil {
-		return "", err
+		return "", expiresAt, err
 	}
 
-	return token, err
+	return token, expiresAt, err
 }
 
 func (u *userUsecase) GetUserDetails(c context.Context, id int32) (details *model.UserDetails, err error) {
@@ -154,20 +334,152 @@ func (u *userUsecase) Update(c context.Context, m *model.User, p model.Password)
 
 	if p.IsChanged {
 		// check old password is correct
-		err := bcrypt.CompareHashAndPassword([]byte(m.Hash), []byte(p.OldPassword))
+		ok, _, err := u.hasher.Verify(p.OldPassword, m.Hash)
 		if err != nil {
+			return err
+		}
+
+		if !ok {
 			return response.WrapError(errors.New("old password doesn't match"), http.StatusBadRequest)
 		}
 
+		if err = u.policy.Check(p.NewPassword, m.Email, m.FullName); err != nil {
+			return response.WrapError(err, http.StatusBadRequest)
+		}
+
 		// generate password salted hash
-		hash, err := bcrypt.GenerateFromPassword([]byte(p.NewPassword), bcrypt.DefaultCost)
+		m.Hash, err = u.hasher.Hash(p.NewPassword)
 		if err != nil {
 			return err
 		}
-
-		m.Hash = string(hash)
 	}
 
 	// update
-	return u.repo.UpdateUser(ctx, m)
+	if err := u.repo.UpdateUser(ctx, m); err != nil {
+		return err
+	}
+
+	if p.IsChanged {
+		u.audit.Record(ctx, &model.AuditEvent{UserID: &m.ID, ActorID: &m.ID, Action: model.AuditPasswordChanged})
+	}
+
+	event := model.EventAccountUpdated
+	if m.IsTrashed == 1 {
+		event = model.EventAccountDeleted
+
+		u.audit.Record(ctx, &model.AuditEvent{UserID: &m.ID, ActorID: &m.ID, Action: model.AuditAccountDeleted,
+			Details: map[string]interface{}{"email": m.Email}})
+	}
+
+	u.events.Publish(ctx, m.ID, event, map[string]interface{}{
+		"id":        m.ID,
+		"full_name": m.FullName,
+		"email":     m.Email,
+	})
+
+	return nil
+}
+
+func (u *userUsecase) Meta(c context.Context, userID int32) (*model.Meta, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	colors, err := u.repo.FetchColors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Meta{
+		Colors:       model.Colors,
+		CustomColors: colors,
+		NoteTypes:    model.NoteTypes,
+	}, nil
+}
+
+func (u *userUsecase) FetchColors(c context.Context, userID int32) ([]model.CustomColor, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.repo.FetchColors(ctx, userID)
+}
+
+// AddColor refuses the names of the palette, the notes store the color names
+func (u *userUsecase) AddColor(c context.Context, m *model.CustomColor) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	m.Hex = strings.ToLower(m.Hex)
+
+	if model.IsColor(m.Name) {
+		return response.WrapError(fmt.Errorf("the palette already has the color %s", m.Name), http.StatusConflict)
+	}
+
+	colors, err := u.repo.FetchColors(ctx, m.UserID)
+	if err != nil {
+		return err
+	}
+
+	if len(colors) >= maxCustomColors {
+		return response.WrapError(fmt.Errorf("not more than %d custom colors", maxCustomColors), http.StatusBadRequest)
+	}
+
+	for _, color := range colors {
+		if color.Name == m.Name {
+			return response.ErrConflict
+		}
+	}
+
+	return u.repo.CreateColor(ctx, m)
+}
+
+// DeleteColor keeps the color of the notes having it
+func (u *userUsecase) DeleteColor(c context.Context, id, userID int32) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	err := u.repo.DeleteColor(ctx, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return response.ErrNotFound
+	}
+
+	return err
+}
+
+func (u *userUsecase) GetKeyEnvelope(c context.Context, userID int32) (*model.KeyEnvelope, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	envelope, err := u.repo.GetKeyEnvelope(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, response.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &envelope, nil
+}
+
+// SaveKeyEnvelope a new passphrase keeps the key version, a new master key increases it. Going back to an older master
+// key would leave the notes encrypted since unreadable
+func (u *userUsecase) SaveKeyEnvelope(c context.Context, m *model.KeyEnvelope) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	current, err := u.repo.GetKeyEnvelope(ctx, m.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err == nil {
+		if m.KeyVersion < current.KeyVersion {
+			return response.WrapError(fmt.Errorf("the key version can't go back from %d", current.KeyVersion),
+				http.StatusConflict)
+		}
+
+		m.CreatedAt = current.CreatedAt
+	}
+
+	return u.repo.SaveKeyEnvelope(ctx, m)
 }
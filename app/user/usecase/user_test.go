@@ -2,10 +2,16 @@ package usecase_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"librenote/app/model"
 	"librenote/app/model/mocks"
+	"librenote/app/password"
+	"librenote/app/response"
 	"librenote/app/user/usecase"
+	"librenote/infrastructure/config"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +20,36 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// noEvents accepts any published event
+func noEvents() *mocks.EventPublisher {
+	events := new(mocks.EventPublisher)
+	events.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	return events
+}
+
+// noAudit accepts any recorded audit event
+func noAudit() *mocks.AuditRecorder {
+	audit := new(mocks.AuditRecorder)
+	audit.On("Record", mock.Anything, mock.Anything).Maybe()
+
+	return audit
+}
+
+// lenPolicy only asks for 8 characters long passwords without the email or the name
+func lenPolicy() *password.Policy {
+	policy, _ := password.NewPolicy(config.PasswordConfig{MinLength: 8})
+
+	return policy
+}
+
+// bcryptHasher hashes by the cost of the hashes of the tests, so they are never upgraded
+func bcryptHasher() *password.Hasher {
+	hasher, _ := password.NewHasher(config.PasswordConfig{Hasher: password.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+
+	return hasher
+}
+
 func TestRegistration(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
@@ -36,7 +72,8 @@ func TestRegistration(t *testing.T) {
 		mockUserRepo.On("CreateUser", mock.Anything, mock.AnythingOfType("*model.User")).
 			Return(nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 
 		err := u.Registration(context.TODO(), &tMockUser)
 		assert.NoError(t, err)
@@ -50,12 +87,33 @@ func TestRegistration(t *testing.T) {
 		mockUserRepo.On("GetUserByEmail", mock.Anything, mock.AnythingOfType("string")).
 			Return(existingUser, nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		err := u.Registration(context.TODO(), &existingUser)
 
 		assert.Error(t, err)
 		mockUserRepo.AssertExpectations(t)
 	})
+
+	t.Run("weak-password", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
+
+		for _, pass := range []string{"short", "mrtest_password"} {
+			tMockUser := mockUser
+			tMockUser.Hash = pass
+
+			mockUserRepo.On("GetUserByEmail", mock.Anything, mock.AnythingOfType("string")).
+				Return(model.User{}, errors.New("not found")).Once()
+
+			err := u.Registration(context.TODO(), &tMockUser)
+			code, _ := response.RespondError(err)
+			assert.Equal(t, http.StatusBadRequest, code)
+		}
+
+		mockUserRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
 }
 
 func TestLoginSuccessAndWrongPassword(t *testing.T) {
@@ -77,7 +135,8 @@ func TestLoginSuccessAndWrongPassword(t *testing.T) {
 		mockUserRepo.On("GetUserByEmail", mock.Anything, mock.AnythingOfType("string")).
 			Return(existingUser, nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		token, err := u.Login(context.TODO(), "mrtest@example.com", "super_password")
 
 		assert.NoError(t, err)
@@ -91,7 +150,8 @@ func TestLoginSuccessAndWrongPassword(t *testing.T) {
 		mockUserRepo.On("GetUserByEmail", mock.Anything, mock.AnythingOfType("string")).
 			Return(existingUser, nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		_, err := u.Login(context.TODO(), "mrtest@example.com", "super")
 
 		assert.Error(t, err)
@@ -99,6 +159,36 @@ func TestLoginSuccessAndWrongPassword(t *testing.T) {
 	})
 }
 
+func TestLoginRehash(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("super_password"), bcrypt.MinCost)
+	mockUser := model.User{ID: 1, Email: "mrtest@example.com", Hash: string(hash), IsActive: 1}
+
+	hasher, err := password.NewHasher(config.PasswordConfig{
+		Hasher: password.AlgorithmArgon2id, Argon2Memory: 1024, Argon2Iterations: 1, Argon2Parallelism: 1,
+		BcryptCost: bcrypt.MinCost,
+	})
+	assert.NoError(t, err)
+
+	for _, updateErr := range []error{nil, errors.New("database is locked")} {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetUserByEmail", mock.Anything, "mrtest@example.com").Return(mockUser, nil).Once()
+		mockUserRepo.On("UpdateUser", mock.Anything, mock.MatchedBy(func(m *model.User) bool {
+			ok, rehash, err := hasher.Verify("super_password", m.Hash)
+
+			return strings.HasPrefix(m.Hash, "$argon2id$") && ok && !rehash && err == nil
+		})).Return(updateErr).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), hasher, time.Second*2,
+			config.RateLimitConfig{})
+
+		// the login goes on when the new hash isn't stored
+		token, err := u.Login(context.TODO(), "mrtest@example.com", "super_password")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		mockUserRepo.AssertExpectations(t)
+	}
+}
+
 func TestLoginWrongEmailAndInactive(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 
@@ -116,7 +206,8 @@ func TestLoginWrongEmailAndInactive(t *testing.T) {
 		mockUserRepo.On("GetUserByEmail", mock.Anything, mock.AnythingOfType("string")).
 			Return(model.User{}, errors.New("not found")).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		_, err := u.Login(context.TODO(), "test@example.com", "super_password")
 
 		assert.Error(t, err)
@@ -130,7 +221,8 @@ func TestLoginWrongEmailAndInactive(t *testing.T) {
 		mockUserRepo.On("GetUserByEmail", mock.Anything, mock.AnythingOfType("string")).
 			Return(existingUser, nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		_, err := u.Login(context.TODO(), "mrtest@example.com", "super_password")
 
 		assert.Error(t, err)
@@ -138,6 +230,148 @@ func TestLoginWrongEmailAndInactive(t *testing.T) {
 	})
 }
 
+func TestLoginLockout(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("super_password"), bcrypt.MinCost)
+	mockUser := model.User{ID: 1, Email: "mrtest@example.com", Hash: string(hash), IsActive: 1}
+	cfg := config.RateLimitConfig{LockoutThreshold: 3, LockoutDuration: time.Minute, MaxLockout: 5 * time.Minute}
+	timeLayout := "2006-01-02 15:04:05"
+
+	lockedFor := func(d time.Duration) func(lockedUntil string) bool {
+		return func(lockedUntil string) bool {
+			until, err := time.Parse(timeLayout, lockedUntil)
+
+			return err == nil && time.Until(until) > d-5*time.Second && time.Until(until) <= d
+		}
+	}
+
+	t.Run("first-failure", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetUserByEmail", mock.Anything, "mrtest@example.com").Return(mockUser, nil).Once()
+		mockUserRepo.On("GetLoginFailures", mock.Anything, int32(1)).
+			Return(model.LoginFailures{}, sql.ErrNoRows).Once()
+		mockUserRepo.On("AddLoginFailure", mock.Anything, int32(1), mock.AnythingOfType("string")).
+			Return(int32(1), nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			cfg)
+		_, err := u.Login(context.TODO(), "mrtest@example.com", "super")
+
+		assert.EqualError(t, err, "email/password is incorrect")
+		mockUserRepo.AssertNotCalled(t, "LockLogin", mock.Anything, mock.Anything, mock.Anything)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("lock", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetUserByEmail", mock.Anything, "mrtest@example.com").Return(mockUser, nil).Once()
+		mockUserRepo.On("GetLoginFailures", mock.Anything, int32(1)).
+			Return(model.LoginFailures{UserID: 1, Attempts: 2}, nil).Once()
+		mockUserRepo.On("AddLoginFailure", mock.Anything, int32(1), mock.AnythingOfType("string")).
+			Return(int32(3), nil).Once()
+		mockUserRepo.On("LockLogin", mock.Anything, int32(1), mock.MatchedBy(lockedFor(time.Minute))).
+			Return(nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			cfg)
+		_, err := u.Login(context.TODO(), "mrtest@example.com", "super")
+
+		assert.EqualError(t, err, "email/password is incorrect")
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("progressive", func(t *testing.T) {
+		expired := time.Now().UTC().Add(-time.Second).Format(timeLayout)
+
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetUserByEmail", mock.Anything, "mrtest@example.com").Return(mockUser, nil).Times(3)
+		mockUserRepo.On("GetLoginFailures", mock.Anything, int32(1)).
+			Return(model.LoginFailures{UserID: 1, Attempts: 4, LockedUntil: &expired}, nil).Times(3)
+		mockUserRepo.On("AddLoginFailure", mock.Anything, int32(1), mock.AnythingOfType("string")).
+			Return(int32(5), nil).Once()
+		mockUserRepo.On("LockLogin", mock.Anything, int32(1), mock.MatchedBy(lockedFor(4*time.Minute))).
+			Return(nil).Once()
+		mockUserRepo.On("AddLoginFailure", mock.Anything, int32(1), mock.AnythingOfType("string")).
+			Return(int32(8), nil).Once()
+		mockUserRepo.On("LockLogin", mock.Anything, int32(1), mock.MatchedBy(lockedFor(5*time.Minute))).
+			Return(nil).Once()
+		mockUserRepo.On("AddLoginFailure", mock.Anything, int32(1), mock.AnythingOfType("string")).
+			Return(int32(1000), nil).Once()
+		mockUserRepo.On("LockLogin", mock.Anything, int32(1), mock.MatchedBy(lockedFor(5*time.Minute))).
+			Return(nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			cfg)
+
+		_, err := u.Login(context.TODO(), "mrtest@example.com", "super")
+		assert.Error(t, err)
+
+		// up to the max lockout, however many failures there are
+		_, err = u.Login(context.TODO(), "mrtest@example.com", "super")
+		assert.Error(t, err)
+		_, err = u.Login(context.TODO(), "mrtest@example.com", "super")
+		assert.Error(t, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		lockedUntil := time.Now().UTC().Add(2 * time.Minute).Format(timeLayout)
+
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetUserByEmail", mock.Anything, "mrtest@example.com").Return(mockUser, nil).Twice()
+		mockUserRepo.On("GetLoginFailures", mock.Anything, int32(1)).
+			Return(model.LoginFailures{UserID: 1, Attempts: 3, LockedUntil: &lockedUntil}, nil).Twice()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			cfg)
+
+		// the right and the wrong passwords answer as an unknown email, the failures aren't counted
+		for _, password := range []string{"super_password", "super"} {
+			_, err := u.Login(context.TODO(), "mrtest@example.com", password)
+
+			code, _ := response.RespondError(err)
+			assert.Equal(t, http.StatusUnauthorized, code)
+			assert.EqualError(t, err, "email/password is incorrect")
+
+			_, ok := response.RetryAfter(err)
+			assert.False(t, ok)
+		}
+
+		mockUserRepo.AssertNotCalled(t, "AddLoginFailure", mock.Anything, mock.Anything, mock.Anything)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-email", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetUserByEmail", mock.Anything, "nobody@example.com").
+			Return(model.User{}, sql.ErrNoRows).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			cfg)
+		_, err := u.Login(context.TODO(), "nobody@example.com", "super")
+
+		code, _ := response.RespondError(err)
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.EqualError(t, err, "email/password is incorrect")
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-resets", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetUserByEmail", mock.Anything, "mrtest@example.com").Return(mockUser, nil).Once()
+		mockUserRepo.On("GetLoginFailures", mock.Anything, int32(1)).
+			Return(model.LoginFailures{UserID: 1, Attempts: 2}, nil).Once()
+		mockUserRepo.On("DeleteLoginFailures", mock.Anything, int32(1)).Return(nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			cfg)
+		token, err := u.Login(context.TODO(), "mrtest@example.com", "super_password")
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
 func TestGetUserDetails(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 
@@ -159,7 +393,8 @@ func TestGetUserDetails(t *testing.T) {
 		mockUserRepo.On("GetUser", mock.Anything, mock.AnythingOfType("int32")).
 			Return(existingUser, nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		details, err := u.GetUserDetails(context.TODO(), 1)
 
 		assert.NoError(t, err)
@@ -172,7 +407,8 @@ func TestGetUserDetails(t *testing.T) {
 		mockUserRepo.On("GetUser", mock.Anything, mock.AnythingOfType("int32")).
 			Return(model.User{}, errors.New("no row found")).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		_, err := u.GetUserDetails(context.TODO(), 2)
 
 		assert.Error(t, err)
@@ -201,7 +437,8 @@ func TestGetUser(t *testing.T) {
 		mockUserRepo.On("GetUser", mock.Anything, mock.AnythingOfType("int32")).
 			Return(existingUser, nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		user, err := u.GetUser(context.TODO(), 1)
 
 		assert.NoError(t, err)
@@ -217,7 +454,8 @@ func TestGetUser(t *testing.T) {
 		mockUserRepo.On("GetUser", mock.Anything, mock.AnythingOfType("int32")).
 			Return(existingUser, nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		_, err := u.GetUser(context.TODO(), 2)
 
 		assert.Error(t, err)
@@ -250,7 +488,8 @@ func TestUpdate(t *testing.T) {
 		mockUserRepo.On("UpdateUser", mock.Anything, mock.AnythingOfType("*model.User")).
 			Return(nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		err := u.Update(context.TODO(), &existingUser, pass)
 
 		assert.NoError(t, err)
@@ -265,13 +504,88 @@ func TestUpdate(t *testing.T) {
 			IsChanged:   true,
 		}
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		err := u.Update(context.TODO(), &existingUser, pass)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "old password doesn't match")
 		mockUserRepo.AssertExpectations(t)
 	})
+
+	t.Run("weak-password", func(t *testing.T) {
+		existingUser := mockUser
+		pass := model.Password{
+			OldPassword: "super_password",
+			NewPassword: "mr.test@example.com",
+			IsChanged:   true,
+		}
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
+		err := u.Update(context.TODO(), &existingUser, pass)
+
+		assert.ErrorIs(t, err, password.ErrPersonal)
+		assert.Equal(t, mockUser.Hash, existingUser.Hash)
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+func TestAudit(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("super_password"), bcrypt.MinCost)
+	mockUser := model.User{ID: 1, Email: "mrtest@example.com", Hash: string(hash), IsActive: 1}
+
+	recorded := func(action string, userID int32) interface{} {
+		return mock.MatchedBy(func(e *model.AuditEvent) bool {
+			return e.Action == action && (userID == 0 && e.UserID == nil || e.UserID != nil && *e.UserID == userID)
+		})
+	}
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "mrtest@example.com").Return(mockUser, nil).Twice()
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "test@example.com").
+		Return(model.User{}, sql.ErrNoRows).Once()
+	mockUserRepo.On("UpdateUser", mock.Anything, mock.Anything).Return(nil).Twice()
+
+	audit := new(mocks.AuditRecorder)
+	audit.On("Record", mock.Anything, recorded(model.AuditLogin, 1)).Once()
+	audit.On("Record", mock.Anything, mock.MatchedBy(func(e *model.AuditEvent) bool {
+		return e.Action == model.AuditTokenCreated && e.Details["expires_at"] != ""
+	})).Once()
+	audit.On("Record", mock.Anything, mock.MatchedBy(func(e *model.AuditEvent) bool {
+		return e.Action == model.AuditLoginFailed && e.UserID != nil && e.ActorID == nil &&
+			e.Details["reason"] == "wrong password"
+	})).Once()
+	audit.On("Record", mock.Anything, mock.MatchedBy(func(e *model.AuditEvent) bool {
+		return e.Action == model.AuditLoginFailed && e.UserID == nil && e.Details["email"] == "test@example.com"
+	})).Once()
+	audit.On("Record", mock.Anything, recorded(model.AuditPasswordChanged, 1)).Once()
+	audit.On("Record", mock.Anything, recorded(model.AuditAccountDeleted, 1)).Once()
+	audit.On("Record", mock.Anything, recorded(model.AuditLogout, 1)).Once()
+
+	u := usecase.NewUserUsecase(mockUserRepo, noEvents(), audit, lenPolicy(), bcryptHasher(), time.Second*2,
+		config.RateLimitConfig{})
+
+	_, err := u.Login(context.TODO(), "mrtest@example.com", "super_password")
+	assert.NoError(t, err)
+
+	_, err = u.Login(context.TODO(), "mrtest@example.com", "super")
+	assert.Error(t, err)
+
+	_, err = u.Login(context.TODO(), "test@example.com", "super_password")
+	assert.Error(t, err)
+
+	user := mockUser
+	assert.NoError(t, u.Update(context.TODO(), &user, model.Password{
+		OldPassword: "super_password", NewPassword: "super_new_pass", IsChanged: true,
+	}))
+
+	u.Logout(context.TODO(), 1)
+
+	user.IsTrashed = 1
+	assert.NoError(t, u.Update(context.TODO(), &user, model.Password{}))
+
+	audit.AssertExpectations(t)
 }
 
 func TestDelete(t *testing.T) {
@@ -295,10 +609,128 @@ func TestDelete(t *testing.T) {
 		mockUserRepo.On("UpdateUser", mock.Anything, mock.AnythingOfType("*model.User")).
 			Return(nil).Once()
 
-		u := usecase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
 		err := u.Update(context.TODO(), &existingUser, pass)
 
 		assert.NoError(t, err)
 		mockUserRepo.AssertExpectations(t)
 	})
 }
+
+func TestMeta(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	colors := []model.CustomColor{{ID: 1, UserID: 1, Name: "sea green", Hex: "#2e8b57"}}
+	mockUserRepo.On("FetchColors", mock.Anything, int32(1)).Return(colors, nil).Once()
+
+	u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+		config.RateLimitConfig{})
+	meta, err := u.Meta(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Colors, meta.Colors)
+	assert.Equal(t, colors, meta.CustomColors)
+	assert.Equal(t, []string{"note", "list", "drawing"}, meta.NoteTypes)
+}
+
+func TestAddColor(t *testing.T) {
+	existing := []model.CustomColor{{ID: 1, UserID: 1, Name: "sea green", Hex: "#2e8b57"}}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("FetchColors", mock.Anything, int32(1)).Return(existing, nil).Once()
+		mockUserRepo.On("CreateColor", mock.Anything, mock.MatchedBy(func(c *model.CustomColor) bool {
+			return c.Hex == "#ffaa00"
+		})).Return(nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
+		assert.NoError(t, u.AddColor(context.TODO(), &model.CustomColor{UserID: 1, Name: "amber", Hex: "#FFAA00"}))
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("FetchColors", mock.Anything, int32(1)).Return(existing, nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
+
+		for _, name := range []string{"sea green", "dark blue"} {
+			err := u.AddColor(context.TODO(), &model.CustomColor{UserID: 1, Name: name, Hex: "#000000"})
+			code, _ := response.RespondError(err)
+			assert.Equal(t, http.StatusConflict, code, name)
+		}
+	})
+
+	t.Run("too-many", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("FetchColors", mock.Anything, int32(1)).Return(make([]model.CustomColor, 50), nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
+		err := u.AddColor(context.TODO(), &model.CustomColor{UserID: 1, Name: "amber", Hex: "#ffaa00"})
+		code, _ := response.RespondError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+func TestGetKeyEnvelope(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetKeyEnvelope", mock.Anything, int32(1)).
+		Return(model.KeyEnvelope{UserID: 1, WrappedKey: "d3JhcHBlZA==", KeyVersion: 2}, nil).Once()
+	mockUserRepo.On("GetKeyEnvelope", mock.Anything, int32(2)).Return(model.KeyEnvelope{}, sql.ErrNoRows).Once()
+
+	u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+		config.RateLimitConfig{})
+
+	envelope, err := u.GetKeyEnvelope(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), envelope.KeyVersion)
+
+	_, err = u.GetKeyEnvelope(context.TODO(), 2)
+	assert.ErrorIs(t, err, response.ErrNotFound)
+}
+
+func TestSaveKeyEnvelope(t *testing.T) {
+	current := model.KeyEnvelope{UserID: 1, WrappedKey: "b2xk", KeyVersion: 2, CreatedAt: "2022-01-31 09:00:00"}
+
+	t.Run("first", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetKeyEnvelope", mock.Anything, int32(1)).Return(model.KeyEnvelope{}, sql.ErrNoRows).Once()
+		mockUserRepo.On("SaveKeyEnvelope", mock.Anything, mock.AnythingOfType("*model.KeyEnvelope")).
+			Return(nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
+		assert.NoError(t, u.SaveKeyEnvelope(context.TODO(), &model.KeyEnvelope{UserID: 1, KeyVersion: 1}))
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("new-passphrase", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetKeyEnvelope", mock.Anything, int32(1)).Return(current, nil).Once()
+		mockUserRepo.On("SaveKeyEnvelope", mock.Anything, mock.MatchedBy(func(m *model.KeyEnvelope) bool {
+			return m.WrappedKey == "bmV3" && m.CreatedAt == current.CreatedAt
+		})).Return(nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
+		envelope := &model.KeyEnvelope{UserID: 1, WrappedKey: "bmV3", KeyVersion: 2, CreatedAt: "2022-02-01 09:00:00"}
+		assert.NoError(t, u.SaveKeyEnvelope(context.TODO(), envelope))
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("older-version", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetKeyEnvelope", mock.Anything, int32(1)).Return(current, nil).Once()
+
+		u := usecase.NewUserUsecase(mockUserRepo, noEvents(), noAudit(), lenPolicy(), bcryptHasher(), time.Second*2,
+			config.RateLimitConfig{})
+		err := u.SaveKeyEnvelope(context.TODO(), &model.KeyEnvelope{UserID: 1, KeyVersion: 1})
+
+		code, _ := response.RespondError(err)
+		assert.Equal(t, http.StatusConflict, code)
+		mockUserRepo.AssertNotCalled(t, "SaveKeyEnvelope", mock.Anything, mock.Anything)
+	})
+}
@@ -3,12 +3,20 @@ package validation
 import (
 	"errors"
 	"fmt"
+	"librenote/app/model"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
 
+// colorName is the name of a custom color, like "sea green"
+var colorName = regexp.MustCompile(`^[a-z0-9]+([ -][a-z0-9]+)*$`)
+
+// maxColorName is the size of users_colors.name
+const maxColorName = 20
+
 func Validate(r interface{}) (bool, error) {
 	validate := validator.New()
 	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
@@ -19,6 +27,17 @@ func Validate(r interface{}) (bool, error) {
 		return name
 	})
 
+	for tag, fn := range map[string]validator.Func{
+		"notecolor":   isNoteColor,
+		"notetype":    isNoteType,
+		"colorname":   isColorName,
+		"auditaction": isAuditAction,
+	} {
+		if err := validate.RegisterValidation(tag, fn); err != nil {
+			return false, err
+		}
+	}
+
 	err := validate.Struct(r)
 	if err != nil {
 		return false, err
@@ -27,6 +46,28 @@ func Validate(r interface{}) (bool, error) {
 	return true, nil
 }
 
+// isNoteColor accepts no color, a color of the palette or the name of a custom color,
+// the note usecase checks the user has that custom color
+func isNoteColor(fl validator.FieldLevel) bool {
+	color := fl.Field().String()
+
+	return color == "" || model.IsColor(color) || isColorName(fl)
+}
+
+func isNoteType(fl validator.FieldLevel) bool {
+	return model.IsNoteType(fl.Field().String())
+}
+
+func isAuditAction(fl validator.FieldLevel) bool {
+	return model.IsAuditAction(fl.Field().String())
+}
+
+func isColorName(fl validator.FieldLevel) bool {
+	name := fl.Field().String()
+
+	return len(name) <= maxColorName && colorName.MatchString(name)
+}
+
 func FormatErrors(err error) (map[string]interface{}, error) {
 	var ve validator.ValidationErrors
 	if errors.As(err, &ve) {
@@ -45,8 +86,12 @@ func FormatErrors(err error) (map[string]interface{}, error) {
 
 func msgForField(fe validator.FieldError) string {
 	switch fe.Tag() {
-	case "required":
+	case "required", "required_if":
 		return "This field is required"
+	case "required_without":
+		return fmt.Sprintf("This field is required without %s", strings.ToLower(fe.Param()))
+	case "excluded_with":
+		return fmt.Sprintf("Not allowed along with %s", strings.ToLower(fe.Param()))
 	case "email":
 		return "Invalid email"
 	case "min":
@@ -63,6 +108,25 @@ func msgForField(fe validator.FieldError) string {
 		}
 
 		return m
+	case "gt":
+		return fmt.Sprintf("Must be greater than %v", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("Must be one of [%v]", fe.Param())
+	case "len":
+		return fmt.Sprintf("Must be %v characters", fe.Param())
+	case "base64":
+		return "Must be base64 encoded"
+	case "hexcolor":
+		return "Invalid hex color, like #a1b2c3"
+	case "notecolor":
+		return "Must be a color of the palette or a custom color"
+	case "notetype":
+		return fmt.Sprintf("Must be one of [%v]", strings.Join(model.NoteTypes, " "))
+	case "auditaction":
+		return fmt.Sprintf("Must be one of [%v]", strings.Join(model.AuditActions, " "))
+	case "colorname":
+		return fmt.Sprintf("Lowercase letters, digits, single spaces or dashes, not more than %d characters",
+			maxColorName)
 	}
 
 	return "unknown error"
@@ -0,0 +1,77 @@
+package validation_test
+
+import (
+	"librenote/app/validation"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type colorsReq struct {
+	Color  string `json:"color" validate:"notecolor"`
+	Type   string `json:"type" validate:"notetype"`
+	Name   string `json:"name" validate:"omitempty,colorname"`
+	Action string `json:"action" validate:"omitempty,auditaction"`
+}
+
+func TestCustomTags(t *testing.T) {
+	for _, req := range []colorsReq{
+		{Type: "note"},
+		{Color: "dark blue", Type: "list"},
+		{Color: "sea-green 2", Type: "drawing", Name: "sea-green 2"},
+		{Type: "note", Action: "admin.granted"},
+	} {
+		ok, err := validation.Validate(&req)
+		assert.True(t, ok, req)
+		assert.NoError(t, err)
+	}
+
+	cases := map[string]colorsReq{
+		"color":  {Color: "Red", Type: "note"},
+		"type":   {Type: "sketch"},
+		"name":   {Type: "note", Name: "a very very long color name"},
+		"action": {Type: "note", Action: "admin.promoted"},
+	}
+
+	for field, req := range cases {
+		ok, err := validation.Validate(&req)
+		assert.False(t, ok, field)
+
+		errs, err := validation.FormatErrors(err)
+		assert.NoError(t, err)
+		assert.Contains(t, errs, field)
+	}
+
+	_, err := validation.Validate(&colorsReq{Type: "sketch"})
+	errs, _ := validation.FormatErrors(err)
+	assert.Equal(t, "Must be one of [note list drawing]", errs["type"])
+}
+
+func TestEitherField(t *testing.T) {
+	type eitherReq struct {
+		IDs    []int32 `json:"ids" validate:"required_without=Filter,excluded_with=Filter"`
+		Filter *string `json:"filter" validate:"required_without=IDs"`
+	}
+
+	_, err := validation.Validate(&eitherReq{})
+	errs, _ := validation.FormatErrors(err)
+	assert.Equal(t, "This field is required without filter", errs["ids"])
+
+	filter := "all"
+	_, err = validation.Validate(&eitherReq{IDs: []int32{1}, Filter: &filter})
+	errs, _ = validation.FormatErrors(err)
+	assert.Equal(t, "Not allowed along with filter", errs["ids"])
+}
+
+func TestBase64(t *testing.T) {
+	type cipherReq struct {
+		Ciphertext string `json:"ciphertext" validate:"required,base64"`
+	}
+
+	ok, _ := validation.Validate(&cipherReq{Ciphertext: "c2VjcmV0"})
+	assert.True(t, ok)
+
+	_, err := validation.Validate(&cipherReq{Ciphertext: "not base64!"})
+	errs, _ := validation.FormatErrors(err)
+	assert.Equal(t, "Must be base64 encoded", errs["ciphertext"])
+}
@@ -0,0 +1,21 @@
+package http
+
+type createWebhookReq struct {
+	URL      string   `json:"url" validate:"required,max=1000"`
+	Secret   string   `json:"secret" validate:"required,min=8,max=255"`
+	Events   []string `json:"events" validate:"required,min=1,dive,oneof=* note.created note.updated note.deleted label.created label.updated label.deleted account.updated account.deleted"` // nolint:lll
+	IsActive *int8    `json:"is_active" validate:"omitempty,min=0,max=1"`
+}
+
+type updateWebhookReq struct {
+	URL string `json:"url" validate:"required,max=1000"`
+	// the current secret is kept when empty
+	Secret   string   `json:"secret" validate:"omitempty,min=8,max=255"`
+	Events   []string `json:"events" validate:"required,min=1,dive,oneof=* note.created note.updated note.deleted label.created label.updated label.deleted account.updated account.deleted"` // nolint:lll
+	IsActive *int8    `json:"is_active" validate:"omitempty,min=0,max=1"`
+}
+
+type fetchDeliveriesReq struct {
+	Page     int `json:"page" query:"page" validate:"omitempty,min=1"`
+	PageSize int `json:"page_size" query:"page_size" validate:"omitempty,min=1"`
+}
@@ -0,0 +1,228 @@
+package http
+
+import (
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/app/validation"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookHandler represent the http handler for webhook
+type WebhookHandler struct {
+	WUseCase model.WebhookUsecase
+}
+
+func NewWebhookHandler(e *echo.Echo, us model.WebhookUsecase) {
+	handler := &WebhookHandler{
+		WUseCase: us,
+	}
+
+	webhooks := e.Group("/api/v1/webhooks")
+	_ = middlewares.AttachJwtToGroup(webhooks)
+	webhooks.GET("", handler.FetchWebhooks)
+	webhooks.POST("", handler.CreateWebhook)
+	webhooks.GET("/:id", handler.GetWebhook)
+	webhooks.PUT("/:id", handler.UpdateWebhook)
+	webhooks.DELETE("/:id", handler.DeleteWebhook)
+	webhooks.GET("/:id/deliveries", handler.FetchDeliveries)
+	webhooks.POST("/:id/test", handler.SendTest)
+}
+
+func (w *WebhookHandler) FetchWebhooks(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	webhooks, err := w.WUseCase.Fetch(ctx, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", webhooks))
+}
+
+func (w *WebhookHandler) CreateWebhook(c echo.Context) error {
+	var wReq createWebhookReq
+
+	err := c.Bind(&wReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&wReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	webhook := model.Webhook{
+		UserID:    middlewares.GetUserID(c),
+		URL:       wReq.URL,
+		Secret:    wReq.Secret,
+		Events:    wReq.Events,
+		IsActive:  isActive(wReq.IsActive),
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+	}
+
+	ctx := c.Request().Context()
+
+	err = w.WUseCase.Create(ctx, &webhook)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("webhook created", webhook))
+}
+
+func (w *WebhookHandler) GetWebhook(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	webhook, err := w.WUseCase.Get(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("request success", webhook))
+}
+
+func (w *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var wReq updateWebhookReq
+
+	err = c.Bind(&wReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&wReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	webhook := model.Webhook{
+		ID:        id,
+		UserID:    middlewares.GetUserID(c),
+		URL:       wReq.URL,
+		Secret:    wReq.Secret,
+		Events:    wReq.Events,
+		IsActive:  isActive(wReq.IsActive),
+		UpdatedAt: time.Now().UTC().Format("2006-01-02 15:04:05"),
+	}
+
+	ctx := c.Request().Context()
+
+	err = w.WUseCase.Update(ctx, &webhook)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("updated successfully", webhook))
+}
+
+func (w *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	err = w.WUseCase.Delete(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (w *WebhookHandler) FetchDeliveries(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	var fReq fetchDeliveriesReq
+
+	err = c.Bind(&fReq)
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrUnprocessableEntity, err))
+	}
+
+	if ok, err := validation.Validate(&fReq); !ok {
+		valErrors, valErr := validation.FormatErrors(err)
+		if valErr != nil {
+			return c.JSON(response.RespondError(response.ErrBadRequest, valErr))
+		}
+
+		return c.JSON(response.RespondValidationError(response.ErrBadRequest, valErrors))
+	}
+
+	cfg := config.Get().App
+
+	if fReq.Page == 0 {
+		fReq.Page = 1
+	}
+
+	if fReq.PageSize == 0 {
+		fReq.PageSize = cfg.DefaultPageSize
+	}
+
+	if cfg.MaxPageSize > 0 && fReq.PageSize > cfg.MaxPageSize {
+		fReq.PageSize = cfg.MaxPageSize
+	}
+
+	ctx := c.Request().Context()
+
+	deliveries, count, err := w.WUseCase.FetchDeliveries(ctx, id, middlewares.GetUserID(c), fReq.Page, fReq.PageSize)
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondPage("request success", deliveries, count, fReq.Page, fReq.PageSize))
+}
+
+func (w *WebhookHandler) SendTest(c echo.Context) error {
+	id, err := middlewares.ParamID(c, "id")
+	if err != nil {
+		return c.JSON(response.RespondError(response.ErrBadRequest, err))
+	}
+
+	ctx := c.Request().Context()
+
+	delivery, err := w.WUseCase.SendTest(ctx, id, middlewares.GetUserID(c))
+	if err != nil {
+		return c.JSON(response.RespondError(err))
+	}
+
+	return c.JSON(response.RespondSuccess("test event sent", delivery))
+}
+
+// isActive defaults to an active webhook
+func isActive(v *int8) int8 {
+	if v == nil {
+		return 1
+	}
+
+	return *v
+}
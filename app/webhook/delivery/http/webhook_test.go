@@ -0,0 +1,172 @@
+package http_test
+
+import (
+	"encoding/json"
+	"io"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	webhookHttp "librenote/app/webhook/delivery/http"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var BaseURLV1 = "/api/v1"
+
+func buildEchoAuthorizedRequest(t *testing.T, method, path, token string, payload io.Reader) (
+	echo.Context, *httptest.ResponseRecorder) {
+	var req *http.Request
+
+	var err error
+
+	if payload != nil {
+		req, err = http.NewRequest(method, path, payload)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	} else {
+		req, err = http.NewRequest(method, path, nil)
+	}
+
+	assert.NoError(t, err)
+
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+	res := httptest.NewRecorder()
+	e := echo.New()
+	ctx := e.NewContext(req, res)
+
+	return ctx, res
+}
+
+// nolint:unparam
+func getToken(userID int32) string {
+	jwtCfg := config.Get().Jwt
+	claims := &middlewares.JwtCustomClaims{
+		UserID: userID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(jwtCfg.ExpireTime).Unix(),
+		},
+	}
+	unsignedToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token, _ := unsignedToken.SignedString([]byte(jwtCfg.SecretKey))
+
+	return token
+}
+
+func attachJWTMiddleware(hfc echo.HandlerFunc) echo.HandlerFunc {
+	mhfc := middleware.JWTWithConfig(
+		middleware.JWTConfig{
+			Claims:     &middlewares.JwtCustomClaims{},
+			SigningKey: []byte(config.Get().Jwt.SecretKey),
+		})(hfc)
+
+	return mhfc
+}
+
+func TestCreateWebhook(t *testing.T) {
+	endPoint := BaseURLV1 + "/webhooks"
+
+	mockUsecase := new(mocks.WebhookUsecase)
+	mockUsecase.On("Create", mock.Anything, mock.MatchedBy(func(w *model.Webhook) bool {
+		return w.UserID == 1 && w.IsActive == 1 && w.Secret == "s3cr3t-key"
+	})).Return(nil).Once()
+
+	handler := webhookHttp.WebhookHandler{
+		WUseCase: mockUsecase,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		body := `{"url": "http://localhost:9000/hook", "secret": "s3cr3t-key", "events": ["note.created", "*"]}`
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+		handle := attachJWTMiddleware(handler.CreateWebhook)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		var r response.Response
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+		assert.True(t, r.Success)
+
+		resultsMap := r.Results.(map[string]interface{})
+		assert.NotContains(t, resultsMap, "secret")
+		assert.Len(t, resultsMap["events"], 2)
+
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"unknown-event":  `{"url": "http://localhost:9000/hook", "secret": "s3cr3t-key", "events": ["note.read"]}`,
+			"missing-secret": `{"url": "http://localhost:9000/hook", "events": ["*"]}`,
+			"no-events":      `{"url": "http://localhost:9000/hook", "secret": "s3cr3t-key", "events": []}`,
+		} {
+			ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), strings.NewReader(body))
+			handle := attachJWTMiddleware(handler.CreateWebhook)
+
+			assert.NoError(t, handle(ctx))
+			assert.Equal(t, http.StatusBadRequest, res.Code, name)
+		}
+	})
+}
+
+func TestFetchDeliveries(t *testing.T) {
+	endPoint := BaseURLV1 + "/webhooks/:id/deliveries?page=2&page_size=1"
+
+	mockUsecase := new(mocks.WebhookUsecase)
+	mockUsecase.On("FetchDeliveries", mock.Anything, int32(2), int32(1), 2, 1).
+		Return([]model.WebhookDelivery{{ID: 5, WebhookID: 2, Event: model.EventPing}}, 3, nil).Once()
+
+	handler := webhookHttp.WebhookHandler{
+		WUseCase: mockUsecase,
+	}
+
+	ctx, res := buildEchoAuthorizedRequest(t, echo.GET, endPoint, getToken(1), nil)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("2")
+	handle := attachJWTMiddleware(handler.FetchDeliveries)
+
+	assert.NoError(t, handle(ctx))
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var r response.Response
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+	assert.Equal(t, 3, *r.Count)
+	assert.Equal(t, 3, *r.Next)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestSendTest(t *testing.T) {
+	endPoint := BaseURLV1 + "/webhooks/:id/test"
+
+	mockUsecase := new(mocks.WebhookUsecase)
+	mockUsecase.On("SendTest", mock.Anything, int32(2), int32(1)).
+		Return(&model.WebhookDelivery{ID: 5, Status: model.DeliveryDelivered, ResponseStatus: 200}, nil).Once()
+	mockUsecase.On("SendTest", mock.Anything, int32(3), int32(1)).Return(nil, response.ErrNotFound).Once()
+
+	handler := webhookHttp.WebhookHandler{
+		WUseCase: mockUsecase,
+	}
+
+	for id, code := range map[string]int{"2": http.StatusOK, "3": http.StatusNotFound} {
+		ctx, res := buildEchoAuthorizedRequest(t, echo.POST, endPoint, getToken(1), nil)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues(id)
+		handle := attachJWTMiddleware(handler.SendTest)
+
+		assert.NoError(t, handle(ctx))
+		assert.Equal(t, code, res.Code)
+	}
+
+	mockUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,71 @@
+package encrypted
+
+import (
+	"context"
+	"librenote/app/encryption"
+	"librenote/app/model"
+)
+
+// webhookRepository encrypts the payloads of the deliveries stored by another repository, as they hold the notes
+// of the events, and decrypts them back on reading. The webhooks themselves stay in clear
+type webhookRepository struct {
+	model.WebhookRepository
+	keyring *encryption.Keyring
+}
+
+// NewWebhookRepository wraps the repository of any database
+func NewWebhookRepository(repo model.WebhookRepository, keyring *encryption.Keyring) model.WebhookRepository {
+	return &webhookRepository{
+		WebhookRepository: repo,
+		keyring:           keyring,
+	}
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	encDelivery := *delivery
+
+	var err error
+	if encDelivery.Payload, err = r.keyring.EncryptString(delivery.Payload); err != nil {
+		return err
+	}
+
+	if err = r.WebhookRepository.CreateDelivery(ctx, &encDelivery); err != nil {
+		return err
+	}
+
+	delivery.ID = encDelivery.ID
+
+	return nil
+}
+
+func (r *webhookRepository) FetchDeliveries(ctx context.Context, webhookID int32, limit, offset int) (
+	[]model.WebhookDelivery, int, error) {
+	deliveries, total, err := r.WebhookRepository.FetchDeliveries(ctx, webhookID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, r.decrypt(deliveries)
+}
+
+func (r *webhookRepository) FetchDueDeliveries(ctx context.Context, now string, limit int) (
+	[]model.WebhookDelivery, error) {
+	deliveries, err := r.WebhookRepository.FetchDueDeliveries(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, r.decrypt(deliveries)
+}
+
+func (r *webhookRepository) decrypt(deliveries []model.WebhookDelivery) error {
+	var err error
+
+	for i := range deliveries {
+		if deliveries[i].Payload, err = r.keyring.DecryptString(deliveries[i].Payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package encrypted_test
+
+import (
+	"context"
+	"librenote/app/encryption"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/webhook/repository/encrypted"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeliveries(t *testing.T) {
+	key, err := encryption.GenerateKey()
+	assert.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(key)
+	assert.NoError(t, err)
+
+	delivery := &model.WebhookDelivery{WebhookID: 2, UserID: 1, Event: "note.created", Payload: `{"title":"Groceries"}`}
+
+	var payload string
+
+	mockRepo := new(mocks.WebhookRepository)
+	mockRepo.On("CreateDelivery", mock.Anything, mock.AnythingOfType("*model.WebhookDelivery")).
+		Run(func(args mock.Arguments) {
+			stored := args.Get(1).(*model.WebhookDelivery)
+			stored.ID = 6
+			payload = stored.Payload
+		}).Return(nil).Once()
+
+	r := encrypted.NewWebhookRepository(mockRepo, keyring)
+	assert.NoError(t, r.CreateDelivery(context.TODO(), delivery))
+	assert.True(t, keyring.IsCurrent(payload))
+	assert.Equal(t, `{"title":"Groceries"}`, delivery.Payload)
+	assert.Equal(t, int32(6), delivery.ID)
+
+	mockRepo.On("FetchDueDeliveries", mock.Anything, "2022-01-31 09:00:00", 10).
+		Return([]model.WebhookDelivery{{ID: 6, Payload: payload}, {ID: 7, Payload: "{}"}}, nil).Once()
+	mockRepo.On("FetchDeliveries", mock.Anything, int32(2), 10, 0).
+		Return([]model.WebhookDelivery{{ID: 6, Payload: payload}}, 1, nil).Once()
+
+	deliveries, err := r.FetchDueDeliveries(context.TODO(), "2022-01-31 09:00:00", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"title":"Groceries"}`, deliveries[0].Payload)
+	assert.Equal(t, "{}", deliveries[1].Payload)
+
+	deliveries, total, err := r.FetchDeliveries(context.TODO(), 2, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, `{"title":"Groceries"}`, deliveries[0].Payload)
+	mockRepo.AssertExpectations(t)
+}
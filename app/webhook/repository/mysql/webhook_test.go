@@ -0,0 +1,127 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"librenote/app/model"
+	webhookRepo "librenote/app/webhook/repository/mysql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	w := &model.Webhook{
+		UserID: 1, URL: "http://localhost:9000/hook", Secret: "s3cr3t-key", IsActive: 1,
+		Events: []string{model.EventNoteCreated, model.EventNoteDeleted}, CreatedAt: nowTime, UpdatedAt: nowTime,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO webhooks").
+		WithArgs(w.UserID, w.URL, w.Secret, "note.created,note.deleted", w.IsActive, w.CreatedAt, w.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	wr := webhookRepo.NewMysqlWebhookRepository(db)
+	assert.NoError(t, wr.CreateWebhook(context.TODO(), w))
+	assert.Equal(t, int32(2), w.ID)
+}
+
+func TestGetWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	rows := sqlmock.NewRows([]string{"id", "user_id", "url", "secret", "events", "is_active", "created_at",
+		"updated_at"}).
+		AddRow(2, 1, "http://localhost:9000/hook", "s3cr3t-key", "*", 1, nowTime, nowTime)
+
+	mock.ExpectQuery("SELECT (.+) FROM webhooks WHERE").WithArgs(int32(2), int32(1)).WillReturnRows(rows)
+
+	wr := webhookRepo.NewMysqlWebhookRepository(db)
+	webhook, err := wr.GetWebhook(context.TODO(), 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"*"}, webhook.Events)
+	assert.Equal(t, "s3cr3t-key", webhook.Secret)
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	wr := webhookRepo.NewMysqlWebhookRepository(db)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM webhooks").WithArgs(int32(2), int32(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectExec("DELETE FROM webhooks_deliveries").WithArgs(int32(2)).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec("DELETE FROM webhooks").WithArgs(int32(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, wr.DeleteWebhook(context.TODO(), 2, 1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not-owner", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM webhooks").WithArgs(int32(2), int32(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, wr.DeleteWebhook(context.TODO(), 2, 3), sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFetchDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	rows := sqlmock.NewRows([]string{"id", "webhook_id", "user_id", "event", "payload", "status", "attempts",
+		"due_at", "response_status", "last_error", "created_at", "updated_at"}).
+		AddRow(5, 2, 1, "ping", "{}", "delivered", 1, nowTime, 200, "", nowTime, nowTime)
+
+	mock.ExpectQuery("SELECT COUNT").WithArgs(int32(2)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(6))
+	mock.ExpectQuery("SELECT (.+) FROM webhooks_deliveries").WithArgs(int32(2), 5, 5).WillReturnRows(rows)
+
+	wr := webhookRepo.NewMysqlWebhookRepository(db)
+	deliveries, count, err := wr.FetchDeliveries(context.TODO(), 2, 5, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, count)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, 200, deliveries[0].ResponseStatus)
+}
+
+func TestClaimDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE webhooks_deliveries SET due_at").WithArgs("lease", int32(5), "due").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	wr := webhookRepo.NewMysqlWebhookRepository(db)
+	ok, err := wr.ClaimDelivery(context.TODO(), 5, "due", "lease")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,303 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"librenote/app/model"
+	"strings"
+)
+
+type webhookRepository struct {
+	db *sql.DB
+}
+
+func NewPgsqlWebhookRepository(db *sql.DB) model.WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+const createWebhook = `INSERT INTO webhooks (
+  user_id, url, secret, events, is_active, created_at, updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING id
+`
+
+func (r *webhookRepository) CreateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	return r.db.QueryRowContext(ctx, createWebhook,
+		webhook.UserID,
+		webhook.URL,
+		webhook.Secret,
+		strings.Join(webhook.Events, ","),
+		webhook.IsActive,
+		webhook.CreatedAt,
+		webhook.UpdatedAt,
+	).Scan(&webhook.ID)
+}
+
+const webhookColumns = `id, user_id, url, secret, events, is_active, created_at::text, updated_at::text`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhook(row scanner) (model.Webhook, error) {
+	var (
+		i      model.Webhook
+		events string
+	)
+
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.URL,
+		&i.Secret,
+		&events,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	i.Events = strings.Split(events, ",")
+
+	return i, err
+}
+
+const getWebhook = `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+func (r *webhookRepository) GetWebhook(ctx context.Context, id, userID int32) (model.Webhook, error) {
+	return scanWebhook(r.db.QueryRowContext(ctx, getWebhook, id, userID))
+}
+
+const fetchWebhooks = `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY id`
+
+func (r *webhookRepository) FetchWebhooks(ctx context.Context, userID int32) ([]model.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, fetchWebhooks, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	webhooks := make([]model.Webhook, 0)
+
+	for rows.Next() {
+		i, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, i)
+	}
+
+	return webhooks, rows.Err()
+}
+
+const updateWebhook = `UPDATE webhooks
+SET url = $1,
+secret = $2,
+events = $3,
+is_active = $4,
+updated_at = $5
+WHERE id = $6 AND user_id = $7
+`
+
+func (r *webhookRepository) UpdateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	res, err := r.db.ExecContext(ctx, updateWebhook,
+		webhook.URL,
+		webhook.Secret,
+		strings.Join(webhook.Events, ","),
+		webhook.IsActive,
+		webhook.UpdatedAt,
+		webhook.ID,
+		webhook.UserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return errors.New("nothing changed")
+	}
+
+	return nil
+}
+
+const getWebhookID = `SELECT id FROM webhooks WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+const deleteWebhookDeliveries = `DELETE FROM webhooks_deliveries WHERE webhook_id = $1`
+
+const deleteWebhook = `DELETE FROM webhooks WHERE id = $1`
+
+func (r *webhookRepository) DeleteWebhook(ctx context.Context, id, userID int32) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	if err = tx.QueryRowContext(ctx, getWebhookID, id, userID).Scan(&id); err != nil {
+		return err
+	}
+
+	for _, query := range []string{deleteWebhookDeliveries, deleteWebhook} {
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+const createDelivery = `INSERT INTO webhooks_deliveries (
+  webhook_id, user_id, event, payload, status, attempts, due_at, response_status, last_error, created_at, updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING id
+`
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return r.db.QueryRowContext(ctx, createDelivery,
+		delivery.WebhookID,
+		delivery.UserID,
+		delivery.Event,
+		delivery.Payload,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.DueAt,
+		delivery.ResponseStatus,
+		delivery.LastError,
+		delivery.CreatedAt,
+		delivery.UpdatedAt,
+	).Scan(&delivery.ID)
+}
+
+const deliveryColumns = `id, webhook_id, user_id, event, payload, status, attempts, due_at::text, response_status,
+last_error, created_at::text, updated_at::text`
+
+func scanDeliveries(rows *sql.Rows) ([]model.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := make([]model.WebhookDelivery, 0)
+
+	for rows.Next() {
+		var i model.WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.UserID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.DueAt,
+			&i.ResponseStatus,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, i)
+	}
+
+	return deliveries, rows.Err()
+}
+
+const countDeliveries = `SELECT COUNT(*) FROM webhooks_deliveries WHERE webhook_id = $1`
+
+const fetchDeliveries = `SELECT ` + deliveryColumns + ` FROM webhooks_deliveries
+WHERE webhook_id = $1 ORDER BY id DESC LIMIT $2 OFFSET $3
+`
+
+func (r *webhookRepository) FetchDeliveries(ctx context.Context, webhookID int32, limit, offset int) (
+	[]model.WebhookDelivery, int, error) {
+	var count int
+
+	if err := r.db.QueryRowContext(ctx, countDeliveries, webhookID).Scan(&count); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, fetchDeliveries, webhookID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, err := scanDeliveries(rows)
+
+	return deliveries, count, err
+}
+
+const fetchDueDeliveries = `SELECT ` + deliveryColumns + ` FROM webhooks_deliveries
+WHERE status = 'pending' AND due_at <= $1 ORDER BY due_at, id LIMIT $2
+`
+
+func (r *webhookRepository) FetchDueDeliveries(ctx context.Context, now string, limit int) (
+	[]model.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, fetchDueDeliveries, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanDeliveries(rows)
+}
+
+const claimDelivery = `UPDATE webhooks_deliveries SET due_at = $1 WHERE id = $2 AND due_at = $3 AND status = 'pending'`
+
+func (r *webhookRepository) ClaimDelivery(ctx context.Context, id int32, dueAt, leaseUntil string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, claimDelivery, leaseUntil, id, dueAt)
+	if err != nil {
+		return false, err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affect == 1, nil
+}
+
+const updateDelivery = `UPDATE webhooks_deliveries
+SET status = $1,
+attempts = $2,
+due_at = $3,
+response_status = $4,
+last_error = $5,
+updated_at = $6
+WHERE id = $7
+`
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	res, err := r.db.ExecContext(ctx, updateDelivery,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.DueAt,
+		delivery.ResponseStatus,
+		delivery.LastError,
+		delivery.UpdatedAt,
+		delivery.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return errors.New("nothing changed")
+	}
+
+	return nil
+}
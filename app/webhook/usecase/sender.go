@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"librenote/app/model"
+	"net/http"
+	"strconv"
+)
+
+// maxErrorLength keeps last_error within its column
+const maxErrorLength = 1000
+
+const (
+	HeaderEvent     = "X-LibreNote-Event"
+	HeaderDelivery  = "X-LibreNote-Delivery"
+	HeaderSignature = "X-LibreNote-Signature"
+)
+
+// Sign returns the value of the signature header, the hex encoded HMAC-SHA256 of the body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// send posts the payload and returns the response status code
+func (u *webhookUsecase) send(ctx context.Context, webhook *model.Webhook, delivery *model.WebhookDelivery) (
+	int, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LibreNote-Webhook")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(int(delivery.ID)))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, body))
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	// drain, so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,336 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"librenote/app/model"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/httpclient"
+	"librenote/infrastructure/logger"
+	"net/http"
+	"time"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+type webhookUsecase struct {
+	repo           model.WebhookRepository
+	client         *http.Client
+	contextTimeout time.Duration
+	cfg            config.WebhookConfig
+}
+
+func NewWebhookUsecase(repo model.WebhookRepository, timeout time.Duration,
+	cfg config.WebhookConfig) model.WebhookUsecase {
+	return &webhookUsecase{
+		repo:           repo,
+		client:         httpclient.New(cfg.Timeout, cfg.AllowPrivate),
+		contextTimeout: timeout,
+		cfg:            cfg,
+	}
+}
+
+func (u *webhookUsecase) Create(c context.Context, m *model.Webhook) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err := u.checkURL(m.URL); err != nil {
+		return err
+	}
+
+	return u.repo.CreateWebhook(ctx, m)
+}
+
+func (u *webhookUsecase) checkURL(rawURL string) error {
+	if err := httpclient.CheckURL(rawURL, u.cfg.AllowPrivate); err != nil {
+		return response.WrapError(fmt.Errorf("url %s", err), http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (u *webhookUsecase) Fetch(c context.Context, userID int32) ([]model.Webhook, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.repo.FetchWebhooks(ctx, userID)
+}
+
+func (u *webhookUsecase) Get(c context.Context, id, userID int32) (*model.Webhook, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.getWebhook(ctx, id, userID)
+}
+
+func (u *webhookUsecase) getWebhook(ctx context.Context, id, userID int32) (*model.Webhook, error) {
+	webhook, err := u.repo.GetWebhook(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, response.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// Update keeps the current secret when m.Secret is empty
+func (u *webhookUsecase) Update(c context.Context, m *model.Webhook) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	current, err := u.getWebhook(ctx, m.ID, m.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err = u.checkURL(m.URL); err != nil {
+		return err
+	}
+
+	if m.Secret == "" {
+		m.Secret = current.Secret
+	}
+
+	m.CreatedAt = current.CreatedAt
+
+	return u.repo.UpdateWebhook(ctx, m)
+}
+
+func (u *webhookUsecase) Delete(c context.Context, id, userID int32) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	err := u.repo.DeleteWebhook(ctx, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return response.ErrNotFound
+	}
+
+	return err
+}
+
+func (u *webhookUsecase) FetchDeliveries(c context.Context, id, userID int32, page, pageSize int) (
+	[]model.WebhookDelivery, int, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if page < 1 || pageSize < 1 {
+		return nil, 0, response.ErrInvalidPage
+	}
+
+	if _, err := u.getWebhook(ctx, id, userID); err != nil {
+		return nil, 0, err
+	}
+
+	return u.repo.FetchDeliveries(ctx, id, pageSize, (page-1)*pageSize)
+}
+
+// payload is the json body posted to the webhooks
+type payload struct {
+	Event     string      `json:"event"`
+	UserID    int32       `json:"user_id"`
+	CreatedAt string      `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+func newDelivery(webhook *model.Webhook, event string, data interface{}, now time.Time) (
+	*model.WebhookDelivery, error) {
+	nowTime := now.UTC().Format(timeLayout)
+
+	body, err := json.Marshal(payload{
+		Event:     event,
+		UserID:    webhook.UserID,
+		CreatedAt: nowTime,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WebhookDelivery{
+		WebhookID: webhook.ID,
+		UserID:    webhook.UserID,
+		Event:     event,
+		Payload:   string(body),
+		Status:    model.DeliveryPending,
+		DueAt:     nowTime,
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+	}, nil
+}
+
+// Publish queues the event for every active webhook of the user subscribed to it.
+// Failures are only logged, the change which triggered the event is already stored
+func (u *webhookUsecase) Publish(c context.Context, userID int32, event string, data interface{}) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	webhooks, err := u.repo.FetchWebhooks(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("failed to fetch webhooks of user %d: %s", userID, err)
+		return
+	}
+
+	now := time.Now()
+
+	for i := range webhooks {
+		if webhooks[i].IsActive != 1 || !subscribed(webhooks[i].Events, event) {
+			continue
+		}
+
+		delivery, err := newDelivery(&webhooks[i], event, data, now)
+		if err == nil {
+			err = u.repo.CreateDelivery(ctx, delivery)
+		}
+
+		if err != nil {
+			logger.FromContext(ctx).Errorf("failed to queue %s for webhook %d: %s", event, webhooks[i].ID, err)
+		}
+	}
+}
+
+func subscribed(events []string, event string) bool {
+	for _, e := range events {
+		if e == "*" || e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SendTest delivers a ping event right away, it is retried like any other delivery when it fails
+func (u *webhookUsecase) SendTest(c context.Context, id, userID int32) (*model.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout+u.cfg.Timeout)
+	defer cancel()
+
+	webhook, err := u.getWebhook(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	delivery, err := newDelivery(webhook, model.EventPing, map[string]int32{"webhook_id": webhook.ID}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the scheduler away while sending
+	delivery.DueAt = u.lease(now)
+	if err = u.repo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	u.attempt(ctx, webhook, delivery, now)
+
+	if err = u.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// DeliverDue sends a batch of queued deliveries and returns the number of successful ones.
+// Every delivery is claimed before sending, so several instances can share the same database
+func (u *webhookUsecase) DeliverDue(c context.Context, now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	deliveries, err := u.repo.FetchDueDeliveries(ctx, now.UTC().Format(timeLayout), u.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+
+	for i := range deliveries {
+		ok, err := u.deliver(c, &deliveries[i], now)
+		if err != nil {
+			return delivered, err
+		}
+
+		if ok {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+func (u *webhookUsecase) lease(now time.Time) string {
+	return now.Add(u.cfg.Timeout + u.cfg.RetryBackoff).UTC().Format(timeLayout)
+}
+
+func (u *webhookUsecase) deliver(c context.Context, delivery *model.WebhookDelivery, now time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout+u.cfg.Timeout)
+	defer cancel()
+
+	claimed, err := u.repo.ClaimDelivery(ctx, delivery.ID, delivery.DueAt, u.lease(now))
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	webhook, err := u.repo.GetWebhook(ctx, delivery.WebhookID, delivery.UserID)
+
+	switch {
+	case err != nil:
+		u.retry(delivery, now, err)
+	case webhook.IsActive != 1:
+		delivery.Status = model.DeliveryFailed
+		delivery.LastError = "webhook is inactive"
+	default:
+		u.attempt(ctx, &webhook, delivery, now)
+	}
+
+	delivery.UpdatedAt = now.UTC().Format(timeLayout)
+	if err = u.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return false, err
+	}
+
+	return delivery.Status == model.DeliveryDelivered, nil
+}
+
+// attempt posts the delivery and records the outcome
+func (u *webhookUsecase) attempt(ctx context.Context, webhook *model.Webhook, delivery *model.WebhookDelivery,
+	now time.Time) {
+	delivery.Attempts++
+	delivery.UpdatedAt = now.UTC().Format(timeLayout)
+
+	status, err := u.send(ctx, webhook, delivery)
+	delivery.ResponseStatus = status
+
+	if err != nil {
+		u.retry(delivery, now, err)
+		return
+	}
+
+	delivery.Status = model.DeliveryDelivered
+	delivery.LastError = ""
+}
+
+// retry schedules another attempt with exponential backoff, until MaxAttempts is reached
+func (u *webhookUsecase) retry(delivery *model.WebhookDelivery, now time.Time, err error) {
+	delivery.LastError = err.Error()
+	if len(delivery.LastError) > maxErrorLength {
+		delivery.LastError = delivery.LastError[:maxErrorLength]
+	}
+
+	if delivery.Attempts >= u.cfg.MaxAttempts {
+		delivery.Status = model.DeliveryFailed
+		return
+	}
+
+	attempts := delivery.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delivery.DueAt = now.Add(u.cfg.RetryBackoff << (attempts - 1)).UTC().Format(timeLayout)
+}
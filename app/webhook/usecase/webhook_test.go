@@ -0,0 +1,175 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"librenote/app/model"
+	"librenote/app/model/mocks"
+	"librenote/app/response"
+	"librenote/app/webhook/usecase"
+	"librenote/infrastructure/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var webhookCfg = config.WebhookConfig{
+	PollInterval: time.Second,
+	RetryBackoff: 30 * time.Second,
+	Timeout:      time.Second,
+	BatchSize:    10,
+	MaxAttempts:  3,
+	// the receivers are local
+	AllowPrivate: true,
+}
+
+// receiver is a local http server recording the last request
+type receiver struct {
+	*httptest.Server
+	status int
+	header http.Header
+	body   string
+}
+
+func newReceiver(status int) *receiver {
+	r := &receiver{status: status}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.header = req.Header
+		r.body = string(body)
+		w.WriteHeader(r.status)
+	}))
+
+	return r
+}
+
+func TestCreate(t *testing.T) {
+	mockRepo := new(mocks.WebhookRepository)
+	mockRepo.On("CreateWebhook", mock.Anything, mock.AnythingOfType("*model.Webhook")).Return(nil).Once()
+
+	u := usecase.NewWebhookUsecase(mockRepo, time.Second, webhookCfg)
+
+	assert.NoError(t, u.Create(context.TODO(), &model.Webhook{URL: "http://127.0.0.1:9000/hook"}))
+
+	code, _ := response.RespondError(u.Create(context.TODO(), &model.Webhook{URL: "file:///etc/passwd"}))
+	assert.Equal(t, http.StatusBadRequest, code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPublish(t *testing.T) {
+	mockRepo := new(mocks.WebhookRepository)
+	mockRepo.On("FetchWebhooks", mock.Anything, int32(1)).Return([]model.Webhook{
+		{ID: 1, UserID: 1, IsActive: 1, Events: []string{model.EventNoteCreated}},
+		{ID: 2, UserID: 1, IsActive: 1, Events: []string{"*"}},
+		{ID: 3, UserID: 1, IsActive: 0, Events: []string{"*"}},
+		{ID: 4, UserID: 1, IsActive: 1, Events: []string{model.EventAccountDeleted}},
+	}, nil).Once()
+	mockRepo.On("CreateDelivery", mock.Anything, mock.MatchedBy(func(d *model.WebhookDelivery) bool {
+		return (d.WebhookID == 1 || d.WebhookID == 2) && d.Event == model.EventNoteCreated &&
+			d.Status == model.DeliveryPending && strings.Contains(d.Payload, `"data":{"id":7}`)
+	})).Return(nil).Twice()
+
+	u := usecase.NewWebhookUsecase(mockRepo, time.Second, webhookCfg)
+	u.Publish(context.TODO(), 1, model.EventNoteCreated, map[string]int32{"id": 7})
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSendTest(t *testing.T) {
+	server := newReceiver(http.StatusOK)
+	defer server.Close()
+
+	webhook := model.Webhook{ID: 2, UserID: 1, URL: server.URL, Secret: "s3cr3t-key", IsActive: 1}
+
+	mockRepo := new(mocks.WebhookRepository)
+	mockRepo.On("GetWebhook", mock.Anything, int32(2), int32(1)).Return(webhook, nil).Once()
+	mockRepo.On("CreateDelivery", mock.Anything, mock.AnythingOfType("*model.WebhookDelivery")).
+		Run(func(args mock.Arguments) { args.Get(1).(*model.WebhookDelivery).ID = 9 }).Return(nil).Once()
+	mockRepo.On("UpdateDelivery", mock.Anything, mock.AnythingOfType("*model.WebhookDelivery")).Return(nil).Once()
+
+	u := usecase.NewWebhookUsecase(mockRepo, time.Second, webhookCfg)
+
+	delivery, err := u.SendTest(context.TODO(), 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.DeliveryDelivered, delivery.Status)
+	assert.Equal(t, http.StatusOK, delivery.ResponseStatus)
+	assert.Equal(t, 1, delivery.Attempts)
+
+	assert.Equal(t, model.EventPing, server.header.Get(usecase.HeaderEvent))
+	assert.Equal(t, "9", server.header.Get(usecase.HeaderDelivery))
+	assert.Equal(t, usecase.Sign("s3cr3t-key", []byte(server.body)), server.header.Get(usecase.HeaderSignature))
+	assert.Equal(t, delivery.Payload, server.body)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeliverDue(t *testing.T) {
+	now := time.Date(2022, 1, 31, 9, 0, 0, 0, time.UTC)
+	nowTime := now.Format("2006-01-02 15:04:05")
+
+	setup := func(server *receiver, attempts int) (model.WebhookUsecase, *mocks.WebhookRepository) {
+		webhook := model.Webhook{ID: 2, UserID: 1, URL: server.URL, Secret: "s3cr3t-key", IsActive: 1}
+		delivery := model.WebhookDelivery{
+			ID: 5, WebhookID: 2, UserID: 1, Event: model.EventNoteDeleted, Payload: `{"event":"note.deleted"}`,
+			Status: model.DeliveryPending, Attempts: attempts, DueAt: nowTime,
+		}
+
+		mockRepo := new(mocks.WebhookRepository)
+		mockRepo.On("FetchDueDeliveries", mock.Anything, nowTime, 10).
+			Return([]model.WebhookDelivery{delivery}, nil).Once()
+		mockRepo.On("ClaimDelivery", mock.Anything, int32(5), nowTime, "2022-01-31 09:00:31").Return(true, nil).Once()
+		mockRepo.On("GetWebhook", mock.Anything, int32(2), int32(1)).Return(webhook, nil).Once()
+
+		return usecase.NewWebhookUsecase(mockRepo, time.Second, webhookCfg), mockRepo
+	}
+
+	t.Run("delivered", func(t *testing.T) {
+		server := newReceiver(http.StatusNoContent)
+		defer server.Close()
+
+		u, mockRepo := setup(server, 0)
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *model.WebhookDelivery) bool {
+			return d.Status == model.DeliveryDelivered && d.ResponseStatus == http.StatusNoContent && d.Attempts == 1
+		})).Return(nil).Once()
+
+		delivered, err := u.DeliverDue(context.TODO(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("retry-with-backoff", func(t *testing.T) {
+		server := newReceiver(http.StatusBadGateway)
+		defer server.Close()
+
+		u, mockRepo := setup(server, 1)
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *model.WebhookDelivery) bool {
+			// second failure waits twice the backoff
+			return d.Status == model.DeliveryPending && d.Attempts == 2 && d.DueAt == "2022-01-31 09:01:00" &&
+				d.ResponseStatus == http.StatusBadGateway
+		})).Return(nil).Once()
+
+		delivered, err := u.DeliverDue(context.TODO(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("failed-after-max-attempts", func(t *testing.T) {
+		server := newReceiver(http.StatusInternalServerError)
+		defer server.Close()
+
+		u, mockRepo := setup(server, 2)
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *model.WebhookDelivery) bool {
+			return d.Status == model.DeliveryFailed && d.LastError == "webhook responded with status 500"
+		})).Return(nil).Once()
+
+		_, err := u.DeliverDue(context.TODO(), now)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
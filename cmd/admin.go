@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"librenote/app/model"
+	"librenote/app/server"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/db"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var (
+	adminUser string
+	adminCmd  = &cobra.Command{
+		Use:   "admin",
+		Short: "manage the admins",
+		Long:  `manage the admins, they read the audit log of all the accounts`,
+	}
+	adminGrantCmd = &cobra.Command{
+		Use:   "grant",
+		Short: "make an user an admin",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := setAdmin(true); err != nil {
+				logrus.Errorln(err)
+				os.Exit(1)
+			}
+		},
+	}
+	adminRevokeCmd = &cobra.Command{
+		Use:   "revoke",
+		Short: "make an admin an user again",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := setAdmin(false); err != nil {
+				logrus.Errorln(err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(adminCmd)
+
+	for _, cmd := range []*cobra.Command{adminGrantCmd, adminRevokeCmd} {
+		adminCmd.AddCommand(cmd)
+		cmd.Flags().StringVarP(&adminUser, "user", "u", "", "email of the user")
+		_ = cmd.MarkFlagRequired("user")
+	}
+}
+
+func setAdmin(admin bool) error {
+	db.Connect()
+	defer db.Close()
+
+	u := server.NewUsecases(config.Get().App)
+
+	user, err := findUser(u, adminUser)
+	if err != nil {
+		return err
+	}
+
+	if err = u.UserRepo.SetAdmin(context.Background(), user.ID, admin); err != nil {
+		return err
+	}
+
+	action, done := model.AuditAdminGranted, "is an admin now"
+	if !admin {
+		action, done = model.AuditAdminRevoked, "is not an admin anymore"
+	}
+
+	u.Audit.Record(context.Background(), &model.AuditEvent{UserID: &user.ID, Action: action,
+		UserAgent: "librenote admin"})
+
+	fmt.Printf("%s %s\n", user.Email, done)
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"librenote/app/model"
+	"librenote/app/server"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/db"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var (
+	exportUser   string
+	exportOutput string
+	exportCmd    = &cobra.Command{
+		Use:   "export",
+		Short: "export all data of an user",
+		Long:  `export all data of an user as a zip of Markdown notes, attachments and a JSON dump`,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := exportAccount(); err != nil {
+				logrus.Errorln(err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVarP(&exportUser, "user", "u", "", "email of the user to export")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "",
+		"path of the zip, defaults to librenote-export-<date>.zip")
+	_ = exportCmd.MarkFlagRequired("user")
+}
+
+func exportAccount() error {
+	db.Connect()
+	defer db.Close()
+
+	u := server.NewUsecases(config.Get().App)
+
+	user, err := findUser(u, exportUser)
+	if err != nil {
+		return err
+	}
+
+	export, err := u.Export.Export(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	if exportOutput == "" {
+		exportOutput = fmt.Sprintf("librenote-export-%s.zip", time.Now().UTC().Format("20060102"))
+	}
+
+	f, err := os.OpenFile(exportOutput, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if err = u.Export.WriteZip(context.Background(), export, f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(exportOutput)
+
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	u.Audit.Record(context.Background(), &model.AuditEvent{UserID: &user.ID, Action: model.AuditAdminExport,
+		UserAgent: "librenote export", Details: map[string]interface{}{"notes": len(export.Notes)}})
+
+	fmt.Printf("exported %d notes to %s\n", len(export.Notes), exportOutput)
+
+	return nil
+}
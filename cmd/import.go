@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"librenote/app/importer"
+	"librenote/app/model"
+	"librenote/app/server"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/db"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var (
+	importUser   string
+	importDryRun bool
+	importCmd    = &cobra.Command{
+		Use:   "import <source> <path>",
+		Short: "import notes",
+		Long: `import notes of other applications to a user, the source is one of
+  keep           zip of a Google Keep export of Google Takeout
+  markdown       folder or zip of Markdown files
+  enex           Evernote notebook or zip of notebooks
+  standardnotes  decrypted Standard Notes backup`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := importNotes(args[0], args[1]); err != nil {
+				logrus.Errorln(err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVarP(&importUser, "user", "u", "", "email of the user to import to")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "report what would be created only")
+	_ = importCmd.MarkFlagRequired("user")
+}
+
+func importNotes(source, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	var fsys fs.FS
+
+	if info.IsDir() {
+		fsys = os.DirFS(path)
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		if fsys, err = importer.NewFS(f, info.Size(), info.Name()); err != nil {
+			return err
+		}
+	}
+
+	db.Connect()
+	defer db.Close()
+
+	u := server.NewUsecases(config.Get().App)
+
+	user, err := findUser(u, importUser)
+	if err != nil {
+		return err
+	}
+
+	report, err := u.Import.Import(context.Background(), source, user.ID, fsys, importDryRun)
+	if err != nil {
+		return err
+	}
+
+	u.Audit.Record(context.Background(), &model.AuditEvent{UserID: &user.ID, Action: model.AuditAdminImport,
+		UserAgent: "librenote import", Details: map[string]interface{}{
+			"source": source, "dry_run": importDryRun, "notes": report.Notes, "attachments": report.Attachments,
+		}})
+
+	printImportReport(report)
+
+	return nil
+}
+
+func printImportReport(report *model.ImportReport) {
+	verb := "created"
+	if report.DryRun {
+		verb = "would be created"
+	}
+
+	for _, item := range report.Imported {
+		title := "(untitled)"
+		if item.Title != nil {
+			title = *item.Title
+		}
+
+		fmt.Printf("imported %s: %s\n", item.Source, title)
+	}
+
+	for _, skip := range report.Skipped {
+		fmt.Printf("skipped %s: %s\n", skip.Source, skip.Reason)
+	}
+
+	fmt.Printf("notes %s: %d (%d items)\n", verb, report.Notes, report.Items)
+	fmt.Printf("attachments %s: %d\n", verb, report.Attachments)
+	fmt.Printf("labels %s: %d\n", verb, len(report.Labels))
+
+	if len(report.Labels) > 0 {
+		fmt.Printf("  %s\n", strings.Join(report.Labels, ", "))
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"librenote/app/encryption"
+	"librenote/app/model"
+	"librenote/app/server"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/db"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var (
+	rotateBatchSize int
+	rotateKeepKey   bool
+	rotateKeyFile   string
+	keysCmd         = &cobra.Command{
+		Use:   "keys",
+		Short: "manage the keys of the encryption at rest",
+	}
+	keysRotateCmd = &cobra.Command{
+		Use:   "rotate",
+		Short: "add a master key and re-encrypt the data by it",
+		Long: `add a master key to the key file and re-encrypt the notes and the attachments by it, in batches.
+The key file is created when missing, the data stored in clear is encrypted as well.
+The previous keys stay in the key file, the data is still encrypted by them when the rotation is interrupted.
+With the encryption key of the config, give the key file to create by --key-file. The key is copied into it
+first, then replace the key by the key_file in the config.
+Stop the server first, it reads the key file on start only`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rotateKeys(); err != nil {
+				logrus.Errorln(err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(keysCmd)
+	keysCmd.AddCommand(keysRotateCmd)
+	keysRotateCmd.Flags().IntVar(&rotateBatchSize, "batch-size", 100, "rows re-encrypted at once")
+	keysRotateCmd.Flags().BoolVar(&rotateKeepKey, "keep-key", false,
+		"re-encrypt by the current key, like after an interrupted rotation")
+	keysRotateCmd.Flags().StringVar(&rotateKeyFile, "key-file", "",
+		"key file to rotate, the key_file of the config by default")
+}
+
+func rotateKeys() error {
+	cfg := config.Get().Encryption
+
+	keyFile := rotateKeyFile
+	if keyFile == "" {
+		keyFile = cfg.KeyFile
+	}
+
+	if keyFile == "" {
+		return errors.New("the rotation needs the encryption key_file in the config or --key-file")
+	}
+
+	if rotateBatchSize <= 0 {
+		return errors.New("the batch size must be positive")
+	}
+
+	keys, err := encryption.ReadKeyFile(keyFile)
+
+	// the first rotation creates the key file
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the data is encrypted by the key of the config so far, it has to stay readable
+	if keys, err = seedKeyFile(keyFile, keys, cfg.Key); err != nil {
+		return err
+	}
+
+	if len(keys) == 0 && rotateKeepKey {
+		return fmt.Errorf("no key to keep, %s is missing", keyFile)
+	}
+
+	if !rotateKeepKey {
+		key, err := encryption.GenerateKey()
+		if err != nil {
+			return err
+		}
+
+		// the key is saved first, so the data encrypted by it stays readable whatever happens next
+		if err = encryption.AppendKeyFile(keyFile, key); err != nil {
+			return err
+		}
+
+		keys = append(keys, key)
+	}
+
+	keyring, err := encryption.NewKeyring(keys...)
+	if err != nil {
+		return err
+	}
+
+	db.Connect()
+	defer db.Close()
+
+	report, err := server.NewKeyRotation(config.Get().App, keyring).Rotate(context.Background(), rotateBatchSize)
+	printRotation(report)
+
+	if cfg.Key != "" {
+		fmt.Printf("set the encryption key_file to %s in place of the key in the config\n", keyFile)
+	}
+
+	return err
+}
+
+// seedKeyFile adds the key of the config to the key file unless it holds it already, the key stays the primary
+// one until the rotation adds another
+func seedKeyFile(keyFile string, keys [][]byte, configKey string) ([][]byte, error) {
+	if configKey == "" {
+		return keys, nil
+	}
+
+	key, err := encryption.ParseKey(configKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range keys {
+		if bytes.Equal(k, key) {
+			return keys, nil
+		}
+	}
+
+	if err = encryption.AppendKeyFile(keyFile, key); err != nil {
+		return nil, err
+	}
+
+	return append(keys, key), nil
+}
+
+func printRotation(report *model.KeyRotationReport) {
+	for _, columns := range [][]string{model.EncryptedColumns, model.EncryptedItemColumns} {
+		for _, column := range columns {
+			fmt.Printf("re-encrypted %d values of %s\n", report.Values[column], column)
+		}
+	}
+
+	for _, column := range model.HashedColumns {
+		fmt.Printf("hashed %d values of %s again\n", report.Hashes[column], column)
+	}
+
+	fmt.Printf("re-encrypted %d attachments and thumbnails\n", report.Blobs)
+}
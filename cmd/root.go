@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"librenote/app"
 	"librenote/infrastructure/config"
+	"librenote/infrastructure/logger"
 	"os"
 
 	"github.com/sirupsen/logrus"
@@ -35,14 +36,16 @@ func initConfig() {
 		logrus.Errorln(err)
 		os.Exit(1)
 	}
+
+	if err = logger.Setup(config.Get().Log); err != nil {
+		logrus.Errorln(err)
+		os.Exit(1)
+	}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	// log config
-	logrus.SetLevel(logrus.InfoLevel)
-
 	if err := rootCmd.Execute(); err != nil {
 		logrus.Errorln(err)
 		os.Exit(1)
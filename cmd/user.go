@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"librenote/app/model"
+	"librenote/app/server"
+)
+
+// findUser resolves the --user flag of the commands working on the data of an user
+func findUser(u *server.Usecases, email string) (*model.User, error) {
+	user, err := u.UserRepo.GetUserByEmail(context.Background(), email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("no user with email %q", email)
+		}
+
+		return nil, err
+	}
+
+	return &user, nil
+}
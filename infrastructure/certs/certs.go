@@ -0,0 +1,110 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"librenote/infrastructure/config"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// reloadInterval is how often the certificate files are checked for a change
+const reloadInterval = 10 * time.Second
+
+// Manager serves the certificates of the TLS config, from the certificate files or from an ACME server
+type Manager struct {
+	getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	acme           *autocert.Manager
+}
+
+// New returns the manager of the config, nil when the TLS is disabled
+func New(cfg config.TLSConfig) (*Manager, error) {
+	if cfg.CertFile != "" {
+		reloader, err := NewReloader(cfg.CertFile, cfg.KeyFile, reloadInterval)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Manager{getCertificate: reloader.GetCertificate}, nil
+	}
+
+	if !cfg.ACME.Enabled {
+		return nil, nil // nolint:nilnil
+	}
+
+	client := &acme.Client{DirectoryURL: cfg.ACME.DirectoryURL}
+
+	if cfg.ACME.CAFile != "" {
+		pem, err := os.ReadFile(cfg.ACME.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificate in the acme ca_file " + cfg.ACME.CAFile)
+		}
+
+		client.HTTPClient = &http.Client{Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+		}}
+	}
+
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(cfg.ACME.Domains...),
+		Cache:      autocert.DirCache(cfg.ACME.CacheDir),
+		Email:      cfg.ACME.Email,
+		Client:     client,
+	}
+
+	return &Manager{getCertificate: m.GetCertificate, acme: m}, nil
+}
+
+// TLSConfig of the https server, it answers the ACME tls-alpn-01 challenges too
+func (m *Manager) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: m.getCertificate,
+		NextProtos:     []string{"http/1.1", acme.ALPNProto},
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+// HTTPHandler of the http listener, it answers the ACME http-01 challenges and redirects the other requests to the
+// https server of httpsPort
+func (m *Manager) HTTPHandler(httpsPort int) http.Handler {
+	redirect := Redirect(httpsPort)
+	if m.acme == nil {
+		return redirect
+	}
+
+	return m.acme.HTTPHandler(redirect)
+}
+
+// Redirect redirects the requests to the same URL over https on httpsPort, keeping the method and the body
+func Redirect(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			// no port, an IPv6 literal is still in brackets
+			host = strings.Trim(r.Host, "[]")
+		}
+
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
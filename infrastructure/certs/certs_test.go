@@ -0,0 +1,143 @@
+package certs_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"librenote/infrastructure/certs"
+	"librenote/infrastructure/config"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCert writes a self signed certificate of the common name and its key
+func writeCert(t *testing.T, certFile, keyFile, commonName string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		0o600))
+}
+
+func commonName(t *testing.T, r *certs.Reloader) string {
+	cert, err := r.GetCertificate(nil)
+	assert.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(t, err)
+
+	return leaf.Subject.CommonName
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	writeCert(t, certFile, keyFile, "old.example.com")
+
+	r, err := certs.NewReloader(certFile, keyFile, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "old.example.com", commonName(t, r))
+
+	// the renewed certificate
+	writeCert(t, certFile, keyFile, "new.example.com")
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(certFile, later, later))
+	assert.Equal(t, "new.example.com", commonName(t, r))
+
+	// a broken pair keeps the previous certificate
+	assert.NoError(t, os.WriteFile(keyFile, []byte("not a key"), 0o600))
+	later = later.Add(time.Minute)
+	assert.NoError(t, os.Chtimes(keyFile, later, later))
+	assert.Equal(t, "new.example.com", commonName(t, r))
+
+	_, err = certs.NewReloader(certFile, keyFile, 0)
+	assert.Error(t, err)
+
+	_, err = certs.NewReloader(filepath.Join(dir, "missing.pem"), keyFile, 0)
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	m, err := certs.New(config.TLSConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, m)
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeCert(t, certFile, keyFile, "notes.example.com")
+
+	m, err = certs.New(config.TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	assert.NoError(t, err)
+
+	cert, err := m.TLSConfig().GetCertificate(nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, cert)
+
+	acme := config.ACMEConfig{
+		Enabled: true, Domains: []string{"notes.example.com"}, CacheDir: dir,
+		DirectoryURL: "https://localhost:14000/dir", CAFile: keyFile,
+	}
+
+	_, err = certs.New(config.TLSConfig{ACME: acme})
+	assert.EqualError(t, err, "no certificate in the acme ca_file "+keyFile)
+
+	acme.CAFile = certFile
+	m, err = certs.New(config.TLSConfig{ACME: acme})
+	assert.NoError(t, err)
+
+	// the challenges of other hosts aren't answered
+	_, err = m.TLSConfig().GetCertificate(&tls.ClientHelloInfo{ServerName: "evil.example.com"})
+	assert.Error(t, err)
+}
+
+func TestRedirect(t *testing.T) {
+	for _, c := range []struct {
+		port     int
+		host     string
+		location string
+	}{
+		{443, "notes.example.com", "https://notes.example.com/api/v1/notes?page=2"},
+		{443, "notes.example.com:80", "https://notes.example.com/api/v1/notes?page=2"},
+		{8443, "notes.example.com:8080", "https://notes.example.com:8443/api/v1/notes?page=2"},
+		{443, "[::1]:80", "https://[::1]/api/v1/notes?page=2"},
+		{443, "[::1]", "https://[::1]/api/v1/notes?page=2"},
+		{8443, "[::1]", "https://[::1]:8443/api/v1/notes?page=2"},
+		{8443, "[2001:db8::1]:8080", "https://[2001:db8::1]:8443/api/v1/notes?page=2"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/notes?page=2", nil)
+		req.Host = c.host
+
+		rec := httptest.NewRecorder()
+		certs.Redirect(c.port).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusPermanentRedirect, rec.Code)
+		assert.Equal(t, c.location, rec.Header().Get("Location"))
+	}
+}
@@ -0,0 +1,92 @@
+package certs
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Reloader serves the certificate of a pair of files, reloaded once one of them changes. The files are checked at
+// most once per interval, on the handshakes
+type Reloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+// NewReloader loads the certificate, it fails when the files aren't a valid pair
+func NewReloader(certFile, keyFile string, interval time.Duration) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, interval: interval}
+
+	modTime, err := r.lastModified()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.load(modTime); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate is the tls.Config one, a certificate failing to reload is logged and the previous one kept
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := time.Now(); now.Sub(r.checked) >= r.interval {
+		r.checked = now
+
+		modTime, err := r.lastModified()
+		if err == nil && !modTime.Equal(r.modTime) {
+			err = r.load(modTime)
+		}
+
+		if err != nil {
+			logrus.Errorf("failed to reload the certificate %s: %s", r.certFile, err)
+		}
+	}
+
+	return r.cert, nil
+}
+
+// lastModified returns the latest modification time of the files
+func (r *Reloader) lastModified() (time.Time, error) {
+	var latest time.Time
+
+	for _, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
+func (r *Reloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.cert = &cert
+	r.modTime = modTime
+
+	logrus.Infof("loaded the certificate %s", r.certFile)
+
+	return nil
+}
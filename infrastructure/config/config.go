@@ -2,16 +2,28 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/labstack/gommon/bytes"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	App      AppConfig      `mapstructure:"app"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Jwt      JwtConfig      `mapstructure:"jwt"`
+	App        AppConfig        `mapstructure:"app"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	Jwt        JwtConfig        `mapstructure:"jwt"`
+	Reminder   ReminderConfig   `mapstructure:"reminder"`
+	Webhook    WebhookConfig    `mapstructure:"webhook"`
+	Mail       MailConfig       `mapstructure:"mail"`
+	Storage    StorageConfig    `mapstructure:"storage"`
+	Encryption EncryptionConfig `mapstructure:"encryption"`
+	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
+	Password   PasswordConfig   `mapstructure:"password"`
+	Security   SecurityConfig   `mapstructure:"security"`
+	TLS        TLSConfig        `mapstructure:"tls"`
+	Log        LogConfig        `mapstructure:"log"`
 }
 
 // AppConfig app specific config
@@ -19,17 +31,20 @@ type AppConfig struct {
 	Env              string `mapstructure:"env"`
 	DataPath         string `mapstructure:"data_path"`
 	RequestBodyLimit string `mapstructure:"request_body_limit"`
-	DateFormat       string
-	TimestampFormat  string
-	Host             string        `mapstructure:"host"`
-	ReadTimeout      time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout     time.Duration `mapstructure:"write_timeout"`
-	IdleTimeout      time.Duration `mapstructure:"idle_timeout"`
-	ContextTimeout   time.Duration `mapstructure:"context_timeout"`
-	Port             int           `mapstructure:"port"`
-	MaxPageSize      int           `mapstructure:"max_page_size"`
-	DefaultPageSize  int           `mapstructure:"default_page_size"`
-	RegistrationOpen bool          `mapstructure:"registration_open"`
+	// RequestBodyLimit in bytes
+	RequestBodyLimitBytes int64
+	DateFormat            string
+	TimestampFormat       string
+	Host                  string        `mapstructure:"host"`
+	ReadTimeout           time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout          time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout           time.Duration `mapstructure:"idle_timeout"`
+	ContextTimeout        time.Duration `mapstructure:"context_timeout"`
+	Port                  int           `mapstructure:"port"`
+	MaxPageSize           int           `mapstructure:"max_page_size"`
+	DefaultPageSize       int           `mapstructure:"default_page_size"`
+	MaxNoteRevisions      int           `mapstructure:"max_note_revisions"`
+	RegistrationOpen      bool          `mapstructure:"registration_open"`
 }
 
 // DatabaseConfig DB specific config
@@ -50,6 +65,194 @@ type DatabaseConfig struct {
 type JwtConfig struct {
 	SecretKey  string        `mapstructure:"secret_key"`
 	ExpireTime time.Duration `mapstructure:"expire_time"`
+	Cookie     CookieConfig  `mapstructure:"cookie"`
+}
+
+// CookieConfig of the session cookie, set instead of returning the token to the clients asking for it at the login.
+// The token is out of the reach of the scripts, and the bearer tokens keep working along
+type CookieConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Name    string `mapstructure:"name"`
+	// strict, lax or none, none needs the allowed credentials of the CORS
+	SameSite string `mapstructure:"same_site"`
+}
+
+// ReminderConfig reminder scheduler specific config
+type ReminderConfig struct {
+	PollInterval   time.Duration `mapstructure:"poll_interval"`
+	RetryBackoff   time.Duration `mapstructure:"retry_backoff"`
+	WebhookTimeout time.Duration `mapstructure:"webhook_timeout"`
+	BatchSize      int           `mapstructure:"batch_size"`
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	// the webhook targets can be loopback, private or other non public addresses
+	WebhookAllowPrivate bool `mapstructure:"webhook_allow_private"`
+}
+
+// WebhookConfig outgoing webhook delivery specific config
+type WebhookConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	MaxAttempts  int           `mapstructure:"max_attempts"`
+	// the urls can be loopback, private or other non public addresses
+	AllowPrivate bool `mapstructure:"allow_private"`
+}
+
+// MailConfig smtp server used to send emails, email delivery is disabled when host is empty
+type MailConfig struct {
+	Host     string `mapstructure:"host"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+	Port     int    `mapstructure:"port"`
+}
+
+// StorageConfig attachment storage specific config
+type StorageConfig struct {
+	// local or s3
+	Driver string `mapstructure:"driver"`
+	// root directory of the local driver, defaults to <data_path>/blobs
+	Path string `mapstructure:"path"`
+	// like 500M, empty for unlimited
+	UserQuota      string `mapstructure:"user_quota"`
+	UserQuotaBytes int64
+	// images are scaled down to fit a square of this size
+	ThumbnailSize int      `mapstructure:"thumbnail_size"`
+	S3            S3Config `mapstructure:"s3"`
+}
+
+// S3Config any S3 compatible object storage, like AWS S3 or MinIO
+type S3Config struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	// use bucket.endpoint host instead of endpoint/bucket path
+	VirtualHost bool `mapstructure:"virtual_host"`
+}
+
+// EncryptionConfig encryption at rest of the notes, the templates, the webhook deliveries and the attachments, disabled
+// when neither key nor key_file is set. model.EncryptedColumns lists what stays in clear
+type EncryptionConfig struct {
+	// base64 encoded 32 bytes master key
+	Key string `mapstructure:"key"`
+	// file of base64 encoded master keys, one per line, the last one encrypts and all of them decrypt.
+	// The keys rotate command adds a key to it
+	KeyFile string `mapstructure:"key_file"`
+}
+
+// RateLimitConfig throttles the login and the registration requests, then locks the accounts after failed logins
+type RateLimitConfig struct {
+	// requests accepted from an IP address within the ip_window
+	IPLimit  int           `mapstructure:"ip_limit"`
+	IPWindow time.Duration `mapstructure:"ip_window"`
+	// requests accepted for an email within the email_window
+	EmailLimit  int           `mapstructure:"email_limit"`
+	EmailWindow time.Duration `mapstructure:"email_window"`
+	// failed logins in a row before the account is locked for lockout_duration,
+	// every next failure doubles it up to max_lockout
+	LockoutThreshold int           `mapstructure:"lockout_threshold"`
+	LockoutDuration  time.Duration `mapstructure:"lockout_duration"`
+	MaxLockout       time.Duration `mapstructure:"max_lockout"`
+	Disabled         bool          `mapstructure:"disabled"`
+}
+
+// PasswordConfig policy of the passwords set at the registration and on changing the password
+type PasswordConfig struct {
+	MinLength     int  `mapstructure:"min_length"`
+	RequireUpper  bool `mapstructure:"require_upper"`
+	RequireLower  bool `mapstructure:"require_lower"`
+	RequireDigit  bool `mapstructure:"require_digit"`
+	RequireSymbol bool `mapstructure:"require_symbol"`
+	// the passwords containing the email or a part of the full name are refused, unless it's allowed
+	AllowPersonal bool `mapstructure:"allow_personal"`
+	// SHA-1 hashes of breached passwords, the Pwned Passwords file ordered by hash or a directory of its
+	// ranges, a SUFFIX:COUNT file per hash prefix. The passwords found in it are refused, empty to disable
+	BreachedFile string `mapstructure:"breached_file"`
+	// argon2id or bcrypt, the hashes of the other one or of other parameters are upgraded on the next login
+	Hasher string `mapstructure:"hasher"`
+	// in KiB
+	Argon2Memory      uint32 `mapstructure:"argon2_memory"`
+	Argon2Iterations  uint32 `mapstructure:"argon2_iterations"`
+	Argon2Parallelism uint8  `mapstructure:"argon2_parallelism"`
+	BcryptCost        int    `mapstructure:"bcrypt_cost"`
+}
+
+// SecurityConfig of the browser clients, the CORS, the security headers and the CSRF protection
+type SecurityConfig struct {
+	CORS CORSConfig `mapstructure:"cors"`
+	// Strict-Transport-Security of the https requests, in seconds, 0 to not send it
+	HSTSMaxAge            int        `mapstructure:"hsts_max_age"`
+	HSTSExcludeSubdomains bool       `mapstructure:"hsts_exclude_subdomains"`
+	ContentSecurityPolicy string     `mapstructure:"content_security_policy"`
+	FrameOptions          string     `mapstructure:"frame_options"`
+	ReferrerPolicy        string     `mapstructure:"referrer_policy"`
+	CSRF                  CSRFConfig `mapstructure:"csrf"`
+	// the cookies are sent over http too, only for the development without TLS
+	InsecureCookies bool `mapstructure:"insecure_cookies"`
+	// IP addresses or CIDR ranges of the reverse proxies, the client IP is read from their X-Forwarded-For.
+	// Without them it's the address of the connection
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+// CORSConfig of the requests from the browser clients of other origins, disabled when no origin is allowed
+type CORSConfig struct {
+	AllowOrigins     []string `mapstructure:"allow_origins"`
+	AllowMethods     []string `mapstructure:"allow_methods"`
+	AllowHeaders     []string `mapstructure:"allow_headers"`
+	ExposeHeaders    []string `mapstructure:"expose_headers"`
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
+	// in seconds
+	MaxAge int `mapstructure:"max_age"`
+}
+
+// CSRFConfig double submit cookie protection of the requests authenticated by a cookie, the bearer tokens aren't
+// sent by the browsers on their own so their requests aren't checked
+type CSRFConfig struct {
+	CookieName string `mapstructure:"cookie_name"`
+	HeaderName string `mapstructure:"header_name"`
+	Disabled   bool   `mapstructure:"disabled"`
+}
+
+// TLSConfig of the https server, disabled when there is neither a certificate nor ACME
+type TLSConfig struct {
+	// reloaded once they change, so the renewed certificates are served without a restart
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// address of the http listener redirecting to https and answering the ACME http-01 challenges, empty to disable
+	RedirectAddr string     `mapstructure:"redirect_addr"`
+	ACME         ACMEConfig `mapstructure:"acme"`
+}
+
+// ACMEConfig gets the certificates of the domains from an ACME server, like Let's Encrypt
+type ACMEConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	Domains []string `mapstructure:"domains"`
+	Email   string   `mapstructure:"email"`
+	// defaults to Let's Encrypt
+	DirectoryURL string `mapstructure:"directory_url"`
+	// where the account key and the certificates are kept, defaults to <data_path>/acme
+	CacheDir string `mapstructure:"cache_dir"`
+	// PEM certificates of the CAs trusted for the directory, like the one of a local test server
+	CAFile string `mapstructure:"ca_file"`
+}
+
+// LogConfig of the logs of the server and the commands
+type LogConfig struct {
+	// debug, info, warn or error
+	Level string `mapstructure:"level"`
+	// text or json
+	Format string `mapstructure:"format"`
+	// the logs are written to stderr when there is no file
+	File string `mapstructure:"file"`
+	// the file is rotated once bigger, never when empty
+	MaxSize string `mapstructure:"max_size"`
+	// MaxSize in bytes
+	MaxSizeBytes int64
+	// rotated files kept
+	MaxBackups int `mapstructure:"max_backups"`
 }
 
 // c is the configuration instance
@@ -83,6 +286,13 @@ func Load(path string) error {
 		c.App.RequestBodyLimit = "20M"
 	}
 
+	bodyLimit, err := bytes.Parse(c.App.RequestBodyLimit)
+	if err != nil {
+		return fmt.Errorf("invalid request_body_limit: %w", err)
+	}
+
+	c.App.RequestBodyLimitBytes = bodyLimit
+
 	if c.App.MaxPageSize <= 5 {
 		c.App.MaxPageSize = 50
 	}
@@ -91,6 +301,54 @@ func Load(path string) error {
 		c.App.DefaultPageSize = 30
 	}
 
+	if c.App.MaxNoteRevisions <= 0 {
+		c.App.MaxNoteRevisions = 50
+	}
+
+	if c.Reminder.PollInterval <= 0 {
+		c.Reminder.PollInterval = 30 * time.Second
+	}
+
+	if c.Reminder.RetryBackoff <= 0 {
+		c.Reminder.RetryBackoff = time.Minute
+	}
+
+	if c.Reminder.WebhookTimeout <= 0 {
+		c.Reminder.WebhookTimeout = 10 * time.Second
+	}
+
+	if c.Reminder.BatchSize <= 0 {
+		c.Reminder.BatchSize = 50
+	}
+
+	if c.Reminder.MaxAttempts <= 0 {
+		c.Reminder.MaxAttempts = 5
+	}
+
+	if c.Webhook.PollInterval <= 0 {
+		c.Webhook.PollInterval = 10 * time.Second
+	}
+
+	if c.Webhook.RetryBackoff <= 0 {
+		c.Webhook.RetryBackoff = 30 * time.Second
+	}
+
+	if c.Webhook.Timeout <= 0 {
+		c.Webhook.Timeout = 10 * time.Second
+	}
+
+	if c.Webhook.BatchSize <= 0 {
+		c.Webhook.BatchSize = 50
+	}
+
+	if c.Webhook.MaxAttempts <= 0 {
+		c.Webhook.MaxAttempts = 6
+	}
+
+	if c.Mail.Port == 0 {
+		c.Mail.Port = 587
+	}
+
 	// yyyy-mm-dd
 	c.App.DateFormat = "2006-01-02"
 	c.App.TimestampFormat = "2006-01-02T15:04:05-0700"
@@ -102,6 +360,194 @@ func Load(path string) error {
 
 	c.App.DataPath = dataPath
 
+	if c.Storage.Driver == "" {
+		c.Storage.Driver = "local"
+	}
+
+	if c.Storage.Driver != "local" && c.Storage.Driver != "s3" {
+		return fmt.Errorf("unknown storage driver %s", c.Storage.Driver)
+	}
+
+	if c.Storage.Path == "" {
+		c.Storage.Path = filepath.Join(dataPath, "blobs")
+	}
+
+	if c.Storage.UserQuota != "" {
+		quota, err := bytes.Parse(c.Storage.UserQuota)
+		if err != nil {
+			return fmt.Errorf("invalid storage user_quota: %w", err)
+		}
+
+		c.Storage.UserQuotaBytes = quota
+	}
+
+	if c.Storage.ThumbnailSize <= 0 {
+		c.Storage.ThumbnailSize = 256
+	}
+
+	if c.Storage.S3.Region == "" {
+		c.Storage.S3.Region = "us-east-1"
+	}
+
+	if c.Encryption.Key != "" && c.Encryption.KeyFile != "" {
+		return fmt.Errorf("set either encryption key or key_file, not both")
+	}
+
+	if c.RateLimit.IPLimit <= 0 {
+		c.RateLimit.IPLimit = 20
+	}
+
+	if c.RateLimit.IPWindow <= 0 {
+		c.RateLimit.IPWindow = time.Minute
+	}
+
+	if c.RateLimit.EmailLimit <= 0 {
+		c.RateLimit.EmailLimit = 10
+	}
+
+	if c.RateLimit.EmailWindow <= 0 {
+		c.RateLimit.EmailWindow = 15 * time.Minute
+	}
+
+	if c.RateLimit.LockoutThreshold <= 0 {
+		c.RateLimit.LockoutThreshold = 5
+	}
+
+	if c.RateLimit.LockoutDuration <= 0 {
+		c.RateLimit.LockoutDuration = time.Minute
+	}
+
+	if c.RateLimit.MaxLockout <= 0 {
+		c.RateLimit.MaxLockout = time.Hour
+	}
+
+	if c.RateLimit.MaxLockout < c.RateLimit.LockoutDuration {
+		c.RateLimit.MaxLockout = c.RateLimit.LockoutDuration
+	}
+
+	if c.Password.MinLength <= 0 {
+		c.Password.MinLength = 8
+	}
+
+	if c.Password.MinLength > 100 {
+		return fmt.Errorf("password min_length must be 100 characters at most")
+	}
+
+	if c.Password.Hasher == "" {
+		c.Password.Hasher = "argon2id"
+	}
+
+	if c.Password.Hasher != "argon2id" && c.Password.Hasher != "bcrypt" {
+		return fmt.Errorf("unknown password hasher %s", c.Password.Hasher)
+	}
+
+	if c.Password.Argon2Memory == 0 {
+		c.Password.Argon2Memory = 19 * 1024
+	}
+
+	if c.Password.Argon2Iterations == 0 {
+		c.Password.Argon2Iterations = 2
+	}
+
+	if c.Password.Argon2Parallelism == 0 {
+		c.Password.Argon2Parallelism = 1
+	}
+
+	if c.Password.BcryptCost == 0 {
+		c.Password.BcryptCost = 10
+	}
+
+	if c.Password.BcryptCost < 4 || c.Password.BcryptCost > 31 {
+		return fmt.Errorf("password bcrypt_cost must be between 4 and 31")
+	}
+
+	if c.Security.FrameOptions == "" {
+		c.Security.FrameOptions = "DENY"
+	}
+
+	if c.Security.ContentSecurityPolicy == "" {
+		c.Security.ContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'; sandbox"
+	}
+
+	if c.Security.ReferrerPolicy == "" {
+		c.Security.ReferrerPolicy = "no-referrer"
+	}
+
+	if c.Security.CORS.AllowCredentials {
+		for _, origin := range c.Security.CORS.AllowOrigins {
+			if origin == "*" {
+				return fmt.Errorf("security cors can't allow the credentials of any origin")
+			}
+		}
+	}
+
+	if c.Security.CSRF.CookieName == "" {
+		c.Security.CSRF.CookieName = "_csrf"
+	}
+
+	if c.Security.CSRF.HeaderName == "" {
+		c.Security.CSRF.HeaderName = "X-CSRF-Token"
+	}
+
+	if c.Jwt.Cookie.Name == "" {
+		c.Jwt.Cookie.Name = "librenote_session"
+	}
+
+	if c.Jwt.Cookie.SameSite == "" {
+		c.Jwt.Cookie.SameSite = "strict"
+	}
+
+	if c.Jwt.Cookie.SameSite != "strict" && c.Jwt.Cookie.SameSite != "lax" && c.Jwt.Cookie.SameSite != "none" {
+		return fmt.Errorf("unknown jwt cookie same_site %s", c.Jwt.Cookie.SameSite)
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return fmt.Errorf("set both tls cert_file and key_file")
+	}
+
+	if c.TLS.ACME.Enabled {
+		if c.TLS.CertFile != "" {
+			return fmt.Errorf("set either tls cert_file or acme, not both")
+		}
+
+		if len(c.TLS.ACME.Domains) == 0 {
+			return fmt.Errorf("tls acme needs the domains")
+		}
+	}
+
+	if c.TLS.RedirectAddr != "" && c.TLS.CertFile == "" && !c.TLS.ACME.Enabled {
+		return fmt.Errorf("tls redirect_addr needs a cert_file or acme")
+	}
+
+	if c.TLS.ACME.CacheDir == "" {
+		c.TLS.ACME.CacheDir = filepath.Join(dataPath, "acme")
+	}
+
+	if c.Log.Level == "" {
+		c.Log.Level = "info"
+	}
+
+	if c.Log.Format == "" {
+		c.Log.Format = "text"
+	}
+
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		return fmt.Errorf("unknown log format %s", c.Log.Format)
+	}
+
+	if c.Log.MaxSize != "" {
+		maxSize, err := bytes.Parse(c.Log.MaxSize)
+		if err != nil {
+			return fmt.Errorf("invalid log max_size: %w", err)
+		}
+
+		c.Log.MaxSizeBytes = maxSize
+	}
+
+	if c.Log.MaxBackups <= 0 {
+		c.Log.MaxBackups = 5
+	}
+
 	if len(c.Jwt.SecretKey) < 32 {
 		return fmt.Errorf("jwt secret_key length must be equal or greater than 32 characters")
 	}
@@ -0,0 +1,8 @@
+CREATE TABLE `notes_revisions` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `note_id` int NOT NULL,
+  `snapshot` mediumtext NOT NULL COMMENT 'json encoded note state',
+  `created_at` timestamp NOT NULL
+);
+
+ALTER TABLE `notes_revisions` ADD FOREIGN KEY (`note_id`) REFERENCES `notes` (`id`);
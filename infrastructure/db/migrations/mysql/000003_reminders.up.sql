@@ -0,0 +1,36 @@
+CREATE TABLE `reminders` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `note_id` int NOT NULL,
+  `user_id` int NOT NULL,
+  `remind_at` timestamp NOT NULL COMMENT 'next occurrence in UTC',
+  `timezone` varchar(64) NOT NULL DEFAULT "UTC",
+  `recurrence` varchar(255) NOT NULL DEFAULT "",
+  `channel` varchar(10) NOT NULL,
+  `target` varchar(1000) NOT NULL DEFAULT "",
+  `status` varchar(10) NOT NULL DEFAULT "pending",
+  `attempts` int NOT NULL DEFAULT 0,
+  `due_at` timestamp NOT NULL COMMENT 'next delivery attempt in UTC',
+  `last_error` varchar(1000) NOT NULL DEFAULT "",
+  `created_at` timestamp NOT NULL,
+  `updated_at` timestamp NOT NULL
+);
+
+CREATE TABLE `reminders_events` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `reminder_id` int NOT NULL,
+  `note_id` int NOT NULL,
+  `message` varchar(1000) NOT NULL,
+  `is_read` tinyint(1) NOT NULL DEFAULT 0,
+  `created_at` timestamp NOT NULL
+);
+
+CREATE INDEX `reminders_status_due_at_idx` ON `reminders` (`status`, `due_at`);
+
+ALTER TABLE `reminders` ADD FOREIGN KEY (`note_id`) REFERENCES `notes` (`id`);
+
+ALTER TABLE `reminders` ADD FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);
+
+ALTER TABLE `reminders_events` ADD FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);
+
+ALTER TABLE `reminders_events` ADD FOREIGN KEY (`reminder_id`) REFERENCES `reminders` (`id`);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS webhooks_deliveries;
+DROP TABLE IF EXISTS webhooks;
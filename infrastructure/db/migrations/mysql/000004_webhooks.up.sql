@@ -0,0 +1,33 @@
+CREATE TABLE `webhooks` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `url` varchar(1000) NOT NULL,
+  `secret` varchar(255) NOT NULL,
+  `events` varchar(1000) NOT NULL COMMENT 'comma separated event names, * for all',
+  `is_active` tinyint(1) NOT NULL DEFAULT 1,
+  `created_at` timestamp NOT NULL,
+  `updated_at` timestamp NOT NULL
+);
+
+CREATE TABLE `webhooks_deliveries` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `webhook_id` int NOT NULL,
+  `user_id` int NOT NULL,
+  `event` varchar(50) NOT NULL,
+  `payload` mediumtext NOT NULL,
+  `status` varchar(10) NOT NULL DEFAULT "pending",
+  `attempts` int NOT NULL DEFAULT 0,
+  `due_at` timestamp NOT NULL COMMENT 'next delivery attempt in UTC',
+  `response_status` int NOT NULL DEFAULT 0,
+  `last_error` varchar(1000) NOT NULL DEFAULT "",
+  `created_at` timestamp NOT NULL,
+  `updated_at` timestamp NOT NULL
+);
+
+CREATE INDEX `webhooks_deliveries_status_due_at_idx` ON `webhooks_deliveries` (`status`, `due_at`);
+
+ALTER TABLE `webhooks` ADD FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);
+
+ALTER TABLE `webhooks_deliveries` ADD FOREIGN KEY (`webhook_id`) REFERENCES `webhooks` (`id`);
+
+ALTER TABLE `webhooks_deliveries` ADD FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);
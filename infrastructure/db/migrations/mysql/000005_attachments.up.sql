@@ -0,0 +1,18 @@
+CREATE TABLE `attachments` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `note_id` int NOT NULL COMMENT 'the blobs are removed by the application when the note is deleted',
+  `user_id` int NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `content_type` varchar(255) NOT NULL,
+  `size` bigint NOT NULL,
+  `blob_key` varchar(255) NOT NULL,
+  `thumbnail_key` varchar(255) NOT NULL DEFAULT "",
+  `thumbnail_size` bigint NOT NULL DEFAULT 0,
+  `created_at` timestamp NOT NULL
+);
+
+CREATE INDEX `attachments_note_id_idx` ON `attachments` (`note_id`);
+
+CREATE INDEX `attachments_user_id_idx` ON `attachments` (`user_id`);
+
+ALTER TABLE `attachments` ADD FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);
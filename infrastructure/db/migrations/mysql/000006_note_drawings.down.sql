@@ -0,0 +1,3 @@
+ALTER TABLE notes DROP COLUMN drawing;
+UPDATE notes SET type = "note" WHERE type = "drawing";
+ALTER TABLE notes MODIFY type varchar(4) NOT NULL DEFAULT "note";
@@ -0,0 +1,3 @@
+ALTER TABLE `notes` MODIFY `type` varchar(10) NOT NULL DEFAULT "note";
+
+ALTER TABLE `notes` ADD COLUMN `drawing` mediumtext NULL COMMENT 'json encoded strokes of drawing notes';
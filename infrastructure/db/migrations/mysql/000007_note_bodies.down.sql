@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS notes_links;
+ALTER TABLE notes DROP COLUMN body;
@@ -0,0 +1,11 @@
+ALTER TABLE `notes` ADD COLUMN `body` mediumtext NULL COMMENT 'markdown';
+
+CREATE TABLE `notes_links` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `note_id` int NOT NULL,
+  `url` varchar(2048) NOT NULL COMMENT 'destination of a link of the note body'
+);
+
+CREATE INDEX `notes_links_note_id_idx` ON `notes_links` (`note_id`);
+
+ALTER TABLE `notes_links` ADD FOREIGN KEY (`note_id`) REFERENCES `notes` (`id`);
@@ -0,0 +1,14 @@
+CREATE TABLE `notes_references` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `note_id` int NOT NULL,
+  `target_id` int NULL COMMENT 'set by the references by id, not a foreign key as the target may be deleted',
+  `title` varchar(255) NULL COMMENT 'lower cased title of the referenced notes'
+);
+
+CREATE INDEX `notes_references_note_id_idx` ON `notes_references` (`note_id`);
+
+CREATE INDEX `notes_references_target_id_idx` ON `notes_references` (`target_id`);
+
+CREATE INDEX `notes_references_title_idx` ON `notes_references` (`title`);
+
+ALTER TABLE `notes_references` ADD FOREIGN KEY (`note_id`) REFERENCES `notes` (`id`);
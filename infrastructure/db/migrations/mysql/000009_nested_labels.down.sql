@@ -0,0 +1,3 @@
+ALTER TABLE labels DROP FOREIGN KEY labels_parent_id_fk;
+ALTER TABLE labels DROP INDEX labels_parent_id_idx;
+ALTER TABLE labels DROP COLUMN parent_id;
@@ -0,0 +1,5 @@
+ALTER TABLE `labels` ADD COLUMN `parent_id` int NULL COMMENT 'null for a top level label';
+
+CREATE INDEX `labels_parent_id_idx` ON `labels` (`parent_id`);
+
+ALTER TABLE `labels` ADD CONSTRAINT `labels_parent_id_fk` FOREIGN KEY (`parent_id`) REFERENCES `labels` (`id`);
@@ -0,0 +1,13 @@
+ALTER TABLE `notes` MODIFY `color` varchar(20) COMMENT 'a color of the palette or the name of a custom color';
+
+CREATE TABLE `users_colors` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `name` varchar(20) NOT NULL COMMENT 'stored as the color of the notes',
+  `hex` char(7) NOT NULL,
+  `created_at` timestamp NOT NULL
+);
+
+CREATE UNIQUE INDEX `users_colors_name_idx` ON `users_colors` (`user_id`, `name`);
+
+ALTER TABLE `users_colors` ADD FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);
@@ -0,0 +1,5 @@
+-- the foreign key of user_id may use the index of the positions
+CREATE INDEX `notes_user_id_idx` ON `notes` (`user_id`);
+ALTER TABLE notes DROP INDEX notes_user_id_position_idx;
+ALTER TABLE notes_items DROP COLUMN position;
+ALTER TABLE notes DROP COLUMN position;
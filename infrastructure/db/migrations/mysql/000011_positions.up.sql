@@ -0,0 +1,19 @@
+ALTER TABLE `notes` ADD COLUMN `position` varchar(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT ''
+  COMMENT 'fractional index, the notes of a user sort by it';
+ALTER TABLE `notes_items` ADD COLUMN `position` varchar(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT ''
+  COMMENT 'fractional index, the items of a note sort by it';
+
+-- the notes keep their order, pinned then the last updated first
+UPDATE `notes` n JOIN (
+  SELECT a.id, COUNT(*) AS r FROM `notes` a JOIN `notes` b ON b.user_id = a.user_id AND (
+    b.is_pinned > a.is_pinned OR (b.is_pinned = a.is_pinned AND (
+      b.updated_at > a.updated_at OR (b.updated_at = a.updated_at AND b.id >= a.id))))
+  GROUP BY a.id
+) ranks ON ranks.id = n.id SET n.position = CONCAT(LPAD(ranks.r, 10, '0'), 'i');
+
+UPDATE `notes_items` n JOIN (
+  SELECT a.id, COUNT(*) AS r FROM `notes_items` a JOIN `notes_items` b ON b.note_id = a.note_id AND b.id <= a.id
+  GROUP BY a.id
+) ranks ON ranks.id = n.id SET n.position = CONCAT(LPAD(ranks.r, 10, '0'), 'i');
+
+CREATE INDEX `notes_user_id_position_idx` ON `notes` (`user_id`, `position`);
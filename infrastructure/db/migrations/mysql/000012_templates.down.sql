@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS templates_labels;
+DROP TABLE IF EXISTS templates;
@@ -0,0 +1,27 @@
+CREATE TABLE `templates` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `title` varchar(255),
+  `body` mediumtext NOT NULL COMMENT 'markdown, may hold variables like {{date}}',
+  `color` varchar(20),
+  `type` varchar(10) NOT NULL DEFAULT "note",
+  `items` mediumtext NOT NULL COMMENT 'json encoded items of list templates',
+  `drawing` mediumtext NULL COMMENT 'json encoded strokes of drawing templates',
+  `created_at` timestamp NOT NULL,
+  `updated_at` timestamp NOT NULL
+);
+
+CREATE TABLE `templates_labels` (
+  `template_id` int,
+  `label_id` int,
+  PRIMARY KEY (`template_id`, `label_id`)
+);
+
+CREATE UNIQUE INDEX `templates_name_idx` ON `templates` (`user_id`, `name`);
+
+ALTER TABLE `templates` ADD FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);
+
+ALTER TABLE `templates_labels` ADD FOREIGN KEY (`template_id`) REFERENCES `templates` (`id`);
+
+ALTER TABLE `templates_labels` ADD FOREIGN KEY (`label_id`) REFERENCES `labels` (`id`);
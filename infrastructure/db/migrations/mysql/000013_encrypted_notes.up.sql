@@ -0,0 +1,18 @@
+ALTER TABLE `notes` ADD COLUMN `encrypted` mediumtext NULL COMMENT 'json encoded ciphertext of end-to-end encrypted notes';
+
+CREATE TABLE `users_key_envelopes` (
+  `user_id` int PRIMARY KEY,
+  `wrapped_key` text NOT NULL COMMENT 'master key of the encrypted notes, wrapped by the client',
+  `algorithm` varchar(50) NOT NULL,
+  `nonce` varchar(255) NOT NULL,
+  `kdf` varchar(50) NOT NULL COMMENT 'derives the wrapping key from the passphrase',
+  `salt` varchar(255) NOT NULL,
+  `iterations` int NOT NULL,
+  `memory` int NOT NULL,
+  `parallelism` int NOT NULL,
+  `key_version` int NOT NULL,
+  `created_at` timestamp NOT NULL,
+  `updated_at` timestamp NOT NULL
+);
+
+ALTER TABLE `users_key_envelopes` ADD FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);
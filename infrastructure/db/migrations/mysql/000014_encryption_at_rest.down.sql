@@ -0,0 +1,3 @@
+ALTER TABLE templates MODIFY title varchar(255);
+ALTER TABLE notes_items MODIFY text varchar(1000) NOT NULL;
+ALTER TABLE notes MODIFY title varchar(255);
@@ -0,0 +1,3 @@
+ALTER TABLE `notes` MODIFY `title` text COMMENT 'encrypted when the encryption at rest is enabled';
+ALTER TABLE `notes_items` MODIFY `text` text NOT NULL COMMENT 'encrypted when the encryption at rest is enabled';
+ALTER TABLE `templates` MODIFY `title` text COMMENT 'encrypted when the encryption at rest is enabled';
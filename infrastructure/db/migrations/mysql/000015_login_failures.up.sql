@@ -0,0 +1,8 @@
+CREATE TABLE `users_login_failures` (
+  `user_id` int PRIMARY KEY,
+  `attempts` int NOT NULL COMMENT 'failed logins since the last successful one',
+  `locked_until` timestamp NULL COMMENT 'in UTC, the login is refused until then',
+  `updated_at` timestamp NOT NULL
+);
+
+ALTER TABLE `users_login_failures` ADD FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);
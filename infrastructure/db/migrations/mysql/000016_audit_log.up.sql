@@ -0,0 +1,20 @@
+CREATE TABLE `audit_events` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `user_id` int NULL COMMENT 'the account the action is about',
+  `actor_id` int NULL COMMENT 'who did the action, null when anonymous or from the command line',
+  `action` varchar(50) NOT NULL,
+  `ip` varchar(45) NOT NULL,
+  `user_agent` varchar(500) NOT NULL,
+  `details` text NOT NULL COMMENT 'json encoded',
+  `created_at` timestamp NOT NULL
+);
+
+CREATE INDEX `audit_events_user_id_created_at_idx` ON `audit_events` (`user_id`, `created_at`);
+
+CREATE INDEX `audit_events_created_at_idx` ON `audit_events` (`created_at`);
+
+CREATE TRIGGER `audit_events_no_update` BEFORE UPDATE ON `audit_events`
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit events are append-only';
+
+CREATE TRIGGER `audit_events_no_delete` BEFORE DELETE ON `audit_events`
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit events are append-only';
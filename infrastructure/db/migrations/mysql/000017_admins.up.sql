@@ -0,0 +1,2 @@
+ALTER TABLE `users` ADD COLUMN `is_admin` tinyint(1) NOT NULL DEFAULT 0
+  COMMENT 'reads the audit log of all the accounts, set by the admin command';
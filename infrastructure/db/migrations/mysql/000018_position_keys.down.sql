@@ -0,0 +1,11 @@
+UPDATE `notes` n JOIN (
+  SELECT a.id, COUNT(*) AS r FROM `notes` a JOIN `notes` b ON b.user_id = a.user_id AND (
+    b.position < a.position OR (b.position = a.position AND b.id <= a.id))
+  GROUP BY a.id
+) ranks ON ranks.id = n.id SET n.position = CONCAT(LPAD(ranks.r, 10, '0'), 'i');
+
+UPDATE `notes_items` n JOIN (
+  SELECT a.id, COUNT(*) AS r FROM `notes_items` a JOIN `notes_items` b ON b.note_id = a.note_id AND (
+    b.position < a.position OR (b.position = a.position AND b.id <= a.id))
+  GROUP BY a.id
+) ranks ON ranks.id = n.id SET n.position = CONCAT(LPAD(ranks.r, 10, '0'), 'i');
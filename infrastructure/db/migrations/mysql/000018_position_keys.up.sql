@@ -0,0 +1,13 @@
+-- the positions become keys of an integer and a fraction, they keep their order
+
+UPDATE `notes` n JOIN (
+  SELECT a.id, COUNT(*) AS r FROM `notes` a JOIN `notes` b ON b.user_id = a.user_id AND (
+    b.position < a.position OR (b.position = a.position AND b.id <= a.id))
+  GROUP BY a.id
+) ranks ON ranks.id = n.id SET n.position = CONCAT('h', LPAD(ranks.r, 8, '0'));
+
+UPDATE `notes_items` n JOIN (
+  SELECT a.id, COUNT(*) AS r FROM `notes_items` a JOIN `notes_items` b ON b.note_id = a.note_id AND (
+    b.position < a.position OR (b.position = a.position AND b.id <= a.id))
+  GROUP BY a.id
+) ranks ON ranks.id = n.id SET n.position = CONCAT('h', LPAD(ranks.r, 8, '0'));
@@ -0,0 +1,6 @@
+ALTER TABLE reminders_events MODIFY message varchar(1000) NOT NULL;
+DROP INDEX notes_references_title_hash_idx ON notes_references;
+DROP INDEX notes_references_title_idx ON notes_references;
+ALTER TABLE notes_references DROP COLUMN title_hash;
+ALTER TABLE notes_references MODIFY title varchar(255) NULL COMMENT 'lower cased title of the referenced notes';
+CREATE INDEX notes_references_title_idx ON notes_references (title);
@@ -0,0 +1,14 @@
+DROP INDEX `notes_references_title_idx` ON `notes_references`;
+
+ALTER TABLE `notes_references` MODIFY `title` text
+  COMMENT 'lower cased title of the referenced notes, encrypted when the encryption at rest is enabled';
+
+ALTER TABLE `notes_references` ADD COLUMN `title_hash` varchar(64) NULL
+  COMMENT 'keyed hash of the title when the encryption at rest is enabled, the backlinks are looked up by it';
+
+CREATE INDEX `notes_references_title_idx` ON `notes_references` (`title`(255));
+
+CREATE INDEX `notes_references_title_hash_idx` ON `notes_references` (`title_hash`);
+
+ALTER TABLE `reminders_events` MODIFY `message` text NOT NULL
+  COMMENT 'encrypted when the encryption at rest is enabled';
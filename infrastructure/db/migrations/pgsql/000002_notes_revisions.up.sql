@@ -0,0 +1,12 @@
+CREATE TABLE "notes_revisions" (
+  "id" serial PRIMARY KEY,
+  "note_id" int NOT NULL,
+  "snapshot" text NOT NULL,
+  "created_at" TIMESTAMP(0) NOT NULL
+);
+
+ALTER TABLE "notes_revisions" ADD FOREIGN KEY ("note_id") REFERENCES "notes" ("id");
+
+CREATE INDEX "notes_revisions_note_id_idx" ON "notes_revisions" ("note_id");
+
+COMMENT ON COLUMN "notes_revisions"."snapshot" IS 'json encoded note state';
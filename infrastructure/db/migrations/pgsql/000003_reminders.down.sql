@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reminders_events;
+DROP TABLE IF EXISTS reminders;
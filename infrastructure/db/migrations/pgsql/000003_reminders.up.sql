@@ -0,0 +1,40 @@
+CREATE TABLE "reminders" (
+  "id" serial PRIMARY KEY,
+  "note_id" int NOT NULL,
+  "user_id" int NOT NULL,
+  "remind_at" TIMESTAMP(0) NOT NULL,
+  "timezone" varchar(64) NOT NULL DEFAULT 'UTC',
+  "recurrence" varchar(255) NOT NULL DEFAULT '',
+  "channel" varchar(10) NOT NULL,
+  "target" varchar(1000) NOT NULL DEFAULT '',
+  "status" varchar(10) NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "due_at" TIMESTAMP(0) NOT NULL,
+  "last_error" varchar(1000) NOT NULL DEFAULT '',
+  "created_at" TIMESTAMP(0) NOT NULL,
+  "updated_at" TIMESTAMP(0) NOT NULL
+);
+
+CREATE TABLE "reminders_events" (
+  "id" serial PRIMARY KEY,
+  "user_id" int NOT NULL,
+  "reminder_id" int NOT NULL,
+  "note_id" int NOT NULL,
+  "message" varchar(1000) NOT NULL,
+  "is_read" smallint NOT NULL DEFAULT 0,
+  "created_at" TIMESTAMP(0) NOT NULL
+);
+
+CREATE INDEX "reminders_status_due_at_idx" ON "reminders" ("status", "due_at");
+
+ALTER TABLE "reminders" ADD FOREIGN KEY ("note_id") REFERENCES "notes" ("id");
+
+ALTER TABLE "reminders" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "reminders_events" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "reminders_events" ADD FOREIGN KEY ("reminder_id") REFERENCES "reminders" ("id");
+
+COMMENT ON COLUMN "reminders"."remind_at" IS 'next occurrence in UTC';
+
+COMMENT ON COLUMN "reminders"."due_at" IS 'next delivery attempt in UTC';
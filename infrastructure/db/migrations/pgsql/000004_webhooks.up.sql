@@ -0,0 +1,37 @@
+CREATE TABLE "webhooks" (
+  "id" serial PRIMARY KEY,
+  "user_id" int NOT NULL,
+  "url" varchar(1000) NOT NULL,
+  "secret" varchar(255) NOT NULL,
+  "events" varchar(1000) NOT NULL,
+  "is_active" smallint NOT NULL DEFAULT 1,
+  "created_at" TIMESTAMP(0) NOT NULL,
+  "updated_at" TIMESTAMP(0) NOT NULL
+);
+
+CREATE TABLE "webhooks_deliveries" (
+  "id" serial PRIMARY KEY,
+  "webhook_id" int NOT NULL,
+  "user_id" int NOT NULL,
+  "event" varchar(50) NOT NULL,
+  "payload" text NOT NULL,
+  "status" varchar(10) NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "due_at" TIMESTAMP(0) NOT NULL,
+  "response_status" int NOT NULL DEFAULT 0,
+  "last_error" varchar(1000) NOT NULL DEFAULT '',
+  "created_at" TIMESTAMP(0) NOT NULL,
+  "updated_at" TIMESTAMP(0) NOT NULL
+);
+
+CREATE INDEX "webhooks_deliveries_status_due_at_idx" ON "webhooks_deliveries" ("status", "due_at");
+
+ALTER TABLE "webhooks" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "webhooks_deliveries" ADD FOREIGN KEY ("webhook_id") REFERENCES "webhooks" ("id");
+
+ALTER TABLE "webhooks_deliveries" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+COMMENT ON COLUMN "webhooks"."events" IS 'comma separated event names, * for all';
+
+COMMENT ON COLUMN "webhooks_deliveries"."due_at" IS 'next delivery attempt in UTC';
@@ -0,0 +1,20 @@
+CREATE TABLE "attachments" (
+  "id" serial PRIMARY KEY,
+  "note_id" int NOT NULL,
+  "user_id" int NOT NULL,
+  "name" varchar(255) NOT NULL,
+  "content_type" varchar(255) NOT NULL,
+  "size" bigint NOT NULL,
+  "blob_key" varchar(255) NOT NULL,
+  "thumbnail_key" varchar(255) NOT NULL DEFAULT '',
+  "thumbnail_size" bigint NOT NULL DEFAULT 0,
+  "created_at" TIMESTAMP(0) NOT NULL
+);
+
+CREATE INDEX "attachments_note_id_idx" ON "attachments" ("note_id");
+
+CREATE INDEX "attachments_user_id_idx" ON "attachments" ("user_id");
+
+ALTER TABLE "attachments" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+COMMENT ON COLUMN "attachments"."note_id" IS 'the blobs are removed by the application when the note is deleted';
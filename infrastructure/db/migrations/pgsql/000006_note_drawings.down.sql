@@ -0,0 +1,3 @@
+ALTER TABLE notes DROP COLUMN drawing;
+UPDATE notes SET type = 'note' WHERE type = 'drawing';
+ALTER TABLE notes ALTER COLUMN type TYPE varchar(4);
@@ -0,0 +1,5 @@
+ALTER TABLE "notes" ALTER COLUMN "type" TYPE varchar(10);
+
+ALTER TABLE "notes" ADD COLUMN "drawing" text NULL;
+
+COMMENT ON COLUMN "notes"."drawing" IS 'json encoded strokes of drawing notes';
@@ -0,0 +1,15 @@
+ALTER TABLE "notes" ADD COLUMN "body" text NULL;
+
+CREATE TABLE "notes_links" (
+  "id" serial PRIMARY KEY,
+  "note_id" int NOT NULL,
+  "url" varchar(2048) NOT NULL
+);
+
+CREATE INDEX "notes_links_note_id_idx" ON "notes_links" ("note_id");
+
+ALTER TABLE "notes_links" ADD FOREIGN KEY ("note_id") REFERENCES "notes" ("id");
+
+COMMENT ON COLUMN "notes"."body" IS 'markdown';
+
+COMMENT ON COLUMN "notes_links"."url" IS 'destination of a link of the note body';
@@ -0,0 +1,18 @@
+CREATE TABLE "notes_references" (
+  "id" serial PRIMARY KEY,
+  "note_id" int NOT NULL,
+  "target_id" int NULL,
+  "title" varchar(255) NULL
+);
+
+CREATE INDEX "notes_references_note_id_idx" ON "notes_references" ("note_id");
+
+CREATE INDEX "notes_references_target_id_idx" ON "notes_references" ("target_id");
+
+CREATE INDEX "notes_references_title_idx" ON "notes_references" ("title");
+
+ALTER TABLE "notes_references" ADD FOREIGN KEY ("note_id") REFERENCES "notes" ("id");
+
+COMMENT ON COLUMN "notes_references"."target_id" IS 'set by the references by id, not a foreign key as the target may be deleted';
+
+COMMENT ON COLUMN "notes_references"."title" IS 'lower cased title of the referenced notes';
@@ -0,0 +1 @@
+ALTER TABLE labels DROP COLUMN parent_id;
@@ -0,0 +1,7 @@
+ALTER TABLE "labels" ADD COLUMN "parent_id" int NULL;
+
+CREATE INDEX "labels_parent_id_idx" ON "labels" ("parent_id");
+
+ALTER TABLE "labels" ADD FOREIGN KEY ("parent_id") REFERENCES "labels" ("id");
+
+COMMENT ON COLUMN "labels"."parent_id" IS 'null for a top level label';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS users_colors;
+UPDATE notes SET color = NULL WHERE LENGTH(color) > 10;
+ALTER TABLE notes ALTER COLUMN color TYPE varchar(10);
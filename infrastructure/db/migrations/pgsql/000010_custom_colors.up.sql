@@ -0,0 +1,15 @@
+ALTER TABLE "notes" ALTER COLUMN "color" TYPE varchar(20);
+
+CREATE TABLE "users_colors" (
+  "id" serial PRIMARY KEY,
+  "user_id" int NOT NULL,
+  "name" varchar(20) NOT NULL,
+  "hex" char(7) NOT NULL,
+  "created_at" TIMESTAMP(0) NOT NULL
+);
+
+CREATE UNIQUE INDEX "users_colors_name_idx" ON "users_colors" ("user_id", "name");
+
+ALTER TABLE "users_colors" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+COMMENT ON COLUMN "users_colors"."name" IS 'stored as the color of the notes';
@@ -0,0 +1,33 @@
+CREATE TABLE "templates" (
+  "id" serial PRIMARY KEY,
+  "user_id" int NOT NULL,
+  "name" varchar(100) NOT NULL,
+  "title" varchar(255),
+  "body" text NOT NULL,
+  "color" varchar(20),
+  "type" varchar(10) NOT NULL DEFAULT 'note',
+  "items" text NOT NULL,
+  "drawing" text NULL,
+  "created_at" TIMESTAMP(0) NOT NULL,
+  "updated_at" TIMESTAMP(0) NOT NULL
+);
+
+CREATE TABLE "templates_labels" (
+  "template_id" int,
+  "label_id" int,
+  PRIMARY KEY ("template_id", "label_id")
+);
+
+CREATE UNIQUE INDEX "templates_name_idx" ON "templates" ("user_id", "name");
+
+ALTER TABLE "templates" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "templates_labels" ADD FOREIGN KEY ("template_id") REFERENCES "templates" ("id");
+
+ALTER TABLE "templates_labels" ADD FOREIGN KEY ("label_id") REFERENCES "labels" ("id");
+
+COMMENT ON COLUMN "templates"."body" IS 'markdown, may hold variables like {{date}}';
+
+COMMENT ON COLUMN "templates"."items" IS 'json encoded items of list templates';
+
+COMMENT ON COLUMN "templates"."drawing" IS 'json encoded strokes of drawing templates';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS users_key_envelopes;
+ALTER TABLE notes DROP COLUMN encrypted;
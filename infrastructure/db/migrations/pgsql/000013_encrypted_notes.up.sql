@@ -0,0 +1,24 @@
+ALTER TABLE "notes" ADD COLUMN "encrypted" text NULL;
+
+CREATE TABLE "users_key_envelopes" (
+  "user_id" int PRIMARY KEY,
+  "wrapped_key" text NOT NULL,
+  "algorithm" varchar(50) NOT NULL,
+  "nonce" varchar(255) NOT NULL,
+  "kdf" varchar(50) NOT NULL,
+  "salt" varchar(255) NOT NULL,
+  "iterations" int NOT NULL,
+  "memory" int NOT NULL,
+  "parallelism" int NOT NULL,
+  "key_version" int NOT NULL,
+  "created_at" TIMESTAMP(0) NOT NULL,
+  "updated_at" TIMESTAMP(0) NOT NULL
+);
+
+ALTER TABLE "users_key_envelopes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+COMMENT ON COLUMN "notes"."encrypted" IS 'json encoded ciphertext of end-to-end encrypted notes';
+
+COMMENT ON COLUMN "users_key_envelopes"."wrapped_key" IS 'master key of the encrypted notes, wrapped by the client';
+
+COMMENT ON COLUMN "users_key_envelopes"."kdf" IS 'derives the wrapping key from the passphrase';
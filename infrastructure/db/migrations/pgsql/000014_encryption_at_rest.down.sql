@@ -0,0 +1,3 @@
+ALTER TABLE templates ALTER COLUMN title TYPE varchar(255);
+ALTER TABLE notes_items ALTER COLUMN text TYPE varchar(1000);
+ALTER TABLE notes ALTER COLUMN title TYPE varchar(255);
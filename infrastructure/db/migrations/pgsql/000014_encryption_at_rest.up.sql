@@ -0,0 +1,7 @@
+ALTER TABLE "notes" ALTER COLUMN "title" TYPE text;
+ALTER TABLE "notes_items" ALTER COLUMN "text" TYPE text;
+ALTER TABLE "templates" ALTER COLUMN "title" TYPE text;
+
+COMMENT ON COLUMN "notes"."title" IS 'encrypted when the encryption at rest is enabled';
+COMMENT ON COLUMN "notes_items"."text" IS 'encrypted when the encryption at rest is enabled';
+COMMENT ON COLUMN "templates"."title" IS 'encrypted when the encryption at rest is enabled';
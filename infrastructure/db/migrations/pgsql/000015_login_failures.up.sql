@@ -0,0 +1,12 @@
+CREATE TABLE "users_login_failures" (
+  "user_id" int PRIMARY KEY,
+  "attempts" int NOT NULL,
+  "locked_until" TIMESTAMP(0) NULL,
+  "updated_at" TIMESTAMP(0) NOT NULL
+);
+
+ALTER TABLE "users_login_failures" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+COMMENT ON COLUMN "users_login_failures"."attempts" IS 'failed logins since the last successful one';
+
+COMMENT ON COLUMN "users_login_failures"."locked_until" IS 'in UTC, the login is refused until then';
@@ -0,0 +1,29 @@
+CREATE TABLE "audit_events" (
+  "id" serial PRIMARY KEY,
+  "user_id" int NULL,
+  "actor_id" int NULL,
+  "action" varchar(50) NOT NULL,
+  "ip" varchar(45) NOT NULL,
+  "user_agent" varchar(500) NOT NULL,
+  "details" text NOT NULL,
+  "created_at" TIMESTAMP(0) NOT NULL
+);
+
+CREATE INDEX "audit_events_user_id_created_at_idx" ON "audit_events" ("user_id", "created_at");
+
+CREATE INDEX "audit_events_created_at_idx" ON "audit_events" ("created_at");
+
+CREATE FUNCTION "audit_events_append_only"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit events are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_events_append_only" BEFORE UPDATE OR DELETE ON "audit_events"
+FOR EACH ROW EXECUTE PROCEDURE "audit_events_append_only"();
+
+COMMENT ON COLUMN "audit_events"."user_id" IS 'the account the action is about';
+
+COMMENT ON COLUMN "audit_events"."actor_id" IS 'who did the action, null when anonymous or from the command line';
+
+COMMENT ON COLUMN "audit_events"."details" IS 'json encoded';
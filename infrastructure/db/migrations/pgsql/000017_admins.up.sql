@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "is_admin" smallint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "users"."is_admin" IS 'reads the audit log of all the accounts, set by the admin command';
@@ -0,0 +1,9 @@
+UPDATE "notes" SET "position" = (
+  SELECT LPAD(COUNT(*)::text, 10, '0') || 'i' FROM "notes" n WHERE n.user_id = notes.user_id AND (
+    n.position < notes.position OR (n.position = notes.position AND n.id <= notes.id))
+);
+
+UPDATE "notes_items" SET "position" = (
+  SELECT LPAD(COUNT(*)::text, 10, '0') || 'i' FROM "notes_items" i WHERE i.note_id = notes_items.note_id AND (
+    i.position < notes_items.position OR (i.position = notes_items.position AND i.id <= notes_items.id))
+);
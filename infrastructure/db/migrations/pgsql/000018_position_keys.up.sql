@@ -0,0 +1,11 @@
+-- the positions become keys of an integer and a fraction, they keep their order
+
+UPDATE "notes" SET "position" = (
+  SELECT 'h' || LPAD(COUNT(*)::text, 8, '0') FROM "notes" n WHERE n.user_id = notes.user_id AND (
+    n.position < notes.position OR (n.position = notes.position AND n.id <= notes.id))
+);
+
+UPDATE "notes_items" SET "position" = (
+  SELECT 'h' || LPAD(COUNT(*)::text, 8, '0') FROM "notes_items" i WHERE i.note_id = notes_items.note_id AND (
+    i.position < notes_items.position OR (i.position = notes_items.position AND i.id <= notes_items.id))
+);
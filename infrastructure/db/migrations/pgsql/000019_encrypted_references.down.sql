@@ -0,0 +1,4 @@
+ALTER TABLE reminders_events ALTER COLUMN message TYPE varchar(1000);
+ALTER TABLE notes_references DROP COLUMN title_hash;
+ALTER TABLE notes_references ALTER COLUMN title TYPE varchar(255);
+COMMENT ON COLUMN notes_references.title IS 'lower cased title of the referenced notes';
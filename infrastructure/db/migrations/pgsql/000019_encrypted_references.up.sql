@@ -0,0 +1,13 @@
+ALTER TABLE "notes_references" ALTER COLUMN "title" TYPE text;
+
+ALTER TABLE "notes_references" ADD COLUMN "title_hash" varchar(64) NULL;
+
+CREATE INDEX "notes_references_title_hash_idx" ON "notes_references" ("title_hash");
+
+ALTER TABLE "reminders_events" ALTER COLUMN "message" TYPE text;
+
+COMMENT ON COLUMN "notes_references"."title" IS
+  'lower cased title of the referenced notes, encrypted when the encryption at rest is enabled';
+COMMENT ON COLUMN "notes_references"."title_hash" IS
+  'keyed hash of the title when the encryption at rest is enabled, the backlinks are looked up by it';
+COMMENT ON COLUMN "reminders_events"."message" IS 'encrypted when the encryption at rest is enabled';
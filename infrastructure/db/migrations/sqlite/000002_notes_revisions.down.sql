@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS notes_revisions_note_id_IDX;
+DROP TABLE IF EXISTS notes_revisions;
@@ -0,0 +1,10 @@
+CREATE TABLE `notes_revisions` (
+  `id` INTEGER NOT NULL,
+  `note_id` INTEGER NOT NULL,
+  `snapshot` TEXT NOT NULL,
+  `created_at` TEXT NOT NULL,
+   CONSTRAINT notes_revision_PK PRIMARY KEY(id),
+   CONSTRAINT note_id_FK FOREIGN KEY(note_id) REFERENCES notes(id)
+);
+
+CREATE INDEX notes_revisions_note_id_IDX ON notes_revisions(note_id);
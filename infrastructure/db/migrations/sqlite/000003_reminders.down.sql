@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reminders_events;
+DROP INDEX IF EXISTS reminders_status_due_at_IDX;
+DROP TABLE IF EXISTS reminders;
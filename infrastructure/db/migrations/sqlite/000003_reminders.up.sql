@@ -0,0 +1,34 @@
+CREATE TABLE `reminders` (
+  `id` INTEGER NOT NULL,
+  `note_id` INTEGER NOT NULL,
+  `user_id` INTEGER NOT NULL,
+  `remind_at` TEXT NOT NULL,
+  `timezone` TEXT NOT NULL DEFAULT "UTC",
+  `recurrence` TEXT NOT NULL DEFAULT "",
+  `channel` TEXT NOT NULL,
+  `target` TEXT NOT NULL DEFAULT "",
+  `status` TEXT NOT NULL DEFAULT "pending",
+  `attempts` INTEGER NOT NULL DEFAULT 0,
+  `due_at` TEXT NOT NULL,
+  `last_error` TEXT NOT NULL DEFAULT "",
+  `created_at` TEXT NOT NULL,
+  `updated_at` TEXT NOT NULL,
+   CONSTRAINT reminder_PK PRIMARY KEY(id),
+   CONSTRAINT note_id_FK FOREIGN KEY(note_id) REFERENCES notes(id),
+   CONSTRAINT user_id_FK FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE INDEX reminders_status_due_at_IDX ON reminders(status, due_at);
+
+CREATE TABLE `reminders_events` (
+  `id` INTEGER NOT NULL,
+  `user_id` INTEGER NOT NULL,
+  `reminder_id` INTEGER NOT NULL,
+  `note_id` INTEGER NOT NULL,
+  `message` TEXT NOT NULL,
+  `is_read` INTEGER NOT NULL DEFAULT 0,
+  `created_at` TEXT NOT NULL,
+   CONSTRAINT reminders_event_PK PRIMARY KEY(id),
+   CONSTRAINT user_id_FK FOREIGN KEY(user_id) REFERENCES users(id),
+   CONSTRAINT reminder_id_FK FOREIGN KEY(reminder_id) REFERENCES reminders(id)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS webhooks_deliveries_status_due_at_IDX;
+DROP TABLE IF EXISTS webhooks_deliveries;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,32 @@
+CREATE TABLE `webhooks` (
+  `id` INTEGER NOT NULL,
+  `user_id` INTEGER NOT NULL,
+  `url` TEXT NOT NULL,
+  `secret` TEXT NOT NULL,
+  `events` TEXT NOT NULL,
+  `is_active` INTEGER NOT NULL DEFAULT 1,
+  `created_at` TEXT NOT NULL,
+  `updated_at` TEXT NOT NULL,
+   CONSTRAINT webhook_PK PRIMARY KEY(id),
+   CONSTRAINT user_id_FK FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE TABLE `webhooks_deliveries` (
+  `id` INTEGER NOT NULL,
+  `webhook_id` INTEGER NOT NULL,
+  `user_id` INTEGER NOT NULL,
+  `event` TEXT NOT NULL,
+  `payload` TEXT NOT NULL,
+  `status` TEXT NOT NULL DEFAULT "pending",
+  `attempts` INTEGER NOT NULL DEFAULT 0,
+  `due_at` TEXT NOT NULL,
+  `response_status` INTEGER NOT NULL DEFAULT 0,
+  `last_error` TEXT NOT NULL DEFAULT "",
+  `created_at` TEXT NOT NULL,
+  `updated_at` TEXT NOT NULL,
+   CONSTRAINT webhooks_delivery_PK PRIMARY KEY(id),
+   CONSTRAINT webhook_id_FK FOREIGN KEY(webhook_id) REFERENCES webhooks(id),
+   CONSTRAINT user_id_FK FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE INDEX webhooks_deliveries_status_due_at_IDX ON webhooks_deliveries(status, due_at);
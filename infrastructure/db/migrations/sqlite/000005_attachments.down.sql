@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS attachments_user_id_IDX;
+DROP INDEX IF EXISTS attachments_note_id_IDX;
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,18 @@
+CREATE TABLE `attachments` (
+  `id` INTEGER NOT NULL,
+  `note_id` INTEGER NOT NULL,
+  `user_id` INTEGER NOT NULL,
+  `name` TEXT NOT NULL,
+  `content_type` TEXT NOT NULL,
+  `size` INTEGER NOT NULL,
+  `blob_key` TEXT NOT NULL,
+  `thumbnail_key` TEXT NOT NULL DEFAULT "",
+  `thumbnail_size` INTEGER NOT NULL DEFAULT 0,
+  `created_at` TEXT NOT NULL,
+   CONSTRAINT attachment_PK PRIMARY KEY(id),
+   CONSTRAINT user_id_FK FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE INDEX attachments_note_id_IDX ON attachments(note_id);
+
+CREATE INDEX attachments_user_id_IDX ON attachments(user_id);
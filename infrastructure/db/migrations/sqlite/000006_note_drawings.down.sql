@@ -0,0 +1,2 @@
+ALTER TABLE notes DROP COLUMN drawing;
+UPDATE notes SET type = "note" WHERE type = "drawing";
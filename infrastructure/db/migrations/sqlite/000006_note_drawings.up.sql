@@ -0,0 +1 @@
+ALTER TABLE `notes` ADD COLUMN `drawing` TEXT NULL;
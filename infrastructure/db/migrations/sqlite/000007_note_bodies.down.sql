@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS notes_links_note_id_IDX;
+DROP TABLE IF EXISTS notes_links;
+ALTER TABLE notes DROP COLUMN body;
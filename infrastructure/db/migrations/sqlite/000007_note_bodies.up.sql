@@ -0,0 +1,11 @@
+ALTER TABLE `notes` ADD COLUMN `body` TEXT NULL;
+
+CREATE TABLE `notes_links` (
+  `id` INTEGER NOT NULL,
+  `note_id` INTEGER NOT NULL,
+  `url` TEXT NOT NULL,
+   CONSTRAINT notes_links_PK PRIMARY KEY(id),
+   CONSTRAINT note_id_FK FOREIGN KEY(note_id) REFERENCES notes(id)
+);
+
+CREATE INDEX notes_links_note_id_IDX ON notes_links(note_id);
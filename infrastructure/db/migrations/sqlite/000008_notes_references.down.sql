@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS notes_references_title_IDX;
+DROP INDEX IF EXISTS notes_references_target_id_IDX;
+DROP INDEX IF EXISTS notes_references_note_id_IDX;
+DROP TABLE IF EXISTS notes_references;
@@ -0,0 +1,14 @@
+CREATE TABLE `notes_references` (
+  `id` INTEGER NOT NULL,
+  `note_id` INTEGER NOT NULL,
+  `target_id` INTEGER NULL,
+  `title` TEXT NULL,
+   CONSTRAINT notes_references_PK PRIMARY KEY(id),
+   CONSTRAINT note_id_FK FOREIGN KEY(note_id) REFERENCES notes(id)
+);
+
+CREATE INDEX notes_references_note_id_IDX ON notes_references(note_id);
+
+CREATE INDEX notes_references_target_id_IDX ON notes_references(target_id);
+
+CREATE INDEX notes_references_title_IDX ON notes_references(title);
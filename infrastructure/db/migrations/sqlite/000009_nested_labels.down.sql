@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS labels_parent_id_IDX;
+ALTER TABLE labels DROP COLUMN parent_id;
@@ -0,0 +1,3 @@
+ALTER TABLE `labels` ADD COLUMN `parent_id` INTEGER NULL REFERENCES labels(id);
+
+CREATE INDEX labels_parent_id_IDX ON labels(parent_id);
@@ -0,0 +1,10 @@
+CREATE TABLE `users_colors` (
+  `id` INTEGER NOT NULL,
+  `user_id` INTEGER NOT NULL,
+  `name` TEXT NOT NULL,
+  `hex` TEXT NOT NULL,
+  `created_at` TEXT NOT NULL,
+   CONSTRAINT users_colors_PK PRIMARY KEY(id),
+   CONSTRAINT users_colors_name_UNIQUE UNIQUE(user_id, name),
+   CONSTRAINT user_id_FK FOREIGN KEY(user_id) REFERENCES users(id)
+);
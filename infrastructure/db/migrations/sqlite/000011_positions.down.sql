@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS notes_user_id_position_IDX;
+ALTER TABLE notes_items DROP COLUMN position;
+ALTER TABLE notes DROP COLUMN position;
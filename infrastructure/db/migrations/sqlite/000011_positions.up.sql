@@ -0,0 +1,15 @@
+ALTER TABLE `notes` ADD COLUMN `position` TEXT NOT NULL DEFAULT '';
+ALTER TABLE `notes_items` ADD COLUMN `position` TEXT NOT NULL DEFAULT '';
+
+-- the notes keep their order, pinned then the last updated first
+UPDATE notes SET position = (
+  SELECT printf('%010di', COUNT(*)) FROM notes n WHERE n.user_id = notes.user_id AND (
+    n.is_pinned > notes.is_pinned OR (n.is_pinned = notes.is_pinned AND (
+      n.updated_at > notes.updated_at OR (n.updated_at = notes.updated_at AND n.id >= notes.id))))
+);
+
+UPDATE notes_items SET position = (
+  SELECT printf('%010di', COUNT(*)) FROM notes_items i WHERE i.note_id = notes_items.note_id AND i.id <= notes_items.id
+);
+
+CREATE INDEX notes_user_id_position_IDX ON notes(user_id, position);
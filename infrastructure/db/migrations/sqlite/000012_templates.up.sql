@@ -0,0 +1,24 @@
+CREATE TABLE `templates` (
+  `id` INTEGER NOT NULL,
+  `user_id` INTEGER NOT NULL,
+  `name` TEXT NOT NULL,
+  `title` TEXT NULL,
+  `body` TEXT NOT NULL,
+  `color` TEXT NULL,
+  `type` TEXT NOT NULL DEFAULT 'note',
+  `items` TEXT NOT NULL,
+  `drawing` TEXT NULL,
+  `created_at` TEXT NOT NULL,
+  `updated_at` TEXT NOT NULL,
+   CONSTRAINT templates_PK PRIMARY KEY(id),
+   CONSTRAINT templates_name_UNIQUE UNIQUE(user_id, name),
+   CONSTRAINT user_id_FK FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE TABLE `templates_labels` (
+  `template_id` INTEGER NOT NULL,
+  `label_id` INTEGER NOT NULL,
+  CONSTRAINT templates_labels_PK PRIMARY KEY(template_id, label_id),
+  CONSTRAINT template_id_FK FOREIGN KEY(template_id) REFERENCES templates(id),
+  CONSTRAINT label_id_FK FOREIGN KEY(label_id) REFERENCES labels(id)
+);
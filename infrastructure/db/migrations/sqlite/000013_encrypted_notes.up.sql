@@ -0,0 +1,18 @@
+ALTER TABLE `notes` ADD COLUMN `encrypted` TEXT NULL;
+
+CREATE TABLE `users_key_envelopes` (
+  `user_id` INTEGER NOT NULL,
+  `wrapped_key` TEXT NOT NULL,
+  `algorithm` TEXT NOT NULL,
+  `nonce` TEXT NOT NULL,
+  `kdf` TEXT NOT NULL,
+  `salt` TEXT NOT NULL,
+  `iterations` INTEGER NOT NULL,
+  `memory` INTEGER NOT NULL,
+  `parallelism` INTEGER NOT NULL,
+  `key_version` INTEGER NOT NULL,
+  `created_at` TEXT NOT NULL,
+  `updated_at` TEXT NOT NULL,
+   CONSTRAINT users_key_envelopes_PK PRIMARY KEY(user_id),
+   CONSTRAINT user_id_FK FOREIGN KEY(user_id) REFERENCES users(id)
+);
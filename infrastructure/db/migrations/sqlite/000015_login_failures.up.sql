@@ -0,0 +1,8 @@
+CREATE TABLE `users_login_failures` (
+  `user_id` INTEGER NOT NULL,
+  `attempts` INTEGER NOT NULL,
+  `locked_until` TEXT NULL,
+  `updated_at` TEXT NOT NULL,
+   CONSTRAINT users_login_failures_PK PRIMARY KEY(user_id),
+   CONSTRAINT user_id_FK FOREIGN KEY(user_id) REFERENCES users(id)
+);
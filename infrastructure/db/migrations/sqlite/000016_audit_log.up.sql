@@ -0,0 +1,25 @@
+CREATE TABLE `audit_events` (
+  `id` INTEGER NOT NULL,
+  `user_id` INTEGER NULL,
+  `actor_id` INTEGER NULL,
+  `action` TEXT NOT NULL,
+  `ip` TEXT NOT NULL,
+  `user_agent` TEXT NOT NULL,
+  `details` TEXT NOT NULL,
+  `created_at` TEXT NOT NULL,
+   CONSTRAINT audit_events_PK PRIMARY KEY(id)
+);
+
+CREATE INDEX `audit_events_user_id_created_at_idx` ON `audit_events` (`user_id`, `created_at`);
+
+CREATE INDEX `audit_events_created_at_idx` ON `audit_events` (`created_at`);
+
+CREATE TRIGGER `audit_events_no_update` BEFORE UPDATE ON `audit_events`
+BEGIN
+  SELECT RAISE(ABORT, 'audit events are append-only');
+END;
+
+CREATE TRIGGER `audit_events_no_delete` BEFORE DELETE ON `audit_events`
+BEGIN
+  SELECT RAISE(ABORT, 'audit events are append-only');
+END;
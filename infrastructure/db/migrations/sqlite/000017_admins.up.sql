@@ -0,0 +1,2 @@
+-- reads the audit log of all the accounts, set by the admin command
+ALTER TABLE `users` ADD COLUMN `is_admin` INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,17 @@
+CREATE TEMP TABLE notes_ranks AS SELECT a.id, COUNT(*) AS r FROM notes a JOIN notes b ON b.user_id = a.user_id AND (
+  b.position < a.position OR (b.position = a.position AND b.id <= a.id)
+) GROUP BY a.id;
+
+UPDATE notes SET position = (SELECT printf('%010di', r) FROM notes_ranks WHERE notes_ranks.id = notes.id);
+
+DROP TABLE notes_ranks;
+
+CREATE TEMP TABLE notes_items_ranks AS SELECT a.id, COUNT(*) AS r FROM notes_items a JOIN notes_items b
+  ON b.note_id = a.note_id AND (b.position < a.position OR (b.position = a.position AND b.id <= a.id))
+GROUP BY a.id;
+
+UPDATE notes_items SET position = (
+  SELECT printf('%010di', r) FROM notes_items_ranks WHERE notes_items_ranks.id = notes_items.id
+);
+
+DROP TABLE notes_items_ranks;
@@ -0,0 +1,2 @@
+DROP INDEX notes_references_title_hash_IDX;
+ALTER TABLE notes_references DROP COLUMN title_hash;
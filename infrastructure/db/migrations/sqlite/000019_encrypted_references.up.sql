@@ -0,0 +1,4 @@
+-- keyed hash of the title when the encryption at rest is enabled, the backlinks are looked up by it
+ALTER TABLE `notes_references` ADD COLUMN `title_hash` TEXT NULL;
+
+CREATE INDEX notes_references_title_hash_IDX ON notes_references(title_hash);
@@ -0,0 +1,135 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// MaxRedirects followed by the clients, every hop is checked as the first request
+const MaxRedirects = 5
+
+// ErrNotPublic is returned when a target resolves to a loopback, private or otherwise reserved address
+var ErrNotPublic = errors.New("target is not a public address")
+
+// reserved ranges, on top of the loopback, private, link local, multicast and unspecified ones of net.IP
+var reserved = parseCIDRs(
+	"0.0.0.0/8",          // this network
+	"100.64.0.0/10",      // carrier-grade NAT
+	"192.0.0.0/24",       // IETF protocol assignments
+	"192.0.2.0/24",       // documentation
+	"198.18.0.0/15",      // benchmarking
+	"198.51.100.0/24",    // documentation
+	"203.0.113.0/24",     // documentation
+	"240.0.0.0/4",        // reserved, broadcast
+	"64:ff9b::/96",       // NAT64, embeds an IPv4 address
+	"64:ff9b:1::/48",     // local NAT64
+	"100::/64",           // discard
+	"2001::/23",          // IETF protocol assignments, Teredo
+	"2001:db8::/32",      // documentation
+	"2002::/16",          // 6to4, embeds an IPv4 address
+	"fec0::/10",          // deprecated site local
+	"::ffff:0:0:0/96",    // IPv4-translated
+	"255.255.255.255/32", // broadcast
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, nets[i], _ = net.ParseCIDR(cidr)
+	}
+
+	return nets
+}
+
+// IsPublic tells whether the ip is routable on the internet
+func IsPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+
+	for _, ipRange := range reserved {
+		if ipRange.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckURL accepts the http(s) urls. Unless private targets are allowed, the url can't name a non public IP,
+// the host names are checked once resolved, on every connection
+func CheckURL(rawURL string, allowPrivate bool) error {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return errors.New("must be a http(s) url")
+	}
+
+	if allowPrivate {
+		return nil
+	}
+
+	if target.Hostname() == "localhost" {
+		return ErrNotPublic
+	}
+
+	if ip := net.ParseIP(target.Hostname()); ip != nil && !IsPublic(ip) {
+		return ErrNotPublic
+	}
+
+	return nil
+}
+
+// New returns a client of outgoing requests to the targets set by the users, like the webhooks.
+// Unless private targets are allowed, it refuses to connect to the addresses that aren't public.
+// The check is done on the resolved address of every connection, the names resolving to a public address
+// then to a private one are caught too. The proxies of the environment aren't used, they would hide the address
+func New(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = checkAddress
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= MaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", MaxRedirects)
+			}
+
+			if err := CheckURL(req.URL.String(), allowPrivate); err != nil {
+				return fmt.Errorf("redirect to %s: %w", req.URL.Redacted(), err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// checkAddress is the control of the dialer, it's called with the resolved address before connecting
+func checkAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublic(ip) {
+		return fmt.Errorf("%s %s: %w", network, address, ErrNotPublic)
+	}
+
+	return nil
+}
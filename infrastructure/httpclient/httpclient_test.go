@@ -0,0 +1,80 @@
+package httpclient_test
+
+import (
+	"errors"
+	"librenote/infrastructure/httpclient"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublic(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"93.184.216.34":        true,
+		"2606:2800:220:1::":    true,
+		"127.0.0.1":            false,
+		"10.1.2.3":             false,
+		"172.16.0.1":           false,
+		"192.168.1.1":          false,
+		"169.254.169.254":      false,
+		"100.64.0.1":           false,
+		"0.0.0.0":              false,
+		"255.255.255.255":      false,
+		"::1":                  false,
+		"::":                   false,
+		"fd00::1":              false,
+		"fe80::1":              false,
+		"::ffff:127.0.0.1":     false,
+		"64:ff9b::a00:1":       false,
+		"2002:a00:1::":         false,
+		"ff02::1":              false,
+		"::ffff:93.184.216.34": true,
+	} {
+		assert.Equal(t, public, httpclient.IsPublic(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	assert.NoError(t, httpclient.CheckURL("https://hooks.example.com/notes", false))
+	assert.Error(t, httpclient.CheckURL("ftp://example.com", false))
+	assert.Error(t, httpclient.CheckURL("http://", false))
+	assert.ErrorIs(t, httpclient.CheckURL("http://127.0.0.1:8000/api", false), httpclient.ErrNotPublic)
+	assert.ErrorIs(t, httpclient.CheckURL("http://[::1]/", false), httpclient.ErrNotPublic)
+	assert.ErrorIs(t, httpclient.CheckURL("http://localhost/", false), httpclient.ErrNotPublic)
+	assert.NoError(t, httpclient.CheckURL("http://127.0.0.1:8000/api", true))
+}
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/loop" {
+			http.Redirect(w, r, "/loop", http.StatusFound)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	t.Run("private-refused", func(t *testing.T) {
+		_, err := httpclient.New(time.Second, false).Get(server.URL)
+		assert.True(t, errors.Is(err, httpclient.ErrNotPublic), err)
+	})
+
+	t.Run("private-allowed", func(t *testing.T) {
+		resp, err := httpclient.New(time.Second, true).Get(server.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		resp.Body.Close()
+	})
+
+	t.Run("redirects-capped", func(t *testing.T) {
+		_, err := httpclient.New(time.Second, true).Get(server.URL + "/loop")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "stopped after 5 redirects")
+	})
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"librenote/infrastructure/config"
+	"log"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+type requestIDKey struct{}
+
+type userIDKey struct{}
+
+// Setup configures the logrus standard logger used all over the application, the stdlib log is written to it too
+func Setup(cfg config.LogConfig) error {
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+
+	logrus.SetLevel(level)
+
+	if cfg.Format == "json" {
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	}
+
+	var out io.Writer = os.Stderr
+
+	if cfg.File != "" {
+		out, err = NewRotatingFile(cfg.File, cfg.MaxSizeBytes, cfg.MaxBackups)
+		if err != nil {
+			return err
+		}
+	}
+
+	logrus.SetOutput(out)
+
+	log.SetFlags(0)
+	log.SetOutput(logrus.StandardLogger().WriterLevel(logrus.InfoLevel))
+
+	return nil
+}
+
+// IsJSON tells whether the logs are structured as json, rather than read by a human
+func IsJSON() bool {
+	_, ok := logrus.StandardLogger().Formatter.(*logrus.JSONFormatter)
+
+	return ok
+}
+
+// WithRequestID keeps the request id in the context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// WithUserID keeps the authenticated user id in the context
+func WithUserID(ctx context.Context, id int32) context.Context {
+	return context.WithValue(ctx, userIDKey{}, id)
+}
+
+// RequestID returns the request id kept in the context, it's empty outside of a request
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
+
+// FromContext returns a log entry tagged with the request and the user ids of the context, when there are some
+func FromContext(ctx context.Context) *logrus.Entry {
+	fields := logrus.Fields{}
+
+	if id := RequestID(ctx); id != "" {
+		fields["request_id"] = id
+	}
+
+	if id, ok := ctx.Value(userIDKey{}).(int32); ok {
+		fields["user_id"] = id
+	}
+
+	return logrus.WithContext(ctx).WithFields(fields)
+}
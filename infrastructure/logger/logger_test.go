@@ -0,0 +1,55 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/logger"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "librenote.log")
+
+	defer func() {
+		logrus.SetOutput(os.Stderr)
+		logrus.SetFormatter(&logrus.TextFormatter{})
+		logrus.SetLevel(logrus.InfoLevel)
+		log.SetOutput(os.Stderr)
+	}()
+
+	t.Run("json", func(t *testing.T) {
+		err := logger.Setup(config.LogConfig{Level: "warn", Format: "json", File: path})
+		assert.NoError(t, err)
+		assert.True(t, logger.IsJSON())
+
+		ctx := logger.WithUserID(logger.WithRequestID(context.Background(), "req-1"), 7)
+		logger.FromContext(ctx).Info("skipped")
+		logger.FromContext(ctx).Warn("kept")
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal(data, &entry))
+		assert.Equal(t, "kept", entry["msg"])
+		assert.Equal(t, "req-1", entry["request_id"])
+		assert.Equal(t, float64(7), entry["user_id"])
+	})
+
+	t.Run("unknown-level", func(t *testing.T) {
+		assert.Error(t, logger.Setup(config.LogConfig{Level: "verbose", Format: "text"}))
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	entry := logger.FromContext(context.Background())
+	assert.Empty(t, entry.Data)
+	assert.Empty(t, logger.RequestID(context.Background()))
+}
@@ -0,0 +1,53 @@
+package logger_test
+
+import (
+	"librenote/infrastructure/logger"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "librenote.log")
+
+	f, err := logger.NewRotatingFile(path, 10, 2)
+	assert.NoError(t, err)
+
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = f.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+
+	for name, content := range map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	} {
+		data, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRotatingFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "librenote.log")
+	assert.NoError(t, os.WriteFile(path, []byte("old\n"), 0o600))
+
+	f, err := logger.NewRotatingFile(path, 0, 2)
+	assert.NoError(t, err)
+
+	_, err = f.Write([]byte("new\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "old\nnew\n", string(data))
+}
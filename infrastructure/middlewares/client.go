@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"fmt"
+	"librenote/app/model"
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IPExtractor returns the client IP of the requests. It's the address of the connection, unless it comes from
+// one of the trusted proxies, then it's the last address of X-Forwarded-For not added by a trusted proxy.
+// The proxies are IP addresses or CIDR ranges
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range trustedProxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %s", proxy)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				bits = 8 * net.IPv4len
+			}
+
+			proxy = fmt.Sprintf("%s/%d", proxy, bits)
+		}
+
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %s", proxy)
+		}
+
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// ClientInfo keeps the client in the request context, for the usecases recording it
+func ClientInfo(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		ctx := model.WithClient(req.Context(), model.Client{IP: c.RealIP(), UserAgent: req.UserAgent()})
+		c.SetRequest(req.WithContext(ctx))
+
+		return next(c)
+	}
+}
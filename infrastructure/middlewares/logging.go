@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"librenote/infrastructure/logger"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+// RequestID gives every request an id, kept in the request context and returned in the X-Request-ID header. The id
+// sent by a proxy in the same header is kept
+func RequestID() echo.MiddlewareFunc {
+	return middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, id string) {
+			req := c.Request()
+			c.SetRequest(req.WithContext(logger.WithRequestID(req.Context(), id)))
+		},
+	})
+}
+
+// RequestLogger logs every request once answered, tagged with the request and the user ids
+func RequestLogger(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+
+		if err := next(c); err != nil {
+			c.Error(err)
+		}
+
+		req := c.Request()
+		res := c.Response()
+
+		entry := logger.FromContext(req.Context()).WithFields(logrus.Fields{
+			"method":     req.Method,
+			"uri":        req.RequestURI,
+			"status":     res.Status,
+			"latency":    time.Since(start).String(),
+			"ip":         c.RealIP(),
+			"user_agent": req.UserAgent(),
+		})
+
+		if res.Status >= http.StatusInternalServerError {
+			entry.Error("request")
+		} else {
+			entry.Info("request")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package middlewares_test
+
+import (
+	"errors"
+	"librenote/infrastructure/logger"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLogger(t *testing.T) {
+	hook := test.NewGlobal()
+	defer logrus.StandardLogger().ReplaceHooks(logrus.LevelHooks{})
+
+	e := echo.New()
+	e.Use(middlewares.RequestID())
+	e.Use(middlewares.RequestLogger)
+	e.GET("/api/v1/notes", func(c echo.Context) error {
+		req := c.Request()
+		c.SetRequest(req.WithContext(logger.WithUserID(req.Context(), 7)))
+
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/api/v1/fail", func(c echo.Context) error {
+		return errors.New("failed")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		hook.Reset()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/notes", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		id := rec.Header().Get(echo.HeaderXRequestID)
+		assert.NotEmpty(t, id)
+
+		entry := hook.LastEntry()
+		assert.Equal(t, logrus.InfoLevel, entry.Level)
+		assert.Equal(t, id, entry.Data["request_id"])
+		assert.Equal(t, int32(7), entry.Data["user_id"])
+		assert.Equal(t, http.StatusOK, entry.Data["status"])
+		assert.Equal(t, "/api/v1/notes", entry.Data["uri"])
+	})
+
+	t.Run("proxy-id", func(t *testing.T) {
+		hook.Reset()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/notes", nil)
+		req.Header.Set(echo.HeaderXRequestID, "proxy-id")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "proxy-id", rec.Header().Get(echo.HeaderXRequestID))
+		assert.Equal(t, "proxy-id", hook.LastEntry().Data["request_id"])
+	})
+
+	t.Run("error", func(t *testing.T) {
+		hook.Reset()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/fail", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Len(t, hook.AllEntries(), 1)
+
+		entry := hook.LastEntry()
+		assert.Equal(t, logrus.ErrorLevel, entry.Level)
+		assert.Equal(t, http.StatusInternalServerError, entry.Data["status"])
+		assert.NotContains(t, entry.Data, "user_id")
+	})
+}
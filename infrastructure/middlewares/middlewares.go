@@ -1,7 +1,10 @@
 package middlewares
 
 import (
+	"errors"
 	"librenote/infrastructure/config"
+	"librenote/infrastructure/logger"
+	"strconv"
 
 	"github.com/golang-jwt/jwt"
 
@@ -9,8 +12,8 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 )
 
-const EchoLogFormat = "time: ${time_rfc3339_nano} || ${method}: ${uri} || u_agent: ${user_agent} || status: ${status}" +
-	" || latency: ${latency_human} \n"
+// authScheme of the Authorization header carrying the jwt token
+const authScheme = "Bearer"
 
 type JwtCustomClaims struct {
 	UserID int32 `json:"user_id"`
@@ -20,23 +23,75 @@ type JwtCustomClaims struct {
 // Attach middlewares required for the application
 func Attach(e *echo.Echo) error {
 	cfg := config.Get().App
+	security := config.Get().Security
+
+	ipExtractor, err := IPExtractor(security.TrustedProxies)
+	if err != nil {
+		return err
+	}
+
+	e.IPExtractor = ipExtractor
 
 	// echo middlewares
-	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{Format: EchoLogFormat}))
+	e.Use(RequestID())
+	e.Use(RequestLogger)
 	e.Use(middleware.Recover())
+
+	if len(security.CORS.AllowOrigins) > 0 {
+		e.Use(CORS(security.CORS))
+	}
+
+	e.Use(SecurityHeaders(security))
 	e.Use(middleware.BodyLimit(cfg.RequestBodyLimit))
+	e.Use(ClientInfo)
+
+	if jwtCookie := config.Get().Jwt.Cookie; jwtCookie.Enabled {
+		e.Use(CSRF(security, jwtCookie.Name))
+	}
+
 	e.Pre(middleware.RemoveTrailingSlash())
 
 	return nil
 }
 
+// AttachJwtToGroup authenticates the requests of the group by the bearer token, or by the session cookie when enabled.
+// The user id is kept in the request context for the logs
 func AttachJwtToGroup(eg *echo.Group) error {
+	jwtCfg := config.Get().Jwt
+
+	tokenLookup := "header:" + echo.HeaderAuthorization
+	if jwtCfg.Cookie.Enabled {
+		tokenLookup += ",cookie:" + jwtCfg.Cookie.Name
+	}
+
 	eg.Use(middleware.JWTWithConfig(
 		middleware.JWTConfig{
-			Claims:     &JwtCustomClaims{},
-			SigningKey: []byte(config.Get().Jwt.SecretKey),
+			Claims:      &JwtCustomClaims{},
+			SigningKey:  []byte(jwtCfg.SecretKey),
+			TokenLookup: tokenLookup,
+			AuthScheme:  authScheme,
+			SuccessHandler: func(c echo.Context) {
+				req := c.Request()
+				c.SetRequest(req.WithContext(logger.WithUserID(req.Context(), GetUserID(c))))
+			},
 		}),
 	)
 
 	return nil
 }
+
+// GetUserID returns the authenticated user id from the jwt token
+func GetUserID(c echo.Context) int32 {
+	token := c.Get("user").(*jwt.Token)
+	return token.Claims.(*JwtCustomClaims).UserID
+}
+
+// ParamID returns the positive id from the named path param
+func ParamID(c echo.Context, name string) (int32, error) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 32)
+	if err != nil || id < 1 {
+		return 0, errors.New("invalid " + name)
+	}
+
+	return int32(id), nil
+}
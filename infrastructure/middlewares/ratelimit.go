@@ -0,0 +1,147 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"librenote/app/response"
+	"librenote/infrastructure/config"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimiter counts the requests of every key within fixed windows
+type RateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	windows map[string]*rateWindow
+	// the expired windows are dropped once per window
+	swept time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter accepts limit requests per key within the window, a limit of 0 accepts all of them
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		windows: map[string]*rateWindow{},
+	}
+}
+
+// Allow counts a request of the key, it returns how long to wait when the key is over its limit
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	if l.limit <= 0 || key == "" {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.swept) >= l.window {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+
+		l.swept = now
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+
+	if w.count >= l.limit {
+		return false, w.start.Add(l.window).Sub(now)
+	}
+
+	w.count++
+
+	return true, 0
+}
+
+// RateLimit throttles the requests by the IP address and by the email of the body, like the login ones.
+// The requests over either limit get 429 with a Retry-After header
+func RateLimit(cfg config.RateLimitConfig) echo.MiddlewareFunc {
+	if cfg.Disabled {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	byIP := NewRateLimiter(cfg.IPLimit, cfg.IPWindow)
+	byEmail := NewRateLimiter(cfg.EmailLimit, cfg.EmailWindow)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ok, retryAfter := byIP.Allow(c.RealIP())
+			if ok {
+				ok, retryAfter = byEmail.Allow(requestEmail(c))
+			}
+
+			if !ok {
+				err := response.TooManyRequests(errors.New("too many requests, retry later"), retryAfter)
+				SetRetryAfter(c, retryAfter)
+
+				return c.JSON(response.RespondError(err))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// SetRetryAfter tells the client how many seconds to wait before retrying
+func SetRetryAfter(c echo.Context, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	c.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+}
+
+// requestEmail reads the email of a json or a form body, the body stays readable by the handler
+func requestEmail(c echo.Context) string {
+	req := c.Request()
+
+	if !strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		return normalizeEmail(c.FormValue("email"))
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err != nil {
+		return ""
+	}
+
+	var payload struct {
+		Email string `json:"email"`
+	}
+
+	if err = json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+
+	return normalizeEmail(payload.Email)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
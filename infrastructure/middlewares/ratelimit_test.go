@@ -0,0 +1,115 @@
+package middlewares_test
+
+import (
+	"io"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := middlewares.NewRateLimiter(2, 100*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		ok, _ := limiter.Allow("10.0.0.1")
+		assert.True(t, ok)
+	}
+
+	ok, retryAfter := limiter.Allow("10.0.0.1")
+	assert.False(t, ok)
+	assert.True(t, retryAfter > 0 && retryAfter <= 100*time.Millisecond)
+
+	// the keys are counted apart
+	ok, _ = limiter.Allow("10.0.0.2")
+	assert.True(t, ok)
+
+	time.Sleep(retryAfter)
+
+	ok, _ = limiter.Allow("10.0.0.1")
+	assert.True(t, ok)
+
+	// no limit
+	limiter = middlewares.NewRateLimiter(0, time.Minute)
+
+	for i := 0; i < 100; i++ {
+		ok, _ = limiter.Allow("10.0.0.1")
+		assert.True(t, ok)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	e := echo.New()
+	e.POST("/login", func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+
+		return c.String(http.StatusOK, string(body))
+	}, middlewares.RateLimit(config.RateLimitConfig{
+		IPLimit: 3, IPWindow: time.Minute, EmailLimit: 2, EmailWindow: time.Minute,
+	}))
+
+	login := func(ip, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		req.Header.Set(echo.HeaderXRealIP, ip)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	t.Run("by-email", func(t *testing.T) {
+		body := `{"email":"mrtest@example.com","password":"12345678"}`
+
+		rec := login("10.0.0.1", echo.MIMEApplicationJSON, body)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		// the handler still reads the body
+		assert.Equal(t, body, rec.Body.String())
+
+		form := url.Values{"email": {" MrTest@example.com"}, "password": {"12345678"}}.Encode()
+		rec = login("10.0.0.2", echo.MIMEApplicationForm, form)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = login("10.0.0.3", echo.MIMEApplicationJSON, body)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+		assert.Contains(t, rec.Body.String(), "too many requests")
+	})
+
+	t.Run("by-ip", func(t *testing.T) {
+		for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+			rec := login("10.0.0.4", echo.MIMEApplicationJSON, `{"email":"`+email+`"}`)
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+
+		rec := login("10.0.0.4", echo.MIMEApplicationJSON, `{"email":"d@example.com"}`)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+		rec = login("10.0.0.4", echo.MIMEApplicationJSON, `not json`)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		handler := middlewares.RateLimit(config.RateLimitConfig{Disabled: true, IPLimit: 1})(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+		for i := 0; i < 3; i++ {
+			rec := httptest.NewRecorder()
+			assert.NoError(t, handler(e.NewContext(httptest.NewRequest(http.MethodPost, "/login", nil), rec)))
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+}
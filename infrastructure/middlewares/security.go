@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"librenote/infrastructure/config"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// CORS lets the browser clients of the allowed origins call the API
+func CORS(cfg config.CORSConfig) echo.MiddlewareFunc {
+	return middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins:     cfg.AllowOrigins,
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    cfg.ExposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	})
+}
+
+// SecurityHeaders sends the security headers of the config, the Strict-Transport-Security one only over https
+func SecurityHeaders(cfg config.SecurityConfig) echo.MiddlewareFunc {
+	return middleware.SecureWithConfig(middleware.SecureConfig{
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         cfg.FrameOptions,
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+		HSTSExcludeSubdomains: cfg.HSTSExcludeSubdomains,
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+		ReferrerPolicy:        cfg.ReferrerPolicy,
+	})
+}
+
+// CSRF checks the double submitted token of the requests authenticated by the session cookie: the unsafe ones
+// must send the value of the CSRF cookie in the CSRF header. The requests with a Bearer token or without the
+// session cookie aren't checked, the browsers never send them on their own. Any other Authorization header, like the
+// Basic credentials the browsers cache, is checked as the JWT middleware then falls back to the cookie.
+// The token is sent in the CSRF header of the responses too, as the clients of other origins can't read the cookie
+func CSRF(cfg config.SecurityConfig, sessionCookie string) echo.MiddlewareFunc {
+	csrf := middleware.CSRFWithConfig(middleware.CSRFConfig{
+		Skipper: func(c echo.Context) bool {
+			if cfg.CSRF.Disabled || hasBearer(c.Request()) {
+				return true
+			}
+
+			_, err := c.Cookie(sessionCookie)
+
+			return err != nil
+		},
+		TokenLookup:    "header:" + cfg.CSRF.HeaderName,
+		CookieName:     cfg.CSRF.CookieName,
+		CookiePath:     "/",
+		CookieSecure:   !cfg.InsecureCookies,
+		CookieSameSite: http.SameSiteStrictMode,
+	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return csrf(func(c echo.Context) error {
+			if token, ok := c.Get(middleware.DefaultCSRFConfig.ContextKey).(string); ok {
+				c.Response().Header().Set(cfg.CSRF.HeaderName, token)
+			}
+
+			return next(c)
+		})
+	}
+}
+
+// hasBearer tells whether the JWT middleware authenticates the request by its Authorization header, as it does
+// for a Bearer token only
+func hasBearer(r *http.Request) bool {
+	auth := r.Header.Get(echo.HeaderAuthorization)
+
+	return len(auth) > len(authScheme)+1 && strings.EqualFold(auth[:len(authScheme)], authScheme)
+}
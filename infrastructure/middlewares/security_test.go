@@ -0,0 +1,198 @@
+package middlewares_test
+
+import (
+	"librenote/app/model"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	e := echo.New()
+	e.Use(middlewares.CORS(config.CORSConfig{
+		AllowOrigins: []string{"https://notes.example.com"}, ExposeHeaders: []string{"Retry-After"},
+		AllowCredentials: true, MaxAge: 600,
+	}))
+	e.GET("/api/v1/notes", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/notes", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://notes.example.com")
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://notes.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Equal(t, "true", rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+		assert.Equal(t, "600", rec.Header().Get(echo.HeaderAccessControlMaxAge))
+	})
+
+	t.Run("other-origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/notes", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://evil.example.com")
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	})
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	handler := middlewares.SecurityHeaders(config.SecurityConfig{
+		HSTSMaxAge: 3600, FrameOptions: "DENY", ContentSecurityPolicy: "default-src 'none'",
+		ReferrerPolicy: "no-referrer",
+	})(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	rec := httptest.NewRecorder()
+	assert.NoError(t, handler(echo.New().NewContext(req, rec)))
+
+	assert.Equal(t, "nosniff", rec.Header().Get(echo.HeaderXContentTypeOptions))
+	assert.Equal(t, "DENY", rec.Header().Get(echo.HeaderXFrameOptions))
+	assert.Equal(t, "default-src 'none'", rec.Header().Get(echo.HeaderContentSecurityPolicy))
+	assert.Equal(t, "no-referrer", rec.Header().Get(echo.HeaderReferrerPolicy))
+	// over http
+	assert.Empty(t, rec.Header().Get(echo.HeaderStrictTransportSecurity))
+
+	req.Header.Set(echo.HeaderXForwardedProto, "https")
+	rec = httptest.NewRecorder()
+	assert.NoError(t, handler(echo.New().NewContext(req, rec)))
+
+	assert.Equal(t, "max-age=3600; includeSubdomains", rec.Header().Get(echo.HeaderStrictTransportSecurity))
+}
+
+func TestCSRF(t *testing.T) {
+	cfg := config.SecurityConfig{CSRF: config.CSRFConfig{CookieName: "_csrf", HeaderName: "X-CSRF-Token"}}
+
+	e := echo.New()
+	e.Use(middlewares.CSRF(cfg, "session"))
+	e.GET("/api/v1/me", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.POST("/api/v1/me", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	send := func(method string, cookies []*http.Cookie, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/me", nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	session := &http.Cookie{Name: "session", Value: "jwt"}
+
+	// the token is given along with the safe requests
+	rec := send(http.MethodGet, []*http.Cookie{session}, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	cookies := rec.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Equal(t, "_csrf", cookies[0].Name)
+	assert.True(t, cookies[0].Secure)
+	assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+
+	token := cookies[0].Value
+
+	rec = send(http.MethodPost, []*http.Cookie{session}, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = send(http.MethodPost, []*http.Cookie{session, cookies[0]}, map[string]string{"X-CSRF-Token": "forged"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = send(http.MethodPost, []*http.Cookie{session, cookies[0]}, map[string]string{"X-CSRF-Token": token})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// the bearer token clients
+	rec = send(http.MethodPost, nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = send(http.MethodPost, []*http.Cookie{session}, map[string]string{echo.HeaderAuthorization: "Bearer jwt"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// the JWT middleware falls back to the cookie with the other schemes, like the Basic credentials of a proxy
+	for _, auth := range []string{"Basic dXNlcjpwYXNz", "Bearer", "Bearer "} {
+		rec = send(http.MethodPost, []*http.Cookie{session}, map[string]string{echo.HeaderAuthorization: auth})
+		assert.Equal(t, http.StatusForbidden, rec.Code, auth)
+	}
+
+	rec = send(http.MethodPost, []*http.Cookie{session, cookies[0]},
+		map[string]string{echo.HeaderAuthorization: "Basic dXNlcjpwYXNz", "X-CSRF-Token": token})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestIPExtractor(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/login", nil)
+	req.RemoteAddr = "10.0.0.2:4000"
+	req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.9, 198.51.100.7")
+	req.Header.Set(echo.HeaderXRealIP, "203.0.113.9")
+
+	t.Run("direct", func(t *testing.T) {
+		extract, err := middlewares.IPExtractor(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "10.0.0.2", extract(req))
+	})
+
+	t.Run("trusted-proxy", func(t *testing.T) {
+		extract, err := middlewares.IPExtractor([]string{"10.0.0.0/24"})
+		assert.NoError(t, err)
+		assert.Equal(t, "198.51.100.7", extract(req))
+	})
+
+	t.Run("trusted-chain", func(t *testing.T) {
+		extract, err := middlewares.IPExtractor([]string{"10.0.0.2", "198.51.100.7"})
+		assert.NoError(t, err)
+		assert.Equal(t, "203.0.113.9", extract(req))
+	})
+
+	t.Run("untrusted-proxy", func(t *testing.T) {
+		extract, err := middlewares.IPExtractor([]string{"10.0.1.0/24"})
+		assert.NoError(t, err)
+		assert.Equal(t, "10.0.0.2", extract(req))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := middlewares.IPExtractor([]string{"proxy.local"})
+		assert.Error(t, err)
+	})
+}
+
+func TestClientInfo(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	req.RemoteAddr = "10.0.0.1:4000"
+	req.Header.Set("User-Agent", "LibreNote-Android")
+
+	var client model.Client
+
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	err := middlewares.ClientInfo(func(c echo.Context) error {
+		client = model.ClientFrom(c.Request().Context())
+
+		return nil
+	})(e.NewContext(req, httptest.NewRecorder()))
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Client{IP: "10.0.0.1", UserAgent: "LibreNote-Android"}, client)
+}
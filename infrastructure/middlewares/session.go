@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"librenote/infrastructure/config"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+var sameSiteModes = map[string]http.SameSite{ // nolint:gochecknoglobals
+	"strict": http.SameSiteStrictMode,
+	"lax":    http.SameSiteLaxMode,
+	"none":   http.SameSiteNoneMode,
+}
+
+// SetSessionCookie keeps the token in the session cookie until the token expires, the scripts can't read it
+func SetSessionCookie(c echo.Context, token string) {
+	c.SetCookie(sessionCookie(token, int(config.Get().Jwt.ExpireTime.Seconds())))
+}
+
+// ClearSessionCookie asks the browser to drop the session cookie
+func ClearSessionCookie(c echo.Context) {
+	c.SetCookie(sessionCookie("", -1))
+}
+
+func sessionCookie(value string, maxAge int) *http.Cookie {
+	cfg := config.Get()
+
+	return &http.Cookie{
+		Name:     cfg.Jwt.Cookie.Name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   !cfg.Security.InsecureCookies,
+		SameSite: sameSiteModes[cfg.Jwt.Cookie.SameSite],
+	}
+}
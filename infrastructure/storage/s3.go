@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"librenote/infrastructure/config"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// S3Store keeps the blobs in a bucket of an S3 compatible object storage
+type S3Store struct {
+	cfg      config.S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+func NewS3Store(cfg config.S3Config) (*S3Store, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+
+	if cfg.Bucket == "" {
+		return nil, errors.New("s3 bucket is required")
+	}
+
+	return &S3Store{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{},
+	}, nil
+}
+
+func (s *S3Store) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	p := strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket + "/" + strings.TrimPrefix(key, "/")
+
+	if s.cfg.VirtualHost {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		p = strings.TrimSuffix(u.Path, "/") + "/" + strings.TrimPrefix(key, "/")
+	}
+
+	u.Path = p
+	u.RawPath = uriEncode(p, false)
+
+	return &u
+}
+
+func (s *S3Store) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (
+	*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	payloadHash := emptyPayload
+	if body != nil {
+		req.ContentLength = size
+		payloadHash = unsignedPayload
+	}
+
+	signV4(req, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, payloadHash, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", os.ErrNotExist, key)
+		}
+
+		return nil, fmt.Errorf("s3 %s %s responded with status %d: %s", method, key, resp.StatusCode, msg)
+	}
+
+	return resp, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, r, size, http.Header{"Content-Type": {contentType}})
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// Open fetches the blob lazily, every seek starts a new ranged request from the new offset
+func (s *S3Store) Open(ctx context.Context, key string, size int64) (io.ReadSeekCloser, error) {
+	return &s3Reader{
+		ctx:   ctx,
+		store: s,
+		key:   key,
+		size:  size,
+	}, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+type s3Reader struct {
+	ctx    context.Context
+	store  *S3Store
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+func (r *s3Reader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	if r.body == nil {
+		resp, err := r.store.do(r.ctx, http.MethodGet, r.key, nil, 0,
+			http.Header{"Range": {fmt.Sprintf("bytes=%d-", r.offset)}})
+		if err != nil {
+			return 0, err
+		}
+
+		r.body = resp.Body
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+
+	return n, err
+}
+
+func (r *s3Reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	}
+
+	if offset < 0 {
+		return 0, errors.New("negative seek offset")
+	}
+
+	if offset != r.offset {
+		_ = r.Close()
+		r.offset = offset
+	}
+
+	return offset, nil
+}
+
+func (r *s3Reader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+
+	err := r.body.Close()
+	r.body = nil
+
+	return err
+}
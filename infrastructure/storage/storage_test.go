@@ -0,0 +1,111 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"librenote/infrastructure/config"
+	"librenote/infrastructure/storage"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string, size int64) (io.ReadSeekCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+func testStore(t *testing.T, s store) {
+	ctx := context.TODO()
+	content := []byte("hello blob store")
+
+	assert.NoError(t, s.Put(ctx, "attachments/1/abc", bytes.NewReader(content), int64(len(content)), "text/plain"))
+
+	r, err := s.Open(ctx, "attachments/1/abc", int64(len(content)))
+	assert.NoError(t, err)
+
+	stored, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, content, stored)
+
+	_, err = r.Seek(6, io.SeekStart)
+	assert.NoError(t, err)
+
+	stored, err = io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "blob store", string(stored))
+	assert.NoError(t, r.Close())
+
+	assert.NoError(t, s.Delete(ctx, "attachments/1/abc"))
+	assert.NoError(t, s.Delete(ctx, "attachments/1/abc"))
+
+	r, err = s.Open(ctx, "attachments/1/abc", int64(len(content)))
+	if err == nil {
+		_, err = io.ReadAll(r)
+	}
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLocalStore(t *testing.T) {
+	testStore(t, storage.NewLocalStore(t.TempDir()))
+
+	s := storage.NewLocalStore(t.TempDir())
+	assert.Error(t, s.Put(context.TODO(), "short", strings.NewReader("abc"), 4, "text/plain"))
+	assert.Error(t, s.Put(context.TODO(), "../", strings.NewReader("abc"), 3, "text/plain"))
+}
+
+// s3Server is an in memory stand-in for an S3 bucket
+func s3Server(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+
+	objects := make(map[string][]byte)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/"))
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			assert.Equal(t, "UNSIGNED-PAYLOAD", r.Header.Get("X-Amz-Content-Sha256"))
+
+			objects[r.URL.Path], _ = io.ReadAll(r.Body)
+		case http.MethodGet:
+			object, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(object))
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestS3Store(t *testing.T) {
+	server := s3Server(t)
+	defer server.Close()
+
+	s, err := storage.NewS3Store(config.S3Config{
+		Endpoint: server.URL, Region: "us-east-1", Bucket: "librenote", AccessKey: "minio", SecretKey: "minio123",
+	})
+	assert.NoError(t, err)
+
+	testStore(t, s)
+
+	_, err = storage.NewS3Store(config.S3Config{Endpoint: server.URL})
+	assert.Error(t, err)
+}
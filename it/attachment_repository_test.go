@@ -0,0 +1,56 @@
+package it_test
+
+import (
+	"context"
+	"database/sql"
+	attachmentRepo "librenote/app/attachment/repository/sqlite"
+	"librenote/app/model"
+	"time"
+)
+
+func (s *SqliteRepositoryTestSuite) TestSqliteAttachmentRepository() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	r := attachmentRepo.NewSqliteAttachmentRepository(s.db)
+	image := &model.Attachment{
+		NoteID: 1, UserID: userID, Name: "cat.png", ContentType: "image/png", Size: 2048,
+		BlobKey: "attachments/1/abc", ThumbnailKey: "attachments/1/abc.thumb", ThumbnailSize: 300, CreatedAt: nowTime,
+	}
+	text := &model.Attachment{
+		NoteID: 1, UserID: userID, Name: "hello.txt", ContentType: "text/plain", Size: 11,
+		BlobKey: "attachments/1/def", CreatedAt: nowTime,
+	}
+	s.Require().NoError(r.CreateAttachment(context.Background(), image, 0))
+	// the thumbnail counts, the text just fits
+	s.Require().NoError(r.CreateAttachment(context.Background(), text, 2359))
+
+	extra := &model.Attachment{
+		NoteID: 1, UserID: userID, Name: "a.txt", ContentType: "text/plain", Size: 1, BlobKey: "attachments/1/ghi",
+		CreatedAt: nowTime,
+	}
+	s.Assert().ErrorIs(r.CreateAttachment(context.Background(), extra, 2359), model.ErrQuotaExceeded)
+
+	res, err := r.GetAttachment(context.Background(), image.ID, 1)
+	s.Require().NoError(err)
+	s.Assert().True(res.HasThumbnail)
+	s.Assert().Equal(int64(300), res.ThumbnailSize)
+
+	_, err = r.GetAttachment(context.Background(), image.ID, 2)
+	s.Assert().ErrorIs(err, sql.ErrNoRows)
+
+	attachments, err := r.FetchAttachments(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Assert().Len(attachments, 2)
+
+	used, err := r.UsedStorage(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Assert().Equal(int64(2359), used)
+
+	s.Require().NoError(r.DeleteAttachment(context.Background(), image.ID))
+	s.Assert().ErrorIs(r.DeleteAttachment(context.Background(), image.ID), sql.ErrNoRows)
+
+	used, err = r.UsedStorage(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Assert().Equal(int64(11), used)
+}
@@ -0,0 +1,53 @@
+package it_test
+
+import (
+	"context"
+	auditRepo "librenote/app/audit/repository/sqlite"
+	"librenote/app/model"
+	"time"
+)
+
+func (s *SqliteRepositoryTestSuite) TestSqliteAuditRepository() {
+	userID, otherID := int32(1), int32(2)
+	r := auditRepo.NewSqliteAuditRepository(s.db)
+
+	for i, e := range []model.AuditEvent{
+		{UserID: &userID, Action: model.AuditLoginFailed, Details: map[string]interface{}{"reason": "wrong password"}},
+		{UserID: &userID, ActorID: &userID, Action: model.AuditLogin},
+		{UserID: &otherID, ActorID: &otherID, Action: model.AuditLogin},
+		{Action: model.AuditLoginFailed, Details: map[string]interface{}{"email": "test@example.com"}},
+	} {
+		e := e
+		e.IP, e.UserAgent = "10.0.0.1", "curl/7.81.0"
+		e.CreatedAt = time.Date(2022, 1, 31, 9, i, 0, 0, time.UTC).Format("2006-01-02 15:04:05")
+		s.Require().NoError(r.CreateEvent(context.Background(), &e))
+		s.Assert().NotZero(e.ID)
+	}
+
+	events, count, err := r.FetchEvents(context.Background(), model.AuditFilter{UserID: &userID}, 10, 0)
+	s.Require().NoError(err)
+	s.Assert().Equal(2, count)
+	s.Assert().Equal(model.AuditLogin, events[0].Action)
+	s.Assert().Equal("wrong password", events[1].Details["reason"])
+	s.Assert().Nil(events[1].ActorID)
+
+	events, count, err = r.FetchEvents(context.Background(), model.AuditFilter{
+		Action: model.AuditLoginFailed, Since: "2022-01-31 09:01:00", Until: "2022-01-31 09:04:00",
+	}, 10, 0)
+	s.Require().NoError(err)
+	s.Assert().Equal(1, count)
+	s.Assert().Nil(events[0].UserID)
+
+	_, count, err = r.FetchEvents(context.Background(), model.AuditFilter{}, 1, 0)
+	s.Require().NoError(err)
+	s.Assert().Equal(4, count)
+
+	// the events can't be changed afterwards
+	_, err = s.db.Exec("UPDATE audit_events SET action = 'login'")
+	s.Require().Error(err)
+	s.Assert().Contains(err.Error(), "append-only")
+
+	_, err = s.db.Exec("DELETE FROM audit_events")
+	s.Require().Error(err)
+	s.Assert().Contains(err.Error(), "append-only")
+}
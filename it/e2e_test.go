@@ -14,6 +14,7 @@ import (
 	"librenote/infrastructure/config"
 	"librenote/infrastructure/db"
 	"net/http"
+	"net/http/cookiejar"
 	"os"
 	"strings"
 	"syscall"
@@ -135,6 +136,68 @@ func (s *e2eTestSuite) doLogin(payload string) string {
 	return r.Token
 }
 
+func (s *e2eTestSuite) Test_EndToEnd_CookieSession() {
+	s.createUser(3)
+
+	jar, err := cookiejar.New(nil)
+	s.Require().NoError(err)
+
+	client := http.Client{Jar: jar}
+
+	send := func(method, path, body string, header map[string]string) (*http.Response, response.Response) {
+		req, err := http.NewRequest(method, s.apiBaseURL+path, strings.NewReader(body))
+		s.Require().NoError(err)
+
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+
+		res, err := client.Do(req)
+		s.Require().NoError(err)
+
+		byteBody, err := io.ReadAll(res.Body)
+		s.NoError(err)
+
+		_ = res.Body.Close()
+
+		var r response.Response
+
+		_ = json.Unmarshal(byteBody, &r)
+
+		return res, r
+	}
+
+	// the token is only in the cookie
+	res, r := send(echo.POST, "/login", `{"email": "mrtest3@example.com", "password":"12345678", "cookie":true}`, nil)
+	s.Equal(http.StatusOK, res.StatusCode)
+	s.Empty(r.Token)
+
+	res, r = send(echo.GET, "/me", "", nil)
+	s.Equal(http.StatusOK, res.StatusCode)
+	s.Equal("mrtest3@example.com", r.Results.(map[string]interface{})["email"])
+
+	csrfToken := res.Header.Get("X-CSRF-Token")
+	s.NotEmpty(csrfToken)
+
+	res, _ = send(echo.POST, "/logout", "", nil)
+	s.Equal(http.StatusForbidden, res.StatusCode)
+
+	res, r = send(echo.POST, "/logout", "", map[string]string{"X-CSRF-Token": csrfToken})
+	s.Equal(http.StatusOK, res.StatusCode)
+	s.Equal("logout successful", r.Message)
+
+	res, _ = send(echo.GET, "/me", "", nil)
+	s.Equal(http.StatusBadRequest, res.StatusCode)
+
+	// the bearer tokens keep working
+	token := s.doLogin(loginJSON)
+
+	res, _ = send(echo.POST, "/logout", "", map[string]string{echo.HeaderAuthorization: "Bearer " + token})
+	s.Equal(http.StatusOK, res.StatusCode)
+}
+
 func (s *e2eTestSuite) createUser(howMany int) {
 	for i := 1; i <= howMany; i++ {
 		nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
@@ -0,0 +1,94 @@
+package it_test
+
+import (
+	"context"
+	"librenote/app/encryption"
+	rotationRepo "librenote/app/encryption/repository/sqlite"
+	"librenote/app/encryption/usecase"
+	"librenote/app/model"
+	"librenote/app/note/repository/encrypted"
+	noteRepo "librenote/app/note/repository/sqlite"
+	"librenote/infrastructure/storage"
+	"time"
+)
+
+func (s *SqliteRepositoryTestSuite) TestSqliteKeyRotation() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	title, milk, plan := "Groceries", "milk", "plan"
+
+	oldKey, err := encryption.GenerateKey()
+	s.Require().NoError(err)
+
+	newKey, err := encryption.GenerateKey()
+	s.Require().NoError(err)
+
+	oldKeyring, _ := encryption.NewKeyring(oldKey)
+	keyring, _ := encryption.NewKeyring(oldKey, newKey)
+
+	// a note stored in clear and one encrypted by the previous key
+	inClear := &model.Note{UserID: userID, Title: &title, Body: "in clear [[Plan]]", Type: "note", CreatedAt: nowTime,
+		UpdatedAt: nowTime, References: []model.NoteReference{{Title: &plan}}}
+	s.Require().NoError(noteRepo.NewSqliteNoteRepository(s.db).CreateNote(context.Background(), inClear,
+		&model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+
+	old := &model.Note{UserID: userID, Title: &title, Body: "encrypted", Type: "list", CreatedAt: nowTime,
+		UpdatedAt: nowTime, Items: []model.NotesItem{{Text: &milk, CreatedAt: nowTime}},
+		References: []model.NoteReference{{Title: &plan}}}
+	s.Require().NoError(encrypted.NewNoteRepository(noteRepo.NewSqliteNoteRepository(s.db), oldKeyring).
+		CreateNote(context.Background(), old, &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+
+	var stored string
+	s.Require().NoError(s.db.QueryRow("SELECT title FROM notes WHERE id = ?", old.ID).Scan(&stored))
+	s.Assert().NotEqual(title, stored)
+
+	var hash string
+	s.Require().NoError(s.db.QueryRow("SELECT title, title_hash FROM notes_references WHERE note_id = ?", old.ID).
+		Scan(&stored, &hash))
+	s.Assert().NotEqual(plan, stored)
+	s.Assert().Equal(oldKeyring.Hash(plan), hash)
+
+	u := usecase.NewKeyRotationUsecase(rotationRepo.NewSqliteKeyRotationRepository(s.db), keyring,
+		encryption.NewBlobStore(storage.NewLocalStore(s.T().TempDir()), keyring), time.Second*2)
+
+	report, err := u.Rotate(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Assert().Equal(2, report.Values["notes.title"])
+	s.Assert().Equal(2, report.Values["notes.body"])
+	s.Assert().Equal(1, report.Values["notes_items.text"])
+	s.Assert().Equal(2, report.Values["notes_revisions.snapshot"])
+	s.Assert().Equal(2, report.Values["notes_references.title"])
+	s.Assert().Equal(2, report.Hashes["notes_references.title"])
+
+	// the previous key isn't needed anymore
+	newOnly, _ := encryption.NewKeyring(newKey)
+	r := encrypted.NewNoteRepository(noteRepo.NewSqliteNoteRepository(s.db), newOnly)
+
+	notes, _, err := r.FetchNotes(context.Background(), model.NoteFilter{UserID: userID}, 10, 0)
+	s.Require().NoError(err)
+	s.Require().Len(notes, 2)
+
+	for _, note := range notes {
+		s.Assert().Equal(title, *note.Title)
+	}
+
+	res, err := r.GetNote(context.Background(), old.ID, userID)
+	s.Require().NoError(err)
+	s.Assert().Equal("encrypted", res.Body)
+	s.Assert().Equal(milk, *res.Items[0].Text)
+
+	// the references stored in clear or hashed by the previous key are looked up by the new one
+	backlinks, err := r.FetchBacklinks(context.Background(), 0, userID, plan)
+	s.Require().NoError(err)
+	s.Assert().Len(backlinks, 2)
+
+	_, refs, err := r.FetchGraph(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Require().Len(refs, 2)
+	s.Assert().Equal(plan, *refs[0].Title)
+
+	report, err = u.Rotate(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Assert().Equal(0, report.Values["notes.title"])
+	s.Assert().Equal(0, report.Hashes["notes_references.title"])
+}
@@ -0,0 +1,86 @@
+package it_test
+
+import (
+	"context"
+	labelRepo "librenote/app/label/repository/sqlite"
+	"librenote/app/model"
+	noteRepo "librenote/app/note/repository/sqlite"
+	"time"
+)
+
+func (s *SqliteRepositoryTestSuite) TestSqliteLabelRepository() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	note := &model.Note{UserID: userID, Type: "note", CreatedAt: nowTime, UpdatedAt: nowTime}
+	nr := noteRepo.NewSqliteNoteRepository(s.db)
+	s.Require().NoError(nr.CreateNote(context.Background(), note, &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+
+	r := labelRepo.NewSqliteLabelRepository(s.db)
+	work := &model.Label{Name: "Work", UserID: userID, CreatedAt: nowTime, UpdatedAt: nowTime}
+	home := &model.Label{Name: "Home", UserID: userID, CreatedAt: nowTime, UpdatedAt: nowTime}
+	s.Require().NoError(r.CreateLabel(context.Background(), work))
+	s.Require().NoError(r.CreateLabel(context.Background(), home))
+
+	labels, err := r.FetchLabels(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Require().Len(labels, 2)
+	s.Assert().Equal("Home", labels[0].Name)
+
+	s.Require().NoError(r.AddNoteLabel(context.Background(), note.ID, work.ID))
+	s.Require().NoError(r.AddNoteLabel(context.Background(), note.ID, work.ID))
+
+	var count int
+	s.Require().NoError(s.db.QueryRow(`SELECT COUNT(*) FROM notes_labels WHERE note_id = ?`, note.ID).Scan(&count))
+	s.Assert().Equal(1, count)
+
+	notesLabels, err := r.FetchNotesLabels(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Assert().Equal([]model.NotesLabel{{NoteID: note.ID, LabelID: work.ID}}, notesLabels)
+}
+
+func (s *SqliteRepositoryTestSuite) TestSqliteLabelRepository_Tree() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	r := labelRepo.NewSqliteLabelRepository(s.db)
+	work := &model.Label{Name: "work", UserID: userID, CreatedAt: nowTime, UpdatedAt: nowTime}
+	s.Require().NoError(r.CreateLabel(context.Background(), work))
+
+	projects := &model.Label{Name: "projects", UserID: userID, ParentID: &work.ID, CreatedAt: nowTime, UpdatedAt: nowTime}
+	s.Require().NoError(r.CreateLabel(context.Background(), projects))
+
+	x := &model.Label{Name: "x", UserID: userID, ParentID: &projects.ID, CreatedAt: nowTime, UpdatedAt: nowTime}
+	s.Require().NoError(r.CreateLabel(context.Background(), x))
+
+	note := &model.Note{UserID: userID, Type: "note", CreatedAt: nowTime, UpdatedAt: nowTime}
+	nr := noteRepo.NewSqliteNoteRepository(s.db)
+	s.Require().NoError(nr.CreateNote(context.Background(), note, &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+	s.Require().NoError(r.AddNoteLabel(context.Background(), note.ID, x.ID))
+
+	filter := model.NoteFilter{UserID: userID, LabelIDs: []int32{work.ID, projects.ID, x.ID}}
+	notes, count, err := nr.FetchNotes(context.Background(), filter, 10, 0)
+	s.Require().NoError(err)
+	s.Assert().Equal(1, count)
+	s.Assert().Len(notes, 1)
+
+	x.ParentID = nil
+	s.Require().NoError(r.UpdateLabel(context.Background(), x))
+
+	res, err := r.GetLabel(context.Background(), x.ID, userID)
+	s.Require().NoError(err)
+	s.Assert().Nil(res.ParentID)
+
+	x.ParentID = &projects.ID
+	s.Require().NoError(r.UpdateLabel(context.Background(), x))
+	s.Require().NoError(r.DeleteLabel(context.Background(), projects.ID, userID))
+
+	res, err = r.GetLabel(context.Background(), x.ID, userID)
+	s.Require().NoError(err)
+	s.Assert().Equal(work.ID, *res.ParentID)
+
+	filter.LabelIDs = []int32{projects.ID}
+	notes, _, err = nr.FetchNotes(context.Background(), filter, 10, 0)
+	s.Require().NoError(err)
+	s.Assert().Empty(notes)
+}
@@ -0,0 +1,290 @@
+package it_test
+
+import (
+	"context"
+	"fmt"
+	labelRepo "librenote/app/label/repository/sqlite"
+	"librenote/app/model"
+	noteRepo "librenote/app/note/repository/sqlite"
+	userRepo "librenote/app/user/repository/sqlite"
+	"time"
+)
+
+func (s *SqliteRepositoryTestSuite) createNoteOwner() int32 {
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	newUser := &model.User{
+		FullName:  "Mr. Test",
+		Email:     "mrtest@example.com",
+		Hash:      "sfj34ksfdsfj$24247834skfjskdf",
+		IsActive:  1,
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+	}
+
+	ur := userRepo.NewSqliteUserRepository(s.db)
+	s.Require().NoError(ur.CreateUser(context.Background(), newUser))
+
+	user, err := ur.GetUserByEmail(context.Background(), newUser.Email)
+	s.Require().NoError(err)
+
+	return user.ID
+}
+
+func (s *SqliteRepositoryTestSuite) TestSqliteNoteRepository_CreateAndGetNote() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	title, milk, eggs := "Groceries", "milk", "eggs"
+
+	note := &model.Note{
+		UserID: userID, Title: &title, Body: "from [the shop](https://shop.example)", Type: "list",
+		CreatedAt: nowTime, UpdatedAt: nowTime, Links: []string{"https://shop.example"},
+		Items: []model.NotesItem{
+			{Text: &milk, CreatedAt: nowTime},
+			{Text: &eggs, IsChecked: 1, CreatedAt: nowTime},
+		},
+	}
+
+	r := noteRepo.NewSqliteNoteRepository(s.db)
+	s.Require().NoError(r.CreateNote(context.Background(), note, &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+
+	res, err := r.GetNote(context.Background(), note.ID, userID)
+	s.Require().NoError(err)
+	s.Assert().Equal(title, *res.Title)
+	s.Assert().Equal(note.Body, res.Body)
+	s.Assert().Equal("", res.Color)
+	s.Assert().Len(res.Items, 2)
+	s.Assert().Equal(int8(1), res.Items[1].IsChecked)
+
+	_, err = r.GetNote(context.Background(), note.ID, userID+1)
+	s.Assert().Error(err)
+
+	var link string
+	s.Require().NoError(s.db.QueryRow("SELECT url FROM notes_links WHERE note_id = ?", note.ID).Scan(&link))
+	s.Assert().Equal("https://shop.example", link)
+
+	bread, milkID := "bread", note.Items[0].ID
+	note.Links = nil
+	note.Items = []model.NotesItem{note.Items[0], {Text: &bread, CreatedAt: nowTime}}
+	revision := &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}
+	s.Require().NoError(r.UpdateNote(context.Background(), note, revision, 10))
+
+	var count int
+	s.Require().NoError(s.db.QueryRow("SELECT COUNT(*) FROM notes_links WHERE note_id = ?", note.ID).Scan(&count))
+	s.Assert().Equal(0, count)
+
+	// the items are kept by id, the removed ones are deleted
+	res, err = r.GetNote(context.Background(), note.ID, userID)
+	s.Require().NoError(err)
+	s.Require().Len(res.Items, 2)
+	s.Assert().Equal(milkID, res.Items[0].ID)
+	s.Assert().Equal(note.Items[1].ID, res.Items[1].ID)
+	s.Assert().Equal(bread, *res.Items[1].Text)
+}
+
+func (s *SqliteRepositoryTestSuite) TestSqliteNoteRepository_Drawing() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	drawing := &model.Drawing{Width: 800, Height: 600, Strokes: []model.Stroke{
+		{Color: "#1e88e5", Width: 3, Points: []float64{10, 10, 20.5, 30}},
+	}}
+
+	note := &model.Note{UserID: userID, Type: "drawing", Drawing: drawing, CreatedAt: nowTime, UpdatedAt: nowTime}
+
+	r := noteRepo.NewSqliteNoteRepository(s.db)
+	s.Require().NoError(r.CreateNote(context.Background(), note, &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+
+	res, err := r.GetNote(context.Background(), note.ID, userID)
+	s.Require().NoError(err)
+	s.Assert().Equal(drawing, res.Drawing)
+
+	note.Type, note.Drawing = "note", nil
+	revision := &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}
+	s.Require().NoError(r.UpdateNote(context.Background(), note, revision, 10))
+
+	notes, _, err := r.FetchNotes(context.Background(), model.NoteFilter{UserID: userID}, 10, 0)
+	s.Require().NoError(err)
+	s.Require().Len(notes, 1)
+	s.Assert().Nil(notes[0].Drawing)
+}
+
+func (s *SqliteRepositoryTestSuite) TestSqliteNoteRepository_Encrypted() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	encrypted := &model.EncryptedContent{Ciphertext: "c2VjcmV0", Algorithm: "AES-GCM", Nonce: "bm9uY2U=", KeyVersion: 1}
+
+	note := &model.Note{UserID: userID, Type: "note", Encrypted: encrypted, CreatedAt: nowTime, UpdatedAt: nowTime}
+
+	r := noteRepo.NewSqliteNoteRepository(s.db)
+	s.Require().NoError(r.CreateNote(context.Background(), note, &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+
+	res, err := r.GetNote(context.Background(), note.ID, userID)
+	s.Require().NoError(err)
+	s.Assert().Equal(encrypted, res.Encrypted)
+	s.Assert().Nil(res.Title)
+	s.Assert().Empty(res.Body)
+
+	note.Encrypted = &model.EncryptedContent{Ciphertext: "bmV3", Algorithm: "AES-GCM", KeyVersion: 2}
+	revision := &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}
+	s.Require().NoError(r.UpdateNote(context.Background(), note, revision, 10))
+
+	notes, _, err := r.FetchNotes(context.Background(), model.NoteFilter{UserID: userID}, 10, 0)
+	s.Require().NoError(err)
+	s.Require().Len(notes, 1)
+	s.Assert().Equal(note.Encrypted, notes[0].Encrypted)
+}
+
+func (s *SqliteRepositoryTestSuite) TestSqliteNoteRepository_RevisionsArePruned() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	note := &model.Note{UserID: userID, Type: "note", CreatedAt: nowTime, UpdatedAt: nowTime}
+
+	r := noteRepo.NewSqliteNoteRepository(s.db)
+	s.Require().NoError(r.CreateNote(context.Background(), note, &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+
+	for i := 1; i <= 4; i++ {
+		title := fmt.Sprintf("title %d", i)
+		note.Title = &title
+		revision := &model.NoteRevision{Snapshot: fmt.Sprintf(`{"title":%q}`, title), CreatedAt: nowTime}
+		s.Require().NoError(r.UpdateNote(context.Background(), note, revision, 3))
+	}
+
+	revisions, err := r.FetchRevisions(context.Background(), note.ID)
+	s.Require().NoError(err)
+	s.Assert().Len(revisions, 3)
+	s.Assert().Equal(`{"title":"title 4"}`, revisions[0].Snapshot)
+
+	s.Require().NoError(r.DeleteNote(context.Background(), note.ID, userID))
+
+	revisions, err = r.FetchRevisions(context.Background(), note.ID)
+	s.Require().NoError(err)
+	s.Assert().Len(revisions, 0)
+}
+
+func (s *SqliteRepositoryTestSuite) TestSqliteNoteRepository_BacklinksAndGraph() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	title, ref := "Plan", "plan"
+
+	r := noteRepo.NewSqliteNoteRepository(s.db)
+	target := &model.Note{UserID: userID, Title: &title, Type: "note", CreatedAt: nowTime, UpdatedAt: nowTime}
+	revision := &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}
+	s.Require().NoError(r.CreateNote(context.Background(), target, revision))
+
+	byTitle := &model.Note{
+		UserID: userID, Body: "[[Plan]]", Type: "note", CreatedAt: nowTime, UpdatedAt: nowTime,
+		References: []model.NoteReference{{Title: &ref}},
+	}
+	byID := &model.Note{
+		UserID: userID, Body: "[[#1]]", Type: "note", CreatedAt: nowTime, UpdatedAt: nowTime,
+		References: []model.NoteReference{{TargetID: &target.ID}},
+	}
+
+	for _, n := range []*model.Note{byTitle, byID} {
+		s.Require().NoError(r.CreateNote(context.Background(), n, &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+	}
+
+	backlinks, err := r.FetchBacklinks(context.Background(), target.ID, userID, ref)
+	s.Require().NoError(err)
+	s.Assert().Len(backlinks, 2)
+
+	byID.IsTrashed = 1
+	s.Require().NoError(r.UpdateNote(context.Background(), byID, revision, 10))
+
+	backlinks, err = r.FetchBacklinks(context.Background(), target.ID, userID, ref)
+	s.Require().NoError(err)
+	s.Require().Len(backlinks, 1)
+	s.Assert().Equal(byTitle.ID, backlinks[0].ID)
+
+	notes, refs, err := r.FetchGraph(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Assert().Len(notes, 2)
+	s.Require().Len(refs, 1)
+	s.Assert().Equal(byTitle.ID, refs[0].NoteID)
+	s.Assert().Equal(ref, *refs[0].Title)
+}
+
+func (s *SqliteRepositoryTestSuite) TestSqliteNoteRepository_BulkNotes() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	nr := noteRepo.NewSqliteNoteRepository(s.db)
+	notes := []*model.Note{
+		{UserID: userID, Type: "note", CreatedAt: nowTime, UpdatedAt: nowTime},
+		{UserID: userID, Type: "note", CreatedAt: nowTime, UpdatedAt: nowTime},
+	}
+
+	for _, n := range notes {
+		s.Require().NoError(nr.CreateNote(context.Background(), n, &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+	}
+
+	label := &model.Label{Name: "Work", UserID: userID, CreatedAt: nowTime, UpdatedAt: nowTime}
+	s.Require().NoError(labelRepo.NewSqliteLabelRepository(s.db).CreateLabel(context.Background(), label))
+
+	ids := []int32{notes[0].ID, notes[1].ID, notes[1].ID + 1}
+	op := model.BulkOperation{UserID: userID, Action: model.BulkRecolor, IDs: ids, Color: "teal", UpdatedAt: nowTime}
+
+	results, err := nr.BulkNotes(context.Background(), op)
+	s.Require().NoError(err)
+	s.Assert().Equal(model.BulkNotFound, results[2].Status)
+
+	op.Action, op.LabelID = model.BulkAddLabel, label.ID
+	_, err = nr.BulkNotes(context.Background(), op)
+	s.Require().NoError(err)
+
+	labeled, count, err := nr.FetchNotes(context.Background(),
+		model.NoteFilter{UserID: userID, LabelIDs: []int32{label.ID}}, 10, 0)
+	s.Require().NoError(err)
+	s.Require().Equal(2, count)
+	s.Assert().Equal("teal", labeled[0].Color)
+
+	op.Action, op.IDs = model.BulkDelete, ids[:1]
+	_, err = nr.BulkNotes(context.Background(), op)
+	s.Require().NoError(err)
+
+	_, err = nr.GetNote(context.Background(), notes[0].ID, userID)
+	s.Assert().Error(err)
+
+	// a label of another user fails the whole operation
+	op.Action, op.IDs, op.LabelID = model.BulkRemoveLabel, ids[1:2], label.ID+1
+	_, err = nr.BulkNotes(context.Background(), op)
+	s.Assert().Error(err)
+}
+
+func (s *SqliteRepositoryTestSuite) TestSqliteNoteRepository_Positions() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+	first, second := "first", "second"
+
+	r := noteRepo.NewSqliteNoteRepository(s.db)
+	notes := []*model.Note{
+		{UserID: userID, Type: "note", Position: "a2", CreatedAt: nowTime, UpdatedAt: nowTime},
+		{UserID: userID, Type: "note", Position: "a1", CreatedAt: nowTime, UpdatedAt: nowTime},
+		{UserID: userID, Type: "list", Position: "a3", CreatedAt: nowTime, UpdatedAt: nowTime, Items: []model.NotesItem{
+			{Text: &first, Position: "a1", CreatedAt: nowTime},
+			{Text: &second, Position: "a0", CreatedAt: nowTime},
+		}},
+	}
+
+	for _, n := range notes {
+		s.Require().NoError(r.CreateNote(context.Background(), n, &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+	}
+
+	next, err := r.NextPosition(context.Background(), userID, notes[0].ID, "a1")
+	s.Require().NoError(err)
+	s.Assert().Equal("a3", next)
+
+	next, err = r.NextPosition(context.Background(), userID, 0, "a3")
+	s.Require().NoError(err)
+	s.Assert().Equal("", next)
+
+	s.Require().NoError(r.UpdatePosition(context.Background(), notes[2].ID, userID, "a0"))
+	s.Require().NoError(r.UpdateItemPosition(context.Background(), notes[2].ID, notes[2].Items[0].ID, "Zz"))
+
+	fetched, _, err := r.FetchNotes(context.Background(), model.NoteFilter{UserID: userID}, 10, 0)
+	s.Require().NoError(err)
+	s.Require().Len(fetched, 3)
+	s.Assert().Equal([]int32{notes[2].ID, notes[1].ID, notes[0].ID},
+		[]int32{fetched[0].ID, fetched[1].ID, fetched[2].ID})
+	s.Assert().Equal(first, *fetched[0].Items[0].Text)
+}
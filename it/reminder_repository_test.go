@@ -0,0 +1,63 @@
+package it_test
+
+import (
+	"context"
+	"librenote/app/model"
+	noteRepo "librenote/app/note/repository/sqlite"
+	reminderRepo "librenote/app/reminder/repository/sqlite"
+	"time"
+)
+
+func (s *SqliteRepositoryTestSuite) TestSqliteReminderRepository_DueAndClaim() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	note := &model.Note{UserID: userID, Type: "note", CreatedAt: nowTime, UpdatedAt: nowTime}
+	nr := noteRepo.NewSqliteNoteRepository(s.db)
+	s.Require().NoError(nr.CreateNote(context.Background(), note, &model.NoteRevision{Snapshot: "{}", CreatedAt: nowTime}))
+
+	r := reminderRepo.NewSqliteReminderRepository(s.db)
+	reminder := &model.Reminder{
+		NoteID: note.ID, UserID: userID, RemindAt: "2022-01-31 09:00:00", Timezone: "UTC", Channel: "event",
+		Status: model.ReminderPending, DueAt: "2022-01-31 09:00:00", CreatedAt: nowTime, UpdatedAt: nowTime,
+	}
+	s.Require().NoError(r.CreateReminder(context.Background(), reminder))
+
+	due, err := r.FetchDueReminders(context.Background(), "2022-01-31 08:59:59", 10)
+	s.Require().NoError(err)
+	s.Assert().Len(due, 0)
+
+	due, err = r.FetchDueReminders(context.Background(), "2022-01-31 09:00:00", 10)
+	s.Require().NoError(err)
+	s.Require().Len(due, 1)
+
+	ok, err := r.ClaimReminder(context.Background(), reminder.ID, due[0].DueAt, "2022-01-31 09:05:00")
+	s.Require().NoError(err)
+	s.Assert().True(ok)
+
+	// a second claim with the stale due_at loses
+	ok, err = r.ClaimReminder(context.Background(), reminder.ID, due[0].DueAt, "2022-01-31 09:05:00")
+	s.Require().NoError(err)
+	s.Assert().False(ok)
+
+	s.Require().NoError(r.CreateEvent(context.Background(), &model.ReminderEvent{
+		UserID: userID, ReminderID: reminder.ID, NoteID: note.ID, Message: "Reminder", CreatedAt: nowTime,
+	}))
+
+	events, err := r.FetchEvents(context.Background(), userID, true)
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+
+	s.Require().NoError(r.MarkEventRead(context.Background(), events[0].ID, userID))
+
+	events, err = r.FetchEvents(context.Background(), userID, true)
+	s.Require().NoError(err)
+	s.Assert().Len(events, 0)
+
+	// deleting the note removes its reminders and events
+	s.Require().NoError(nr.DeleteNote(context.Background(), note.ID, userID))
+
+	events, err = r.FetchEvents(context.Background(), userID, false)
+	s.Require().NoError(err)
+	s.Assert().Len(events, 0)
+}
@@ -0,0 +1,71 @@
+package it_test
+
+import (
+	"context"
+	"database/sql"
+	labelRepo "librenote/app/label/repository/sqlite"
+	"librenote/app/model"
+	templateRepo "librenote/app/template/repository/sqlite"
+	"time"
+)
+
+func (s *SqliteRepositoryTestSuite) TestSqliteTemplateRepository() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	lr := labelRepo.NewSqliteLabelRepository(s.db)
+	work := &model.Label{Name: "work", UserID: userID, CreatedAt: nowTime, UpdatedAt: nowTime}
+	home := &model.Label{Name: "home", UserID: userID, CreatedAt: nowTime, UpdatedAt: nowTime}
+	s.Require().NoError(lr.CreateLabel(context.Background(), work))
+	s.Require().NoError(lr.CreateLabel(context.Background(), home))
+
+	title := "Release of {{date}}"
+	r := templateRepo.NewSqliteTemplateRepository(s.db)
+	release := &model.Template{
+		UserID:    userID,
+		Name:      "release",
+		Title:     &title,
+		Type:      "list",
+		Items:     []model.SnapshotItem{{Text: "tag"}, {Text: "announce", IsChecked: 1}},
+		LabelIDs:  []int32{work.ID, home.ID},
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+	}
+	s.Require().NoError(r.CreateTemplate(context.Background(), release))
+
+	drawing := &model.Template{
+		UserID:    userID,
+		Name:      "sketch",
+		Type:      "drawing",
+		Items:     []model.SnapshotItem{},
+		Drawing:   &model.Drawing{Width: 100, Height: 50, Strokes: []model.Stroke{}},
+		CreatedAt: nowTime,
+		UpdatedAt: nowTime,
+	}
+	s.Require().NoError(r.CreateTemplate(context.Background(), drawing))
+
+	res, err := r.GetTemplate(context.Background(), release.ID, userID)
+	s.Require().NoError(err)
+	s.Assert().Equal(title, *res.Title)
+	s.Assert().Equal(release.Items, res.Items)
+	s.Assert().Equal([]int32{home.ID, work.ID}, res.LabelIDs)
+
+	_, err = r.GetTemplate(context.Background(), release.ID, userID+1)
+	s.Assert().ErrorIs(err, sql.ErrNoRows)
+
+	// deleting a label removes it from the templates
+	s.Require().NoError(lr.DeleteLabel(context.Background(), home.ID, userID))
+
+	templates, err := r.FetchTemplates(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Require().Len(templates, 2)
+	s.Assert().Equal([]int32{work.ID}, templates[0].LabelIDs)
+	s.Assert().Equal(100, templates[1].Drawing.Width)
+
+	s.Assert().ErrorIs(r.DeleteTemplate(context.Background(), release.ID, userID+1), sql.ErrNoRows)
+	s.Require().NoError(r.DeleteTemplate(context.Background(), release.ID, userID))
+
+	templates, err = r.FetchTemplates(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Assert().Len(templates, 1)
+}
@@ -2,6 +2,7 @@ package it_test
 
 import (
 	"context"
+	"database/sql"
 	"librenote/app/model"
 	repo "librenote/app/user/repository/sqlite"
 	"time"
@@ -123,3 +124,103 @@ func (s *SqliteRepositoryTestSuite) TestSqliteUserRepository_UpdateUser() {
 	s.Assert().Equal(updateUser.Hash, result.Hash)
 	s.Assert().Equal(updateUser.DarkModeEnabled, result.DarkModeEnabled)
 }
+
+func (s *SqliteRepositoryTestSuite) TestSqliteUserRepository_Colors() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	r := repo.NewSqliteUserRepository(s.db)
+	color := &model.CustomColor{UserID: userID, Name: "sea green", Hex: "#2e8b57", CreatedAt: nowTime}
+	s.Require().NoError(r.CreateColor(context.Background(), color))
+	s.Assert().NotZero(color.ID)
+
+	duplicate := &model.CustomColor{UserID: userID, Name: "sea green", Hex: "#000000", CreatedAt: nowTime}
+	s.Assert().Error(r.CreateColor(context.Background(), duplicate))
+
+	colors, err := r.FetchColors(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Require().Len(colors, 1)
+	s.Assert().Equal("#2e8b57", colors[0].Hex)
+
+	s.Assert().ErrorIs(r.DeleteColor(context.Background(), color.ID, userID+1), sql.ErrNoRows)
+	s.Require().NoError(r.DeleteColor(context.Background(), color.ID, userID))
+
+	colors, err = r.FetchColors(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Assert().Empty(colors)
+}
+
+func (s *SqliteRepositoryTestSuite) TestSqliteUserRepository_KeyEnvelope() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	r := repo.NewSqliteUserRepository(s.db)
+	_, err := r.GetKeyEnvelope(context.Background(), userID)
+	s.Assert().ErrorIs(err, sql.ErrNoRows)
+
+	envelope := &model.KeyEnvelope{
+		UserID: userID, WrappedKey: "b2xk", Algorithm: "AES-KW", KDF: "argon2id", Salt: "c2FsdA==",
+		Iterations: 3, Memory: 65536, Parallelism: 1, KeyVersion: 1, CreatedAt: nowTime, UpdatedAt: nowTime,
+	}
+	s.Require().NoError(r.SaveKeyEnvelope(context.Background(), envelope))
+
+	envelope.WrappedKey, envelope.KeyVersion = "bmV3", 2
+	s.Require().NoError(r.SaveKeyEnvelope(context.Background(), envelope))
+
+	res, err := r.GetKeyEnvelope(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Assert().Equal(*envelope, res)
+}
+
+func (s *SqliteRepositoryTestSuite) TestSqliteUserRepository_LoginFailures() {
+	userID := s.createNoteOwner()
+	nowTime := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	r := repo.NewSqliteUserRepository(s.db)
+	_, err := r.GetLoginFailures(context.Background(), userID)
+	s.Assert().ErrorIs(err, sql.ErrNoRows)
+
+	attempts, err := r.AddLoginFailure(context.Background(), userID, nowTime)
+	s.Require().NoError(err)
+	s.Assert().Equal(int32(1), attempts)
+
+	attempts, err = r.AddLoginFailure(context.Background(), userID, nowTime)
+	s.Require().NoError(err)
+	s.Assert().Equal(int32(2), attempts)
+
+	later := time.Now().UTC().Add(time.Hour).Format("2006-01-02 15:04:05")
+	s.Require().NoError(r.LockLogin(context.Background(), userID, later))
+
+	// a shorter lock doesn't shorten it
+	s.Require().NoError(r.LockLogin(context.Background(), userID, nowTime))
+
+	res, err := r.GetLoginFailures(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Assert().Equal(model.LoginFailures{UserID: userID, Attempts: 2, LockedUntil: &later, UpdatedAt: nowTime}, res)
+
+	s.Require().NoError(r.DeleteLoginFailures(context.Background(), userID))
+
+	_, err = r.GetLoginFailures(context.Background(), userID)
+	s.Assert().ErrorIs(err, sql.ErrNoRows)
+}
+
+func (s *SqliteRepositoryTestSuite) TestSqliteUserRepository_Admin() {
+	userID := s.createNoteOwner()
+
+	r := repo.NewSqliteUserRepository(s.db)
+	isAdmin, err := r.IsAdmin(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Assert().False(isAdmin)
+
+	s.Require().NoError(r.SetAdmin(context.Background(), userID, true))
+
+	isAdmin, err = r.IsAdmin(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Assert().True(isAdmin)
+
+	s.Require().NoError(r.SetAdmin(context.Background(), userID, false))
+
+	isAdmin, err = r.IsAdmin(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Assert().False(isAdmin)
+}
@@ -0,0 +1,61 @@
+package it_test
+
+import (
+	"context"
+	"fmt"
+	"librenote/app/model"
+	webhookRepo "librenote/app/webhook/repository/sqlite"
+	"time"
+)
+
+func (s *SqliteRepositoryTestSuite) TestSqliteWebhookRepository_QueueAndLog() {
+	userID := s.createNoteOwner()
+	now := time.Date(2022, 1, 31, 9, 0, 0, 0, time.UTC)
+	nowTime := now.Format("2006-01-02 15:04:05")
+
+	r := webhookRepo.NewSqliteWebhookRepository(s.db)
+	webhook := &model.Webhook{
+		UserID: userID, URL: "http://127.0.0.1:9000/hook", Secret: "s3cr3t-key", IsActive: 1,
+		Events: []string{model.EventNoteCreated, model.EventNoteUpdated}, CreatedAt: nowTime, UpdatedAt: nowTime,
+	}
+	s.Require().NoError(r.CreateWebhook(context.Background(), webhook))
+
+	res, err := r.GetWebhook(context.Background(), webhook.ID, userID)
+	s.Require().NoError(err)
+	s.Assert().Equal(webhook.Events, res.Events)
+
+	for i := 0; i < 3; i++ {
+		s.Require().NoError(r.CreateDelivery(context.Background(), &model.WebhookDelivery{
+			WebhookID: webhook.ID, UserID: userID, Event: model.EventNoteCreated, Payload: fmt.Sprintf(`{"n":%d}`, i),
+			Status: model.DeliveryPending, DueAt: nowTime, CreatedAt: nowTime, UpdatedAt: nowTime,
+		}))
+	}
+
+	due, err := r.FetchDueDeliveries(context.Background(), nowTime, 2)
+	s.Require().NoError(err)
+	s.Require().Len(due, 2)
+
+	ok, err := r.ClaimDelivery(context.Background(), due[0].ID, due[0].DueAt, "2022-01-31 09:01:00")
+	s.Require().NoError(err)
+	s.Assert().True(ok)
+
+	due[0].Status = model.DeliveryDelivered
+	due[0].ResponseStatus = 200
+	due[0].Attempts = 1
+	s.Require().NoError(r.UpdateDelivery(context.Background(), &due[0]))
+
+	due, err = r.FetchDueDeliveries(context.Background(), nowTime, 10)
+	s.Require().NoError(err)
+	s.Assert().Len(due, 2)
+
+	deliveries, count, err := r.FetchDeliveries(context.Background(), webhook.ID, 2, 0)
+	s.Require().NoError(err)
+	s.Assert().Equal(3, count)
+	s.Assert().Len(deliveries, 2)
+
+	s.Require().NoError(r.DeleteWebhook(context.Background(), webhook.ID, userID))
+
+	_, count, err = r.FetchDeliveries(context.Background(), webhook.ID, 2, 0)
+	s.Require().NoError(err)
+	s.Assert().Equal(0, count)
+}